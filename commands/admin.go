@@ -0,0 +1,288 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+	"github.com/charmbracelet/huh"
+)
+
+// adminPasswordEnv passes the password to the runner, so it doesn't show up in its arguments
+const adminPasswordEnv = "BUI_ADMIN_PASSWORD"
+
+// minAdminPasswordLength is the shortest password bui admin create accepts
+const minAdminPasswordLength = 8
+
+var (
+	// adminEmail is the email the admin signs in with
+	adminEmail string
+
+	// adminPassword is the password; it is asked for when not given
+	adminPassword string
+
+	// adminRole is the name of the role the admin gets
+	adminRole string
+
+	// adminName is the admin's full name, split into first and last name
+	adminName string
+
+	// adminUpdate resets the password and role of an existing user with the email
+	adminUpdate bool
+)
+
+var adminCmd = &mamba.Command{
+	Use:   "admin",
+	Short: "Manage admin users of the backend",
+	Long: `Manage the users that sign in to the generated admin dashboard.
+
+Examples:
+  bui admin create --email admin@example.com
+  bui admin create --email admin@example.com --password 's3cret-pass' --role admin`,
+}
+
+var adminCreateCmd = &mamba.Command{
+	Use:   "create",
+	Short: "Create an admin user in the database",
+	Long: `Insert a user into the users table of the database configured in the backend's .env,
+so a new project can be signed in to without signing up or writing SQL.
+
+The password is hashed with bcrypt, like the template's login checks it, and is asked for
+when --password isn't given. The role is looked up by name in the roles table: an exact
+match first, then the one role whose name starts with it, so admin finds Administrator.
+The user's username, when the table has one, is the part of the email before the @.
+
+Run the backend once first, so it creates the users and roles tables.
+
+Examples:
+  bui admin create --email admin@example.com
+  bui admin create --email admin@example.com --password 's3cret-pass' --name 'Ada Admin'
+  bui admin create --email editor@example.com --role editor
+  bui admin create --email admin@example.com --update   # Reset the password and role`,
+	Run: createAdmin,
+}
+
+func init() {
+	rootCmd.AddCommand(adminCmd)
+	adminCmd.AddCommand(adminCreateCmd)
+	adminCreateCmd.Flags().StringVar(&adminEmail, "email", "", "Email the admin signs in with (required)")
+	adminCreateCmd.Flags().StringVar(&adminPassword, "password", "", "Password (asked for when not given)")
+	adminCreateCmd.Flags().StringVar(&adminRole, "role", "admin", "Name of the role in the roles table")
+	adminCreateCmd.Flags().StringVar(&adminName, "name", "", "Full name of the admin")
+	adminCreateCmd.Flags().BoolVar(&adminUpdate, "update", false, "Reset the password and role when the email already has a user")
+}
+
+// createAdmin runs a temporary program that hashes the password and inserts the user
+func createAdmin(cmd *mamba.Command, args []string) {
+	adminEmail = strings.TrimSpace(adminEmail)
+	if adminEmail == "" || !strings.Contains(adminEmail, "@") {
+		cmd.PrintError("A valid --email is required")
+		cmd.PrintInfo("Example: bui admin create --email admin@example.com")
+		os.Exit(1)
+	}
+
+	backendDir, _ := detectProjectDirs()
+	if _, err := os.Stat(filepath.Join(backendDir, "main.go")); os.IsNotExist(err) {
+		cmd.PrintError("Base project structure not found")
+		cmd.PrintInfo("Run bui admin create from the project root or the backend directory")
+		os.Exit(1)
+	}
+	goMod, err := os.ReadFile(filepath.Join(backendDir, "go.mod"))
+	if err != nil || !strings.Contains(string(goMod), "golang.org/x/crypto") {
+		cmd.PrintError("The backend doesn't depend on golang.org/x/crypto, which hashes the passwords")
+		cmd.PrintInfo("Add it with: go get golang.org/x/crypto")
+		os.Exit(1)
+	}
+
+	password := adminPassword
+	if password == "" {
+		if !isInteractiveInput() {
+			cmd.PrintError("--password is required when stdin isn't a terminal")
+			os.Exit(1)
+		}
+		if password, err = askAdminPassword(); err != nil {
+			cmd.PrintError(fmt.Sprintf("Failed to read the password: %v", err))
+			os.Exit(1)
+		}
+	}
+	if len(password) < minAdminPasswordLength {
+		cmd.PrintError(fmt.Sprintf("The password must have at least %d characters", minAdminPasswordLength))
+		os.Exit(1)
+	}
+
+	firstName, lastName, _ := strings.Cut(strings.TrimSpace(adminName), " ")
+	mode := "create"
+	if adminUpdate {
+		mode = "update"
+	}
+
+	os.Setenv(adminPasswordEnv, password)
+	defer os.Unsetenv(adminPasswordEnv)
+	source := strings.ReplaceAll(adminRunnerSource, "{{module}}", utils.GetGoModuleNameIn(backendDir))
+	if err := runBackendProgram(backendDir, source, mode, adminEmail, adminRole, firstName, strings.TrimSpace(lastName)); err != nil {
+		cmd.PrintError(fmt.Sprintf("Creating the admin failed: %v", err))
+		os.Exit(1)
+	}
+}
+
+// askAdminPassword asks for the password twice without echoing it
+func askAdminPassword() (string, error) {
+	var password, confirm string
+	form := huh.NewForm(huh.NewGroup(
+		huh.NewInput().Title("Password").EchoMode(huh.EchoModePassword).Value(&password).
+			Validate(func(s string) error {
+				if len(s) < minAdminPasswordLength {
+					return fmt.Errorf("use at least %d characters", minAdminPasswordLength)
+				}
+				return nil
+			}),
+		huh.NewInput().Title("Confirm password").EchoMode(huh.EchoModePassword).Value(&confirm).
+			Validate(func(s string) error {
+				if s != password {
+					return fmt.Errorf("the passwords don't match")
+				}
+				return nil
+			}),
+	))
+	if err := form.Run(); err != nil {
+		return "", err
+	}
+	return password, nil
+}
+
+// adminRunnerSource inserts or updates the user with the project's database connection. It writes
+// to the users table directly, setting only the columns the table has, so it doesn't depend on
+// the fields of the template's user model.
+const adminRunnerSource = `// Code generated by bui admin. DO NOT EDIT.
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"{{module}}/core/config"
+	"{{module}}/core/database"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+func main() {
+	mode, email, roleName, firstName, lastName := os.Args[1], os.Args[2], os.Args[3], os.Args[4], os.Args[5]
+
+	db, err := database.InitDB(config.NewConfig())
+	if err != nil {
+		fail("failed to connect to database: %v", err)
+	}
+	conn := db.DB
+	migrator := conn.Migrator()
+	if !migrator.HasTable("users") {
+		fail("the users table doesn't exist yet; start the backend once so it creates it")
+	}
+
+	var existing int64
+	if err := conn.Table("users").Where("LOWER(email) = LOWER(?)", email).Count(&existing).Error; err != nil {
+		fail("failed to look up %s: %v", email, err)
+	}
+	if existing > 0 && mode != "update" {
+		fail("a user with the email %s already exists; use --update to reset their password and role", email)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(os.Getenv("BUI_ADMIN_PASSWORD")), bcrypt.DefaultCost)
+	if err != nil {
+		fail("failed to hash the password: %v", err)
+	}
+	values := map[string]any{"password": string(hash)}
+
+	var role struct {
+		Id   uint
+		Name string
+	}
+	if roleName != "" && migrator.HasColumn("users", "role_id") {
+		role.Id, role.Name = findRole(conn, roleName)
+		values["role_id"] = role.Id
+	}
+	if firstName != "" && migrator.HasColumn("users", "first_name") {
+		values["first_name"] = firstName
+	}
+	if lastName != "" && migrator.HasColumn("users", "last_name") {
+		values["last_name"] = lastName
+	}
+	now := time.Now()
+	if migrator.HasColumn("users", "updated_at") {
+		values["updated_at"] = now
+	}
+
+	action := "Updated"
+	if existing > 0 {
+		if err := conn.Table("users").Where("LOWER(email) = LOWER(?)", email).Updates(values).Error; err != nil {
+			fail("failed to update %s: %v", email, err)
+		}
+	} else {
+		action = "Created"
+		values["email"] = email
+		if migrator.HasColumn("users", "username") {
+			username, _, _ := strings.Cut(email, "@")
+			values["username"] = username
+		}
+		if migrator.HasColumn("users", "created_at") {
+			values["created_at"] = now
+		}
+		if err := conn.Table("users").Create(values).Error; err != nil {
+			fail("failed to insert %s: %v", email, err)
+		}
+	}
+
+	if role.Name != "" {
+		fmt.Printf("%s %s with the role %s\n", action, email, role.Name)
+	} else {
+		fmt.Printf("%s %s\n", action, email)
+	}
+}
+
+// findRole returns the role named name, or else the only role whose name starts with it
+func findRole(conn *gorm.DB, name string) (uint, string) {
+	if !conn.Migrator().HasTable("roles") {
+		fail("the roles table doesn't exist; pass --role '' to create the user without a role")
+	}
+	var roles []struct {
+		Id   uint
+		Name string
+	}
+	if err := conn.Table("roles").Select("id, name").Order("id").Find(&roles).Error; err != nil {
+		fail("failed to read the roles: %v", err)
+	}
+
+	var names []string
+	for _, role := range roles {
+		if strings.EqualFold(role.Name, name) {
+			return role.Id, role.Name
+		}
+		names = append(names, role.Name)
+	}
+	var matches []int
+	for i, role := range roles {
+		if strings.HasPrefix(strings.ToLower(role.Name), strings.ToLower(name)) {
+			matches = append(matches, i)
+		}
+	}
+	if len(matches) == 1 {
+		return roles[matches[0]].Id, roles[matches[0]].Name
+	}
+	if len(names) == 0 {
+		fail("the roles table is empty; start the backend once so it seeds the roles")
+	}
+	fail("no role named %s (roles: %s)", name, strings.Join(names, ", "))
+	return 0, ""
+}
+
+func fail(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
+`
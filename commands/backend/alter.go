@@ -0,0 +1,192 @@
+package backend
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// alterBackendModule adds fields to an existing backend module: the model, service, validator
+// and seed files get the new fields in place and an ALTER migration is written
+func alterBackendModule(cmd *mamba.Command, w *utils.Writer, naming *utils.NamingConvention, newDefs []string) {
+	modelPath := w.Path("app", "models", naming.ModelSnake+".go")
+	source, err := os.ReadFile(modelPath)
+	if err != nil {
+		cmd.PrintError(fmt.Sprintf("Cannot alter %s: %s not found", naming.Model, modelPath))
+		cmd.PrintInfo("Generate the module without --alter first")
+		return
+	}
+
+	existingDefs, err := utils.RecoverFieldDefs(source, naming.Model)
+	if err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to read the fields of %s: %v", modelPath, err))
+		return
+	}
+
+	// The table the model was generated with, unless --table renames it. It isn't set as
+	// --table, which the frontend generator reads while this runs.
+	table := cmp.Or(utils.TableOverride, utils.RecoverTableName(source, naming.Model))
+
+	before := utils.NewTableTemplateData(naming.Model, table, existingDefs)
+	existing := map[string]bool{}
+	for _, field := range before.Fields {
+		existing[field.Name] = true
+	}
+
+	defs := existingDefs
+	for _, def := range newDefs {
+		// A point is stored as its <name>Lat and <name>Lng columns
+		field := utils.ParseField(def)
+		if existing[field.Name] || (field.IsPoint && existing[field.Name+"Lat"]) {
+			cmd.PrintWarning(fmt.Sprintf("%s already has field %s; skipping it", naming.Model, field.Name))
+			continue
+		}
+		defs = append(defs, def)
+	}
+	if len(defs) == len(existingDefs) {
+		cmd.PrintWarning(fmt.Sprintf("No new fields to add to %s", naming.Model))
+		return
+	}
+
+	after := utils.NewTableTemplateData(naming.Model, table, defs)
+	naming.TableName = after.TableName
+	warnUnknownIndexColumns(cmd, after.Fields)
+
+	var added []utils.Field
+	for _, field := range after.Fields {
+		if !existing[field.Name] {
+			added = append(added, field)
+		}
+	}
+
+	files := []struct{ path, template string }{
+		{modelPath, "model.tmpl"},
+		{w.Path("app", naming.DirName, "service.go"), "service.tmpl"},
+		{w.Path("app", naming.DirName, "validator.go"), "validator.tmpl"},
+		{w.Path("app", naming.DirName, "seed.go"), "seed.tmpl"},
+		{w.Path("app", naming.DirName, "revisions.go"), "revision.tmpl"},
+		{w.Path("app", naming.DirName, "pivots.go"), "pivot.tmpl"},
+		{w.Path("app", naming.DirName, naming.PackageName+"_test.go"), "test.tmpl"},
+	}
+	for _, file := range files {
+		if _, err := os.Stat(file.path); err != nil {
+			continue
+		}
+		if err := alterGoFile(w, file.path, file.template, naming, before.Fields, after.Fields); err != nil {
+			cmd.PrintWarning(fmt.Sprintf("Could not update %s: %v", file.path, err))
+		} else if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess(fmt.Sprintf("Updated %s", file.path))
+		}
+	}
+
+	// A first encrypted field needs the shared encryption package and its key
+	if len(utils.EncryptedFields(added)) > 0 {
+		scaffoldEncryption(cmd, w, naming)
+	}
+
+	// The validator checks the rules= modifiers of new fields with the shared rules package
+	if utils.HasRules(added) {
+		generateRules(cmd, w, naming, added)
+	}
+
+	// The service and validator of state fields call the transition checks in state.go
+	if len(utils.StateFields(added)) > 0 {
+		generateState(cmd, w, naming, after.Fields)
+	}
+
+	// A first manyToMany field with pivot columns needs pivots.go; later ones are added to it above
+	pivots := utils.PivotRelations(added)
+	if len(pivots) > 0 {
+		if _, err := os.Stat(w.Path("app", naming.DirName, "pivots.go")); err != nil {
+			generatePivots(cmd, w, naming, after.Fields)
+		}
+	}
+
+	// Computed fields have no column, so adding only those needs no migration
+	up, down := utils.AlterTableSQL(naming, after.Fields, added)
+	if up != "" {
+		migrationName := fmt.Sprintf("add_%s_to_%s", utils.ToSnakeCase(added[0].Name), naming.TableName)
+		if len(added) > 1 {
+			migrationName = fmt.Sprintf("add_fields_to_%s", naming.TableName)
+		}
+		upPath, err := w.WriteMigration(w.Path(utils.MigrationsDir), migrationName, up, down)
+		if err != nil {
+			cmd.PrintWarning(fmt.Sprintf("Failed to write migration: %v", err))
+		} else if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess(fmt.Sprintf("Generated %s", upPath))
+		}
+	}
+
+	if utils.DryRun {
+		cmd.PrintInfo(fmt.Sprintf("Dry run: backend module %s was not altered", naming.Model))
+		return
+	}
+
+	cmd.PrintSuccess(fmt.Sprintf("Added %d fields to backend module: %s", len(added), naming.Model))
+	if len(utils.GeoPoints(added)) > 0 {
+		cmd.PrintInfo("Nearby searches are generated with the module; regenerate it to search the new point fields")
+	}
+	if len(utils.StateFields(added)) > 0 {
+		cmd.PrintInfo(fmt.Sprintf("Route POST /%s/:id/transition in the controller, or regenerate the module, to move the new state fields", naming.PluralKebab))
+	}
+	for _, pivot := range pivots {
+		cmd.PrintInfo(fmt.Sprintf("Route /%s/:id/%s in the controller and set up the %s%s join table in Migrate, or regenerate the module, to edit the pivot data of %s",
+			naming.PluralKebab, utils.ToKebabCase(pivot.Name), naming.Model, pivot.RelatedModel, pivot.JSONName))
+	}
+}
+
+// addInverseRelations adds the hasMany side of the model's belongsTo fields to the related
+// modules (--with-inverse) and records them in the run for the frontend generator
+func addInverseRelations(cmd *mamba.Command, w *utils.Writer, naming *utils.NamingConvention, fields []string) {
+	if !utils.WithInverse {
+		return
+	}
+	inverses, problems := utils.InverseRelations(naming.Model, fields, w.Path("app", "models"))
+	for _, problem := range problems {
+		cmd.PrintWarning(problem)
+	}
+
+	// The table, indexes and nested forms given for this module aren't the related module's
+	table, unique, nested := utils.TableOverride, utils.UniqueIndexes, utils.NestedForms
+	defer func() {
+		utils.TableOverride, utils.UniqueIndexes, utils.NestedForms = table, unique, nested
+	}()
+	for _, inverse := range inverses {
+		utils.TableOverride, utils.UniqueIndexes, utils.NestedForms = "", nil, ""
+		cmd.PrintInfo(fmt.Sprintf("Adding %s to %s", inverse.Def, inverse.Model))
+		alterBackendModule(cmd, w, utils.NewNamingConvention(inverse.Model), []string{inverse.Def})
+		w.Run.Inverses = append(w.Run.Inverses, inverse)
+	}
+}
+
+// alterGoFile adds what the new fields change in a template's output to an existing Go file
+func alterGoFile(w *utils.Writer, path, templateName string, naming *utils.NamingConvention, before, after []utils.Field) error {
+	base, err := utils.RenderTemplateIn(w.Dir, templateName, naming, before)
+	if err != nil {
+		return err
+	}
+	updated, err := utils.RenderTemplateIn(w.Dir, templateName, naming, after)
+	if err != nil {
+		return err
+	}
+
+	missed, err := w.AlterFile(path, base, updated)
+	if err != nil {
+		return err
+	}
+	if missed > 0 {
+		return fmt.Errorf("%d changes did not match the file's layout; add them by hand", missed)
+	}
+
+	if utils.DryRun {
+		return nil
+	}
+	if _, err := exec.LookPath("goimports"); err == nil {
+		_ = exec.Command("goimports", "-w", path).Run()
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// scaffoldAuditLog writes the shared audit log that --audited services record their changes in,
+// its migration, and registers it in app/init.go
+func scaffoldAuditLog(cmd *mamba.Command, w *utils.Writer, naming *utils.NamingConvention) {
+	auditDir := w.Path("app", "auditlog")
+	files := map[string]string{
+		"entry.go":  "auditlog_entry.tmpl",
+		"module.go": "auditlog_module.tmpl",
+	}
+	for _, name := range []string{"entry.go", "module.go"} {
+		if _, err := os.Stat(filepath.Join(auditDir, name)); err == nil {
+			continue
+		}
+		w.GenerateFileFromTemplate(auditDir, name, files[name], naming, nil)
+	}
+
+	if utils.FindMigration(w.Path(utils.MigrationsDir), "create_audit_logs") == "" {
+		up, down := utils.AuditLogTableSQL()
+		if _, err := w.WriteMigration(w.Path(utils.MigrationsDir), "create_audit_logs", up, down); err != nil {
+			cmd.PrintWarning(fmt.Sprintf("Failed to write the audit_logs migration: %v", err))
+		}
+	}
+
+	if err := addModuleToAppInit(w, "auditlog"); err != nil {
+		cmd.PrintWarning("Could not add the auditlog module to app/init.go")
+		cmd.PrintInfo("Manually add to app/init.go: modules[\"auditlog\"] = auditlog.Init(deps)")
+	}
+}
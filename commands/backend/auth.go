@@ -0,0 +1,85 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// authExtrasDir holds the sign-ins bui g auth adds next to the template's auth module
+var authExtrasDir = filepath.Join("app", "auth_extras")
+
+// authData fills in the auth extras templates
+type authData struct {
+	utils.AuthFeatures
+	ModuleName string
+}
+
+// ExistingAuthFeatures returns the features earlier bui g auth runs generated, read from the
+// app/auth_extras of the backend in backendDir
+func ExistingAuthFeatures(backendDir string) utils.AuthFeatures {
+	var features utils.AuthFeatures
+	if oauth, err := os.ReadFile(filepath.Join(backendDir, authExtrasDir, "oauth.go")); err == nil {
+		for _, provider := range utils.OAuthProviders {
+			if strings.Contains(string(oauth), fmt.Sprintf("%q: {", provider)) {
+				features.Providers = append(features.Providers, provider)
+			}
+		}
+	}
+	_, err := os.Stat(filepath.Join(backendDir, authExtrasDir, "magic_link.go"))
+	features.MagicLink = err == nil
+	_, err = os.Stat(filepath.Join(backendDir, authExtrasDir, "totp.go"))
+	features.TOTP = err == nil
+	return features
+}
+
+// GenerateAuthExtras writes the app/auth_extras module for features plus any generated before,
+// registers it in app/init.go and adds the settings it reads to .env and .env.sample. It returns
+// the combined features, which the admin pages are generated for. Paths are those of the backend
+// w writes to.
+func GenerateAuthExtras(cmd *mamba.Command, w *utils.Writer, features utils.AuthFeatures) (utils.AuthFeatures, error) {
+	features = ExistingAuthFeatures(w.Dir).Union(features)
+	data := authData{AuthFeatures: features, ModuleName: utils.GetGoModuleNameIn(w.Dir)}
+
+	files := []struct {
+		name     string
+		template string
+		enabled  bool
+	}{
+		{"module.go", "auth/module.go.tmpl", true},
+		{"session.go", "auth/session.go.tmpl", true},
+		{"oauth.go", "auth/oauth.go.tmpl", features.OAuth()},
+		{"magic_link.go", "auth/magic_link.go.tmpl", features.MagicLink},
+		{"totp.go", "auth/totp.go.tmpl", features.TOTP},
+	}
+	for _, file := range files {
+		if !file.enabled {
+			continue
+		}
+		if err := w.GenerateFileFromData(w.Path(authExtrasDir), file.name, file.template, data); err != nil {
+			return features, fmt.Errorf("failed to generate %s: %w", file.name, err)
+		}
+	}
+
+	if err := addModuleToAppInit(w, "auth_extras"); err != nil {
+		cmd.PrintWarning("Could not add the auth_extras module to app/init.go")
+		cmd.PrintInfo("Manually add to app/init.go: modules[\"auth_extras\"] = auth_extras.Init(deps)")
+	}
+
+	for _, envFile := range []string{".env", ".env.sample"} {
+		added, err := w.AddEnvPlaceholders(w.Path(envFile), "Auth extras (bui g auth)", features.EnvVars())
+		if err != nil {
+			cmd.PrintWarning(fmt.Sprintf("Could not update %s: %v", envFile, err))
+			continue
+		}
+		if len(added) > 0 && !utils.DryRun {
+			cmd.PrintInfo(fmt.Sprintf("Added to %s: %s", envFile, strings.Join(added, ", ")))
+		}
+	}
+
+	return features, nil
+}
@@ -0,0 +1,41 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// scaffoldEncryption writes the shared app/encryption package the models of encrypted fields
+// use, once, and adds ENCRYPTION_KEY to .env with a new random key and to .env.sample empty
+func scaffoldEncryption(cmd *mamba.Command, w *utils.Writer, naming *utils.NamingConvention) {
+	encryptionDir := w.Path("app", "encryption")
+	if _, err := os.Stat(filepath.Join(encryptionDir, "encryption.go")); os.IsNotExist(err) {
+		w.GenerateFileFromTemplate(encryptionDir, "encryption.go", "encryption.tmpl", naming, nil)
+		if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess("Generated app/encryption/encryption.go")
+		}
+	}
+
+	key, err := utils.NewEncryptionKey()
+	if err != nil {
+		cmd.PrintWarning(fmt.Sprintf("Could not create an encryption key: %v", err))
+		return
+	}
+	comment := "AES-256 key of the encrypted fields, base64 encoded (openssl rand -base64 32); changing it makes saved values unreadable"
+	envFiles := []struct{ name, key string }{{".env", key}, {".env.sample", ""}}
+	for _, envFile := range envFiles {
+		vars := []utils.EnvVar{{Key: "ENCRYPTION_KEY", Value: envFile.key, Comment: comment}}
+		added, err := w.AddEnvPlaceholders(w.Path(envFile.name), "Encrypted fields", vars)
+		if err != nil {
+			cmd.PrintWarning(fmt.Sprintf("Could not update %s: %v", envFile.name, err))
+			continue
+		}
+		if len(added) > 0 && !utils.DryRun {
+			cmd.PrintInfo(fmt.Sprintf("Added to %s: ENCRYPTION_KEY", envFile.name))
+		}
+	}
+}
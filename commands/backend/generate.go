@@ -16,6 +16,16 @@ import (
 // Verbose is set by root command
 var Verbose *bool
 
+// NoTests skips generating the module test file
+var NoTests bool
+
+// AllowMissing generates relations to models that don't exist yet, with a warning instead of an error
+var AllowMissing bool
+
+// PendingModels are the models a multi-module run generates, which relations may refer to
+// before they exist
+var PendingModels []string
+
 var GenerateBackendCmd = &mamba.Command{
 	Use:     "backend [name] [field:type...]",
 	Aliases: []string{"be", "api"},
@@ -25,36 +35,158 @@ var GenerateBackendCmd = &mamba.Command{
 	Run:     generateBackendModule,
 }
 
+func init() {
+	GenerateBackendCmd.Flags().BoolVar(&NoTests, "no-tests", false, "Skip generating the module test file")
+	GenerateBackendCmd.Flags().BoolVar(&AllowMissing, "allow-missing", false, "Generate relations to models that don't exist yet")
+	GenerateBackendCmd.Flags().BoolVar(&utils.WithInverse, "with-inverse", false, "Add the hasMany side of belongsTo fields to the related models")
+	GenerateBackendCmd.Flags().BoolVar(&utils.DryRun, "dry-run", false, "Show the files that would be written without touching disk")
+	GenerateBackendCmd.Flags().BoolVar(&utils.ShowDiff, "diff", false, "Print a diff for each file during a dry run")
+	GenerateBackendCmd.Flags().BoolVarP(&utils.Force, "force", "f", false, "Overwrite existing files without asking")
+	GenerateBackendCmd.Flags().BoolVar(&utils.Nested, "nested", false, "Scope routes and admin pages under the first belongsTo parent")
+	GenerateBackendCmd.Flags().StringArrayVar(&utils.UniqueIndexes, "unique", nil, "Add a composite unique index over comma-separated columns (repeatable)")
+	GenerateBackendCmd.Flags().StringVar(&utils.TableOverride, "table", "", "Use a custom table name for the model")
+	GenerateBackendCmd.Flags().StringVar(&utils.PrimaryKey, "pk", "uint", "Primary key type: uint or uuid")
+	GenerateBackendCmd.Flags().StringVar(&utils.DatabaseFlag, "db", "", "Database to generate for: postgres, mysql or sqlite (default: database in .bui.yaml, else postgres)")
+	GenerateBackendCmd.Flags().BoolVar(&utils.NoSoftDelete, "no-soft-delete", false, "Omit the DeletedAt soft-delete column")
+	GenerateBackendCmd.Flags().BoolVar(&utils.Audit, "audit", false, "Add created_by/updated_by columns set from the authenticated user")
+	GenerateBackendCmd.Flags().BoolVar(&utils.Alter, "alter", false, "Add the fields to an existing module and write an ALTER migration")
+	GenerateBackendCmd.Flags().BoolVar(&utils.GraphQL, "graphql", false, "Also generate gqlgen schema and resolvers for the module")
+	GenerateBackendCmd.Flags().BoolVar(&utils.Realtime, "realtime", false, "Publish create/update/delete events to websocket clients")
+	GenerateBackendCmd.Flags().BoolVar(&utils.Tenant, "tenant", false, "Scope the module to the organization of the request")
+	GenerateBackendCmd.Flags().BoolVar(&utils.Audited, "audited", false, "Record create/update/delete history in the audit_logs table")
+	GenerateBackendCmd.Flags().BoolVar(&utils.Versioned, "versioned", false, "Keep a revision on every update, with revision history and restore endpoints")
+	GenerateBackendCmd.Flags().BoolVar(&utils.LockVersion, "lock-version", false, "Add a lock_version column that updates compare and swap, answering 409 on stale updates")
+	GenerateBackendCmd.Flags().BoolVar(&utils.ImportExport, "import-export", false, "Add CSV/XLSX export and import endpoints")
+	GenerateBackendCmd.Flags().BoolVar(&utils.PDF, "pdf", false, "Add an endpoint that downloads a record as PDF")
+	GenerateBackendCmd.Flags().StringVar(&utils.RateLimit, "rate-limit", "", "Limit the requests each client makes to the module's routes, e.g. 100/min")
+	GenerateBackendCmd.Flags().StringVar(&utils.Cache, "cache", "", "Cache the responses of the module's reads for a duration, e.g. 60s; changes invalidate them")
+	GenerateBackendCmd.Flags().StringArrayVar(&utils.FieldsRead, "fields-read", nil, "Only return these comma-separated columns, in list= or detail= responses or both (repeatable)")
+	GenerateBackendCmd.Flags().StringArrayVar(&utils.FieldsWrite, "fields-write", nil, "Only accept these comma-separated columns, in create= or update= requests or both (repeatable)")
+	GenerateBackendCmd.Flags().BoolVar(&utils.Bulk, "bulk", false, "Add bulk delete and bulk status update endpoints")
+	GenerateBackendCmd.Flags().StringVar(&utils.Searchable, "searchable", "", "Add full-text search over comma-separated text columns")
+	GenerateBackendCmd.Flags().BoolVar(&utils.PostGIS, "postgis", false, "Add PostGIS geography columns for point fields")
+	GenerateBackendCmd.Flags().StringVar(&utils.NestedForms, "nested-form", "", "Create and update the rows of comma-separated hasMany fields with the parent")
+	GenerateBackendCmd.Flags().StringVar(&utils.ModuleLabel, "label", "", "Display name of the module, used as its swagger tag (e.g. \"Sales Orders\")")
+}
+
+// checkRelatedModels reports relations to models that are neither in app/models nor pending.
+// It returns false when generation should stop, which --allow-missing turns into a warning.
+func checkRelatedModels(cmd *mamba.Command, w *utils.Writer, model string, fields []string) bool {
+	known := map[string]bool{}
+	for _, name := range append(utils.FindModels(w.Dir), PendingModels...) {
+		known[utils.ToPascalCase(name)] = true
+	}
+	missing := utils.MissingRelations(model, fields, known)
+	if len(missing) == 0 {
+		return true
+	}
+
+	if AllowMissing {
+		for _, relation := range missing {
+			cmd.PrintWarning(fmt.Sprintf("%s: %s doesn't exist yet; generate it before building", relation, relation.Related))
+		}
+		return true
+	}
+	cmd.PrintError("Relations refer to models that don't exist:")
+	for _, relation := range missing {
+		cmd.PrintBullet(relation.String())
+	}
+	related := utils.NewNamingConvention(missing[0].Related)
+	cmd.PrintInfo(fmt.Sprintf("Generate %s first (e.g. bui g %s name:string), generate both with bui g --from, or pass --allow-missing",
+		missing[0].Related, related.ModelSnake))
+	return false
+}
+
 // generateBackendModule generates a new backend module with the specified name and fields.
 func generateBackendModule(cmd *mamba.Command, args []string) {
-	singularName := args[0]
+	// The changelog and manifest are in the project root, found from the directory bui was run in
+	startDir, _ := os.Getwd()
+	w := utils.NewRun(utils.ProjectRoot(startDir), args[0]).Writer(DetectBackendDir())
+
+	if !CheckModule(cmd, w, args) {
+		os.Exit(1)
+	}
+	if err := GenerateModule(cmd, w, args); err != nil {
+		cmd.PrintError(err.Error())
+		return
+	}
+	FinishModule(cmd, w, args)
+}
+
+// CheckModule checks the flags and relations of the module args describe, in the backend w
+// writes to, before anything is written, printing what's wrong. It returns false when the module
+// shouldn't be generated.
+func CheckModule(cmd *mamba.Command, w *utils.Writer, args []string) bool {
 	fields := args[1:]
 
-	// Detect backend directory
-	backendDir := detectBackendDir()
-	if backendDir != "" && backendDir != "." {
-		// Change to backend directory
-		if err := os.Chdir(backendDir); err != nil {
-			cmd.PrintError(fmt.Sprintf("Failed to change to backend directory: %v", err))
-			return
-		}
-		if Verbose != nil && *Verbose {
-			cmd.PrintInfo(fmt.Sprintf("Working in: %s", backendDir))
-		}
+	if err := utils.CheckPrimaryKey(); err != nil {
+		cmd.PrintError(err.Error())
+		return false
+	}
+	if err := utils.CheckDatabase(); err != nil {
+		cmd.PrintError(err.Error())
+		return false
+	}
+	if err := utils.CheckModuleMetadata(); err != nil {
+		cmd.PrintError(err.Error())
+		return false
+	}
+	if err := utils.CheckRateLimitAndCache(); err != nil {
+		cmd.PrintError(err.Error())
+		return false
+	}
+	if err := utils.CheckPayloadFields(); err != nil {
+		cmd.PrintError(err.Error())
+		return false
+	}
+	if err := utils.CheckFieldRules(fields); err != nil {
+		cmd.PrintError(err.Error())
+		return false
+	}
+
+	naming := utils.NewNamingConvention(args[0])
+	if other := utils.NamespaceConflict(utils.FindModuleDirs(w.Dir, "module.go"), naming.PluralSnake, naming.DirName); other != "" {
+		cmd.PrintError(fmt.Sprintf("app/%s already has a %s module, which app/models/%s.go and the %s table belong to", other, naming.Model, naming.ModelSnake, naming.TableName))
+		cmd.PrintInfo("Modules in different namespaces share app/models and the database, so give this module another name")
+		return false
+	}
+
+	return checkRelatedModels(cmd, w, naming.Model, fields)
+}
+
+// newNaming returns the naming of the module being generated, with its --label and --description
+func newNaming(name string) *utils.NamingConvention {
+	naming := utils.NewNamingConvention(name)
+	utils.ApplyModuleMetadata(naming)
+	return naming
+}
+
+// GenerateModule generates the backend module args describe, with the fields as arguments after
+// its name, in the backend directory of w. Each path is resolved against that directory, so it
+// can run alongside the frontend generator; FinishModule adds what changes other modules.
+func GenerateModule(cmd *mamba.Command, w *utils.Writer, args []string) error {
+	naming := newNaming(args[0])
+	fields := args[1:]
+	if w.Dir != "." && Verbose != nil && *Verbose {
+		cmd.PrintInfo(fmt.Sprintf("Working in: %s", w.Dir))
 	}
 
-	// Create naming convention from the input name
-	naming := utils.NewNamingConvention(singularName)
+	if utils.Alter {
+		alterBackendModule(cmd, w, naming, fields)
+		return nil
+	}
 
 	// Create directories (plural names in snake_case)
 	dirs := []string{
-		filepath.Join("app", "models"),
-		filepath.Join("app", naming.DirName),
+		w.Path("app", "models"),
+		w.Path("app", naming.DirName),
 	}
 	for _, dir := range dirs {
+		if utils.DryRun {
+			break
+		}
 		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
-			cmd.PrintError(fmt.Sprintf("Failed to create directory %s: %v", dir, err))
-			return
+			return fmt.Errorf("failed to create directory %s: %w", dir, err)
 		}
 		if Verbose != nil && *Verbose {
 			cmd.PrintInfo(fmt.Sprintf("Created directory: %s", dir))
@@ -63,93 +195,344 @@ func generateBackendModule(cmd *mamba.Command, args []string) {
 
 	// Generate field structs and set module name
 	fieldStructs := utils.NewTemplateData(naming.Model, fields)
-	fieldStructs.ModuleName = getGoModuleName()
+	fieldStructs.ModuleName = utils.GetGoModuleNameIn(w.Dir)
+	naming.TableName = fieldStructs.TableName // Honours --table
+	warnUnknownIndexColumns(cmd, fieldStructs.Fields)
+	warnUnknownPayloadFields(cmd, fieldStructs.Fields)
+
+	if utils.UUIDKey() && (utils.HasImageField(fieldStructs.Fields) || fieldStructs.HasTranslatableFields) {
+		cmd.PrintWarning("Attachments and translations are stored against uint model ids; they need manual changes with --pk uuid")
+	}
+
+	if utils.Nested && utils.NestedParentFor(fieldStructs.Fields) == nil {
+		cmd.PrintWarning("--nested needs a belongsTo field; generating top-level routes only")
+	}
 
 	// Generate model
-	utils.GenerateFileFromTemplate(
-		filepath.Join("app", "models"),
+	w.GenerateFileFromTemplate(
+		w.Path("app", "models"),
 		naming.ModelSnake+".go",
 		"model.tmpl",
 		naming,
 		fieldStructs.Fields,
 	)
-	if Verbose != nil && *Verbose {
+	if Verbose != nil && *Verbose && !utils.DryRun {
 		cmd.PrintSuccess(fmt.Sprintf("Generated app/models/%s.go", naming.ModelSnake))
 	}
 
+	// Generate the create-table migration once; later schema changes get their own migrations
+	if existing := utils.FindMigration(w.Path(utils.MigrationsDir), "create_"+naming.TableName); existing == "" {
+		up, down := utils.CreateTableSQL(naming, fieldStructs.Fields)
+		upPath, err := w.WriteMigration(w.Path(utils.MigrationsDir), "create_"+naming.TableName, up, down)
+		if err != nil {
+			cmd.PrintWarning(fmt.Sprintf("Failed to write migration: %v", err))
+		} else if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess(fmt.Sprintf("Generated %s", upPath))
+		}
+	} else if Verbose != nil && *Verbose {
+		cmd.PrintInfo(fmt.Sprintf("Keeping existing migration %s", existing))
+	}
+
 	// Generate service
-	utils.GenerateFileFromTemplate(
-		filepath.Join("app", naming.DirName),
+	w.GenerateFileFromTemplate(
+		w.Path("app", naming.DirName),
 		"service.go",
 		"service.tmpl",
 		naming,
 		fieldStructs.Fields,
 	)
-	if Verbose != nil && *Verbose {
+	if Verbose != nil && *Verbose && !utils.DryRun {
 		cmd.PrintSuccess(fmt.Sprintf("Generated app/%s/service.go", naming.DirName))
 	}
 
 	// Generate controller
-	utils.GenerateFileFromTemplate(
-		filepath.Join("app", naming.DirName),
+	w.GenerateFileFromTemplate(
+		w.Path("app", naming.DirName),
 		"controller.go",
 		"controller.tmpl",
 		naming,
 		fieldStructs.Fields,
 	)
-	if Verbose != nil && *Verbose {
+	if Verbose != nil && *Verbose && !utils.DryRun {
 		cmd.PrintSuccess(fmt.Sprintf("Generated app/%s/controller.go", naming.DirName))
 	}
 
 	// Generate module
-	utils.GenerateFileFromTemplate(
-		filepath.Join("app", naming.DirName),
+	w.GenerateFileFromTemplate(
+		w.Path("app", naming.DirName),
 		"module.go",
 		"module.tmpl",
 		naming,
 		fieldStructs.Fields,
 	)
-	if Verbose != nil && *Verbose {
+	if Verbose != nil && *Verbose && !utils.DryRun {
 		cmd.PrintSuccess(fmt.Sprintf("Generated app/%s/module.go", naming.DirName))
 	}
 
 	// Generate validator
-	utils.GenerateFileFromTemplate(
-		filepath.Join("app", naming.DirName),
+	w.GenerateFileFromTemplate(
+		w.Path("app", naming.DirName),
 		"validator.go",
 		"validator.tmpl",
 		naming,
 		fieldStructs.Fields,
 	)
-	if Verbose != nil && *Verbose {
+	if Verbose != nil && *Verbose && !utils.DryRun {
 		cmd.PrintSuccess(fmt.Sprintf("Generated app/%s/validator.go", naming.DirName))
 	}
 
-	// Generate tests - disabled for now, will be added in future
-	// if err := utils.GenerateTests(naming, fieldStructs); err != nil {
-	// 	fmt.Printf("Error generating tests: %v\n", err)
-	// 	return
-	// }
+	// The validator checks rules= modifiers with the shared rules package
+	if utils.HasRules(fieldStructs.Fields) {
+		generateRules(cmd, w, naming, fieldStructs.Fields)
+	}
+
+	// Generate seed data
+	w.GenerateFileFromTemplate(
+		w.Path("app", naming.DirName),
+		"seed.go",
+		"seed.tmpl",
+		naming,
+		fieldStructs.Fields,
+	)
+	if Verbose != nil && *Verbose && !utils.DryRun {
+		cmd.PrintSuccess(fmt.Sprintf("Generated app/%s/seed.go", naming.DirName))
+	}
+
+	// Generate tests
+	if !NoTests {
+		w.GenerateFileFromTemplate(
+			w.Path("app", naming.DirName),
+			naming.PackageName+"_test.go",
+			"test.tmpl",
+			naming,
+			fieldStructs.Fields,
+		)
+		if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess(fmt.Sprintf("Generated app/%s/%s_test.go", naming.DirName, naming.PackageName))
+		}
+	}
+
+	// Generate CSV/XLSX export and import
+	if utils.ImportExport {
+		w.GenerateFileFromTemplate(
+			w.Path("app", naming.DirName),
+			"import_export.go",
+			"import_export.tmpl",
+			naming,
+			fieldStructs.Fields,
+		)
+		if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess(fmt.Sprintf("Generated app/%s/import_export.go", naming.DirName))
+		}
+	}
+
+	// Generate the PDF download
+	if utils.PDF {
+		w.GenerateFileFromTemplate(
+			w.Path("app", naming.DirName),
+			"pdf.go",
+			"pdf.tmpl",
+			naming,
+			fieldStructs.Fields,
+		)
+		if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess(fmt.Sprintf("Generated app/%s/pdf.go", naming.DirName))
+		}
+	}
+
+	// Generate the rate limit and response cache of the routes
+	if utils.RateLimit != "" || utils.Cache != "" {
+		generateThrottle(cmd, w, naming, fieldStructs.Fields)
+	}
+
+	// Generate bulk delete and status update
+	if utils.Bulk {
+		w.GenerateFileFromTemplate(
+			w.Path("app", naming.DirName),
+			"bulk.go",
+			"bulk.tmpl",
+			naming,
+			fieldStructs.Fields,
+		)
+		if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess(fmt.Sprintf("Generated app/%s/bulk.go", naming.DirName))
+		}
+	}
+
+	// Generate the joins of media[] fields
+	if utils.HasMediaList(fieldStructs.Fields) {
+		w.GenerateFileFromTemplate(
+			w.Path("app", naming.DirName),
+			"media.go",
+			"media.tmpl",
+			naming,
+			fieldStructs.Fields,
+		)
+		if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess(fmt.Sprintf("Generated app/%s/media.go", naming.DirName))
+		}
+	}
+
+	// Generate full-text search and its search_vector migration
+	if utils.Searchable != "" {
+		generateSearch(cmd, w, naming, fieldStructs.Fields)
+	}
+
+	// Generate the nearby searches of point fields and their geography migration
+	if len(utils.GeoPoints(fieldStructs.Fields)) > 0 {
+		generateGeo(cmd, w, naming, fieldStructs.Fields)
+	}
+
+	// Generate the transition checks and endpoint of state fields
+	if len(utils.StateFields(fieldStructs.Fields)) > 0 {
+		generateState(cmd, w, naming, fieldStructs.Fields)
+	}
+
+	// Generate the compare and swap save of lock_version
+	if utils.LockVersion {
+		w.GenerateFileFromTemplate(
+			w.Path("app", naming.DirName),
+			"lock.go",
+			"lock.tmpl",
+			naming,
+			fieldStructs.Fields,
+		)
+		if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess(fmt.Sprintf("Generated app/%s/lock.go", naming.DirName))
+		}
+	}
+
+	// The models encrypt their encrypted fields with the shared encryption package
+	if len(utils.EncryptedFields(fieldStructs.Fields)) > 0 {
+		scaffoldEncryption(cmd, w, naming)
+	}
+
+	// Generate the saving of hasMany rows edited in the parent's form
+	if utils.NestedForms != "" {
+		generateNestedForms(cmd, w, naming, fieldStructs.Fields)
+	}
+
+	// The services publish to the shared websocket hub
+	if utils.Realtime {
+		scaffoldRealtime(cmd, w, naming)
+	}
+
+	// The services record their changes in the shared audit log
+	if utils.Audited {
+		scaffoldAuditLog(cmd, w, naming)
+	}
 
-	// Check if goimports is installed
+	// The service keeps a revision on every update
+	if utils.Versioned {
+		generateVersioning(cmd, w, naming, fieldStructs.Fields)
+	}
+
+	// manyToMany fields with pivot columns get endpoints to edit the links
+	generatePivots(cmd, w, naming, fieldStructs.Fields)
+
+	// Generate GraphQL schema and resolvers alongside the REST controller
+	if utils.GraphQL {
+		generateGraphQL(cmd, w, naming, fieldStructs)
+	}
+
+	// Dry run: report the app/init.go change and skip formatting and go mod tidy
+	if utils.DryRun {
+		if err := addModuleToAppInit(w, naming.DirName); err != nil {
+			cmd.PrintWarning(fmt.Sprintf("Could not add module to app/init.go: %v", err))
+		}
+		return nil
+	}
+
+	// Check if goimports is installed; without it the files are still registered and recorded
+	goimports := true
 	if _, err := exec.LookPath("goimports"); err != nil {
 		if Verbose != nil && *Verbose {
 			cmd.PrintInfo("Installing goimports...")
 		}
 		if err := exec.Command("go", "install", "golang.org/x/tools/cmd/goimports@latest").Run(); err != nil {
-			cmd.PrintWarning("Failed to install goimports")
+			cmd.PrintWarning("Failed to install goimports, the generated files were not formatted")
 			if Verbose != nil && *Verbose {
 				cmd.PrintInfo("Install manually: go install golang.org/x/tools/cmd/goimports@latest")
 			}
-			return
+			goimports = false
+		} else if Verbose != nil && *Verbose {
+			cmd.PrintSuccess("goimports installed")
 		}
+	}
+	if goimports {
+		formatGeneratedFiles(cmd, w, naming)
+	}
+
+	// Add module to app/init.go
+	if err := addModuleToAppInit(w, naming.DirName); err != nil {
+		cmd.PrintWarning("Could not add module to app/init.go")
+		cmd.PrintInfo(fmt.Sprintf("Manually add to app/init.go: modules[\"%s\"] = %s.Init(deps)", naming.DirName, utils.InitPackage(naming.DirName)))
+	} else {
 		if Verbose != nil && *Verbose {
-			cmd.PrintSuccess("goimports installed")
+			cmd.PrintSuccess("Added module to app/init.go")
+		}
+
+		// Format init.go after modification
+		initGoPath := w.Path("app", "init.go")
+		if err := exec.Command("gofmt", "-w", initGoPath).Run(); err != nil {
+			if Verbose != nil && *Verbose {
+				cmd.PrintWarning("Failed to format app/init.go")
+			}
+		}
+	}
+
+	// Run go mod tidy to ensure dependencies are up to date
+	if Verbose != nil && *Verbose {
+		cmd.PrintInfo("Running go mod tidy...")
+	}
+	tidy := exec.Command("go", "mod", "tidy")
+	tidy.Dir = w.Dir
+	if err := tidy.Run(); err != nil {
+		if Verbose != nil && *Verbose {
+			cmd.PrintWarning("Failed to run go mod tidy")
 		}
 	}
+	return nil
+}
+
+// FinishModule completes the backend module GenerateModule generated from args: the related
+// modules get the hasMany side of its belongsTo fields, its README.md is written and the
+// generation recorded. bui g runs it once both modules are generated, since the inverse
+// relations alter other modules.
+func FinishModule(cmd *mamba.Command, w *utils.Writer, args []string) {
+	naming := newNaming(args[0])
 
+	// The related models get the hasMany side of the belongsTo fields
+	addInverseRelations(cmd, w, naming, args[1:])
+
+	// Document the module as generated in its README.md
+	writeModuleReadme(cmd, w, naming, utils.GenerationCommand(cmd, args))
+
+	if utils.Alter {
+		recordGeneration(cmd, w, args, "Backend module "+naming.Model)
+		return
+	}
+
+	printWriteSummary(cmd, w)
+	if utils.DryRun {
+		cmd.PrintInfo(fmt.Sprintf("Dry run: backend module %s was not written", naming.Model))
+		return
+	}
+
+	recordGeneration(cmd, w, args, "Backend module "+naming.Model)
+
+	if Verbose == nil || !*Verbose {
+		cmd.PrintSuccess(fmt.Sprintf("Generated backend module: %s", naming.Model))
+	}
+	if utils.GraphQL {
+		printGraphQLNextSteps(cmd)
+	}
+}
+
+// formatGeneratedFiles runs goimports and gofmt on the module directory and model file
+func formatGeneratedFiles(cmd *mamba.Command, w *utils.Writer, naming *utils.NamingConvention) {
 	// Run goimports on generated files
-	generatedPath := filepath.Join("app", naming.DirName)
+	generatedPath := w.Path("app", naming.DirName)
 
 	if Verbose != nil && *Verbose {
 		cmd.PrintInfo("Formatting generated files...")
@@ -163,7 +546,7 @@ func generateBackendModule(cmd *mamba.Command, args []string) {
 	}
 
 	// Run goimports on the model file
-	modelPath := filepath.Join("app", "models", naming.ModelSnake+".go")
+	modelPath := w.Path("app", "models", naming.ModelSnake+".go")
 	if err := exec.Command("goimports", "-w", modelPath).Run(); err != nil {
 		if Verbose != nil && *Verbose {
 			cmd.PrintWarning(fmt.Sprintf("Failed to run goimports on %s", modelPath))
@@ -181,56 +564,82 @@ func generateBackendModule(cmd *mamba.Command, args []string) {
 			cmd.PrintWarning(fmt.Sprintf("Failed to format %s", modelPath))
 		}
 	}
+}
 
-	// Add module to app/init.go
-	if err := addModuleToAppInit(naming.DirName); err != nil {
-		cmd.PrintWarning("Could not add module to app/init.go")
-		cmd.PrintInfo(fmt.Sprintf("Manually add to app/init.go: modules[\"%s\"] = %s.Init(deps)", naming.DirName, naming.DirName))
-	} else {
-		if Verbose != nil && *Verbose {
-			cmd.PrintSuccess("Added module to app/init.go")
+// printWriteSummary reports which generated files w wrote and which existing files it kept
+func printWriteSummary(cmd *mamba.Command, w *utils.Writer) {
+	if backupDir := w.OverwriteBackupDir(); backupDir != "" {
+		cmd.PrintInfo("Previous versions of overwritten files saved to " + backupDir)
+		if w.Run.Module != "" {
+			cmd.PrintInfo(fmt.Sprintf("Put them back with: bui restore %s --reason overwrite", utils.BackupName(w.Run.Module)))
 		}
+	}
 
-		// Format init.go after modification
-		initGoPath := filepath.Join("app", "init.go")
-		if err := exec.Command("gofmt", "-w", initGoPath).Run(); err != nil {
-			if Verbose != nil && *Verbose {
-				cmd.PrintWarning("Failed to format app/init.go")
-			}
+	merged, conflicted := w.MergedFiles()
+	if len(merged) > 0 {
+		cmd.PrintInfo(fmt.Sprintf("Merged the edits made since generation into %d files", len(merged)))
+	}
+	if len(conflicted) > 0 {
+		cmd.PrintWarning("Resolve the conflict markers where the edits and the templates changed the same lines:")
+		for _, path := range conflicted {
+			cmd.PrintBullet(path)
 		}
 	}
 
-	// Run go mod tidy to ensure dependencies are up to date
-	if Verbose != nil && *Verbose {
-		cmd.PrintInfo("Running go mod tidy...")
+	written, skipped := w.GeneratedFiles()
+	if len(skipped) == 0 {
+		return
 	}
-	if err := exec.Command("go", "mod", "tidy").Run(); err != nil {
-		if Verbose != nil && *Verbose {
-			cmd.PrintWarning("Failed to run go mod tidy")
-		}
+
+	cmd.PrintWarning(fmt.Sprintf("Wrote %d files, kept %d existing files:", len(written), len(skipped)))
+	for _, path := range skipped {
+		cmd.PrintBullet(path)
 	}
+	cmd.PrintInfo("Re-run with --force to overwrite them")
+}
 
-	if Verbose == nil || !*Verbose {
-		cmd.PrintSuccess(fmt.Sprintf("Generated backend module: %s", naming.Model))
+// warnUnknownIndexColumns reports --unique columns that are not columns of the generated model
+func warnUnknownIndexColumns(cmd *mamba.Command, fields []utils.Field) {
+	for _, column := range utils.UnknownIndexColumns(fields) {
+		cmd.PrintWarning(fmt.Sprintf("--unique column %s is not a field of this model; it was left out of the index", column))
+	}
+}
+
+// warnUnknownPayloadFields reports --fields-read and --fields-write columns that are not fields
+// of the generated model
+func warnUnknownPayloadFields(cmd *mamba.Command, fields []utils.Field) {
+	for _, column := range utils.UnknownPayloadFields(fields) {
+		cmd.PrintWarning(fmt.Sprintf("--fields-read/--fields-write column %s is not a field of this model; it was ignored", column))
+	}
+}
+
+// RegisterModule adds an existing module to app/init.go of the backend in backendDir and formats
+// it. The module is registered even when gofmt fails, which is only a warning.
+func RegisterModule(cmd *mamba.Command, backendDir, moduleName string) error {
+	startDir, _ := os.Getwd()
+	w := utils.NewRun(utils.ProjectRoot(startDir), moduleName).Writer(backendDir)
+	if err := addModuleToAppInit(w, moduleName); err != nil {
+		return err
+	}
+	initGoPath := w.Path("app", "init.go")
+	if err := exec.Command("gofmt", "-w", initGoPath).Run(); err != nil {
+		cmd.PrintWarning(fmt.Sprintf("Failed to format %s", initGoPath))
 	}
+	return nil
 }
 
-// addModuleToAppInit adds the module to app/init.go
-func addModuleToAppInit(moduleName string) error {
-	initGoPath := filepath.Join("app", "init.go")
-	goModuleName := getGoModuleName()
+// addModuleToAppInit adds the module to app/init.go of the backend w writes to
+func addModuleToAppInit(w *utils.Writer, moduleName string) error {
+	initGoPath := w.Path("app", "init.go")
+	goModuleName := utils.GetGoModuleNameIn(w.Dir)
 
 	// Check if app/init.go exists
 	if _, err := os.Stat(initGoPath); os.IsNotExist(err) {
 		// Create app/init.go if it doesn't exist
-		if err := os.MkdirAll("app", os.ModePerm); err != nil {
-			return fmt.Errorf("failed to create app directory: %w", err)
-		}
-
 		content := fmt.Sprintf(`package app
 
 import (
-	"%s/app/%s"
+	%s
 	"%s/core/module"
 )
 
@@ -252,9 +661,9 @@ func (am *AppModules) GetAppModules(deps module.Dependencies) map[string]module.
 func NewAppModules() *AppModules {
 	return &AppModules{}
 }
-`, goModuleName, moduleName, goModuleName, moduleName, moduleName)
+`, utils.InitImport(goModuleName, moduleName), goModuleName, moduleName, utils.InitPackage(moduleName))
 
-		if err := os.WriteFile(initGoPath, []byte(content), 0644); err != nil {
+		if err := w.UpdateProjectFile(initGoPath, []byte(content)); err != nil {
 			return fmt.Errorf("failed to create app/init.go: %w", err)
 		}
 		return nil
@@ -269,14 +678,13 @@ func NewAppModules() *AppModules {
 	contentStr := string(content)
 
 	// Check if module already exists
-	moduleInit := fmt.Sprintf("modules[\"%s\"] = %s.Init(deps)", moduleName, moduleName)
+	moduleInit := fmt.Sprintf("modules[\"%s\"] = %s.Init(deps)", moduleName, utils.InitPackage(moduleName))
 	if strings.Contains(contentStr, moduleInit) {
 		return nil // Already added
 	}
 
 	// Add import if not exists using the proper AddImport function
-	importLine := fmt.Sprintf("\"%s/app/%s\"", goModuleName, moduleName)
-	contentBytes, importAdded := utils.AddImport([]byte(contentStr), importLine)
+	contentBytes, importAdded := utils.AddImport([]byte(contentStr), utils.InitImport(goModuleName, moduleName))
 	if importAdded {
 		contentStr = string(contentBytes)
 	}
@@ -295,34 +703,19 @@ func NewAppModules() *AppModules {
 	contentStr = contentStr[:insertPoint] + moduleInitLine + contentStr[insertPoint:]
 
 	// Write back to file
-	if err := os.WriteFile(initGoPath, []byte(contentStr), 0644); err != nil {
+	if err := w.UpdateProjectFile(initGoPath, []byte(contentStr)); err != nil {
 		return fmt.Errorf("failed to write app/init.go: %w", err)
 	}
 
 	return nil
 }
 
-// getGoModuleName reads the module name from go.mod
-func getGoModuleName() string {
-	content, err := os.ReadFile("go.mod")
-	if err != nil {
-		return "base" // fallback to default
+// DetectBackendDir finds the backend directory in the current working directory
+func DetectBackendDir() string {
+	if dir := utils.Project.BackendDir(); dir != "" {
+		return dir
 	}
 
-	// Parse first line: "module <name>"
-	lines := strings.Split(string(content), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "module ") {
-			return strings.TrimSpace(strings.TrimPrefix(line, "module"))
-		}
-	}
-
-	return "base" // fallback to default
-}
-
-// detectBackendDir finds the backend directory in the current working directory
-func detectBackendDir() string {
 	// Check if we're already in a backend directory
 	if _, err := os.Stat("main.go"); err == nil {
 		if _, err := os.Stat(filepath.Join("app", "models")); err == nil {
@@ -0,0 +1,82 @@
+package backend
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// newTestBackend creates a backend with a go.mod and app/init.go in a temporary directory and
+// makes it the working directory
+func newTestBackend(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	verbose := false
+	Verbose = &verbose
+
+	files := map[string]string{
+		"go.mod":      "module example.com/api\n\ngo 1.22\n",
+		"app/init.go": "package app\n\nfunc initModules() {\n\tmodules := map[string]any{}\n\t_ = modules\n}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// generateTestModule checks and generates a module with a name field, without the formatting
+// and registration FinishModule adds, reporting whether the checks let it through
+func generateTestModule(t *testing.T, dir, name string) bool {
+	t.Helper()
+	cmd := &mamba.Command{Use: "backend"}
+	w := utils.NewRun(dir, name).Writer(".")
+	args := []string{name, "name:string"}
+	if !CheckModule(cmd, w, args) {
+		return false
+	}
+	if err := GenerateModule(cmd, w, args); err != nil {
+		t.Fatalf("generating %s: %v", name, err)
+	}
+	return true
+}
+
+func TestNamespacedModuleDoesNotReplaceModuleOfSameName(t *testing.T) {
+	tests := []struct {
+		first, second, firstDir string
+	}{
+		{"product", "shop/product", "app/products"},
+		{"shop/product", "product", "app/shop/products"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.first+" then "+tt.second, func(t *testing.T) {
+			dir := newTestBackend(t)
+			if !generateTestModule(t, dir, tt.first) {
+				t.Fatalf("generating %s was refused", tt.first)
+			}
+			model, err := os.ReadFile(filepath.Join(dir, "app", "models", "product.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if generateTestModule(t, dir, tt.second) {
+				t.Fatalf("generating %s next to %s wasn't refused", tt.second, tt.first)
+			}
+			if after, _ := os.ReadFile(filepath.Join(dir, "app", "models", "product.go")); string(after) != string(model) {
+				t.Errorf("generating %s changed app/models/product.go", tt.second)
+			}
+			if dirs := utils.FindModuleDirs(dir, "module.go"); len(dirs) != 1 || "app/"+dirs[0] != tt.firstDir {
+				t.Errorf("expected only the %s module, found %q", tt.firstDir, dirs)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package backend
+
+import (
+	"fmt"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// generateGeo writes the module's nearby searches over its point fields and, with --postgis and
+// once per table, the migration that adds their geography columns
+func generateGeo(cmd *mamba.Command, w *utils.Writer, naming *utils.NamingConvention, fields []utils.Field) {
+	w.GenerateFileFromTemplate(w.Path("app", naming.DirName), "geo.go", "geo.tmpl", naming, fields)
+	if Verbose != nil && *Verbose && !utils.DryRun {
+		cmd.PrintSuccess(fmt.Sprintf("Generated app/%s/geo.go", naming.DirName))
+	}
+	if !utils.PostGIS {
+		return
+	}
+	if database := utils.Database(); database != utils.Postgres {
+		cmd.PrintWarning(fmt.Sprintf("--postgis needs PostgreSQL; nearby searches on %s measure the distance in Go", utils.DatabaseLabel(database)))
+		return
+	}
+
+	name := "add_" + naming.TableName + "_geography"
+	if existing := utils.FindMigration(w.Path(utils.MigrationsDir), name); existing != "" {
+		if Verbose != nil && *Verbose {
+			cmd.PrintInfo(fmt.Sprintf("Keeping existing migration %s", existing))
+		}
+		return
+	}
+	up, down := utils.PointMigrationSQL(naming.TableName, utils.GeoPoints(fields))
+	upPath, err := w.WriteMigration(w.Path(utils.MigrationsDir), name, up, down)
+	if err != nil {
+		cmd.PrintWarning(fmt.Sprintf("Failed to write the geography migration: %v", err))
+	} else if Verbose != nil && *Verbose && !utils.DryRun {
+		cmd.PrintSuccess(fmt.Sprintf("Generated %s", upPath))
+	}
+}
@@ -0,0 +1,703 @@
+package backend
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+	"gopkg.in/yaml.v3"
+)
+
+// gqlgenConfigFile is where gqlgen looks for its configuration
+const gqlgenConfigFile = "gqlgen.yml"
+
+// gqlgenConfig is the part of gqlgen.yml that decides where module schema and resolvers go
+type gqlgenConfig struct {
+	Schema any `yaml:"schema"` // A single path or a list of paths and globs
+	Model  struct {
+		Filename string `yaml:"filename"`
+		Package  string `yaml:"package"`
+	} `yaml:"model"`
+	Resolver struct {
+		Layout           string `yaml:"layout"`
+		Dir              string `yaml:"dir"`
+		Package          string `yaml:"package"`
+		Filename         string `yaml:"filename"`
+		FilenameTemplate string `yaml:"filename_template"`
+	} `yaml:"resolver"`
+}
+
+// graphqlModuleData is passed to the module schema and resolver templates
+type graphqlModuleData struct {
+	*utils.NamingConvention
+	ModuleName            string
+	Package               string // Resolver package
+	ModelImport           string // Package gqlgen generates models_gen.go into
+	ModelPackage          string
+	Query                 string // Single item query, e.g. product
+	ListQuery             string // Paginated list query, e.g. products
+	Fields                []utils.GraphQLField
+	Skipped               []string
+	Declarations          []string // Shared scalars and types the project's schema doesn't declare yet
+	DeclareQuery          bool     // The schema has no Query type to extend yet
+	DeclareMutation       bool
+	HasTranslatableFields bool
+	UUIDKey               bool
+}
+
+var graphqlTemplateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"goArg": utils.GraphQLGoArg,
+	"goArgType": func(field utils.GraphQLField) string {
+		if field.Type == "Int" {
+			return "*int"
+		}
+		return "*string"
+	},
+}
+
+var graphqlSchemaTemplate = template.Must(template.New("schema").Funcs(graphqlTemplateFuncs).Parse(`# {{.Model}} types generated by bui g backend --graphql
+{{- if .Skipped}}
+# Served by the REST API only: {{join .Skipped ", "}}
+{{- end}}
+{{- range .Declarations}}
+
+{{.}}
+{{- end}}
+
+type {{.Model}} {
+  id: ID!
+{{- range .Fields}}{{if not .HideInDetail}}
+  {{.Name}}: {{.Type}}{{if .Required}}!{{end}}
+{{- end}}{{end}}
+  created_at: Time!
+  updated_at: Time!
+}
+
+type {{.Model}}ListResponse {
+  id: ID!
+{{- range .Fields}}{{if not (or .ForeignKey .HideInList)}}
+  {{.Name}}: {{.Type}}{{if .Required}}!{{end}}
+{{- end}}{{end}}
+  created_at: Time!
+  updated_at: Time!
+}
+
+type {{.Model}}Page {
+  data: [{{.Model}}ListResponse!]!
+  pagination: Pagination!
+}
+
+input Create{{.Model}}Request {
+{{- range .Fields}}{{if not .NoCreate}}
+  {{.Name}}: {{.Type}}{{if .Required}}!{{end}}
+{{- end}}{{end}}
+}
+
+input Update{{.Model}}Request {
+{{- range .Fields}}{{if not .NoUpdate}}
+  {{.Name}}: {{.Type}}
+{{- end}}{{end}}
+}
+
+{{if not .DeclareQuery}}extend {{end}}type Query {
+  {{.Query}}(id: ID!): {{.Model}}
+  {{.ListQuery}}(page: Int, limit: Int, sortBy: String, sortOrder: String{{range .Fields}}{{if .Filter}}, {{.Name}}: {{.Type}}{{end}}{{end}}): {{.Model}}Page!
+}
+
+{{if not .DeclareMutation}}extend {{end}}type Mutation {
+  create{{.Model}}(input: Create{{.Model}}Request!): {{.Model}}!
+  update{{.Model}}(id: ID!, input: Update{{.Model}}Request!): {{.Model}}!
+  delete{{.Model}}(id: ID!): Boolean!
+}
+`))
+
+// graphqlResolversTemplate holds only resolver methods, since gqlgen comments out anything else in resolver files
+var graphqlResolversTemplate = template.Must(template.New("resolvers").Funcs(graphqlTemplateFuncs).Parse(`package {{.Package}}
+
+import (
+	"context"
+
+	"{{.ModuleName}}/app/models"
+	"{{.ModelImport}}"
+)
+
+// {{.Model}} is the resolver for the {{.Query}} field.
+func (r *queryResolver) {{.Model}}(ctx context.Context, id string) (*models.{{.Model}}, error) {
+	{{.VarSingle}}ID, err := parse{{.Model}}ID(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.{{.VarSingle}}Service().GetById({{.VarSingle}}ID)
+}
+
+// {{.Plural}} is the resolver for the {{.ListQuery}} field.
+func (r *queryResolver) {{.Plural}}(ctx context.Context, page *int, limit *int, sortBy *string, sortOrder *string{{range .Fields}}{{if .Filter}}, {{goArg .Name}} {{goArgType .}}{{end}}{{end}}) (*{{.ModelPackage}}.{{.Model}}Page, error) {
+	filters := map[string]interface{}{}
+	{{- range .Fields}}{{if .Filter}}
+	if {{goArg .Name}} != nil {
+		filters["{{.Name}}"] = *{{goArg .Name}}
+	}
+	{{- end}}{{end}}
+
+	result, err := r.{{.VarSingle}}Service().GetAll(page, limit, sortBy, sortOrder, filters)
+	if err != nil {
+		return nil, err
+	}
+	items, _ := result.Data.([]*models.{{.Model}}ListResponse)
+	return &{{.ModelPackage}}.{{.Model}}Page{Data: items, Pagination: &result.Pagination}, nil
+}
+
+// Create{{.Model}} is the resolver for the create{{.Model}} field.
+func (r *mutationResolver) Create{{.Model}}(ctx context.Context, input models.Create{{.Model}}Request) (*models.{{.Model}}, error) {
+	return r.{{.VarSingle}}Service().Create(&input)
+}
+
+// Update{{.Model}} is the resolver for the update{{.Model}} field.
+func (r *mutationResolver) Update{{.Model}}(ctx context.Context, id string, input models.Update{{.Model}}Request) (*models.{{.Model}}, error) {
+	{{.VarSingle}}ID, err := parse{{.Model}}ID(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.{{.VarSingle}}Service().Update({{.VarSingle}}ID, &input)
+}
+
+// Delete{{.Model}} is the resolver for the delete{{.Model}} field.
+func (r *mutationResolver) Delete{{.Model}}(ctx context.Context, id string) (bool, error) {
+	{{.VarSingle}}ID, err := parse{{.Model}}ID(id)
+	if err != nil {
+		return false, err
+	}
+	if err := r.{{.VarSingle}}Service().Delete({{.VarSingle}}ID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+`))
+
+// graphqlHelpersTemplate builds the module's service for its resolvers
+var graphqlHelpersTemplate = template.Must(template.New("helpers").Parse(`package {{.Package}}
+
+import (
+	"fmt"
+	{{- if not .UUIDKey}}
+	"strconv"
+	{{- end}}
+
+	"{{.ModuleName}}/app/{{.DirName}}"
+	{{- if .HasTranslatableFields}}
+	"{{.ModuleName}}/core/translation"
+	{{- end}}
+	{{- if .UUIDKey}}
+
+	"github.com/google/uuid"
+	{{- end}}
+)
+
+// {{.VarSingle}}Service builds the {{.Model}} service from the resolver's dependencies
+func (r *Resolver) {{.VarSingle}}Service() *{{.PackageName}}.{{.Service}} {
+	{{- if .HasTranslatableFields}}
+	translationService := translation.NewTranslationService(r.Deps.DB, r.Deps.Emitter, r.Deps.Storage, r.Deps.Logger)
+	return {{.PackageName}}.New{{.Service}}(r.Deps.DB, r.Deps.Emitter, r.Deps.Storage, r.Deps.Logger, translation.NewHelper(translationService))
+	{{- else}}
+	return {{.PackageName}}.New{{.Service}}(r.Deps.DB, r.Deps.Emitter, r.Deps.Storage, r.Deps.Logger)
+	{{- end}}
+}
+
+// parse{{.Model}}ID converts a GraphQL ID argument to a {{.ModelLower}} primary key
+{{- if .UUIDKey}}
+func parse{{.Model}}ID(id string) (uuid.UUID, error) {
+	value, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid {{.ModelLower}} id %q", id)
+	}
+	return value, nil
+}
+{{- else}}
+func parse{{.Model}}ID(id string) (uint, error) {
+	value, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid {{.ModelLower}} id %q", id)
+	}
+	return uint(value), nil
+}
+{{- end}}
+`))
+
+// gqlgenScaffold is written on the first --graphql module of a project that has no gqlgen.yml
+var gqlgenScaffold = map[string]*template.Template{
+	gqlgenConfigFile: template.Must(template.New(gqlgenConfigFile).Parse(`# gqlgen configuration written by bui g backend --graphql.
+# Regenerate after adding modules: go run github.com/99designs/gqlgen generate
+schema:
+  - graph/*.graphqls
+
+exec:
+  filename: graph/generated.go
+  package: graph
+
+model:
+  filename: graph/model/models_gen.go
+  package: model
+
+resolver:
+  layout: follow-schema
+  dir: graph
+  package: graph
+  filename_template: "{name}.resolvers.go"
+
+# Fields are matched by their json tags, so GraphQL names follow the REST API
+struct_tag: json
+
+autobind:
+  - "{{.ModuleName}}/app/models"
+  - "{{.ModuleName}}/core/types"
+
+models:
+  ID:
+    model:
+      - github.com/99designs/gqlgen/graphql.ID
+      - github.com/99designs/gqlgen/graphql.UintID
+      {{- if .UUIDKey}}
+      - github.com/99designs/gqlgen/graphql.UUID
+      {{- end}}
+  Int:
+    model:
+      - github.com/99designs/gqlgen/graphql.Int
+      - github.com/99designs/gqlgen/graphql.Int64
+      - github.com/99designs/gqlgen/graphql.Uint
+  DateTime:
+    model:
+      - {{.ModuleName}}/graph.DateTime
+`)),
+	filepath.Join("graph", "schema.graphqls"): template.Must(template.New("schema.graphqls").Parse(`# Shared types; each module adds its own graph/<model>.graphqls
+
+scalar Time
+
+scalar DateTime
+
+type Pagination {
+  total: Int!
+  page: Int!
+  page_size: Int!
+  total_pages: Int!
+}
+
+type Query {
+  ping: String!
+}
+
+type Mutation {
+  ping: String!
+}
+`)),
+	filepath.Join("graph", "resolver.go"): template.Must(template.New("resolver.go").Parse(`package graph
+
+import "{{.ModuleName}}/core/module"
+
+// Resolver is the root GraphQL resolver; module resolvers build their services from Deps.
+// Serve it with handler.NewDefaultServer(NewExecutableSchema(Config{Resolvers: &Resolver{Deps: deps}})).
+type Resolver struct {
+	Deps module.Dependencies
+}
+`)),
+	filepath.Join("graph", "scalars.go"): template.Must(template.New("scalars.go").Parse(`package graph
+
+import (
+	"encoding/json"
+	"io"
+
+	"{{.ModuleName}}/core/types"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// DateTime is the GraphQL scalar for date and datetime fields
+type DateTime = types.DateTime
+
+// MarshalDateTime writes a DateTime in the same format as the REST API
+func MarshalDateTime(t types.DateTime) graphql.Marshaler {
+	return graphql.WriterFunc(func(w io.Writer) {
+		data, err := json.Marshal(t)
+		if err != nil {
+			data = []byte("null")
+		}
+		w.Write(data)
+	})
+}
+
+// UnmarshalDateTime reads a DateTime from any value the REST API accepts
+func UnmarshalDateTime(v interface{}) (types.DateTime, error) {
+	var t types.DateTime
+	data, err := json.Marshal(v)
+	if err != nil {
+		return t, err
+	}
+	err = json.Unmarshal(data, &t)
+	return t, err
+}
+`)),
+	filepath.Join("graph", "schema.resolvers.go"): template.Must(template.New("schema.resolvers.go").Parse(`package graph
+
+import "context"
+
+// Ping is the resolver for the ping field.
+func (r *mutationResolver) Ping(ctx context.Context) (string, error) {
+	return "pong", nil
+}
+
+// Ping is the resolver for the ping field.
+func (r *queryResolver) Ping(ctx context.Context) (string, error) {
+	return "pong", nil
+}
+
+// Mutation returns MutationResolver implementation.
+func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+type mutationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
+`)),
+}
+
+// generateGraphQL writes the module's gqlgen schema and resolvers, scaffolding gqlgen on first use.
+// The paths of gqlgen.yml are relative to the backend directory of w.
+func generateGraphQL(cmd *mamba.Command, w *utils.Writer, naming *utils.NamingConvention, fieldStructs *utils.TemplateData) {
+	fields, skipped := utils.GraphQLFields(fieldStructs.Fields)
+	if len(fields) == 0 {
+		cmd.PrintWarning(fmt.Sprintf("%s has no fields GraphQL can expose; skipping --graphql", naming.Model))
+		return
+	}
+
+	_, err := os.Stat(w.Path(gqlgenConfigFile))
+	scaffolded := os.IsNotExist(err)
+	if scaffolded {
+		if err := scaffoldGqlgen(w, fieldStructs.ModuleName); err != nil {
+			cmd.PrintError(fmt.Sprintf("Failed to set up gqlgen: %v", err))
+			return
+		}
+	}
+
+	config, err := readGqlgenConfig(w.Dir)
+	if err != nil {
+		cmd.PrintError(err.Error())
+		return
+	}
+
+	data := &graphqlModuleData{
+		NamingConvention:      naming,
+		ModuleName:            fieldStructs.ModuleName,
+		Package:               config.Resolver.Package,
+		ModelImport:           fieldStructs.ModuleName + "/" + filepath.ToSlash(filepath.Dir(config.Model.Filename)),
+		ModelPackage:          config.Model.Package,
+		Query:                 naming.VarSingle,
+		ListQuery:             naming.VarPlural,
+		Fields:                fields,
+		Skipped:               skipped,
+		HasTranslatableFields: fieldStructs.HasTranslatableFields,
+		UUIDKey:               utils.UUIDKey(),
+	}
+
+	schemaPath, appendSchema := config.moduleSchemaPath(naming)
+	existingSchema := config.schemaSource(w.Dir)
+	if appendSchema && strings.Contains(existingSchema, "type "+naming.Model+" {") {
+		cmd.PrintWarning(fmt.Sprintf("%s already declares type %s; leaving the schema as it is", schemaPath, naming.Model))
+	} else {
+		if !scaffolded {
+			data.Declarations = missingGraphQLDeclarations(existingSchema, fields)
+			data.DeclareQuery = !regexp.MustCompile(`(?m)^\s*type Query\b`).MatchString(existingSchema)
+			data.DeclareMutation = !regexp.MustCompile(`(?m)^\s*type Mutation\b`).MatchString(existingSchema)
+			for _, declaration := range data.Declarations {
+				if declaration == "scalar DateTime" {
+					cmd.PrintWarning("Map the DateTime scalar to " + fieldStructs.ModuleName + "/core/types.DateTime under models: in " + gqlgenConfigFile)
+				}
+			}
+		}
+		schema, err := renderGraphQL(graphqlSchemaTemplate, data)
+		if err != nil {
+			cmd.PrintError(err.Error())
+			return
+		}
+		if appendSchema {
+			current, _ := os.ReadFile(w.Path(schemaPath))
+			err = w.UpdateProjectFile(w.Path(schemaPath), []byte(strings.TrimRight(string(current), "\n")+"\n\n"+string(schema)))
+		} else {
+			err = w.WriteGeneratedFile(w.Path(schemaPath), schema)
+		}
+		if err != nil {
+			cmd.PrintError(fmt.Sprintf("Failed to write %s: %v", schemaPath, err))
+			return
+		}
+	}
+
+	resolvers, err := renderGraphQL(graphqlResolversTemplate, data)
+	if err != nil {
+		cmd.PrintError(err.Error())
+		return
+	}
+	resolverPath := config.resolverPath(schemaPath)
+	if current, err := os.ReadFile(w.Path(resolverPath)); err == nil && !strings.HasSuffix(resolverPath, naming.ModelSnake+".resolvers.go") {
+		// A shared resolver file: add the methods alongside the existing ones
+		if strings.Contains(string(current), "func (r *queryResolver) "+naming.Model+"(") {
+			cmd.PrintWarning(fmt.Sprintf("%s already has %s resolvers; leaving it as it is", resolverPath, naming.Model))
+		} else if merged, err := appendGoDecls(current, resolvers); err != nil {
+			cmd.PrintError(fmt.Sprintf("Failed to add resolvers to %s: %v", resolverPath, err))
+		} else if err := w.UpdateProjectFile(w.Path(resolverPath), merged); err != nil {
+			cmd.PrintError(err.Error())
+		}
+	} else if err := w.WriteGeneratedFile(w.Path(resolverPath), resolvers); err != nil {
+		cmd.PrintError(err.Error())
+	}
+
+	helpers, err := renderGraphQL(graphqlHelpersTemplate, data)
+	if err != nil {
+		cmd.PrintError(err.Error())
+		return
+	}
+	if err := w.WriteGeneratedFile(w.Path(config.Resolver.Dir, naming.ModelSnake+".go"), helpers); err != nil {
+		cmd.PrintError(err.Error())
+	}
+
+	if Verbose != nil && *Verbose && !utils.DryRun {
+		cmd.PrintSuccess(fmt.Sprintf("Generated GraphQL schema %s and resolvers %s", schemaPath, resolverPath))
+	}
+	if len(skipped) > 0 {
+		cmd.PrintInfo("Not exposed in GraphQL (REST only): " + strings.Join(skipped, ", "))
+	}
+}
+
+// printGraphQLNextSteps explains how to generate and serve the schema
+func printGraphQLNextSteps(cmd *mamba.Command) {
+	cmd.PrintInfo("GraphQL next steps:")
+	cmd.PrintBullet("go get github.com/99designs/gqlgen && go run github.com/99designs/gqlgen generate")
+	cmd.PrintBullet("Serve handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{Deps: deps}})) at /graphql, behind the same auth as /api")
+}
+
+// scaffoldGqlgen writes gqlgen.yml and the shared graph package to the backend of w
+func scaffoldGqlgen(w *utils.Writer, moduleName string) error {
+	data := struct {
+		ModuleName string
+		UUIDKey    bool
+	}{moduleName, utils.UUIDKey()}
+
+	paths := make([]string, 0, len(gqlgenScaffold))
+	for path := range gqlgenScaffold {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		if _, err := os.Stat(w.Path(path)); err == nil {
+			continue
+		}
+		var content bytes.Buffer
+		if err := gqlgenScaffold[path].Execute(&content, data); err != nil {
+			return err
+		}
+		if err := w.WriteGeneratedFile(w.Path(path), content.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readGqlgenConfig loads the gqlgen.yml of the backend in dir with gqlgen's defaults filled in
+func readGqlgenConfig(dir string) (*gqlgenConfig, error) {
+	config := &gqlgenConfig{}
+	content, err := os.ReadFile(filepath.Join(dir, gqlgenConfigFile))
+	if err == nil {
+		if err := yaml.Unmarshal(content, config); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", gqlgenConfigFile, err)
+		}
+	} else if !utils.DryRun {
+		return nil, fmt.Errorf("failed to read %s: %w", gqlgenConfigFile, err)
+	} else {
+		// Dry run on a fresh project: the scaffold was reported, not written
+		var scaffold bytes.Buffer
+		gqlgenScaffold[gqlgenConfigFile].Execute(&scaffold, struct {
+			ModuleName string
+			UUIDKey    bool
+		}{})
+		yaml.Unmarshal(scaffold.Bytes(), config)
+	}
+
+	if config.Schema == nil {
+		config.Schema = "schema.graphql"
+	}
+	if config.Model.Filename == "" {
+		config.Model.Filename = "models_gen.go"
+	}
+	if config.Model.Package == "" {
+		config.Model.Package = filepath.Base(filepath.Dir(config.Model.Filename))
+	}
+	if config.Resolver.Dir == "" {
+		config.Resolver.Dir = filepath.Dir(config.Resolver.Filename)
+	}
+	if config.Resolver.Package == "" {
+		config.Resolver.Package = filepath.Base(config.Resolver.Dir)
+	}
+	if config.Resolver.Filename == "" {
+		config.Resolver.Filename = filepath.Join(config.Resolver.Dir, "resolver.go")
+	}
+	if config.Resolver.FilenameTemplate == "" {
+		config.Resolver.FilenameTemplate = "{name}.resolvers.go"
+	}
+	return config, nil
+}
+
+// schemaPaths returns the schema entries of gqlgen.yml
+func (c *gqlgenConfig) schemaPaths() []string {
+	switch schema := c.Schema.(type) {
+	case string:
+		return []string{schema}
+	case []any:
+		paths := make([]string, 0, len(schema))
+		for _, path := range schema {
+			paths = append(paths, fmt.Sprint(path))
+		}
+		return paths
+	}
+	return nil
+}
+
+// moduleSchemaPath picks the file for a module's types: its own file when the schema is a glob,
+// otherwise the single schema file, which is appended to
+func (c *gqlgenConfig) moduleSchemaPath(naming *utils.NamingConvention) (string, bool) {
+	paths := c.schemaPaths()
+	for _, path := range paths {
+		if !strings.ContainsAny(path, "*?[") {
+			continue
+		}
+		dir := path
+		for strings.ContainsAny(dir, "*?[") {
+			dir = filepath.Dir(dir)
+		}
+		ext := filepath.Ext(path)
+		if ext == "" || strings.ContainsAny(ext, "*?[") {
+			ext = ".graphqls"
+		}
+		return filepath.Join(dir, naming.ModelSnake+ext), false
+	}
+	return paths[len(paths)-1], true
+}
+
+// schemaSource concatenates the existing schema files of the backend in dir
+func (c *gqlgenConfig) schemaSource(dir string) string {
+	var source strings.Builder
+	for _, pattern := range c.schemaPaths() {
+		matches, _ := filepath.Glob(filepath.Join(dir, pattern))
+		for _, path := range matches {
+			if content, err := os.ReadFile(path); err == nil {
+				source.Write(content)
+				source.WriteString("\n")
+			}
+		}
+	}
+	return source.String()
+}
+
+// resolverPath returns the file gqlgen keeps a schema file's resolvers in
+func (c *gqlgenConfig) resolverPath(schemaPath string) string {
+	if c.Resolver.Layout != "follow-schema" {
+		return c.Resolver.Filename
+	}
+	name := strings.TrimSuffix(filepath.Base(schemaPath), filepath.Ext(schemaPath))
+	return filepath.Join(c.Resolver.Dir, strings.ReplaceAll(c.Resolver.FilenameTemplate, "{name}", name))
+}
+
+// missingGraphQLDeclarations returns the shared scalars and types a module needs that the schema lacks
+func missingGraphQLDeclarations(schema string, fields []utils.GraphQLField) []string {
+	var declarations []string
+	if !strings.Contains(schema, "scalar Time") {
+		declarations = append(declarations, "scalar Time")
+	}
+	for _, field := range fields {
+		if field.Type == "DateTime" && !strings.Contains(schema, "scalar DateTime") {
+			declarations = append(declarations, "scalar DateTime")
+			break
+		}
+	}
+	if !strings.Contains(schema, "type Pagination ") {
+		declarations = append(declarations, "type Pagination {\n  total: Int!\n  page: Int!\n  page_size: Int!\n  total_pages: Int!\n}")
+	}
+	return declarations
+}
+
+// renderGraphQL executes a GraphQL template for a module
+func renderGraphQL(tmpl *template.Template, data *graphqlModuleData) ([]byte, error) {
+	var content bytes.Buffer
+	if err := tmpl.Execute(&content, data); err != nil {
+		return nil, fmt.Errorf("error executing template %s: %w", tmpl.Name(), err)
+	}
+	return content.Bytes(), nil
+}
+
+// appendGoDecls adds the declarations of src to an existing Go file, merging its imports
+func appendGoDecls(existing, src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	current, err := parser.ParseFile(fset, "", existing, parser.ImportsOnly)
+	if err != nil {
+		return nil, err
+	}
+	addition, err := parser.ParseFile(fset, "", src, parser.ImportsOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	have := map[string]bool{}
+	for _, spec := range current.Imports {
+		have[spec.Path.Value] = true
+	}
+	var missing []string
+	for _, spec := range addition.Imports {
+		if !have[spec.Path.Value] {
+			missing = append(missing, spec.Path.Value)
+		}
+	}
+
+	// Everything after the import block of src is declarations
+	body := src
+	if n := len(addition.Decls); n > 0 {
+		body = src[fset.Position(addition.Decls[n-1].End()).Offset:]
+	}
+
+	merged := string(existing)
+	if len(missing) > 0 {
+		var decl *ast.GenDecl
+		if len(current.Decls) > 0 {
+			decl, _ = current.Decls[0].(*ast.GenDecl)
+		}
+		switch {
+		case decl == nil:
+			offset := fset.Position(current.Name.End()).Offset
+			merged = merged[:offset] + "\n\nimport (\n\t" + strings.Join(missing, "\n\t") + "\n)" + merged[offset:]
+		case decl.Lparen.IsValid():
+			offset := fset.Position(decl.Rparen).Offset
+			merged = merged[:offset] + "\t" + strings.Join(missing, "\n\t") + "\n" + merged[offset:]
+		default:
+			// A single unparenthesized import becomes a block
+			start, end := fset.Position(decl.Pos()).Offset, fset.Position(decl.End()).Offset
+			specs := append([]string{merged[fset.Position(decl.Specs[0].Pos()).Offset:end]}, missing...)
+			merged = merged[:start] + "import (\n\t" + strings.Join(specs, "\n\t") + "\n)" + merged[end:]
+		}
+	}
+	merged = strings.TrimRight(merged, "\n") + "\n" + string(body)
+
+	formatted, err := format.Source([]byte(merged))
+	if err != nil {
+		return nil, err
+	}
+	return formatted, nil
+}
@@ -0,0 +1,55 @@
+package backend
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// mailerDir holds the mailer package bui g mail generates
+var mailerDir = filepath.Join("app", "mailer")
+
+// mailData fills in the mail templates
+type mailData struct {
+	*utils.Mail
+	ModuleName string
+}
+
+// GenerateMail writes the templates of mail to templates/mail, its typed sender to app/mailer,
+// the mailer itself the first time, and registers the mailer module in app/init.go, in the
+// backend w writes to
+func GenerateMail(cmd *mamba.Command, w *utils.Writer, mail *utils.Mail) error {
+	data := mailData{Mail: mail, ModuleName: utils.GetGoModuleNameIn(w.Dir)}
+	files := []struct{ dir, name, template string }{
+		{mailerDir, "mailer.go", "mail/mailer.go.tmpl"},
+		{mailerDir, "module.go", "mail/module.go.tmpl"},
+		{mailerDir, mail.Name + ".go", "mail/mail.go.tmpl"},
+		{utils.MailTemplatesDir, mail.Name + ".html", "mail/mail.html.tmpl"},
+		{utils.MailTemplatesDir, mail.Name + ".txt", "mail/mail.txt.tmpl"},
+	}
+	for _, file := range files {
+		if err := w.GenerateFileFromData(w.Path(file.dir), file.name, file.template, data); err != nil {
+			return fmt.Errorf("failed to generate %s: %w", file.name, err)
+		}
+	}
+
+	if err := addModuleToAppInit(w, "mailer"); err != nil {
+		cmd.PrintWarning("Could not add the mailer module to app/init.go")
+		cmd.PrintInfo("Manually add to app/init.go: modules[\"mailer\"] = mailer.Init(deps)")
+	}
+
+	for _, envFile := range []string{".env", ".env.sample"} {
+		added, err := w.AddEnvPlaceholders(w.Path(envFile), "Mail (bui g mail)", utils.MailEnvVars)
+		if err != nil {
+			cmd.PrintWarning(fmt.Sprintf("Could not update %s: %v", envFile, err))
+			continue
+		}
+		if len(added) > 0 && !utils.DryRun {
+			cmd.PrintInfo(fmt.Sprintf("Added to %s: %s", envFile, strings.Join(added, ", ")))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// migrateIn names the module whose Migrate() should also migrate the generated model
+var migrateIn string
+
+var GenerateModelCmd = &mamba.Command{
+	Use:   "model [name] [field:type...]",
+	Short: "Generate a backend GORM model only",
+	Long: `Generate only the GORM model file (app/models/<name>.go), without a
+controller, service, module or validator. Useful for internal tables or tables
+managed by another module.
+
+Examples:
+  bui g model audit_log action:string user_id:belongsTo:User
+  bui g model audit_log action:string --migrate-in users   # Add to the users module's AutoMigrate`,
+	Args: mamba.MinimumNArgs(1),
+	Run:  generateModelOnly,
+}
+
+func init() {
+	GenerateModelCmd.Flags().StringVar(&migrateIn, "migrate-in", "", "Register the model in an existing module's Migrate() and GetModels()")
+	GenerateModelCmd.Flags().BoolVar(&utils.DryRun, "dry-run", false, "Show the files that would be written without touching disk")
+	GenerateModelCmd.Flags().BoolVar(&utils.ShowDiff, "diff", false, "Print a diff for each file during a dry run")
+	GenerateModelCmd.Flags().BoolVarP(&utils.Force, "force", "f", false, "Overwrite existing files without asking")
+	GenerateModelCmd.Flags().StringArrayVar(&utils.UniqueIndexes, "unique", nil, "Add a composite unique index over comma-separated columns (repeatable)")
+	GenerateModelCmd.Flags().StringVar(&utils.TableOverride, "table", "", "Use a custom table name for the model")
+	GenerateModelCmd.Flags().StringVar(&utils.PrimaryKey, "pk", "uint", "Primary key type: uint or uuid")
+	GenerateModelCmd.Flags().StringVar(&utils.DatabaseFlag, "db", "", "Database to generate for: postgres, mysql or sqlite (default: database in .bui.yaml, else postgres)")
+	GenerateModelCmd.Flags().BoolVar(&utils.NoSoftDelete, "no-soft-delete", false, "Omit the DeletedAt soft-delete column")
+	GenerateModelCmd.Flags().BoolVar(&utils.Audit, "audit", false, "Add created_by/updated_by columns set from the authenticated user")
+	GenerateModelCmd.Flags().BoolVar(&utils.Tenant, "tenant", false, "Add an organization_id column for multi-tenant modules")
+}
+
+// generateModelOnly generates the model file for a backend module without the rest of the module
+func generateModelOnly(cmd *mamba.Command, args []string) {
+	singularName := args[0]
+	fields := args[1:]
+
+	if err := utils.CheckPrimaryKey(); err != nil {
+		cmd.PrintError(err.Error())
+		return
+	}
+	if err := utils.CheckDatabase(); err != nil {
+		cmd.PrintError(err.Error())
+		return
+	}
+
+	// Overwrite backups go in the project root, found from the directory bui was run in
+	startDir, _ := os.Getwd()
+	w := utils.NewRun(utils.ProjectRoot(startDir), singularName).Writer(DetectBackendDir())
+	if w.Dir != "." && Verbose != nil && *Verbose {
+		cmd.PrintInfo(fmt.Sprintf("Working in: %s", w.Dir))
+	}
+
+	naming := utils.NewNamingConvention(singularName)
+
+	fieldStructs := utils.NewTemplateData(naming.Model, fields)
+	naming.TableName = fieldStructs.TableName // Honours --table
+	warnUnknownIndexColumns(cmd, fieldStructs.Fields)
+
+	w.GenerateFileFromTemplate(
+		w.Path("app", "models"),
+		naming.ModelSnake+".go",
+		"model.tmpl",
+		naming,
+		fieldStructs.Fields,
+	)
+
+	printWriteSummary(cmd, w)
+
+	modelPath := w.Path("app", "models", naming.ModelSnake+".go")
+
+	if migrateIn != "" {
+		if err := addModelToMigrate(w, migrateIn, naming.Model); err != nil {
+			cmd.PrintWarning(fmt.Sprintf("Could not register %s in %s: %v", naming.Model, migrateIn, err))
+			cmd.PrintInfo(fmt.Sprintf("Manually add &models.%s{} to the AutoMigrate call in app/%s/module.go", naming.Model, migrateIn))
+		} else if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess(fmt.Sprintf("Registered %s in app/%s/module.go", naming.Model, migrateIn))
+		}
+	}
+
+	if utils.DryRun {
+		cmd.PrintInfo(fmt.Sprintf("Dry run: model %s was not written", naming.Model))
+		return
+	}
+
+	// Fix imports and format the model
+	if _, err := exec.LookPath("goimports"); err == nil {
+		if err := exec.Command("goimports", "-w", modelPath).Run(); err != nil && Verbose != nil && *Verbose {
+			cmd.PrintWarning(fmt.Sprintf("Failed to run goimports on %s", modelPath))
+		}
+	} else if Verbose != nil && *Verbose {
+		cmd.PrintWarning("goimports not found, imports were not adjusted")
+	}
+	if err := exec.Command("gofmt", "-w", modelPath).Run(); err != nil && Verbose != nil && *Verbose {
+		cmd.PrintWarning(fmt.Sprintf("Failed to format %s", modelPath))
+	}
+
+	cmd.PrintSuccess(fmt.Sprintf("Generated model: %s", modelPath))
+}
+
+// addModelToMigrate adds &models.<Model>{} to the AutoMigrate call and GetModels list of a module
+// in the backend w writes to
+func addModelToMigrate(w *utils.Writer, moduleDir, model string) error {
+	modulePath := w.Path("app", moduleDir, "module.go")
+	content, err := os.ReadFile(modulePath)
+	if err != nil {
+		return err
+	}
+
+	contentStr := string(content)
+	entry := fmt.Sprintf("&models.%s{}", model)
+	if strings.Contains(contentStr, entry) {
+		return nil // Already registered
+	}
+
+	// SeedPermissions also calls AutoMigrate, so look inside Migrate() only
+	migrateFunc := strings.Index(contentStr, "func (m *Module) Migrate() error {")
+	if migrateFunc == -1 {
+		return fmt.Errorf("could not find Migrate() in %s", modulePath)
+	}
+	migrateCall := "AutoMigrate("
+	migrateIndex := strings.Index(contentStr[migrateFunc:], migrateCall)
+	if migrateIndex == -1 {
+		return fmt.Errorf("could not find an AutoMigrate call in Migrate() in %s", modulePath)
+	}
+	insertAt := migrateFunc + migrateIndex + len(migrateCall)
+	contentStr = contentStr[:insertAt] + entry + ", " + contentStr[insertAt:]
+
+	// GetModels is optional, but keep it in sync when present
+	modelsList := "return []any{"
+	if listIndex := strings.Index(contentStr, modelsList); listIndex != -1 {
+		insertAt := listIndex + len(modelsList)
+		contentStr = contentStr[:insertAt] + "\n\t\t" + entry + "," + contentStr[insertAt:]
+	}
+
+	if err := w.UpdateProjectFile(modulePath, []byte(contentStr)); err != nil {
+		return err
+	}
+
+	if utils.DryRun {
+		return nil
+	}
+	return exec.Command("gofmt", "-w", modulePath).Run()
+}
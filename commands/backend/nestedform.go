@@ -0,0 +1,25 @@
+package backend
+
+import (
+	"fmt"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// generateNestedForms writes the helpers that save the --nested-form rows with their parent. The
+// child models are read from app/models, so they need to be generated first.
+func generateNestedForms(cmd *mamba.Command, w *utils.Writer, naming *utils.NamingConvention, fields []utils.Field) {
+	forms, problems := utils.ResolveNestedForms(naming.Model, fields, w.Path("app", "models"))
+	for _, problem := range problems {
+		cmd.PrintWarning(problem)
+	}
+	if len(forms) == 0 {
+		return
+	}
+
+	w.GenerateFileFromTemplate(w.Path("app", naming.DirName), "nested.go", "nested.tmpl", naming, fields)
+	if Verbose != nil && *Verbose && !utils.DryRun {
+		cmd.PrintSuccess(fmt.Sprintf("Generated app/%s/nested.go", naming.DirName))
+	}
+}
@@ -0,0 +1,40 @@
+package backend
+
+import (
+	"fmt"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// notificationsData fills in the notifications templates
+type notificationsData struct {
+	utils.Notifications
+	ModuleName string
+}
+
+// GenerateNotifications writes the app/notifications module, its migration, and registers it in
+// app/init.go, in the backend w writes to
+func GenerateNotifications(cmd *mamba.Command, w *utils.Writer, options utils.Notifications) error {
+	data := notificationsData{Notifications: options, ModuleName: utils.GetGoModuleNameIn(w.Dir)}
+	notificationsDir := w.Path("app", "notifications")
+	for _, name := range []string{"notification.go", "controller.go", "module.go"} {
+		template := "notifications/" + name + ".tmpl"
+		if err := w.GenerateFileFromData(notificationsDir, name, template, data); err != nil {
+			return fmt.Errorf("failed to generate %s: %w", name, err)
+		}
+	}
+
+	if utils.FindMigration(w.Path(utils.MigrationsDir), "create_notifications") == "" {
+		up, down := utils.NotificationsTableSQL()
+		if _, err := w.WriteMigration(w.Path(utils.MigrationsDir), "create_notifications", up, down); err != nil {
+			cmd.PrintWarning(fmt.Sprintf("Failed to write the notifications migration: %v", err))
+		}
+	}
+
+	if err := addModuleToAppInit(w, "notifications"); err != nil {
+		cmd.PrintWarning("Could not add the notifications module to app/init.go")
+		cmd.PrintInfo("Manually add to app/init.go: modules[\"notifications\"] = notifications.Init(deps)")
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package backend
+
+import (
+	"fmt"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// generatePivots writes the attach, update and detach endpoints of the manyToMany fields that
+// have pivot columns
+func generatePivots(cmd *mamba.Command, w *utils.Writer, naming *utils.NamingConvention, fields []utils.Field) {
+	if len(utils.PivotRelations(fields)) == 0 {
+		return
+	}
+	w.GenerateFileFromTemplate(w.Path("app", naming.DirName), "pivots.go", "pivot.tmpl", naming, fields)
+	if Verbose != nil && *Verbose && !utils.DryRun {
+		cmd.PrintSuccess(fmt.Sprintf("Generated app/%s/pivots.go", naming.DirName))
+	}
+}
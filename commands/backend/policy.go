@@ -0,0 +1,141 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// policyRoutePattern matches a route registration such as router.GET("/products", c.List), also
+// with the c.limit and c.cached wrappers of --rate-limit and --cache
+var policyRoutePattern = regexp.MustCompile(`(router\.(?:GET|POST|PUT|PATCH|DELETE)\("[^"]*", (?:c\.limit\()?)(c\.cached\(c\.(\w+)\)|c\.(\w+))\)`)
+
+// policyData fills in policy.tmpl
+type policyData struct {
+	*utils.NamingConvention
+	ModuleName string
+	Roles      []utils.PolicyRole
+}
+
+// GeneratePolicy writes app/<module>/policy.go and wires its permission checks into the
+// module's existing controller routes and permission seed, in the backend w writes to
+func GeneratePolicy(cmd *mamba.Command, w *utils.Writer, naming *utils.NamingConvention, roles []utils.PolicyRole) error {
+	moduleDir := w.Path("app", naming.DirName)
+	controllerPath := filepath.Join(moduleDir, "controller.go")
+	controller, err := os.ReadFile(controllerPath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("no %s module in %s: generate it first with bui g %s", naming.Model, moduleDir, naming.ModelSnake)
+	} else if err != nil {
+		return err
+	}
+	modulePath := filepath.Join(moduleDir, "module.go")
+	module, err := os.ReadFile(modulePath)
+	if err != nil {
+		return err
+	}
+
+	data := policyData{NamingConvention: naming, ModuleName: utils.GetGoModuleNameIn(w.Dir), Roles: roles}
+	if err := w.GenerateFileFromData(moduleDir, "policy.go", "policy.tmpl", data); err != nil {
+		return fmt.Errorf("failed to generate policy.go: %w", err)
+	}
+
+	if !strings.Contains(string(controller), "c.authorize(") {
+		updated, skipped := authorizeRoutes(string(controller), naming)
+		for _, handler := range skipped {
+			cmd.PrintWarning(fmt.Sprintf("Route to c.%s has no matching permission; wrap it with c.authorize yourself", handler))
+		}
+		if err := w.UpdateProjectFile(controllerPath, []byte(updated)); err != nil {
+			return err
+		}
+	}
+
+	if !strings.Contains(string(module), "SeedPolicy()") {
+		seed := "\treturn m.SeedPermissions()\n}"
+		if !strings.Contains(string(module), seed) {
+			cmd.PrintWarning("Could not find SeedPermissions in " + modulePath)
+			cmd.PrintInfo("Manually call m.SeedPolicy() from Init after m.SeedPermissions()")
+			return nil
+		}
+		updated := strings.Replace(string(module), seed, `	if err := m.SeedPermissions(); err != nil {
+		return err
+	}
+
+	// Grant the role permissions in policy.go
+	return m.SeedPolicy()
+}`, 1)
+		if err := w.UpdateProjectFile(modulePath, []byte(updated)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// policyHandlerPermission returns the policy.go permission a generated handler needs
+func policyHandlerPermission(handler string) string {
+	switch {
+	case handler == "List" || handler == "ListAll" || handler == "Export" || handler == "Search" || handler == "Stats" || strings.HasSuffix(handler, "Report"):
+		return "PermissionList"
+	case handler == "Get" || handler == "Activity":
+		return "PermissionRead"
+	case handler == "Create" || handler == "Import":
+		return "PermissionCreate"
+	case handler == "Update" || handler == "BulkUpdate" || strings.HasPrefix(handler, "Upload") || strings.HasPrefix(handler, "Remove"):
+		return "PermissionUpdate"
+	case handler == "Delete" || handler == "BulkDelete":
+		return "PermissionDelete"
+	}
+	return ""
+}
+
+// authorizeRoutes wraps the handlers in a controller's Routes with c.authorize and adds the
+// abilities route. It returns the handlers it couldn't match to a permission.
+func authorizeRoutes(content string, naming *utils.NamingConvention) (string, []string) {
+	start := strings.Index(content, fmt.Sprintf("func (c *%s) Routes(", naming.Controller))
+	if start == -1 {
+		return content, nil
+	}
+	end := strings.Index(content[start:], "\n}\n")
+	if end == -1 {
+		return content, nil
+	}
+	end += start
+
+	var skipped []string
+	routes := policyRoutePattern.ReplaceAllStringFunc(content[start:end], func(route string) string {
+		match := policyRoutePattern.FindStringSubmatch(route)
+		handler := match[3] + match[4]
+		permission := policyHandlerPermission(handler)
+		if permission == "" {
+			skipped = append(skipped, handler)
+			return route
+		}
+		// Inside the rate limit, so denied requests count, and outside the cache
+		return fmt.Sprintf("%sc.authorize(%s, %s))", match[1], permission, match[2])
+	})
+
+	// The abilities route goes before the /:id routes so it isn't read as an id
+	abilitiesHandler := "c.Abilities"
+	if strings.Contains(routes, "c.limit(") {
+		abilitiesHandler = "c.limit(c.Abilities)"
+	}
+	abilities := fmt.Sprintf("\trouter.GET(%q, %s) // Current user's permissions - MUST be before /:id\n", naming.RoutePath+"/abilities", abilitiesHandler)
+	inserted := false
+	for _, anchor := range []string{fmt.Sprintf("%q", naming.RoutePath+"/all"), fmt.Sprintf("%q", naming.RoutePath+"/:id")} {
+		if i := strings.Index(routes, anchor); i != -1 {
+			lineStart := strings.LastIndex(routes[:i], "\n") + 1
+			routes = routes[:lineStart] + abilities + routes[lineStart:]
+			inserted = true
+			break
+		}
+	}
+	if !inserted {
+		routes += "\n" + strings.TrimSuffix(abilities, "\n")
+	}
+
+	return content[:start] + routes + content[end:], skipped
+}
@@ -0,0 +1,98 @@
+package backend
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// moduleFileRoles says what the files a backend module can be generated with are for
+var moduleFileRoles = map[string]string{
+	"module.go":        "Registers the module: its migrations, permissions and routes",
+	"controller.go":    "HTTP handlers and their routes; add endpoints here",
+	"service.go":       "Queries and business logic behind the handlers; add rules that apply to every caller here",
+	"validator.go":     "Checks of create and update requests",
+	"seed.go":          "Sample records for bui seed",
+	"state.go":         "Transitions of the state fields and their endpoint",
+	"lock.go":          "The lock_version check of updates",
+	"revisions.go":     "Revisions kept on every update, and restoring them",
+	"bulk.go":          "Bulk delete and status update",
+	"import_export.go": "CSV and XLSX import and export",
+	"pdf.go":           "Layout of the PDF download",
+	"throttle.go":      "Rate limits and cached reads of the routes",
+	"search.go":        "Full-text search",
+	"geo.go":           "Nearby searches over the point fields",
+	"media.go":         "Joins of the media[] fields",
+	"nested.go":        "Saving the hasMany rows edited with the record",
+	"pivots.go":        "Endpoints editing the pivot columns of manyToMany links",
+	"policy.go":        "Who may do what, from bui g policy",
+	"stats.go":         "Dashboard widget data, from bui g widget",
+	"README.md":        "This file, regenerated with the module",
+}
+
+// writeModuleReadme writes app/<module>/README.md, documenting the fields and endpoints of the
+// module as generated and where to customize it. It's read from the written files, so a dry run
+// skips it.
+func writeModuleReadme(cmd *mamba.Command, w *utils.Writer, naming *utils.NamingConvention, command string) {
+	if utils.DryRun {
+		return
+	}
+	moduleDir := w.Path("app", naming.DirName)
+	doc := utils.ReadModuleDoc(w.Dir, naming.DirName)
+
+	var b strings.Builder
+	b.WriteString(doc.Markdown(nil))
+	b.WriteString("## Customizing\n\n")
+	fmt.Fprintf(&b, "Last generated by `%s`; the %s in the project root lists every command that changed the module.\n\n", command, utils.ChangelogFile)
+	b.WriteString("| File | What it's for |\n")
+	b.WriteString("|---|---|\n")
+	fmt.Fprintf(&b, "| `app/models/%s.go` | The model, its columns and the request and response payloads |\n", naming.ModelSnake)
+
+	entries, _ := os.ReadDir(moduleDir)
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			files = append(files, entry.Name())
+		}
+	}
+	if !slices.Contains(files, "README.md") {
+		files = append(files, "README.md")
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		role, ok := moduleFileRoles[file]
+		if !ok && strings.HasSuffix(file, "_test.go") {
+			role = "Tests of the service and handlers"
+		}
+		fmt.Fprintf(&b, "| `%s` | %s |\n", file, cmp.Or(role, "-"))
+	}
+
+	b.WriteString("\nRe-running the command asks before replacing files that changed, and `--force` replaces them. ")
+	fmt.Fprintf(&b, "Add fields to the module in place with `bui g %s <field:type...> --alter`, ", naming.ModelSnake)
+	b.WriteString("and keep code of your own in new files of the package, which are never overwritten.\n")
+
+	if err := w.WriteGeneratedFile(filepath.Join(moduleDir, "README.md"), []byte(b.String())); err != nil {
+		cmd.PrintWarning(fmt.Sprintf("Could not write %s/README.md: %v", moduleDir, err))
+		return
+	}
+	if Verbose != nil && *Verbose && !utils.DryRun {
+		cmd.PrintSuccess(fmt.Sprintf("Generated %s/README.md", moduleDir))
+	}
+}
+
+// recordGeneration records the files w wrote in the project's CHANGELOG.md and manifest
+func recordGeneration(cmd *mamba.Command, w *utils.Writer, args []string, part string) {
+	if utils.DryRun {
+		return
+	}
+	if err := w.Record(cmd, args, part); err != nil {
+		cmd.PrintWarning(fmt.Sprintf("Could not record the generation: %v", err))
+	}
+}
@@ -0,0 +1,30 @@
+package backend
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// scaffoldRealtime writes the shared websocket hub that --realtime services publish to,
+// and registers it in app/init.go
+func scaffoldRealtime(cmd *mamba.Command, w *utils.Writer, naming *utils.NamingConvention) {
+	realtimeDir := w.Path("app", "realtime")
+	files := map[string]string{
+		"hub.go":    "realtime_hub.tmpl",
+		"module.go": "realtime_module.tmpl",
+	}
+	for _, name := range []string{"hub.go", "module.go"} {
+		if _, err := os.Stat(filepath.Join(realtimeDir, name)); err == nil {
+			continue
+		}
+		w.GenerateFileFromTemplate(realtimeDir, name, files[name], naming, nil)
+	}
+
+	if err := addModuleToAppInit(w, "realtime"); err != nil {
+		cmd.PrintWarning("Could not add the realtime module to app/init.go")
+		cmd.PrintInfo("Manually add to app/init.go: modules[\"realtime\"] = realtime.Init(deps)")
+	}
+}
@@ -0,0 +1,53 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// reportData fills in report.tmpl
+type reportData struct {
+	*utils.Report
+	moduleContext
+}
+
+// GenerateReport writes app/<module>/<name>_report.go with GET /<module>/reports/<name>, a
+// GORM group query over the module's table, and adds the route to the module's controller. The
+// report is checked against the fields of the module's model, in the backend w writes to, and
+// returned for the admin page.
+func GenerateReport(cmd *mamba.Command, w *utils.Writer, naming *utils.NamingConvention, name, groupBy string, sums []string, dateField string) (*utils.Report, error) {
+	controllerPath, module, err := readModuleContext(w, naming)
+	if err != nil {
+		return nil, err
+	}
+
+	modelPath := w.Path("app", "models", naming.ModelSnake+".go")
+	source, err := os.ReadFile(modelPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", modelPath, err)
+	}
+	defs, err := utils.RecoverFieldDefs(source, naming.Model)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the fields of %s: %w", modelPath, err)
+	}
+	fields := utils.NewTemplateData(naming.Model, defs).Fields
+
+	report, err := utils.NewReport(naming, fields, name, groupBy, sums, dateField)
+	if err != nil {
+		return nil, err
+	}
+
+	filename := report.Name + "_report.go"
+	data := reportData{Report: report, moduleContext: module}
+	if err := w.GenerateFileFromData(filepath.Dir(controllerPath), filename, "report.tmpl", data); err != nil {
+		return nil, fmt.Errorf("failed to generate %s: %w", filename, err)
+	}
+	if err := addCollectionRoute(cmd, w, controllerPath, naming, report.Route(), report.Handler, report.Title+" report"); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,73 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// generateRules writes the shared app/rules package the validators check rules= modifiers with,
+// once, and adds a rule that passes everything to app/rules/custom.go for each new custom rule
+func generateRules(cmd *mamba.Command, w *utils.Writer, naming *utils.NamingConvention, fields []utils.Field) {
+	rulesDir := w.Path("app", "rules")
+	files := []struct{ name, template string }{
+		{"rules.go", "rules.tmpl"},
+		{"custom.go", "rules_custom.tmpl"},
+	}
+	for _, file := range files {
+		if _, err := os.Stat(filepath.Join(rulesDir, file.name)); err == nil {
+			continue
+		}
+		w.GenerateFileFromTemplate(rulesDir, file.name, file.template, naming, nil)
+		if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess("Generated app/rules/" + file.name)
+		}
+	}
+
+	customPath := filepath.Join(rulesDir, "custom.go")
+	for _, name := range utils.CustomRules(fields) {
+		added, err := addCustomRule(w, customPath, name, naming.Model)
+		if err != nil {
+			cmd.PrintWarning(fmt.Sprintf("Could not add the %s rule: %v", name, err))
+			cmd.PrintInfo(fmt.Sprintf("Manually add to Custom in %s: %q: func(value any, param string) string { ... }", customPath, name))
+			continue
+		}
+		if added && !utils.DryRun {
+			cmd.PrintInfo(fmt.Sprintf("Added the custom rule %s to %s; it passes every value until you fill it in", name, customPath))
+		}
+	}
+}
+
+// addCustomRule adds a rule that passes everything to the Custom map of custom.go, reporting
+// whether the file changed. A rule that is already there is kept.
+func addCustomRule(w *utils.Writer, path, name, model string) (bool, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) && utils.DryRun {
+		return false, nil // custom.go is only reported during a dry run
+	}
+	if err != nil {
+		return false, err
+	}
+	contentStr := string(content)
+	if strings.Contains(contentStr, fmt.Sprintf("%q:", name)) {
+		return false, nil
+	}
+
+	entry := fmt.Sprintf("\t// %s is a rule of %s; return a message when value breaks it\n\t%q: func(value any, param string) string {\n\t\treturn \"\"\n\t},", name, model, name)
+	if empty := "string{}"; strings.Contains(contentStr, empty) {
+		// The first rule opens up the empty map
+		contentStr = strings.Replace(contentStr, empty, "string{\n"+entry+"\n}", 1)
+	} else {
+		end := strings.LastIndex(contentStr, "\n}")
+		if end == -1 {
+			return false, fmt.Errorf("could not find the end of Custom in %s", path)
+		}
+		contentStr = contentStr[:end+1] + entry + contentStr[end:]
+	}
+
+	return true, w.UpdateProjectFile(path, []byte(contentStr))
+}
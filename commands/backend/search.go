@@ -0,0 +1,49 @@
+package backend
+
+import (
+	"fmt"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// generateSearch writes the module's full-text search endpoint and, once per table, the migration
+// that adds its search_vector column
+func generateSearch(cmd *mamba.Command, w *utils.Writer, naming *utils.NamingConvention, fields []utils.Field) {
+	searchable, unknown := utils.SearchableFields(fields)
+	for _, column := range unknown {
+		cmd.PrintWarning(fmt.Sprintf("--searchable column %s is not a text field of this model; it is not searched", column))
+	}
+	if len(searchable) == 0 {
+		return
+	}
+
+	w.GenerateFileFromTemplate(w.Path("app", naming.DirName), "search.go", "search.tmpl", naming, fields)
+	if Verbose != nil && *Verbose && !utils.DryRun {
+		cmd.PrintSuccess(fmt.Sprintf("Generated app/%s/search.go", naming.DirName))
+	}
+
+	// The search_vector column is PostgreSQL only; other databases match the columns with LIKE
+	if database := utils.Database(); database != utils.Postgres {
+		if Verbose != nil && *Verbose {
+			cmd.PrintInfo(fmt.Sprintf("Skipping the search_vector migration; %s searches match the columns with LIKE", utils.DatabaseLabel(database)))
+		}
+		return
+	}
+
+	// Searching other columns later takes a new migration that replaces the generated column
+	name := "add_" + naming.TableName + "_search"
+	if existing := utils.FindMigration(w.Path(utils.MigrationsDir), name); existing != "" {
+		if Verbose != nil && *Verbose {
+			cmd.PrintInfo(fmt.Sprintf("Keeping existing migration %s", existing))
+		}
+		return
+	}
+	up, down := utils.SearchMigrationSQL(naming.TableName, searchable)
+	upPath, err := w.WriteMigration(w.Path(utils.MigrationsDir), name, up, down)
+	if err != nil {
+		cmd.PrintWarning(fmt.Sprintf("Failed to write the search migration: %v", err))
+	} else if Verbose != nil && *Verbose && !utils.DryRun {
+		cmd.PrintSuccess(fmt.Sprintf("Generated %s", upPath))
+	}
+}
@@ -0,0 +1,48 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// settingsDir holds the settings module bui g settings generates
+var settingsDir = filepath.Join("app", "settings")
+
+// settingsData fills in the settings templates
+type settingsData struct {
+	utils.SettingsModule
+	ModuleName string
+}
+
+// GenerateSettings writes the app/settings module for settings plus those generated before,
+// its migration, and registers it in app/init.go. It returns the combined settings, which the
+// admin page is generated for. Paths are those of the backend w writes to.
+func GenerateSettings(cmd *mamba.Command, w *utils.Writer, settings []utils.Setting) ([]utils.Setting, error) {
+	if existing, err := os.ReadFile(w.Path(settingsDir, "definitions.go")); err == nil {
+		settings = utils.MergeSettings(utils.ParseSettingDefinitions(string(existing)), settings)
+	}
+	data := settingsData{SettingsModule: utils.SettingsModule{Settings: settings}, ModuleName: utils.GetGoModuleNameIn(w.Dir)}
+
+	for _, name := range []string{"setting.go", "definitions.go", "service.go", "controller.go", "module.go"} {
+		if err := w.GenerateFileFromData(w.Path(settingsDir), name, "settings/"+name+".tmpl", data); err != nil {
+			return settings, fmt.Errorf("failed to generate %s: %w", name, err)
+		}
+	}
+
+	if utils.FindMigration(w.Path(utils.MigrationsDir), "create_settings") == "" {
+		up, down := utils.SettingsTableSQL()
+		if _, err := w.WriteMigration(w.Path(utils.MigrationsDir), "create_settings", up, down); err != nil {
+			cmd.PrintWarning(fmt.Sprintf("Failed to write the settings migration: %v", err))
+		}
+	}
+
+	if err := addModuleToAppInit(w, "settings"); err != nil {
+		cmd.PrintWarning("Could not add the settings module to app/init.go")
+		cmd.PrintInfo("Manually add to app/init.go: modules[\"settings\"] = settings.Init(deps)")
+	}
+	return settings, nil
+}
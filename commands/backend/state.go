@@ -0,0 +1,17 @@
+package backend
+
+import (
+	"fmt"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// generateState writes the transition checks and the transition endpoint of the module's state
+// fields
+func generateState(cmd *mamba.Command, w *utils.Writer, naming *utils.NamingConvention, fields []utils.Field) {
+	w.GenerateFileFromTemplate(w.Path("app", naming.DirName), "state.go", "state.tmpl", naming, fields)
+	if Verbose != nil && *Verbose && !utils.DryRun {
+		cmd.PrintSuccess(fmt.Sprintf("Generated app/%s/state.go", naming.DirName))
+	}
+}
@@ -0,0 +1,318 @@
+package backend
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// taskSchedule is the cron expression for bui g task (--cron)
+var taskSchedule string
+
+var GenerateTaskCmd = &mamba.Command{
+	Use:   "task [name]",
+	Short: "Generate a scheduled task",
+	Long: `Generate a recurring task in app/scheduler, run on a cron schedule by the scheduler module.
+
+The first task also writes the scheduler module (on github.com/robfig/cron/v3) and registers
+it in app/init.go. Each task file has a DryRun switch: with it set, or SCHEDULER_DRY_RUN=true,
+the task's Run gets dryRun=true and should only log what it would change.
+
+Schedules use five fields (minute hour day-of-month month day-of-week), a descriptor such as
+@daily or @hourly, or @every <duration>.
+
+Examples:
+  bui g task cleanup_expired_tokens --cron "0 3 * * *"
+  bui g task sync_exchange_rates --cron "@every 15m"`,
+	Args: mamba.ExactArgs(1),
+	Run:  generateTask,
+}
+
+func init() {
+	GenerateTaskCmd.Flags().StringVar(&taskSchedule, "cron", "", "When to run the task, e.g. \"0 3 * * *\" or @hourly")
+	GenerateTaskCmd.Flags().BoolVar(&utils.DryRun, "dry-run", false, "Show the files that would be written without touching disk")
+	GenerateTaskCmd.Flags().BoolVar(&utils.ShowDiff, "diff", false, "Print a diff for each file during a dry run")
+	GenerateTaskCmd.Flags().BoolVarP(&utils.Force, "force", "f", false, "Overwrite existing files without asking")
+}
+
+// taskNamePattern matches task names that make valid Go identifiers
+var taskNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// schedulerDir holds the scheduler module and its tasks
+var schedulerDir = filepath.Join("app", "scheduler")
+
+// taskData fills in the task and scheduler templates
+type taskData struct {
+	ModuleName string
+	Name       string // snake_case, as logged
+	Func       string // Exported constructor registered in tasks.go
+	Run        string // Unexported function holding the task's work
+	Schedule   string
+}
+
+// generateTask writes a task file and registers it with the scheduler module
+func generateTask(cmd *mamba.Command, args []string) {
+	if !taskNamePattern.MatchString(args[0]) {
+		cmd.PrintError(fmt.Sprintf("Invalid task name %q: use letters, digits and underscores", args[0]))
+		return
+	}
+	if taskSchedule == "" {
+		cmd.PrintError("--cron is required, e.g. --cron \"0 3 * * *\"")
+		return
+	}
+	if err := utils.ValidateCron(taskSchedule); err != nil {
+		cmd.PrintError(err.Error())
+		return
+	}
+
+	// Paths are joined to the backend directory rather than changing to it
+	startDir, _ := os.Getwd()
+	w := utils.NewRun(utils.ProjectRoot(startDir), "scheduler").Writer(DetectBackendDir())
+	if w.Dir != "." && Verbose != nil && *Verbose {
+		cmd.PrintInfo(fmt.Sprintf("Working in: %s", w.Dir))
+	}
+
+	name := utils.ToSnakeCase(strings.ReplaceAll(args[0], "-", "_"))
+	data := taskData{
+		ModuleName: utils.GetGoModuleNameIn(w.Dir),
+		Name:       name,
+		Func:       utils.ToPascalCase(name) + "Task",
+		Run:        utils.ToCamelCase(name),
+		Schedule:   strings.TrimSpace(taskSchedule),
+	}
+
+	tasksPath := w.Path(schedulerDir, "tasks.go")
+	tasks, err := os.ReadFile(tasksPath)
+	scaffolded := os.IsNotExist(err)
+	if scaffolded {
+		if tasks, err = scaffoldScheduler(w, data); err != nil {
+			cmd.PrintError(fmt.Sprintf("Failed to generate the scheduler module: %v", err))
+			return
+		}
+		if err := addModuleToAppInit(w, "scheduler"); err != nil {
+			cmd.PrintWarning("Could not add the scheduler module to app/init.go")
+			cmd.PrintInfo("Manually add to app/init.go: modules[\"scheduler\"] = scheduler.Init(deps)")
+		}
+	} else if err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to read %s: %v", tasksPath, err))
+		return
+	}
+
+	taskPath := w.Path(schedulerDir, name+".go")
+	if err := writeTaskTemplate(w, taskPath, taskTemplate, data); err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to generate %s: %v", taskPath, err))
+		return
+	}
+
+	if err := registerTask(w, tasksPath, tasks, data.Func); err != nil {
+		cmd.PrintWarning(fmt.Sprintf("Could not register %s: %v", data.Func, err))
+		cmd.PrintInfo(fmt.Sprintf("Manually add to registeredTasks in %s: tasks = append(tasks, %s(deps))", tasksPath, data.Func))
+	}
+
+	printWriteSummary(cmd, w)
+
+	if utils.DryRun {
+		cmd.PrintInfo(fmt.Sprintf("Dry run: task %s was not written", name))
+		return
+	}
+
+	cmd.PrintSuccess(fmt.Sprintf("Generated task %s (%s) in %s", name, data.Schedule, taskPath))
+	if scaffolded {
+		cmd.PrintInfo("Scheduler next steps:")
+		cmd.PrintBullet("go get github.com/robfig/cron/v3")
+		cmd.PrintBullet("Set SCHEDULER_DISABLED=true on all but one instance when running several")
+	}
+}
+
+// scaffoldScheduler writes the scheduler module of the backend w writes to and returns the new
+// tasks.go content
+func scaffoldScheduler(w *utils.Writer, data taskData) ([]byte, error) {
+	modulePath := w.Path(schedulerDir, "module.go")
+	if _, err := os.Stat(modulePath); os.IsNotExist(err) {
+		if err := writeTaskTemplate(w, modulePath, schedulerModuleTemplate, data); err != nil {
+			return nil, err
+		}
+	}
+
+	var tasks bytes.Buffer
+	if err := schedulerTasksTemplate.Execute(&tasks, data); err != nil {
+		return nil, err
+	}
+	return tasks.Bytes(), nil
+}
+
+// registerTask appends the task's constructor to registeredTasks in tasks.go
+func registerTask(w *utils.Writer, tasksPath string, content []byte, constructor string) error {
+	contentStr := string(content)
+	entry := fmt.Sprintf("tasks = append(tasks, %s(deps))", constructor)
+	if strings.Contains(contentStr, entry) {
+		return nil // Already registered
+	}
+
+	returnIndex := strings.LastIndex(contentStr, "\treturn tasks")
+	if returnIndex == -1 {
+		return fmt.Errorf("could not find 'return tasks' in %s", tasksPath)
+	}
+	contentStr = contentStr[:returnIndex] + "\t" + entry + "\n" + contentStr[returnIndex:]
+
+	return w.UpdateProjectFile(tasksPath, []byte(contentStr))
+}
+
+// writeTaskTemplate renders a scheduler template to path
+func writeTaskTemplate(w *utils.Writer, path string, tmpl *template.Template, data taskData) error {
+	var content bytes.Buffer
+	if err := tmpl.Execute(&content, data); err != nil {
+		return err
+	}
+	return w.WriteGeneratedFile(path, content.Bytes())
+}
+
+var taskTemplate = template.Must(template.New("task").Parse(`package scheduler
+
+import (
+	"context"
+
+	"{{.ModuleName}}/core/module"
+)
+
+// {{.Func}} runs {{.Name}} on {{printf "%q" .Schedule}}
+func {{.Func}}(deps module.Dependencies) Task {
+	return Task{
+		Name:     "{{.Name}}",
+		Schedule: {{printf "%q" .Schedule}},
+		// DryRun passes dryRun=true to Run so it only logs what it would change;
+		// SCHEDULER_DRY_RUN=true does the same for every task
+		DryRun: false,
+		Run: func(ctx context.Context, dryRun bool) error {
+			return {{.Run}}(ctx, deps, dryRun)
+		},
+	}
+}
+
+// {{.Run}} does the task's work. With dryRun set it must not change anything.
+func {{.Run}}(ctx context.Context, deps module.Dependencies, dryRun bool) error {
+	// Find what needs doing, e.g. deps.DB.WithContext(ctx).Where("expires_at < ?", time.Now()).Find(&rows)
+
+	if dryRun {
+		deps.Logger.Info("{{.Name}}: dry run, nothing changed")
+		return nil
+	}
+
+	// Make the changes, e.g. deps.DB.WithContext(ctx).Delete(&rows)
+
+	return nil
+}
+`))
+
+var schedulerTasksTemplate = template.Must(template.New("tasks").Parse(`package scheduler
+
+import "{{.ModuleName}}/core/module"
+
+// registeredTasks lists the tasks the scheduler runs; bui g task adds new ones before the return
+func registeredTasks(deps module.Dependencies) []Task {
+	var tasks []Task
+	return tasks
+}
+`))
+
+var schedulerModuleTemplate = template.Must(template.New("scheduler").Parse(`package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"{{.ModuleName}}/core/logger"
+	"{{.ModuleName}}/core/module"
+	"{{.ModuleName}}/core/router"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Task is recurring work run on a cron schedule
+type Task struct {
+	Name     string
+	Schedule string // Five-field cron expression, a descriptor such as @daily, or @every 10m
+	DryRun   bool   // Run with dryRun set, so the task only logs what it would change
+	Run      func(ctx context.Context, dryRun bool) error
+}
+
+// Module runs the tasks registered in tasks.go
+type Module struct {
+	module.DefaultModule
+	Cron   *cron.Cron
+	Tasks  []Task
+	Logger logger.Logger
+}
+
+// Init creates the scheduler with the registered tasks
+func Init(deps module.Dependencies) module.Module {
+	return &Module{
+		Cron:   cron.New(),
+		Tasks:  registeredTasks(deps),
+		Logger: deps.Logger,
+	}
+}
+
+// Routes registers nothing; tasks run in the background
+func (m *Module) Routes(router *router.RouterGroup) {}
+
+// Init schedules every task and starts the cron loop. SCHEDULER_DISABLED=true skips this,
+// e.g. on all but one replica, and SCHEDULER_DRY_RUN=true runs every task as a dry run.
+func (m *Module) Init() error {
+	if os.Getenv("SCHEDULER_DISABLED") == "true" {
+		m.Logger.Info("scheduler disabled by SCHEDULER_DISABLED")
+		return nil
+	}
+	dryRun := os.Getenv("SCHEDULER_DRY_RUN") == "true"
+
+	for _, task := range m.Tasks {
+		task.DryRun = task.DryRun || dryRun
+		if _, err := m.Cron.AddJob(task.Schedule, m.job(task)); err != nil {
+			return fmt.Errorf("invalid schedule for task %s: %w", task.Name, err)
+		}
+	}
+
+	m.Cron.Start()
+	return nil
+}
+
+// job wraps a task so a run still in progress makes the next one skip rather than overlap
+func (m *Module) job(task Task) cron.Job {
+	return cron.NewChain(cron.SkipIfStillRunning(cron.DiscardLogger)).Then(cron.FuncJob(func() {
+		m.run(task)
+	}))
+}
+
+// run executes one run of a task, logging failures and recovering from panics
+func (m *Module) run(task Task) {
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			m.Logger.Error("scheduled task panicked", logger.String("task", task.Name), logger.String("panic", fmt.Sprint(r)))
+		}
+	}()
+
+	if err := task.Run(context.Background(), task.DryRun); err != nil {
+		m.Logger.Error("scheduled task failed", logger.String("task", task.Name), logger.String("error", err.Error()))
+		return
+	}
+	m.Logger.Info("scheduled task finished", logger.String("task", task.Name), logger.String("duration", time.Since(start).String()))
+}
+
+func (m *Module) Migrate() error {
+	return nil
+}
+
+func (m *Module) GetModels() []any {
+	return nil
+}
+`))
@@ -0,0 +1,43 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// generateThrottle writes the rate limit and response cache wrappers of a --rate-limit or
+// --cache module, and the shared ratelimit and httpcache packages the first time
+func generateThrottle(cmd *mamba.Command, w *utils.Writer, naming *utils.NamingConvention, fields []utils.Field) {
+	var packages []string
+	if utils.RateLimit != "" {
+		packages = append(packages, "ratelimit")
+	}
+	if utils.Cache != "" {
+		packages = append(packages, "httpcache")
+	}
+	for _, name := range packages {
+		dir := w.Path("app", name)
+		if _, err := os.Stat(filepath.Join(dir, name+".go")); err == nil {
+			continue
+		}
+		w.GenerateFileFromTemplate(dir, name+".go", name+".tmpl", naming, nil)
+		if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess(fmt.Sprintf("Generated app/%s/%s.go", name, name))
+		}
+	}
+
+	w.GenerateFileFromTemplate(
+		w.Path("app", naming.DirName),
+		"throttle.go",
+		"throttle.tmpl",
+		naming,
+		fields,
+	)
+	if Verbose != nil && *Verbose && !utils.DryRun {
+		cmd.PrintSuccess(fmt.Sprintf("Generated app/%s/throttle.go", naming.DirName))
+	}
+}
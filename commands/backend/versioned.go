@@ -0,0 +1,25 @@
+package backend
+
+import (
+	"fmt"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// generateVersioning writes the revision history and restore endpoints of a --versioned module,
+// and the migration of its revisions table
+func generateVersioning(cmd *mamba.Command, w *utils.Writer, naming *utils.NamingConvention, fields []utils.Field) {
+	w.GenerateFileFromTemplate(w.Path("app", naming.DirName), "revisions.go", "revision.tmpl", naming, fields)
+	if Verbose != nil && *Verbose && !utils.DryRun {
+		cmd.PrintSuccess(fmt.Sprintf("Generated app/%s/revisions.go", naming.DirName))
+	}
+
+	name := "create_" + naming.ModelSnake + "_revisions"
+	if utils.FindMigration(w.Path(utils.MigrationsDir), name) == "" {
+		up, down := utils.RevisionTableSQL(naming)
+		if _, err := w.WriteMigration(w.Path(utils.MigrationsDir), name, up, down); err != nil {
+			cmd.PrintWarning(fmt.Sprintf("Failed to write the %s_revisions migration: %v", naming.ModelSnake, err))
+		}
+	}
+}
@@ -0,0 +1,425 @@
+package backend
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// WebhookEndpointFields are the bui g fields of the webhook_endpoint module
+var WebhookEndpointFields = []string{
+	"url:url:required",
+	"secret:string:required",
+	"events:text",
+	"active:bool:default=true",
+	"description:string",
+}
+
+// WebhookEndpointResponseFields are the --fields-read columns of the webhook_endpoint module.
+// The secret is left out, so the API accepts it but never returns it.
+const WebhookEndpointResponseFields = "url,events,active,description"
+
+// WebhookDeliveryFields are the bui g fields of the webhook_delivery module
+var WebhookDeliveryFields = []string{
+	"webhook_endpoint:belongsTo",
+	"event:string",
+	"payload:text",
+	"status:enum:pending,succeeded,failed",
+	"attempts:int",
+	"status_code:int",
+	"response_body:text",
+	"error:text",
+	"next_retry_at:datetime",
+	"delivered_at:datetime",
+}
+
+// webhookEventPattern matches dotted event names such as order.created
+var webhookEventPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z][a-z0-9_]*)+$`)
+
+// webhookCRUDActions maps webhook actions to the events generated services emit
+var webhookCRUDActions = map[string]string{
+	"created": "create",
+	"updated": "update",
+	"deleted": "delete",
+}
+
+// webhooksDir holds the dispatcher and the event list
+var webhooksDir = filepath.Join("app", "webhooks")
+
+// webhookData fills in the dispatcher templates
+type webhookData struct {
+	ModuleName string
+	UUIDKey    bool
+	IDType     string
+}
+
+// ValidateWebhookEvent checks an event name such as order.created
+func ValidateWebhookEvent(event string) error {
+	if !webhookEventPattern.MatchString(event) {
+		return fmt.Errorf("invalid event %q: use dotted lowercase names such as order.created", event)
+	}
+	return nil
+}
+
+// WebhookSource returns the emitter event that triggers a webhook event. <model>.created,
+// .updated and .deleted follow the generated services (order.created -> orders.create);
+// any other name is emitted by the app itself under the same name.
+func WebhookSource(event string) (source string, model string) {
+	dot := strings.LastIndex(event, ".")
+	resource, action := event[:dot], event[dot+1:]
+	if crud, ok := webhookCRUDActions[action]; ok && !strings.Contains(resource, ".") {
+		naming := utils.NewNamingConvention(resource)
+		return strings.ToLower(naming.Plural) + "." + crud, naming.Model
+	}
+	return event, ""
+}
+
+// AddWebhookEvent writes the webhook dispatcher on first use and adds event to app/webhooks/events.go,
+// in the backend w writes to
+func AddWebhookEvent(cmd *mamba.Command, w *utils.Writer, event string) error {
+	data := webhookData{
+		ModuleName: utils.GetGoModuleNameIn(w.Dir),
+		UUIDKey:    utils.UUIDKey(),
+		IDType:     utils.IDType(),
+	}
+
+	eventsPath := w.Path(webhooksDir, "events.go")
+	events, err := os.ReadFile(eventsPath)
+	if os.IsNotExist(err) {
+		for _, name := range []string{"dispatcher.go", "module.go"} {
+			path := w.Path(webhooksDir, name)
+			if _, err := os.Stat(path); err == nil {
+				continue
+			}
+			if err := writeWebhookTemplate(w, path, webhookTemplates[name], data); err != nil {
+				return err
+			}
+		}
+		var content bytes.Buffer
+		if err := webhookTemplates["events.go"].Execute(&content, data); err != nil {
+			return err
+		}
+		events = content.Bytes()
+
+		if err := addModuleToAppInit(w, "webhooks"); err != nil {
+			cmd.PrintWarning("Could not add the webhooks module to app/init.go")
+			cmd.PrintInfo("Manually add to app/init.go: modules[\"webhooks\"] = webhooks.Init(deps)")
+		}
+	} else if err != nil {
+		return err
+	}
+
+	source, model := WebhookSource(event)
+	if model != "" {
+		naming := utils.NewNamingConvention(model)
+		if _, err := os.Stat(w.Path("app", naming.PluralSnake)); os.IsNotExist(err) {
+			cmd.PrintWarning(fmt.Sprintf("No %s module yet: %s fires once app/%s emits %s", naming.Model, event, naming.PluralSnake, source))
+		}
+	}
+
+	return registerWebhookEvent(w, eventsPath, events, event, source)
+}
+
+// registerWebhookEvent adds an entry to the events map in events.go
+func registerWebhookEvent(w *utils.Writer, eventsPath string, content []byte, event, source string) error {
+	contentStr := string(content)
+	key := fmt.Sprintf("%q:", event)
+	if strings.Contains(contentStr, key) {
+		return nil // Already registered
+	}
+
+	mapStart := strings.Index(contentStr, "var events = map[string]string{")
+	if mapStart == -1 {
+		return fmt.Errorf("could not find the events map in %s", eventsPath)
+	}
+	mapEnd := strings.Index(contentStr[mapStart:], "\n}")
+	if mapEnd == -1 {
+		return fmt.Errorf("could not find the end of the events map in %s", eventsPath)
+	}
+	insertAt := mapStart + mapEnd + 1
+	entry := fmt.Sprintf("\t%s %q,\n", key, source)
+	contentStr = contentStr[:insertAt] + entry + contentStr[insertAt:]
+
+	return w.UpdateProjectFile(eventsPath, []byte(contentStr))
+}
+
+// writeWebhookTemplate renders a dispatcher template to path
+func writeWebhookTemplate(w *utils.Writer, path string, tmpl *template.Template, data webhookData) error {
+	var content bytes.Buffer
+	if err := tmpl.Execute(&content, data); err != nil {
+		return err
+	}
+	return w.WriteGeneratedFile(path, content.Bytes())
+}
+
+// webhookTemplates are the files of the app/webhooks package
+var webhookTemplates = map[string]*template.Template{
+	"events.go": template.Must(template.New("events.go").Parse(`package webhooks
+
+// events maps each outgoing webhook event to the emitter event that triggers it.
+// bui g webhook adds entries; endpoints subscribe by listing event names (or * for all).
+var events = map[string]string{
+}
+`)),
+
+	"module.go": template.Must(template.New("module.go").Parse(`package webhooks
+
+import (
+	"{{.ModuleName}}/core/emitter"
+	"{{.ModuleName}}/core/module"
+	"{{.ModuleName}}/core/router"
+)
+
+// Module forwards the events in events.go to the registered webhook endpoints
+type Module struct {
+	module.DefaultModule
+	Emitter    *emitter.Emitter
+	Dispatcher *Dispatcher
+}
+
+// Init creates the webhooks module around a Dispatcher
+func Init(deps module.Dependencies) module.Module {
+	return &Module{
+		Emitter:    deps.Emitter,
+		Dispatcher: NewDispatcher(deps.DB, deps.Logger),
+	}
+}
+
+// Routes registers nothing; endpoints and deliveries are managed by their own modules
+func (m *Module) Routes(router *router.RouterGroup) {}
+
+// Init subscribes to the source events and starts retrying failed deliveries
+func (m *Module) Init() error {
+	for event, source := range events {
+		m.Emitter.On(source, m.listener(event))
+	}
+	go m.Dispatcher.RetryLoop()
+	return nil
+}
+
+// listener dispatches event with the data of the emitter event behind it
+func (m *Module) listener(event string) func(data interface{}) {
+	return func(data interface{}) {
+		m.Dispatcher.Dispatch(event, data)
+	}
+}
+
+func (m *Module) Migrate() error {
+	return nil
+}
+
+func (m *Module) GetModels() []any {
+	return nil
+}
+`)),
+
+	"dispatcher.go": template.Must(template.New("dispatcher.go").Parse(`package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"{{.ModuleName}}/app/models"
+	"{{.ModuleName}}/core/logger"
+
+{{if .UUIDKey}}	"github.com/google/uuid"
+{{end}}	"gorm.io/gorm"
+)
+
+// retryDelays spaces out the attempts after the first; a delivery fails for good after the last
+var retryDelays = []time.Duration{time.Minute, 5 * time.Minute, 30 * time.Minute, 2 * time.Hour, 12 * time.Hour}
+
+const (
+	requestTimeout = 10 * time.Second
+	retryInterval  = 30 * time.Second
+	maxStoredBody  = 4096 // Bytes of each response kept in the delivery log
+)
+
+// Dispatcher signs and sends webhook deliveries, logging each attempt in webhook_deliveries
+type Dispatcher struct {
+	DB     *gorm.DB
+	Logger logger.Logger
+	Client *http.Client
+}
+
+// NewDispatcher creates a dispatcher with a request timeout
+func NewDispatcher(db *gorm.DB, log logger.Logger) *Dispatcher {
+	return &Dispatcher{
+		DB:     db,
+		Logger: log,
+		Client: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Dispatch logs a delivery for each active endpoint subscribed to event and sends them in the background
+func (d *Dispatcher) Dispatch(event string, data interface{}) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"event":      event,
+		"data":       data,
+		"created_at": time.Now().UTC(),
+	})
+	if err != nil {
+		d.Logger.Error("failed to encode webhook payload", logger.String("event", event), logger.String("error", err.Error()))
+		return
+	}
+
+	var endpoints []models.WebhookEndpoint
+	if err := d.DB.Where("active = ?", true).Find(&endpoints).Error; err != nil {
+		d.Logger.Error("failed to load webhook endpoints", logger.String("error", err.Error()))
+		return
+	}
+
+	for _, endpoint := range endpoints {
+		if !Subscribed(endpoint.Events, event) {
+			continue
+		}
+		endpointId := endpoint.Id
+		delivery := models.WebhookDelivery{
+			WebhookEndpointId: &endpointId,
+			Event:             event,
+			Payload:           string(payload),
+			Status:            models.WebhookDeliveryStatusPending,
+		}
+		if err := d.DB.Create(&delivery).Error; err != nil {
+			d.Logger.Error("failed to log webhook delivery", logger.String("event", event), logger.String("error", err.Error()))
+			continue
+		}
+		go d.Deliver(delivery.Id)
+	}
+}
+
+// Subscribed reports whether an endpoint's events list (comma or space separated) covers event.
+// An empty list or * matches everything, and order.* matches every order event.
+func Subscribed(list, event string) bool {
+	patterns := strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == ' ' || r == '\n' })
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if pattern == "*" || pattern == event {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(event, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Deliver makes one attempt at a delivery and records the outcome, scheduling a retry on failure
+func (d *Dispatcher) Deliver(id {{.IDType}}) {
+	var delivery models.WebhookDelivery
+	if err := d.DB.Preload("WebhookEndpoint").Where("id = ?", id).First(&delivery).Error; err != nil {
+		return
+	}
+
+	attempts := delivery.Attempts + 1
+	updates := map[string]interface{}{"attempts": attempts, "error": ""}
+	if delivery.WebhookEndpoint == nil {
+		updates["status"] = models.WebhookDeliveryStatusFailed
+		updates["error"] = "endpoint was deleted"
+		d.DB.Model(&models.WebhookDelivery{}).Where("id = ?", id).Updates(updates)
+		return
+	}
+
+	statusCode, body, err := d.send(delivery.WebhookEndpoint, &delivery)
+	updates["status_code"] = statusCode
+	updates["response_body"] = body
+	if err == nil && (statusCode < 200 || statusCode > 299) {
+		err = fmt.Errorf("endpoint responded with %d", statusCode)
+	}
+
+	switch {
+	case err == nil:
+		updates["status"] = models.WebhookDeliveryStatusSucceeded
+		updates["delivered_at"] = time.Now()
+		updates["next_retry_at"] = nil
+	case attempts > len(retryDelays):
+		updates["status"] = models.WebhookDeliveryStatusFailed
+		updates["error"] = err.Error()
+		updates["next_retry_at"] = nil
+	default:
+		updates["error"] = err.Error()
+		updates["next_retry_at"] = time.Now().Add(retryDelays[attempts-1])
+	}
+
+	if err := d.DB.Model(&models.WebhookDelivery{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		d.Logger.Error("failed to update webhook delivery", logger.String("error", err.Error()))
+	}
+}
+
+// RetryLoop resends pending deliveries whose retry time has come, until the process exits
+func (d *Dispatcher) RetryLoop() {
+	ticker := time.NewTicker(retryInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		var due []models.WebhookDelivery
+		now := time.Now()
+		if err := d.DB.Select("id", "next_retry_at").
+			Where("status = ? AND next_retry_at IS NOT NULL AND next_retry_at <= ?", models.WebhookDeliveryStatusPending, now).
+			Limit(100).Find(&due).Error; err != nil {
+			continue
+		}
+
+		for _, delivery := range due {
+			// Clearing next_retry_at claims the delivery, so other instances skip it
+			claim := d.DB.Model(&models.WebhookDelivery{}).
+				Where("id = ? AND next_retry_at IS NOT NULL AND next_retry_at <= ?", delivery.Id, now).
+				Update("next_retry_at", nil)
+			if claim.Error == nil && claim.RowsAffected == 1 {
+				d.Deliver(delivery.Id)
+			}
+		}
+	}
+}
+
+// send posts the payload with its signature headers and returns the response status and body
+func (d *Dispatcher) send(endpoint *models.WebhookEndpoint, delivery *models.WebhookDelivery) (int, string, error) {
+	req, err := http.NewRequest(http.MethodPost, endpoint.Url, strings.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, "", err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", delivery.Event)
+	req.Header.Set("X-Webhook-Delivery", {{if .UUIDKey}}delivery.Id.String(){{else}}strconv.FormatUint(uint64(delivery.Id), 10){{end}})
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+Sign(endpoint.Secret, timestamp, []byte(delivery.Payload)))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxStoredBody))
+	return resp.StatusCode, string(body), nil
+}
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<payload>" keyed with the endpoint secret.
+// Receivers recompute it from the X-Webhook-Timestamp header and the raw body.
+func Sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+`)),
+}
@@ -0,0 +1,102 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// swaggerTagPattern matches the swagger tag of a generated controller's endpoints
+var swaggerTagPattern = regexp.MustCompile(`// @Tags (.+)`)
+
+// moduleContext is what the templates that add endpoints to an existing module need to know of it
+type moduleContext struct {
+	ModuleName string
+	SwaggerTag string // Tag of the module's other endpoints
+	Tenant     bool   // The service counts the current organization's rows only
+	Scoped     bool   // The controller scopes the service to the request
+}
+
+// statsData fills in stats.tmpl
+type statsData struct {
+	*utils.NamingConvention
+	moduleContext
+}
+
+// readModuleContext reads the module's controller and service in the backend w writes to. It
+// returns the controller's path.
+func readModuleContext(w *utils.Writer, naming *utils.NamingConvention) (string, moduleContext, error) {
+	moduleDir := w.Path("app", naming.DirName)
+	controllerPath := filepath.Join(moduleDir, "controller.go")
+	controller, err := os.ReadFile(controllerPath)
+	if os.IsNotExist(err) {
+		return "", moduleContext{}, fmt.Errorf("no %s module in %s: generate it first with bui g %s", naming.Model, moduleDir, naming.ModelSnake)
+	} else if err != nil {
+		return "", moduleContext{}, err
+	}
+	service, err := os.ReadFile(filepath.Join(moduleDir, "service.go"))
+	if err != nil {
+		return "", moduleContext{}, err
+	}
+
+	module := moduleContext{
+		ModuleName: utils.GetGoModuleNameIn(w.Dir),
+		SwaggerTag: "App/" + naming.Tag,
+		Tenant:     strings.Contains(string(service), fmt.Sprintf("func (s *%s) scoped() ", naming.Service)),
+		Scoped:     strings.Contains(string(controller), fmt.Sprintf("func (c *%s) scoped(", naming.Controller)),
+	}
+	if match := swaggerTagPattern.FindStringSubmatch(string(controller)); match != nil {
+		module.SwaggerTag = match[1]
+	}
+	return controllerPath, module, nil
+}
+
+// GenerateStatsEndpoint writes app/<module>/stats.go with GET /<module>/stats, the counts the
+// dashboard widgets show, and adds the route to the module's existing controller, in the backend
+// w writes to
+func GenerateStatsEndpoint(cmd *mamba.Command, w *utils.Writer, naming *utils.NamingConvention) error {
+	controllerPath, module, err := readModuleContext(w, naming)
+	if err != nil {
+		return err
+	}
+	data := statsData{NamingConvention: naming, moduleContext: module}
+	if err := w.GenerateFileFromData(filepath.Dir(controllerPath), "stats.go", "stats.tmpl", data); err != nil {
+		return fmt.Errorf("failed to generate stats.go: %w", err)
+	}
+
+	return addCollectionRoute(cmd, w, controllerPath, naming, naming.RoutePath+"/stats", "Stats", "Dashboard counts")
+}
+
+// addCollectionRoute adds a GET route of the module's collection to its controller's Routes,
+// before the /:id routes so it isn't read as an id. Controllers with a policy check it with the
+// list permission.
+func addCollectionRoute(cmd *mamba.Command, w *utils.Writer, controllerPath string, naming *utils.NamingConvention, route, handler, comment string) error {
+	controller, err := os.ReadFile(controllerPath)
+	if err != nil {
+		return err
+	}
+	content := string(controller)
+	if strings.Contains(content, fmt.Sprintf("%q", route)) {
+		return nil
+	}
+	call := "c." + handler
+	if strings.Contains(content, "c.authorize(") {
+		call = fmt.Sprintf("c.authorize(PermissionList, c.%s)", handler)
+	}
+	line := fmt.Sprintf("\trouter.GET(%q, %s) // %s - MUST be before /:id\n", route, call, comment)
+
+	for _, anchor := range []string{fmt.Sprintf("%q", naming.RoutePath+"/all"), fmt.Sprintf("%q", naming.RoutePath+"/:id")} {
+		if i := strings.Index(content, anchor); i != -1 {
+			lineStart := strings.LastIndex(content[:i], "\n") + 1
+			return w.UpdateProjectFile(controllerPath, []byte(content[:lineStart]+line+content[lineStart:]))
+		}
+	}
+	cmd.PrintWarning("Could not find the routes of " + controllerPath)
+	cmd.PrintInfo(fmt.Sprintf("Manually add router.GET(%q, %s) to Routes, before the /:id routes", route, call))
+	return nil
+}
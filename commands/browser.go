@@ -0,0 +1,20 @@
+package commands
+
+import (
+	"os/exec"
+	"runtime"
+)
+
+// openBrowser opens url in the default browser
+func openBrowser(url string) error {
+	var openCmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		openCmd = exec.Command("open", url)
+	case "windows":
+		openCmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		openCmd = exec.Command("xdg-open", url)
+	}
+	return openCmd.Start()
+}
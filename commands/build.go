@@ -8,19 +8,62 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/base-al/bui/utils"
 	"github.com/base-go/mamba"
 	"github.com/base-go/mamba/pkg/spinner"
 )
 
+var (
+	// buildOS and buildArch cross-compile the backend for one platform
+	buildOS   string
+	buildArch string
+
+	// buildTargets cross-compiles the backend for several platforms, e.g. linux/amd64,darwin/arm64
+	buildTargets string
+)
+
 var buildCmd = &mamba.Command{
 	Use:   "build [backend|frontend]",
 	Short: "Build backend, frontend, or both",
 	Long: `Build the project for production.
 
+The backend is compiled for this machine unless --os/--arch or --targets say
+otherwise. With several targets each binary is named server-<os>-<arch>, and the
+Dockerfile runs the Linux one. Cross builds set CGO_ENABLED=0 unless it is already set.
+
+--docker builds an image from the dist directory (Linux binaries are built for it
+unless targets are given) and --push publishes it.
+
+--ssr runs the frontend's build script instead of generate and puts the Nuxt server
+in app/ with its own Dockerfile, plus a docker-compose.yml that runs both servers.
+
+--archive writes releases/<version>/<dist>-<version>-<os>-<arch>.tar.gz per target with
+a SHA256SUMS file. The version (--release, else git describe), commit, build time and
+bui version go into build-info.json and are linked into the backend as main.Version,
+main.CommitHash, main.BuildDate and main.BuiVersion when it declares them.
+
+--env <name> builds for an environment: the backend's .env.<name> becomes the dist .env
+(and must exist), and its NUXT_* values plus the frontend's .env.<name> are set while the
+frontend is generated, baking NUXT_PUBLIC_API_URL and friends in. The environment is
+stamped into the dist README and the Dockerfile's bui.environment label.
+
+--compose writes a production docker-compose.yml running the API with PostgreSQL and
+Redis (plus the Nuxt server with --ssr), with healthchecks and volumes for storage/ and
+the data. --proxy caddy|traefik adds a reverse proxy that gets a TLS certificate for
+--domain (or DOMAIN in .env).
+
 Examples:
   bui build              # Build both backend and frontend
   bui build backend      # Build backend only
-  bui build frontend     # Build frontend only`,
+  bui build frontend     # Build frontend only
+  bui build --os linux --arch amd64
+  bui build --targets linux/amd64,linux/arm64,darwin/arm64
+  bui build --docker --tag registry.example.com/shop:v1 --push
+  bui build --docker --targets linux/amd64,linux/arm64 --push   # Multi-platform via buildx
+  bui build --ssr        # Nuxt SSR server plus a compose file running it next to the API
+  bui build --archive --release v1.2.3 --targets linux/amd64,linux/arm64
+  bui build --compose --proxy caddy --domain admin.example.com
+  bui build --env staging   # .env.staging instead of .env`,
 	Run: buildBoth,
 }
 
@@ -40,6 +83,9 @@ func init() {
 	rootCmd.AddCommand(buildCmd)
 	buildCmd.AddCommand(buildBackendCmd)
 	buildCmd.AddCommand(buildFrontendCmd)
+	buildCmd.Flags().StringVar(&buildOS, "os", "", "Target operating system for the backend (GOOS)")
+	buildCmd.Flags().StringVar(&buildArch, "arch", "", "Target architecture for the backend (GOARCH)")
+	buildCmd.Flags().StringVar(&buildTargets, "targets", "", "Comma-separated os/arch targets, e.g. linux/amd64,darwin/arm64")
 }
 
 func buildBoth(cmd *mamba.Command, args []string) {
@@ -54,6 +100,51 @@ func buildBoth(cmd *mamba.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if buildPush {
+		buildDocker = true
+	}
+	if buildDocker && backendDir == "" {
+		cmd.PrintError("--docker needs a backend to build the image around")
+		os.Exit(1)
+	}
+	// Images run Linux binaries, whatever the host is
+	if buildDocker && buildOS == "" && buildTargets == "" {
+		buildOS = "linux"
+	}
+
+	targets, err := parseBuildTargets(buildOS, buildArch, buildTargets)
+	if err != nil {
+		cmd.PrintError(err.Error())
+		os.Exit(1)
+	}
+	if buildDocker && len(linuxPlatforms(targets)) == 0 {
+		cmd.PrintError("--docker needs a Linux build; add --os linux or a linux/<arch> target")
+		os.Exit(1)
+	}
+	if err := validateComposeFlags(); err != nil {
+		cmd.PrintError(err.Error())
+		os.Exit(1)
+	}
+	if err := validateBuildEnv(backendDir); err != nil {
+		cmd.PrintError(err.Error())
+		os.Exit(1)
+	}
+	if buildEnv != "" {
+		cmd.PrintInfo("Building for " + buildEnv + " (" + buildEnvFileName() + ")")
+	}
+
+	// Archived builds record their version and commit
+	var info projectBuildInfo
+	ldflags := ""
+	if buildArchive {
+		gitDir := backendDir
+		if gitDir == "" {
+			gitDir = frontendDir
+		}
+		info = newProjectBuildInfo(gitDir)
+		ldflags = info.ldflags()
+	}
+
 	// Determine dist directory name based on project structure
 	distDir := determineDistDir(backendDir, frontendDir)
 
@@ -64,29 +155,53 @@ func buildBoth(cmd *mamba.Command, args []string) {
 
 	// Build backend
 	if backendDir != "" {
-		buildBackendToDist(cmd, backendDir, distDir)
+		buildBackendToDist(cmd, backendDir, distDir, targets, ldflags)
 	}
 
 	// Build frontend
 	if frontendDir != "" {
-		buildFrontendToDist(cmd, frontendDir, distDir)
+		buildFrontendToDist(cmd, frontendDir, distDir, frontendBuildEnv(frontendDir, backendDir))
 	}
 
 	// Create deployment files
 	if backendDir != "" && frontendDir != "" {
-		createDeploymentFiles(cmd, backendDir, distDir)
+		createDeploymentFiles(cmd, backendDir, distDir, targets)
+		if buildSSR {
+			createSSRDeploymentFiles(cmd, distDir)
+		}
 		cmd.PrintInfo("")
 		cmd.PrintSuccess("Production build complete!")
 		cmd.PrintInfo("")
 		cmd.PrintHeader("Deployment Files")
-		cmd.PrintBullet("Backend binary: " + distDir + "/server")
-		cmd.PrintBullet("Frontend files: " + distDir + "/public/")
+		for _, target := range targets {
+			cmd.PrintBullet(fmt.Sprintf("Backend binary (%s): %s/%s", target, distDir, target.binaryName(len(targets) > 1)))
+		}
+		if buildSSR {
+			cmd.PrintBullet("Frontend server: " + distDir + "/" + ssrAppDir + "/ (node server/index.mjs)")
+		} else {
+			cmd.PrintBullet("Frontend files: " + distDir + "/public/")
+		}
 		cmd.PrintBullet("Dockerfile: " + distDir + "/Dockerfile")
+		if buildSSR || buildCompose {
+			cmd.PrintBullet("Compose file: " + distDir + "/docker-compose.yml")
+		}
 		cmd.PrintBullet("CapRover config: " + distDir + "/captain-definition.json")
 		cmd.PrintInfo("")
 	} else {
+		if backendDir != "" && (buildDocker || buildCompose) {
+			// The Dockerfile is otherwise only written for full builds
+			createDeploymentFiles(cmd, backendDir, distDir, targets)
+		}
 		cmd.PrintSuccess("Build complete in " + distDir + "/")
 	}
+
+	if buildArchive {
+		archiveDist(cmd, distDir, targets, info)
+	}
+
+	if buildDocker {
+		buildDockerImage(cmd, distDir, targets)
+	}
 }
 
 func buildBackend(cmd *mamba.Command, args []string) {
@@ -125,7 +240,7 @@ func buildFrontend(cmd *mamba.Command, args []string) {
 
 	// Build Nuxt app with spinner
 	err := spinner.WithSpinner("Building frontend...", func() error {
-		buildCmd := exec.Command("bun", "run", "build")
+		buildCmd := packageScript("build")
 		buildCmd.Dir = frontendDir
 		return buildCmd.Run()
 	})
@@ -193,6 +308,10 @@ func determineDistDir(backendDir, frontendDir string) string {
 
 // detectBackendDir finds the backend directory
 func detectBackendDir() string {
+	if dir := utils.Project.BackendDir(); dir != "" {
+		return dir
+	}
+
 	candidates := []string{
 		"admin-api-template",
 		"admin-api",
@@ -219,6 +338,10 @@ func detectBackendDir() string {
 
 // detectFrontendDir finds the frontend directory
 func detectFrontendDir() string {
+	if dir := utils.Project.FrontendDir(); dir != "" {
+		return dir
+	}
+
 	candidates := []string{
 		"admin-template",
 		"admin",
@@ -243,24 +366,40 @@ func detectFrontendDir() string {
 	return ""
 }
 
-// buildBackendToDist builds the backend to distDir/server
-func buildBackendToDist(cmd *mamba.Command, backendDir, distDir string) {
+// buildBackendToDist builds the backend for each target into distDir, linking with ldflags when set
+func buildBackendToDist(cmd *mamba.Command, backendDir, distDir string, targets []buildTarget, ldflags string) {
 	cmd.PrintInfo("Building backend...")
 
 	// Generate Swagger docs
 	generateSwaggerDocsForBuild(cmd, backendDir)
 
-	// Build binary
-	err := spinner.WithSpinner("Compiling backend binary...", func() error {
-		outputPath := filepath.Join("..", distDir, "server")
-		buildCmd := exec.Command("go", "build", "-o", outputPath, "main.go")
-		buildCmd.Dir = backendDir
-		return buildCmd.Run()
-	})
+	// Build a binary per target
+	absDistDir, _ := filepath.Abs(distDir)
+	for _, target := range targets {
+		message := "Compiling backend binary..."
+		if !target.native() || len(targets) > 1 {
+			message = fmt.Sprintf("Compiling backend binary for %s...", target)
+		}
+		err := spinner.WithSpinner(message, func() error {
+			outputPath := filepath.Join(absDistDir, target.binaryName(len(targets) > 1))
+			buildArgs := []string{"build", "-o", outputPath}
+			if ldflags != "" {
+				buildArgs = append(buildArgs, "-ldflags", ldflags)
+			}
+			buildCmd := exec.Command("go", append(buildArgs, "main.go")...)
+			buildCmd.Dir = backendDir
+			buildCmd.Env = target.env()
+			output, err := buildCmd.CombinedOutput()
+			if err != nil && len(output) > 0 {
+				return fmt.Errorf("%w\n%s", err, strings.TrimSpace(string(output)))
+			}
+			return err
+		})
 
-	if err != nil {
-		cmd.PrintError("Failed to build backend: " + err.Error())
-		os.Exit(1)
+		if err != nil {
+			cmd.PrintError(fmt.Sprintf("Failed to build backend for %s: %v", target, err))
+			os.Exit(1)
+		}
 	}
 
 	// Copy necessary directories
@@ -277,23 +416,33 @@ func buildBackendToDist(cmd *mamba.Command, backendDir, distDir string) {
 		copyFile(filepath.Join(backendDir, ".env.example"), filepath.Join(distDir, ".env.example"))
 	}
 
-	// Copy .env if exists (for local testing)
-	if fileExistsBuild(filepath.Join(backendDir, ".env")) {
-		copyFile(filepath.Join(backendDir, ".env"), filepath.Join(distDir, ".env"))
-		cmd.PrintInfo("Copied .env for local preview")
+	// Copy .env, or .env.<env> with --env, as the build's .env
+	if envFile := filepath.Join(backendDir, buildEnvFileName()); fileExistsBuild(envFile) {
+		copyFile(envFile, filepath.Join(distDir, ".env"))
+		if buildEnv != "" {
+			cmd.PrintInfo("Copied " + buildEnvFileName() + " as .env")
+		} else {
+			cmd.PrintInfo("Copied .env for local preview")
+		}
 	}
 
 	cmd.PrintSuccess("Backend built successfully")
 }
 
-// buildFrontendToDist builds the frontend to distDir/public
-func buildFrontendToDist(cmd *mamba.Command, frontendDir, distDir string) {
+// buildFrontendToDist builds the frontend to distDir/public; env, when set, replaces the generate environment
+func buildFrontendToDist(cmd *mamba.Command, frontendDir, distDir string, env []string) {
+	if buildSSR {
+		buildSSRFrontendToDist(cmd, frontendDir, distDir, env)
+		return
+	}
+
 	cmd.PrintInfo("Building frontend...")
 
 	// Run nuxt generate
 	err := spinner.WithSpinner("Generating static frontend...", func() error {
-		generateCmd := exec.Command("bun", "run", "generate")
+		generateCmd := packageScript("generate")
 		generateCmd.Dir = frontendDir
+		generateCmd.Env = env
 		generateCmd.Stdout = os.Stdout
 		generateCmd.Stderr = os.Stderr
 		return generateCmd.Run()
@@ -315,13 +464,28 @@ func buildFrontendToDist(cmd *mamba.Command, frontendDir, distDir string) {
 	}
 }
 
-// createDeploymentFiles creates Dockerfile and captain-definition.json
-func createDeploymentFiles(cmd *mamba.Command, _ string, distDir string) {
+// createDeploymentFiles creates Dockerfile and captain-definition.json for the backend targets
+func createDeploymentFiles(cmd *mamba.Command, _ string, distDir string, targets []buildTarget) {
 	cmd.PrintInfo("Creating deployment files...")
 
+	binary := dockerBinary(targets)
+	imageBinary := binary
+	selectBinary := `# Make binary executable
+RUN chmod +x ./` + binary
+	if platforms := linuxPlatforms(targets); len(platforms) > 1 {
+		// A multi-platform image picks the binary built for the platform being built
+		_, defaultArch, _ := strings.Cut(strings.TrimSuffix(binary, ".exe"), "server-linux-")
+		selectBinary = `# Pick the binary for the platform being built
+ARG TARGETARCH=` + defaultArch + `
+RUN cp ./server-linux-${TARGETARCH} ./server && chmod +x ./server`
+		imageBinary = "server"
+	}
+
 	// Create Dockerfile
 	dockerfile := `FROM alpine:latest
 
+LABEL bui.environment="` + buildEnvironment() + `"
+
 # Install ca-certificates for HTTPS requests
 RUN apk --no-cache add ca-certificates tzdata
 
@@ -330,14 +494,13 @@ WORKDIR /app
 # Copy everything from dist
 COPY . .
 
-# Make binary executable
-RUN chmod +x ./server
+` + selectBinary + `
 
 # Expose port
 EXPOSE 8000
 
 # Run the binary
-CMD ["./server"]
+CMD ["./` + imageBinary + `"]
 `
 	os.WriteFile(filepath.Join(distDir, "Dockerfile"), []byte(dockerfile), 0644)
 
@@ -363,8 +526,10 @@ storage/upload/*
 
 This directory contains a complete production build ready for deployment.
 
+Environment: ` + buildEnvironment() + ` (.env copied from ` + buildEnvFileName() + `)
+
 ## Structure
-- server - Backend binary
+- ` + binary + ` - Backend binary
 - public/ - Frontend static files
 - swag/ - Swagger documentation
 - templates/ - Email templates
@@ -389,7 +554,7 @@ docker run -p 8000:8000 --env-file .env myapp
 ### Direct Deployment
 1. Copy this directory to your server
 2. Create .env file with production settings
-3. Run: ./server
+3. Run: ./` + binary + `
 
 ## Environment Variables
 Copy .env.example to .env and configure:
@@ -401,6 +566,10 @@ Copy .env.example to .env and configure:
 
 	os.WriteFile(filepath.Join(distDir, "README.md"), []byte(readme), 0644)
 
+	if buildCompose {
+		createProductionCompose(cmd, distDir)
+	}
+
 	cmd.PrintSuccess("Deployment files created")
 }
 
@@ -0,0 +1,190 @@
+package commands
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/base-al/bui/version"
+	"github.com/base-go/mamba"
+)
+
+var (
+	// buildArchive packs the dist directory into a versioned tar.gz per target
+	buildArchive bool
+
+	// buildRelease is the version in archive names; it defaults to git describe
+	buildRelease string
+)
+
+// releasesDir holds the archives and SHA256SUMS, one directory per version
+const releasesDir = "releases"
+
+// buildInfoFile is written into the dist directory of archived builds
+const buildInfoFile = "build-info.json"
+
+func init() {
+	buildCmd.Flags().BoolVar(&buildArchive, "archive", false, "Write versioned tar.gz archives per target with SHA256SUMS")
+	buildCmd.Flags().StringVar(&buildRelease, "release", "", "Version for --archive names and build info (default: git describe)")
+}
+
+// projectBuildInfo describes a build; it is written to build-info.json and injected into the backend
+type projectBuildInfo struct {
+	Version    string `json:"version"`
+	CommitHash string `json:"commit_hash"`
+	BuildDate  string `json:"build_date"`
+	BuiVersion string `json:"bui_version"`
+}
+
+// newProjectBuildInfo collects the version and commit from git in dir
+func newProjectBuildInfo(dir string) projectBuildInfo {
+	info := projectBuildInfo{
+		Version:    buildRelease,
+		CommitHash: "unknown",
+		BuildDate:  time.Now().UTC().Format(time.RFC3339),
+		BuiVersion: version.Version,
+	}
+	if commit, err := gitOutput(dir, "rev-parse", "--short", "HEAD"); err == nil {
+		info.CommitHash = commit
+	}
+	if info.Version == "" {
+		info.Version = "dev"
+		if described, err := gitOutput(dir, "describe", "--tags", "--always", "--dirty"); err == nil {
+			info.Version = described
+		}
+	}
+	return info
+}
+
+// ldflags sets main.Version, main.CommitHash, main.BuildDate and main.BuiVersion in the backend
+// binary; variables the backend doesn't declare are ignored by the linker
+func (info projectBuildInfo) ldflags() string {
+	return fmt.Sprintf("-X 'main.Version=%s' -X 'main.CommitHash=%s' -X 'main.BuildDate=%s' -X 'main.BuiVersion=%s'",
+		info.Version, info.CommitHash, info.BuildDate, info.BuiVersion)
+}
+
+// gitOutput runs git in dir and returns its trimmed output
+func gitOutput(dir string, args ...string) (string, error) {
+	gitCmd := exec.Command("git", args...)
+	gitCmd.Dir = dir
+	out, err := gitCmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// archiveDist writes build-info.json into distDir, packs it into one archive per target under
+// releases/<version>/ and lists their checksums in SHA256SUMS
+func archiveDist(cmd *mamba.Command, distDir string, targets []buildTarget, info projectBuildInfo) {
+	cmd.PrintHeader("Release Archives")
+
+	data, _ := json.MarshalIndent(info, "", "  ")
+	if err := os.WriteFile(filepath.Join(distDir, buildInfoFile), append(data, '\n'), 0644); err != nil {
+		cmd.PrintError("Failed to write build info: " + err.Error())
+		os.Exit(1)
+	}
+
+	// Tags such as release/1.2 would otherwise add directories
+	release := strings.ReplaceAll(info.Version, "/", "-")
+	outDir := filepath.Join(releasesDir, release)
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		cmd.PrintError("Failed to create " + outDir + ": " + err.Error())
+		os.Exit(1)
+	}
+
+	multiple := len(targets) > 1
+	var sums []string
+	for _, target := range targets {
+		name := fmt.Sprintf("%s-%s-%s-%s", filepath.Base(distDir), release, target.goos(), target.goarch())
+		archivePath := filepath.Join(outDir, name+".tar.gz")
+
+		// Each archive carries only its own target's binary
+		skip := map[string]bool{}
+		for _, other := range targets {
+			if other != target {
+				skip[other.binaryName(multiple)] = true
+			}
+		}
+
+		sum, err := writeTarGz(archivePath, distDir, name, skip)
+		if err != nil {
+			cmd.PrintError(fmt.Sprintf("Failed to archive %s: %v", target, err))
+			os.Exit(1)
+		}
+		sums = append(sums, sum+"  "+filepath.Base(archivePath))
+		cmd.PrintBullet(archivePath)
+	}
+
+	sumsPath := filepath.Join(outDir, "SHA256SUMS")
+	if err := os.WriteFile(sumsPath, []byte(strings.Join(sums, "\n")+"\n"), 0644); err != nil {
+		cmd.PrintError("Failed to write SHA256SUMS: " + err.Error())
+		os.Exit(1)
+	}
+	cmd.PrintBullet(sumsPath)
+	cmd.PrintSuccess(fmt.Sprintf("Release %s archived (commit %s)", info.Version, info.CommitHash))
+}
+
+// writeTarGz packs srcDir under the prefix directory into a gzipped tarball, leaving out the
+// top-level files in skip, and returns the archive's SHA-256
+func writeTarGz(archivePath, srcDir, prefix string, skip map[string]bool) (string, error) {
+	file, err := os.Create(archivePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	gz := gzip.NewWriter(io.MultiWriter(file, hash))
+	tw := tar.NewWriter(gz)
+
+	err = filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(srcDir, path)
+		if skip[rel] {
+			return nil
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(filepath.Join(prefix, rel))
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := tw.Close(); err != nil {
+		return "", err
+	}
+	if err := gz.Close(); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
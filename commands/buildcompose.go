@@ -0,0 +1,280 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+var (
+	// buildCompose writes a production docker-compose.yml with the database and Redis into the dist
+	buildCompose bool
+
+	// buildProxy adds a caddy or traefik reverse proxy with TLS to the compose file
+	buildProxy string
+
+	// buildDomain is the domain the proxy requests a certificate for
+	buildDomain string
+)
+
+func init() {
+	buildCmd.Flags().BoolVar(&buildCompose, "compose", false, "Write a production docker-compose.yml with the database, Redis and healthchecks")
+	buildCmd.Flags().StringVar(&buildProxy, "proxy", "", "Add a reverse proxy with TLS to --compose: caddy or traefik")
+	buildCmd.Flags().StringVar(&buildDomain, "domain", "", "Domain for the --proxy certificate (can also be set as DOMAIN in .env)")
+}
+
+// productionComposeData fills in productionComposeTemplate
+type productionComposeData struct {
+	Project  string
+	Database string // postgres or mysql run as a service; SQLite lives in the storage volume
+	SSR      bool
+	Proxy    string
+	Domain   string
+}
+
+var productionComposeTemplate = template.Must(template.New("docker-compose.yml").Parse(`# Production stack generated by bui build --compose.
+# Settings come from .env next to this file{{if ne .Database "sqlite"}}; DB_PASSWORD must be set{{end}}.
+name: {{.Project}}
+
+services:
+  api:
+    build: .
+    env_file:
+      - path: .env
+        required: false
+    environment:
+{{- if eq .Database "mysql"}}
+      DB_DRIVER: mysql
+      DB_HOST: mysql
+      DB_PORT: 3306
+      DB_USER: ${DB_USER:-root}
+      DB_PASSWORD: ${DB_PASSWORD:?DB_PASSWORD must be set in .env}
+      DB_NAME: ${DB_NAME:-{{.Project}}}
+{{- else if eq .Database "sqlite"}}
+      DB_DRIVER: sqlite
+      DB_PATH: /app/storage/{{.Project}}.db
+{{- else}}
+      DB_HOST: postgres
+      DB_PORT: 5432
+      DB_USER: ${DB_USER:-postgres}
+      DB_PASSWORD: ${DB_PASSWORD:?DB_PASSWORD must be set in .env}
+      DB_NAME: ${DB_NAME:-{{.Project}}}
+{{- end}}
+      REDIS_HOST: redis
+      REDIS_PORT: 6379
+    volumes:
+      - storage:/app/storage
+{{- if not .Proxy}}
+    ports:
+      - "8000:8000"
+{{- end}}
+{{- if eq .Proxy "traefik"}}
+    labels:
+      traefik.enable: "true"
+      traefik.http.routers.api.rule: Host(` + "`${DOMAIN:-{{.Domain}}}`" + `){{if .SSR}} && PathPrefix(` + "`/api`" + `){{end}}
+      traefik.http.routers.api.entrypoints: websecure
+      traefik.http.routers.api.tls.certresolver: letsencrypt
+      traefik.http.services.api.loadbalancer.server.port: "8000"
+{{- end}}
+    depends_on:
+{{- if ne .Database "sqlite"}}
+      {{.Database}}:
+        condition: service_healthy
+{{- end}}
+      redis:
+        condition: service_healthy
+    healthcheck:
+      test: ["CMD", "wget", "-q", "--spider", "http://localhost:8000/health"]
+      interval: 10s
+      timeout: 5s
+      retries: 5
+    restart: unless-stopped
+{{- if .SSR}}
+
+  app:
+    build: ./app
+    environment:
+      PORT: 3000
+{{- if not .Proxy}}
+    ports:
+      - "3000:3000"
+{{- end}}
+{{- if eq .Proxy "traefik"}}
+    labels:
+      traefik.enable: "true"
+      traefik.http.routers.app.rule: Host(` + "`${DOMAIN:-{{.Domain}}}`" + `)
+      traefik.http.routers.app.entrypoints: websecure
+      traefik.http.routers.app.tls.certresolver: letsencrypt
+      traefik.http.services.app.loadbalancer.server.port: "3000"
+{{- end}}
+    depends_on:
+      api:
+        condition: service_healthy
+    restart: unless-stopped
+{{- end}}
+{{- if eq .Database "postgres"}}
+
+  postgres:
+    image: postgres:16-alpine
+    environment:
+      POSTGRES_USER: ${DB_USER:-postgres}
+      POSTGRES_PASSWORD: ${DB_PASSWORD:?DB_PASSWORD must be set in .env}
+      POSTGRES_DB: ${DB_NAME:-{{.Project}}}
+    volumes:
+      - postgres-data:/var/lib/postgresql/data
+    healthcheck:
+      test: ["CMD-SHELL", "pg_isready -U $${POSTGRES_USER} -d $${POSTGRES_DB}"]
+      interval: 5s
+      timeout: 5s
+      retries: 10
+    restart: unless-stopped
+{{- else if eq .Database "mysql"}}
+
+  mysql:
+    image: mysql:8.4
+    environment:
+      MYSQL_ROOT_PASSWORD: ${DB_PASSWORD:?DB_PASSWORD must be set in .env}
+      MYSQL_DATABASE: ${DB_NAME:-{{.Project}}}
+    volumes:
+      - mysql-data:/var/lib/mysql
+    healthcheck:
+      test: ["CMD", "mysqladmin", "ping", "-h", "127.0.0.1"]
+      interval: 5s
+      timeout: 5s
+      retries: 20
+    restart: unless-stopped
+{{- end}}
+
+  redis:
+    image: redis:7-alpine
+    volumes:
+      - redis-data:/data
+    healthcheck:
+      test: ["CMD", "redis-cli", "ping"]
+      interval: 5s
+      timeout: 5s
+      retries: 10
+    restart: unless-stopped
+{{- if eq .Proxy "caddy"}}
+
+  caddy:
+    image: caddy:2-alpine
+    environment:
+      DOMAIN: ${DOMAIN:-{{.Domain}}}
+    ports:
+      - "80:80"
+      - "443:443"
+      - "443:443/udp"
+    volumes:
+      - ./Caddyfile:/etc/caddy/Caddyfile:ro
+      - caddy-data:/data
+      - caddy-config:/config
+    depends_on:
+      - api
+    restart: unless-stopped
+{{- end}}
+{{- if eq .Proxy "traefik"}}
+
+  traefik:
+    image: traefik:v3.1
+    command:
+      - --providers.docker=true
+      - --providers.docker.exposedbydefault=false
+      - --entrypoints.web.address=:80
+      - --entrypoints.web.http.redirections.entrypoint.to=websecure
+      - --entrypoints.websecure.address=:443
+      - --certificatesresolvers.letsencrypt.acme.httpchallenge.entrypoint=web
+      - --certificatesresolvers.letsencrypt.acme.email=${ACME_EMAIL:?ACME_EMAIL must be set in .env}
+      - --certificatesresolvers.letsencrypt.acme.storage=/letsencrypt/acme.json
+    ports:
+      - "80:80"
+      - "443:443"
+    volumes:
+      - /var/run/docker.sock:/var/run/docker.sock:ro
+      - letsencrypt:/letsencrypt
+    restart: unless-stopped
+{{- end}}
+
+volumes:
+  storage:
+{{- if ne .Database "sqlite"}}
+  {{.Database}}-data:
+{{- end}}
+  redis-data:
+{{- if eq .Proxy "caddy"}}
+  caddy-data:
+  caddy-config:
+{{- end}}
+{{- if eq .Proxy "traefik"}}
+  letsencrypt:
+{{- end}}
+`))
+
+// caddyfileTemplate routes the domain to the API, or to the Nuxt server with /api going to the API
+var caddyfileTemplate = template.Must(template.New("Caddyfile").Parse(`{$DOMAIN} {
+	encode gzip
+{{- if .SSR}}
+	handle /api/* {
+		reverse_proxy api:8000
+	}
+	handle {
+		reverse_proxy app:3000
+	}
+{{- else}}
+	reverse_proxy api:8000
+{{- end}}
+}
+`))
+
+// validateComposeFlags checks --proxy and --domain before anything is built
+func validateComposeFlags() error {
+	if buildProxy == "" {
+		return nil
+	}
+	if !buildCompose {
+		return fmt.Errorf("--proxy needs --compose")
+	}
+	if buildProxy != "caddy" && buildProxy != "traefik" {
+		return fmt.Errorf("unknown proxy %q (use caddy or traefik)", buildProxy)
+	}
+	return nil
+}
+
+// createProductionCompose writes docker-compose.yml, and a Caddyfile for --proxy caddy, into distDir
+func createProductionCompose(cmd *mamba.Command, distDir string) {
+	project := deployProjectName(distDir)
+	domain := buildDomain
+	if domain == "" {
+		domain = "example.com"
+	}
+	data := productionComposeData{
+		Project:  project,
+		Database: utils.Database(),
+		SSR:      buildSSR && dirExists(filepath.Join(distDir, ssrAppDir)),
+		Proxy:    buildProxy,
+		Domain:   domain,
+	}
+
+	files := map[string]*template.Template{"docker-compose.yml": productionComposeTemplate}
+	if buildProxy == "caddy" {
+		files["Caddyfile"] = caddyfileTemplate
+	}
+	for name, tmpl := range files {
+		var content strings.Builder
+		if err := tmpl.Execute(&content, data); err != nil {
+			cmd.PrintError(fmt.Sprintf("Failed to render %s: %v", name, err))
+			os.Exit(1)
+		}
+		os.WriteFile(filepath.Join(distDir, name), []byte(content.String()), 0644)
+	}
+
+	cmd.PrintSuccess("Production compose file created")
+	if buildProxy != "" && buildDomain == "" {
+		cmd.PrintWarning("No --domain given; set DOMAIN in " + filepath.Join(distDir, ".env") + " before starting the stack")
+	}
+}
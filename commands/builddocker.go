@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/base-go/mamba"
+)
+
+var (
+	// buildDocker builds a Docker image from the dist directory
+	buildDocker bool
+
+	// buildTag names the image; it defaults to <project>:latest
+	buildTag string
+
+	// buildPush pushes the image after building it
+	buildPush bool
+)
+
+func init() {
+	buildCmd.Flags().BoolVar(&buildDocker, "docker", false, "Build a Docker image from the dist directory")
+	buildCmd.Flags().StringVar(&buildTag, "tag", "", "Image tag for --docker, e.g. registry.example.com/app:v1 (default <project>:latest)")
+	buildCmd.Flags().BoolVar(&buildPush, "push", false, "Push the image after building it (implies --docker)")
+}
+
+// linuxPlatforms returns the Linux targets as Docker platforms, e.g. linux/amd64
+func linuxPlatforms(targets []buildTarget) []string {
+	var platforms []string
+	for _, target := range targets {
+		if target.goos() == "linux" {
+			platforms = append(platforms, target.String())
+		}
+	}
+	return platforms
+}
+
+// dockerImageTag returns --tag, or the project name derived from the dist directory
+func dockerImageTag(distDir string) string {
+	if buildTag != "" {
+		return buildTag
+	}
+	name := strings.TrimSuffix(filepath.Base(distDir), "-dist")
+	if name == "dist" {
+		if wd, err := os.Getwd(); err == nil {
+			name = filepath.Base(wd)
+		}
+	}
+	name = strings.Trim(composeNameInvalid.ReplaceAllString(strings.ToLower(name), "-"), "-_")
+	if name == "" {
+		name = "app"
+	}
+	return name + ":latest"
+}
+
+// buildDockerImage builds the image from distDir for its Linux targets and pushes it with --push.
+// Several Linux targets produce a multi-platform image through docker buildx.
+func buildDockerImage(cmd *mamba.Command, distDir string, targets []buildTarget) {
+	if _, err := exec.LookPath("docker"); err != nil {
+		cmd.PrintError("docker not found on PATH")
+		os.Exit(1)
+	}
+
+	platforms := linuxPlatforms(targets)
+	tag := dockerImageTag(distDir)
+	cmd.PrintHeader("Docker Image")
+	if len(platforms) > 1 && !buildPush {
+		cmd.PrintWarning("A multi-platform image stays in the build cache unless it is pushed; add --push to publish it")
+	}
+
+	buildImage(cmd, distDir, tag, platforms)
+	// An SSR build runs the Nuxt server from its own image
+	if appDir := filepath.Join(distDir, ssrAppDir); fileExistsBuild(filepath.Join(appDir, "Dockerfile")) {
+		buildImage(cmd, appDir, appImageTag(tag), platforms)
+	}
+}
+
+// buildImage builds and, with --push, pushes one image from the Dockerfile in dir
+func buildImage(cmd *mamba.Command, dir, tag string, platforms []string) {
+	var args []string
+	if len(platforms) > 1 {
+		args = []string{"buildx", "build", "--platform", strings.Join(platforms, ","), "-t", tag}
+		if buildPush {
+			args = append(args, "--push")
+		}
+	} else {
+		args = []string{"build", "--platform", platforms[0], "-t", tag}
+	}
+
+	cmd.PrintInfo("Building " + tag + " for " + strings.Join(platforms, ", ") + "...")
+	if err := runDocker(dir, append(args, ".")...); err != nil {
+		cmd.PrintError("docker build failed: " + err.Error())
+		os.Exit(1)
+	}
+	cmd.PrintSuccess("Built image " + tag)
+
+	if buildPush && len(platforms) == 1 {
+		cmd.PrintInfo("Pushing " + tag + "...")
+		if err := runDocker(dir, "push", tag); err != nil {
+			cmd.PrintError("docker push failed: " + err.Error())
+			os.Exit(1)
+		}
+	}
+	if buildPush {
+		cmd.PrintSuccess("Pushed " + tag)
+	}
+}
+
+// runDocker runs a docker command in dir with its output shown
+func runDocker(dir string, args ...string) error {
+	dockerCmd := exec.Command("docker", args...)
+	dockerCmd.Dir = dir
+	dockerCmd.Stdout = os.Stdout
+	dockerCmd.Stderr = os.Stderr
+	return dockerCmd.Run()
+}
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// buildEnv selects .env.<name> for the build instead of .env
+var buildEnv string
+
+// envNameValid limits --env to names that make sensible file suffixes
+var envNameValid = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+func init() {
+	buildCmd.Flags().StringVar(&buildEnv, "env", "", "Build for an environment: use .env.<env> and bake its NUXT_* values into the frontend")
+}
+
+// buildEnvironment names the environment the build is for
+func buildEnvironment() string {
+	if buildEnv == "" {
+		return "production"
+	}
+	return buildEnv
+}
+
+// buildEnvFileName is the env file the build reads: .env, or .env.<env> with --env
+func buildEnvFileName() string {
+	if buildEnv == "" {
+		return ".env"
+	}
+	return ".env." + buildEnv
+}
+
+// validateBuildEnv checks --env before anything is built; the backend must have the env file,
+// so a build never silently ships the development .env
+func validateBuildEnv(backendDir string) error {
+	if buildEnv == "" {
+		return nil
+	}
+	if !envNameValid.MatchString(buildEnv) {
+		return fmt.Errorf("invalid environment name %q", buildEnv)
+	}
+	if backendDir != "" {
+		if path := filepath.Join(backendDir, buildEnvFileName()); !fileExistsBuild(path) {
+			return fmt.Errorf("%s not found; create it or drop --env", path)
+		}
+	}
+	return nil
+}
+
+// frontendBuildEnv returns the environment for nuxt generate/build. With --env the NUXT_* values
+// from the backend's env file are passed through, and the frontend's own .env.<env> is applied on
+// top, so runtime config is baked in for that environment. It returns nil without --env.
+func frontendBuildEnv(frontendDir, backendDir string) []string {
+	if buildEnv == "" {
+		return nil
+	}
+
+	values := map[string]string{}
+	if backendDir != "" {
+		for key, value := range readEnvFile(filepath.Join(backendDir, buildEnvFileName())) {
+			if strings.HasPrefix(key, "NUXT_") {
+				values[key] = value
+			}
+		}
+	}
+	for key, value := range readEnvFile(filepath.Join(frontendDir, buildEnvFileName())) {
+		values[key] = value
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// Later entries win, so these override anything set in the shell
+	env := os.Environ()
+	for _, key := range keys {
+		env = append(env, key+"="+values[key])
+	}
+	return env
+}
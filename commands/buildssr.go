@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/base-go/mamba"
+	"github.com/base-go/mamba/pkg/spinner"
+)
+
+// buildSSR builds the frontend as a Nuxt server instead of static files
+var buildSSR bool
+
+// ssrAppDir is where the Nuxt server output goes inside the dist directory
+const ssrAppDir = "app"
+
+func init() {
+	buildCmd.Flags().BoolVar(&buildSSR, "ssr", false, "Build the frontend as a Nuxt SSR server instead of static files")
+}
+
+// buildSSRFrontendToDist runs the Nuxt build and copies its .output server into distDir/app
+func buildSSRFrontendToDist(cmd *mamba.Command, frontendDir, distDir string, env []string) {
+	cmd.PrintInfo("Building SSR frontend...")
+
+	err := spinner.WithSpinner("Building Nuxt server...", func() error {
+		buildCmd := packageScript("build")
+		buildCmd.Dir = frontendDir
+		buildCmd.Env = env
+		buildCmd.Stdout = os.Stdout
+		buildCmd.Stderr = os.Stderr
+		return buildCmd.Run()
+	})
+
+	if err != nil {
+		cmd.PrintError("Failed to build frontend: " + err.Error())
+		os.Exit(1)
+	}
+
+	cmd.PrintInfo("Copying frontend server...")
+	outputDir := filepath.Join(frontendDir, ".output")
+	if !fileExistsBuild(filepath.Join(outputDir, "server", "index.mjs")) {
+		cmd.PrintError("Nuxt server output not found at " + filepath.Join(outputDir, "server"))
+		os.Exit(1)
+	}
+	copyDir(outputDir, filepath.Join(distDir, ssrAppDir))
+
+	dockerfile := `FROM node:20-alpine
+
+LABEL bui.environment="` + buildEnvironment() + `"
+
+WORKDIR /app
+
+# Copy the Nuxt server output
+COPY . .
+
+ENV NODE_ENV=production
+ENV HOST=0.0.0.0
+ENV PORT=3000
+
+EXPOSE 3000
+
+CMD ["node", "server/index.mjs"]
+`
+	os.WriteFile(filepath.Join(distDir, ssrAppDir, "Dockerfile"), []byte(dockerfile), 0644)
+
+	cmd.PrintSuccess("Frontend built successfully")
+}
+
+// createSSRDeploymentFiles adds a compose file that runs the API and the Nuxt server side by side,
+// unless --compose already wrote the full production stack
+func createSSRDeploymentFiles(cmd *mamba.Command, distDir string) {
+	// Keep the Nuxt server out of the API image
+	dockerignore, _ := os.ReadFile(filepath.Join(distDir, ".dockerignore"))
+	os.WriteFile(filepath.Join(distDir, ".dockerignore"), append(dockerignore, []byte(ssrAppDir+"/\n")...), 0644)
+
+	if buildCompose {
+		return
+	}
+
+	compose := `# Runs the Go API and the Nuxt SSR server. Point the app's API URL setting
+# at http://api:8000 for server-side requests and at the public API URL for the browser.
+services:
+  api:
+    build: .
+    env_file:
+      - path: .env
+        required: false
+    ports:
+      - "8000:8000"
+    volumes:
+      - storage:/app/storage
+    restart: unless-stopped
+
+  app:
+    build: ./` + ssrAppDir + `
+    environment:
+      PORT: 3000
+    ports:
+      - "3000:3000"
+    depends_on:
+      - api
+    restart: unless-stopped
+
+volumes:
+  storage:
+`
+	os.WriteFile(filepath.Join(distDir, "docker-compose.yml"), []byte(compose), 0644)
+	cmd.PrintSuccess("Compose file created")
+}
+
+// appImageTag derives the Nuxt server's image tag from the API's: shop:v1 becomes shop-app:v1
+func appImageTag(tag string) string {
+	repo, version := tag, ""
+	if i := strings.LastIndex(tag, ":"); i > strings.LastIndex(tag, "/") {
+		repo, version = tag[:i], tag[i:]
+	}
+	return repo + "-app" + version
+}
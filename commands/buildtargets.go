@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// buildTarget is a GOOS/GOARCH pair the backend is compiled for; empty fields mean the host's
+type buildTarget struct {
+	OS   string
+	Arch string
+}
+
+// String returns the target as os/arch
+func (t buildTarget) String() string {
+	return t.goos() + "/" + t.goarch()
+}
+
+func (t buildTarget) goos() string {
+	if t.OS == "" {
+		return runtime.GOOS
+	}
+	return t.OS
+}
+
+func (t buildTarget) goarch() string {
+	if t.Arch == "" {
+		return runtime.GOARCH
+	}
+	return t.Arch
+}
+
+// native reports whether the target is the machine bui runs on
+func (t buildTarget) native() bool {
+	return t.goos() == runtime.GOOS && t.goarch() == runtime.GOARCH
+}
+
+// binaryName is the backend binary's file name: server for a single target,
+// server-<os>-<arch> when several are built into the same dist
+func (t buildTarget) binaryName(multiple bool) string {
+	name := "server"
+	if multiple {
+		name += "-" + t.goos() + "-" + t.goarch()
+	}
+	if t.goos() == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// env returns the environment for go build. Cross builds disable cgo unless CGO_ENABLED is set,
+// since they'd otherwise need a C cross-compiler.
+func (t buildTarget) env() []string {
+	env := append(os.Environ(), "GOOS="+t.goos(), "GOARCH="+t.goarch())
+	if !t.native() && os.Getenv("CGO_ENABLED") == "" {
+		env = append(env, "CGO_ENABLED=0")
+	}
+	return env
+}
+
+// parseBuildTargets turns --os/--arch or --targets into the targets to build
+func parseBuildTargets(goos, goarch, targets string) ([]buildTarget, error) {
+	if targets == "" {
+		return []buildTarget{{OS: goos, Arch: goarch}}, nil
+	}
+	if goos != "" || goarch != "" {
+		return nil, fmt.Errorf("use either --targets or --os/--arch")
+	}
+
+	var parsed []buildTarget
+	seen := map[string]bool{}
+	for _, target := range strings.Split(targets, ",") {
+		target = strings.TrimSpace(target)
+		targetOS, targetArch, ok := strings.Cut(target, "/")
+		if !ok || targetOS == "" || targetArch == "" {
+			return nil, fmt.Errorf("invalid target %q (expected os/arch, e.g. linux/amd64)", target)
+		}
+		if !seen[target] {
+			seen[target] = true
+			parsed = append(parsed, buildTarget{OS: targetOS, Arch: targetArch})
+		}
+	}
+	return parsed, nil
+}
+
+// dockerBinary returns the binary a Linux container should run: the linux/amd64 build,
+// else the first Linux one, else the only one
+func dockerBinary(targets []buildTarget) string {
+	multiple := len(targets) > 1
+	var linux []buildTarget
+	for _, target := range targets {
+		if target.goos() == "linux" {
+			if target.goarch() == "amd64" {
+				return target.binaryName(multiple)
+			}
+			linux = append(linux, target)
+		}
+	}
+	if len(linux) > 0 {
+		return linux[0].binaryName(multiple)
+	}
+	return targets[0].binaryName(multiple)
+}
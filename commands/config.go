@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+var configCmd = &mamba.Command{
+	Use:   "config",
+	Short: "Read and write user-wide settings",
+	Long: `Read and write settings stored in ~/.bui/config.yaml. They apply to every project;
+a project's .bui.yaml takes precedence where both set the same thing.
+
+Examples:
+  bui config list                             # Show every setting and its value
+  bui config get clone.protocol
+  bui config set clone.protocol https         # Always clone templates over HTTPS
+  bui config set author.name "Jane Doe"       # Written into generated READMEs
+  bui config set templates.backend acme/api-template
+  bui config set telemetry off                # No background requests (update check)
+  bui config unset author.email`,
+}
+
+var configGetCmd = &mamba.Command{
+	Use:   "get [key]",
+	Short: "Print a setting's value",
+	Args:  mamba.ExactArgs(1),
+	Run:   getConfig,
+}
+
+var configSetCmd = &mamba.Command{
+	Use:   "set [key] [value]",
+	Short: "Change a setting",
+	Args:  mamba.ExactArgs(2),
+	Run:   setConfig,
+}
+
+var configUnsetCmd = &mamba.Command{
+	Use:   "unset [key]",
+	Short: "Reset a setting to its default",
+	Args:  mamba.ExactArgs(1),
+	Run: func(cmd *mamba.Command, args []string) {
+		setConfig(cmd, []string{args[0], ""})
+	},
+}
+
+var configListCmd = &mamba.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List all settings",
+	Run:     listConfig,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configListCmd)
+}
+
+// findSetting looks up a setting key, printing the valid keys when it's unknown
+func findSetting(cmd *mamba.Command, key string) (utils.UserSetting, bool) {
+	setting, ok := utils.FindUserSetting(key)
+	if !ok {
+		cmd.PrintError(fmt.Sprintf("Unknown setting: %s", key))
+		keys := make([]string, len(utils.UserSettings))
+		for i, setting := range utils.UserSettings {
+			keys[i] = setting.Key
+		}
+		cmd.PrintInfo("Available settings: " + strings.Join(keys, ", "))
+	}
+	return setting, ok
+}
+
+// getConfig prints one setting's value, or its default when unset
+func getConfig(cmd *mamba.Command, args []string) {
+	setting, ok := findSetting(cmd, args[0])
+	if !ok {
+		os.Exit(1)
+	}
+	fmt.Println(setting.Get(utils.User))
+}
+
+// setConfig stores a setting in ~/.bui/config.yaml; an empty value unsets it
+func setConfig(cmd *mamba.Command, args []string) {
+	setting, ok := findSetting(cmd, args[0])
+	if !ok {
+		os.Exit(1)
+	}
+
+	if err := setting.Set(utils.User, args[1]); err != nil {
+		cmd.PrintError(err.Error())
+		os.Exit(1)
+	}
+	if err := utils.SaveUserConfig(); err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to save config: %v", err))
+		os.Exit(1)
+	}
+
+	if args[1] == "" {
+		cmd.PrintSuccess(fmt.Sprintf("Unset %s", setting.Key))
+		return
+	}
+	cmd.PrintSuccess(fmt.Sprintf("Set %s to %s", setting.Key, args[1]))
+}
+
+// listConfig prints every setting with its current value
+func listConfig(cmd *mamba.Command, args []string) {
+	if path, err := utils.UserConfigPath(); err == nil {
+		cmd.PrintHeader(fmt.Sprintf("Settings (%s)", path))
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE\tDESCRIPTION")
+	for _, setting := range utils.UserSettings {
+		value := setting.Get(utils.User)
+		if value == "" {
+			value = "-"
+		}
+		description := setting.Description
+		if len(setting.Values) > 0 {
+			description += " (" + strings.Join(setting.Values, ", ") + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", setting.Key, value, description)
+	}
+	w.Flush()
+}
@@ -0,0 +1,504 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// consoleEval holds expressions to run instead of starting the interactive console
+var consoleEval []string
+
+var consoleCmd = &mamba.Command{
+	Use:     "console",
+	Aliases: []string{"c"},
+	Short:   "Query the backend's models from an interactive console",
+	Long: `Compile a small program that imports the backend's models and connects to the database
+configured in .env, then read queries from the terminal, like rails console.
+
+Queries start with a model and chain GORM methods:
+  Product.Count()
+  Product.First()
+  Product.Find(42)
+  Product.Where("price > ?", 10).Order("price desc").Limit(5).All()
+  Product.Preload("Category").Last()
+  Product.Pluck("name")
+
+Where, Or, Not, Order, Limit, Offset, Select, Preload, Joins, Group and Unscoped can be
+chained; First, Last, Take, Find(id), All, Count and Pluck run the query. A chain without
+one of those runs All. Records are printed as JSON.
+
+"sql <statement>" runs raw SQL, "models" lists the models and their tables, and "exit"
+or Ctrl+D leaves the console.
+
+Examples:
+  bui console
+  bui console -e 'Product.Count()'
+  bui console -e 'User.Where("email = ?", "me@example.com").First()'`,
+	Run: runConsole,
+}
+
+func init() {
+	rootCmd.AddCommand(consoleCmd)
+	consoleCmd.Flags().StringArrayVarP(&consoleEval, "eval", "e", nil, "Run an expression and exit (repeatable)")
+}
+
+// runConsole runs the console program in the backend module
+func runConsole(cmd *mamba.Command, args []string) {
+	backendDir, _ := detectProjectDirs()
+	if _, err := os.Stat(filepath.Join(backendDir, "main.go")); os.IsNotExist(err) {
+		cmd.PrintError("Base project structure not found")
+		cmd.PrintInfo("Run bui console from the project root or the backend directory")
+		os.Exit(1)
+	}
+
+	models := utils.FindModels(backendDir)
+	if len(models) == 0 {
+		cmd.PrintWarning("No models found in app/models")
+		cmd.PrintInfo("Generate a module first with bui g <name> <field:type>...")
+		return
+	}
+
+	if len(consoleEval) == 0 {
+		cmd.PrintInfo(fmt.Sprintf("Compiling the console with %d models...", len(models)))
+	}
+	// Ctrl+C belongs to the console, which keeps running; it ends with exit or Ctrl+D
+	signal.Ignore(os.Interrupt)
+	defer signal.Reset(os.Interrupt)
+
+	source := consoleRunnerSource(utils.GetGoModuleNameIn(backendDir), models)
+	if err := runBackendProgram(backendDir, source, consoleEval...); err != nil {
+		if len(consoleEval) == 0 {
+			cmd.PrintError(fmt.Sprintf("Console failed: %v", err))
+		}
+		os.Exit(1)
+	}
+}
+
+// consoleRunnerSource returns a main package that evaluates console queries against the models
+func consoleRunnerSource(goModule string, models []string) string {
+	var registry strings.Builder
+	for _, model := range models {
+		registry.WriteString(fmt.Sprintf("\t%q: func() (any, any) { return &models.%s{}, &[]models.%s{} },\n", model, model, model))
+	}
+	return strings.NewReplacer(
+		"{{module}}", goModule,
+		"{{registry}}", registry.String(),
+	).Replace(consoleRunnerTemplate)
+}
+
+// consoleRunnerTemplate parses each query as a Go expression and maps its method chain onto GORM.
+// With arguments it evaluates them and exits; otherwise it reads queries from stdin.
+const consoleRunnerTemplate = `// Code generated by bui console. DO NOT EDIT.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+
+	"{{module}}/app/models"
+	"{{module}}/core/config"
+	"{{module}}/core/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// registry returns a new record and a new slice of records for each model
+var registry = map[string]func() (any, any){
+{{registry}}}
+
+const help = ` + "`" + `Queries start with a model and chain GORM methods, for example:
+  Product.Count()
+  Product.Find(42)
+  Product.Where("price > ?", 10).Order("price desc").Limit(5).All()
+
+Chain:  Where, Or, Not, Order, Limit, Offset, Select, Preload, Joins, Group, Unscoped
+Run:    First, Last, Take, Find(id), All, Count, Pluck(column)
+
+Commands:
+  sql <statement>  Run raw SQL
+  models           List the models and their tables
+  help             Show this help
+  exit             Leave the console (or Ctrl+D)` + "`" + `
+
+type call struct {
+	name string
+	args []any
+}
+
+func main() {
+	db, err := database.InitDB(config.NewConfig())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to connect to database:", err)
+		os.Exit(1)
+	}
+	conn := db.DB
+
+	if len(os.Args) > 1 {
+		for _, line := range os.Args[1:] {
+			if err := eval(conn, line); err != nil {
+				fmt.Fprintln(os.Stderr, "error:", err)
+				os.Exit(1)
+			}
+		}
+		return
+	}
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	go func() {
+		for range interrupts {
+			fmt.Print("\n(type exit or press Ctrl+D to leave)\n> ")
+		}
+	}()
+
+	fmt.Println("Connected. Type help for the query syntax, exit to leave.")
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for {
+		fmt.Print("> ")
+		if !scanner.Scan() {
+			fmt.Println()
+			return
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "exit" || line == "quit" {
+			return
+		}
+		if err := eval(conn, line); err != nil {
+			fmt.Println("error:", err)
+		}
+	}
+}
+
+// eval runs one console line
+func eval(conn *gorm.DB, line string) error {
+	line = strings.TrimSuffix(strings.TrimSpace(line), ";")
+	command, rest, _ := strings.Cut(line, " ")
+	switch command {
+	case "":
+		return nil
+	case "help":
+		fmt.Println(help)
+		return nil
+	case "models":
+		names := make([]string, 0, len(registry))
+		for name := range registry {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			record, _ := registry[name]()
+			fmt.Printf("%-24s %s\n", name, tableName(conn, record))
+		}
+		return nil
+	case "sql":
+		return runSQL(conn, strings.TrimSpace(rest))
+	}
+
+	model, calls, err := parseQuery(line)
+	if err != nil {
+		return err
+	}
+	newRecords, ok := registry[model]
+	if !ok {
+		return fmt.Errorf("unknown model %s (models lists them)", model)
+	}
+	record, records := newRecords()
+
+	tx := conn.Model(record)
+	for i, c := range calls {
+		last := i == len(calls)-1
+		switch c.name {
+		case "Where", "Or", "Not":
+			query, err := stringArg(c, 0)
+			if err != nil {
+				return err
+			}
+			switch c.name {
+			case "Where":
+				tx = tx.Where(query, c.args[1:]...)
+			case "Or":
+				tx = tx.Or(query, c.args[1:]...)
+			default:
+				tx = tx.Not(query, c.args[1:]...)
+			}
+		case "Order", "Preload", "Joins", "Group":
+			value, err := stringArg(c, 0)
+			if err != nil {
+				return err
+			}
+			switch c.name {
+			case "Order":
+				tx = tx.Order(value)
+			case "Preload":
+				tx = tx.Preload(value)
+			case "Joins":
+				tx = tx.Joins(value)
+			default:
+				tx = tx.Group(value)
+			}
+		case "Limit", "Offset":
+			n, err := intArg(c, 0)
+			if err != nil {
+				return err
+			}
+			if c.name == "Limit" {
+				tx = tx.Limit(n)
+			} else {
+				tx = tx.Offset(n)
+			}
+		case "Select":
+			columns := make([]string, len(c.args))
+			for j := range c.args {
+				if columns[j], err = stringArg(c, j); err != nil {
+					return err
+				}
+			}
+			tx = tx.Select(columns)
+		case "Unscoped":
+			tx = tx.Unscoped()
+		case "First", "Last", "Take", "Find", "All", "Count", "Pluck":
+			if !last {
+				return fmt.Errorf("%s() runs the query and must come last", c.name)
+			}
+			return run(tx, c, record, records)
+		default:
+			return fmt.Errorf("unknown method %s (help lists them)", c.name)
+		}
+	}
+	return run(tx, call{name: "All"}, record, records)
+}
+
+// run executes the query with the method that ends the chain and prints the result
+func run(tx *gorm.DB, c call, record, records any) error {
+	// Single records are loaded with Find so a miss isn't logged as an error by GORM
+	primaryKey := clause.Column{Table: clause.CurrentTable, Name: clause.PrimaryKey}
+	switch c.name {
+	case "First":
+		return showRecord(tx.Order(clause.OrderByColumn{Column: primaryKey}).Limit(1).Find(record), record)
+	case "Last":
+		return showRecord(tx.Order(clause.OrderByColumn{Column: primaryKey, Desc: true}).Limit(1).Find(record), record)
+	case "Take":
+		return showRecord(tx.Limit(1).Find(record), record)
+	case "Find":
+		if len(c.args) != 1 {
+			return errors.New("Find takes the id of the record")
+		}
+		return showRecord(tx.Where("id = ?", c.args[0]).Limit(1).Find(record), record)
+	case "Count":
+		var count int64
+		if err := tx.Count(&count).Error; err != nil {
+			return err
+		}
+		fmt.Println(count)
+		return nil
+	case "Pluck":
+		column, err := stringArg(c, 0)
+		if err != nil {
+			return err
+		}
+		var values []any
+		if err := tx.Pluck(column, &values).Error; err != nil {
+			return err
+		}
+		for i, value := range values {
+			if bytes, ok := value.([]byte); ok {
+				values[i] = string(bytes)
+			}
+		}
+		return show(nil, values)
+	}
+	result := tx.Find(records)
+	if err := show(result.Error, records); err != nil {
+		return err
+	}
+	printRowCount(int(result.RowsAffected))
+	return nil
+}
+
+// showRecord prints the record a query loaded
+func showRecord(result *gorm.DB, record any) error {
+	if result.Error == nil && result.RowsAffected == 0 {
+		return errors.New("record not found")
+	}
+	return show(result.Error, record)
+}
+
+// runSQL runs a statement; queries that return rows print them, others the affected row count
+func runSQL(conn *gorm.DB, statement string) error {
+	if statement == "" {
+		return errors.New("usage: sql <statement>")
+	}
+	keyword, _, _ := strings.Cut(strings.ToUpper(statement), " ")
+	switch keyword {
+	case "SELECT", "WITH", "SHOW", "EXPLAIN", "PRAGMA", "DESCRIBE", "VALUES":
+		var rows []map[string]any
+		if err := conn.Raw(statement).Scan(&rows).Error; err != nil {
+			return err
+		}
+		for _, row := range rows {
+			for column, value := range row {
+				if bytes, ok := value.([]byte); ok {
+					row[column] = string(bytes)
+				}
+			}
+		}
+		if err := show(nil, rows); err != nil {
+			return err
+		}
+		printRowCount(len(rows))
+		return nil
+	}
+	result := conn.Exec(statement)
+	if result.Error != nil {
+		return result.Error
+	}
+	fmt.Printf("%d rows affected\n", result.RowsAffected)
+	return nil
+}
+
+// printRowCount prints how many rows a query returned
+func printRowCount(n int) {
+	if n == 1 {
+		fmt.Println("(1 row)")
+		return
+	}
+	fmt.Printf("(%d rows)\n", n)
+}
+
+// show prints value as indented JSON unless the query failed
+func show(err error, value any) error {
+	if err != nil {
+		return err
+	}
+	out, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+// tableName returns the table a model is stored in
+func tableName(conn *gorm.DB, record any) string {
+	statement := &gorm.Statement{DB: conn}
+	if err := statement.Parse(record); err != nil {
+		return "?"
+	}
+	return statement.Table
+}
+
+// parseQuery splits Model.Method(args).Method(args) into the model and its calls
+func parseQuery(line string) (string, []call, error) {
+	expr, err := parser.ParseExpr(line)
+	if err != nil {
+		return "", nil, fmt.Errorf("not a query: %s (help shows the syntax)", line)
+	}
+
+	var calls []call
+	for {
+		switch node := expr.(type) {
+		case *ast.Ident:
+			for i, j := 0, len(calls)-1; i < j; i, j = i+1, j-1 {
+				calls[i], calls[j] = calls[j], calls[i]
+			}
+			return node.Name, calls, nil
+		case *ast.CallExpr:
+			selector, ok := node.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return "", nil, fmt.Errorf("queries start with a model, e.g. Product.First()")
+			}
+			c := call{name: selector.Sel.Name}
+			for _, arg := range node.Args {
+				value, err := literal(arg)
+				if err != nil {
+					return "", nil, err
+				}
+				c.args = append(c.args, value)
+			}
+			calls = append(calls, c)
+			expr = selector.X
+		case *ast.SelectorExpr:
+			return "", nil, fmt.Errorf("%s needs parentheses: %s()", node.Sel.Name, node.Sel.Name)
+		default:
+			return "", nil, fmt.Errorf("not a query: %s (help shows the syntax)", line)
+		}
+	}
+}
+
+// literal returns the value of a string, number, boolean or nil argument
+func literal(expr ast.Expr) (any, error) {
+	switch node := expr.(type) {
+	case *ast.BasicLit:
+		switch node.Kind {
+		case token.INT:
+			return strconv.ParseInt(node.Value, 0, 64)
+		case token.FLOAT:
+			return strconv.ParseFloat(node.Value, 64)
+		case token.STRING:
+			return strconv.Unquote(node.Value)
+		}
+	case *ast.Ident:
+		switch node.Name {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "nil":
+			return nil, nil
+		}
+	case *ast.UnaryExpr:
+		if node.Op == token.SUB {
+			value, err := literal(node.X)
+			switch number := value.(type) {
+			case int64:
+				return -number, err
+			case float64:
+				return -number, err
+			}
+		}
+	}
+	return nil, fmt.Errorf("arguments must be strings, numbers, true, false or nil")
+}
+
+// stringArg returns argument i of c, which must be a string
+func stringArg(c call, i int) (string, error) {
+	if i >= len(c.args) {
+		return "", fmt.Errorf("%s needs a string argument", c.name)
+	}
+	value, ok := c.args[i].(string)
+	if !ok {
+		return "", fmt.Errorf("%s takes a string argument", c.name)
+	}
+	return value, nil
+}
+
+// intArg returns argument i of c, which must be an integer
+func intArg(c call, i int) (int, error) {
+	if i >= len(c.args) {
+		return 0, fmt.Errorf("%s needs a number", c.name)
+	}
+	value, ok := c.args[i].(int64)
+	if !ok {
+		return 0, fmt.Errorf("%s takes a whole number", c.name)
+	}
+	return int(value), nil
+}
+`
@@ -0,0 +1,224 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+var deployCmd = &mamba.Command{
+	Use:   "deploy",
+	Short: "Ship the production build to a server",
+	Long: `Deploy the dist directory written by bui build.
+
+Targets are configured under deploy: in .bui.yaml; secrets come from the environment.
+
+  caprover  Uploads the build as a tarball through the CapRover API
+            (deploy.caprover.url/app, or CAPROVER_URL/CAPROVER_APP; CAPROVER_APP_TOKEN
+            or CAPROVER_PASSWORD)
+  docker    Builds the image and (re)starts the container, on deploy.docker.host when set
+  ssh       Copies the build with rsync and restarts it as a systemd service
+  flyio     Runs fly deploy with the build's Dockerfile
+
+Examples:
+  bui build && bui deploy caprover
+  bui build --os linux && bui deploy ssh`,
+}
+
+var deployCapRoverCmd = &mamba.Command{
+	Use:   "caprover",
+	Short: "Upload the build to a CapRover app",
+	Run:   deployCapRover,
+}
+
+var deployDockerCmd = &mamba.Command{
+	Use:   "docker",
+	Short: "Build the image and run it as a container",
+	Run:   deployDocker,
+}
+
+var deploySSHCmd = &mamba.Command{
+	Use:   "ssh",
+	Short: "Copy the build over SSH and restart its systemd service",
+	Run:   deploySSH,
+}
+
+var deployFlyIOCmd = &mamba.Command{
+	Use:     "flyio",
+	Aliases: []string{"fly"},
+	Short:   "Deploy the build to Fly.io",
+	Run:     deployFlyIO,
+}
+
+func init() {
+	rootCmd.AddCommand(deployCmd)
+	deployCmd.AddCommand(deployCapRoverCmd)
+	deployCmd.AddCommand(deployDockerCmd)
+	deployCmd.AddCommand(deploySSHCmd)
+	deployCmd.AddCommand(deployFlyIOCmd)
+}
+
+// deployDistDir returns the dist directory to deploy, exiting when there is no build yet
+func deployDistDir(cmd *mamba.Command) string {
+	// Deploy from the project root, wherever bui was started
+	if root := utils.Project.Root(); root != "" {
+		os.Chdir(root)
+	}
+
+	distDir := findDistDir()
+	if distDir == "" {
+		cmd.PrintError("No production build found. Run 'bui build' first.")
+		os.Exit(1)
+	}
+	return distDir
+}
+
+// requireDockerfile exits unless the build has the Dockerfile that image-based targets need
+func requireDockerfile(cmd *mamba.Command, distDir string) {
+	if !fileExistsBuild(filepath.Join(distDir, "Dockerfile")) {
+		cmd.PrintError("No Dockerfile in " + distDir + ". Run 'bui build' for both backend and frontend, or 'bui build --docker'.")
+		os.Exit(1)
+	}
+}
+
+// deployProjectName names services and containers after the project
+func deployProjectName(distDir string) string {
+	name, _, _ := strings.Cut(dockerImageTag(distDir), ":")
+	return name
+}
+
+// deployBinary returns the Linux server binary in distDir
+func deployBinary(distDir string) string {
+	if fileExistsBuild(filepath.Join(distDir, "server")) {
+		return "server"
+	}
+	matches, _ := filepath.Glob(filepath.Join(distDir, "server-linux-*"))
+	if len(matches) > 0 {
+		return filepath.Base(matches[0])
+	}
+	return ""
+}
+
+// deployFailed reports a failed step and exits
+func deployFailed(cmd *mamba.Command, step string, err error) {
+	cmd.PrintError(fmt.Sprintf("%s failed: %v", step, err))
+	os.Exit(1)
+}
+
+// deployDocker builds the image from the dist directory and replaces the running container.
+// A dist with a docker-compose.yml (e.g. from --ssr) is started with docker compose instead.
+func deployDocker(cmd *mamba.Command, args []string) {
+	distDir := deployDistDir(cmd)
+	requireDockerfile(cmd, distDir)
+	settings := utils.Project.DeploySettings().Docker
+	if settings.Host != "" {
+		os.Setenv("DOCKER_HOST", settings.Host)
+	}
+	if _, err := exec.LookPath("docker"); err != nil {
+		cmd.PrintError("docker not found on PATH")
+		os.Exit(1)
+	}
+
+	if fileExistsBuild(filepath.Join(distDir, "docker-compose.yml")) {
+		cmd.PrintInfo("Starting services with docker compose...")
+		if err := runDocker(distDir, "compose", "up", "-d", "--build"); err != nil {
+			deployFailed(cmd, "docker compose up", err)
+		}
+		cmd.PrintSuccess("Deployed with docker compose")
+		return
+	}
+
+	image := settings.Image
+	if image == "" {
+		image = dockerImageTag(distDir)
+	}
+	name := settings.Name
+	if name == "" {
+		name = deployProjectName(distDir)
+	}
+	port := settings.Port
+	if port == 0 {
+		port = utils.DefaultBackendPort
+	}
+
+	cmd.PrintInfo("Building " + image + "...")
+	if err := runDocker(distDir, "build", "-t", image, "."); err != nil {
+		deployFailed(cmd, "docker build", err)
+	}
+
+	// Replace the previous container; storage lives in a volume so uploads survive
+	exec.Command("docker", "rm", "-f", name).Run()
+	runArgs := []string{"run", "-d", "--name", name, "--restart", "unless-stopped",
+		"-p", fmt.Sprintf("%d:%d", port, utils.DefaultBackendPort),
+		"-v", name + "-storage:/app/storage"}
+	if fileExistsBuild(filepath.Join(distDir, ".env")) {
+		runArgs = append(runArgs, "--env-file", ".env")
+	}
+	cmd.PrintInfo("Starting container " + name + "...")
+	if err := runDocker(distDir, append(runArgs, image)...); err != nil {
+		deployFailed(cmd, "docker run", err)
+	}
+	cmd.PrintSuccess(fmt.Sprintf("Deployed %s as container %s on port %d", image, name, port))
+}
+
+// deployFlyIO runs fly deploy in the dist directory, writing a fly.toml when it has none
+func deployFlyIO(cmd *mamba.Command, args []string) {
+	distDir := deployDistDir(cmd)
+	requireDockerfile(cmd, distDir)
+	settings := utils.Project.DeploySettings().FlyIO
+	if app := os.Getenv("FLY_APP"); app != "" {
+		settings.App = app
+	}
+
+	fly := ""
+	for _, name := range []string{"fly", "flyctl"} {
+		if path, err := exec.LookPath(name); err == nil {
+			fly = path
+			break
+		}
+	}
+	if fly == "" {
+		cmd.PrintError("flyctl not found; install it from https://fly.io/docs/flyctl/install/")
+		os.Exit(1)
+	}
+
+	configPath := filepath.Join(distDir, "fly.toml")
+	if !fileExistsBuild(configPath) {
+		if settings.App == "" {
+			cmd.PrintError("Set deploy.flyio.app in .bui.yaml or FLY_APP")
+			os.Exit(1)
+		}
+		config := fmt.Sprintf("app = %q\n", settings.App)
+		if settings.Region != "" {
+			config += fmt.Sprintf("primary_region = %q\n", settings.Region)
+		}
+		config += fmt.Sprintf(`
+[http_service]
+  internal_port = %d
+  force_https = true
+`, utils.DefaultBackendPort)
+		if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+			deployFailed(cmd, "Writing fly.toml", err)
+		}
+		cmd.PrintInfo("Created " + configPath)
+	}
+
+	flyArgs := []string{"deploy", "--remote-only"}
+	if settings.App != "" {
+		flyArgs = append(flyArgs, "--app", settings.App)
+	}
+	flyCmd := exec.Command(fly, flyArgs...)
+	flyCmd.Dir = distDir
+	flyCmd.Stdout = os.Stdout
+	flyCmd.Stderr = os.Stderr
+	flyCmd.Stdin = os.Stdin
+	if err := flyCmd.Run(); err != nil {
+		deployFailed(cmd, "fly deploy", err)
+	}
+	cmd.PrintSuccess("Deployed to Fly.io")
+}
@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// capRoverErrorStatus is the lowest status CapRover's API uses for errors; successes are 100-102
+const capRoverErrorStatus = 1000
+
+// capRoverResponse is the envelope of every CapRover API response
+type capRoverResponse struct {
+	Status      int             `json:"status"`
+	Description string          `json:"description"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// deployCapRover packs the dist directory and uploads it to the CapRover app, which builds
+// it with the generated captain-definition.json
+func deployCapRover(cmd *mamba.Command, args []string) {
+	distDir := deployDistDir(cmd)
+	requireDockerfile(cmd, distDir)
+	settings := utils.Project.DeploySettings().CapRover
+	if url := os.Getenv("CAPROVER_URL"); url != "" {
+		settings.URL = url
+	}
+	if app := os.Getenv("CAPROVER_APP"); app != "" {
+		settings.App = app
+	}
+	if settings.URL == "" || settings.App == "" {
+		cmd.PrintError("Set deploy.caprover.url and deploy.caprover.app in .bui.yaml (or CAPROVER_URL and CAPROVER_APP)")
+		os.Exit(1)
+	}
+	baseURL := strings.TrimSuffix(settings.URL, "/") + "/api/v2"
+
+	// An app token only allows deploying that app; the password logs in as the admin
+	headers := map[string]string{}
+	if token := os.Getenv("CAPROVER_APP_TOKEN"); token != "" {
+		headers["x-captain-app-token"] = token
+	} else if password := os.Getenv("CAPROVER_PASSWORD"); password != "" {
+		token, err := capRoverLogin(baseURL, password)
+		if err != nil {
+			deployFailed(cmd, "CapRover login", err)
+		}
+		headers["x-captain-auth"] = token
+	} else {
+		cmd.PrintError("Set CAPROVER_APP_TOKEN or CAPROVER_PASSWORD")
+		os.Exit(1)
+	}
+
+	cmd.PrintInfo("Packing " + distDir + "...")
+	tarball := filepath.Join(os.TempDir(), fmt.Sprintf("bui-deploy-%d.tar.gz", time.Now().UnixNano()))
+	defer os.Remove(tarball)
+	// The server's settings live in CapRover, so the local .env stays behind
+	if _, err := writeTarGz(tarball, distDir, "", map[string]bool{".env": true}); err != nil {
+		deployFailed(cmd, "Packing the build", err)
+	}
+
+	cmd.PrintInfo(fmt.Sprintf("Uploading to %s (app %s)...", settings.URL, settings.App))
+	if err := capRoverUpload(baseURL, settings.App, tarball, headers); err != nil {
+		deployFailed(cmd, "CapRover upload", err)
+	}
+	cmd.PrintSuccess(fmt.Sprintf("Uploaded; CapRover is building %s", settings.App))
+}
+
+// capRoverLogin exchanges the dashboard password for an auth token
+func capRoverLogin(baseURL, password string) (string, error) {
+	body, _ := json.Marshal(map[string]string{"password": password})
+	req, err := http.NewRequest(http.MethodPost, baseURL+"/login", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	data, err := capRoverDo(req)
+	if err != nil {
+		return "", err
+	}
+	var login struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(data, &login); err != nil || login.Token == "" {
+		return "", fmt.Errorf("no token in login response")
+	}
+	return login.Token, nil
+}
+
+// capRoverUpload sends the tarball as the app's new source
+func capRoverUpload(baseURL, app, tarball string, headers map[string]string) error {
+	file, err := os.Open(tarball)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("sourceFile", "deploy.tar.gz")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return err
+	}
+	form.Close()
+
+	req, err := http.NewRequest(http.MethodPost, baseURL+"/user/apps/appData/"+app+"?detached=1", &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	_, err = capRoverDo(req)
+	return err
+}
+
+// capRoverDo sends a request and returns the data of a successful response
+func capRoverDo(req *http.Request) (json.RawMessage, error) {
+	req.Header.Set("x-namespace", "captain")
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result capRoverResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("unexpected response (HTTP %d)", resp.StatusCode)
+	}
+	if result.Status >= capRoverErrorStatus {
+		return nil, fmt.Errorf("%s (status %d)", result.Description, result.Status)
+	}
+	return result.Data, nil
+}
@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// deploySSH copies the dist directory to the server with rsync and restarts the backend as a
+// systemd service, installing the unit on the first deploy
+func deploySSH(cmd *mamba.Command, args []string) {
+	distDir := deployDistDir(cmd)
+	settings := utils.Project.DeploySettings().SSH
+	if settings.Host == "" {
+		cmd.PrintError("Set deploy.ssh.host (user@host) in .bui.yaml")
+		os.Exit(1)
+	}
+	for _, tool := range []string{"ssh", "rsync"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			cmd.PrintError(tool + " not found on PATH")
+			os.Exit(1)
+		}
+	}
+
+	binary := deployBinary(distDir)
+	if binary == "" {
+		cmd.PrintError("No server binary in " + distDir + "; build it for the server with bui build --os linux")
+		os.Exit(1)
+	}
+
+	project := deployProjectName(distDir)
+	path := settings.Path
+	if path == "" {
+		path = "/opt/" + project
+	}
+	service := settings.Service
+	if service == "" {
+		service = project
+	}
+	sshArgs := []string{}
+	if settings.Port != 0 {
+		sshArgs = append(sshArgs, "-p", fmt.Sprint(settings.Port))
+	}
+
+	cmd.PrintInfo(fmt.Sprintf("Copying %s to %s:%s...", distDir, settings.Host, path))
+	if err := runSSH(settings.Host, sshArgs, "mkdir -p "+shellQuote(path), ""); err != nil {
+		deployFailed(cmd, "Creating "+path, err)
+	}
+	// The server keeps its own .env and uploaded files
+	rsyncCmd := exec.Command("rsync", "-az", "--delete", "--exclude", ".env", "--exclude", "storage/",
+		"-e", strings.Join(append([]string{"ssh"}, sshArgs...), " "),
+		distDir+"/", settings.Host+":"+path+"/")
+	rsyncCmd.Stdout = os.Stdout
+	rsyncCmd.Stderr = os.Stderr
+	if err := rsyncCmd.Run(); err != nil {
+		deployFailed(cmd, "rsync", err)
+	}
+
+	cmd.PrintInfo("Restarting " + service + "...")
+	user, _, hasUser := strings.Cut(settings.Host, "@")
+	if !hasUser {
+		user = ""
+	}
+	unit := systemdUnit(project, path, binary, user)
+	script := fmt.Sprintf(`set -e
+mkdir -p %[1]s/storage
+unit=/etc/systemd/system/%[2]s.service
+if ! printf '%%s' %[3]s | sudo cmp -s - "$unit"; then
+  printf '%%s' %[3]s | sudo tee "$unit" >/dev/null
+  sudo systemctl daemon-reload
+  sudo systemctl enable %[2]s
+fi
+sudo systemctl restart %[2]s
+`, shellQuote(path), shellQuote(service), shellQuote(unit))
+	if err := runSSH(settings.Host, sshArgs, "sh -s", script); err != nil {
+		deployFailed(cmd, "Restarting "+service, err)
+	}
+	cmd.PrintSuccess(fmt.Sprintf("Deployed to %s:%s (systemd unit %s)", settings.Host, path, service))
+}
+
+// systemdUnit returns the unit that runs the backend from path, as user when set
+func systemdUnit(project, path, binary, user string) string {
+	unit := fmt.Sprintf(`[Unit]
+Description=%s
+After=network.target
+
+[Service]
+WorkingDirectory=%s
+ExecStart=%s/%s
+EnvironmentFile=-%s/.env
+Restart=always
+`, project, path, path, binary, path)
+	if user != "" {
+		unit += "User=" + user + "\n"
+	}
+	return unit + `
+[Install]
+WantedBy=multi-user.target
+`
+}
+
+// runSSH runs command on host, feeding it stdin
+func runSSH(host string, sshArgs []string, command, stdin string) error {
+	sshCmd := exec.Command("ssh", append(append(sshArgs, host), command)...)
+	sshCmd.Stdin = strings.NewReader(stdin)
+	sshCmd.Stdout = os.Stdout
+	sshCmd.Stderr = os.Stderr
+	return sshCmd.Run()
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
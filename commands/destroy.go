@@ -1,8 +1,12 @@
 package commands
 
 import (
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/base-al/bui/utils"
 	"github.com/base-go/mamba"
@@ -18,7 +22,11 @@ var destroyCmd = &mamba.Command{
 Examples:
   bui d product               # Destroy product module (frontend and backend)
   bui d backend product       # Destroy backend module only
-  bui d frontend product      # Destroy frontend module only`,
+  bui d frontend product      # Destroy frontend module only
+  bui d product --dry-run     # List what would be deleted
+  bui d product --yes         # Skip the confirmation prompt (CI)
+
+Destroyed files are moved to .bui/backups; bring them back with "bui restore [module]".`,
 	Run: destroyBothModules,
 }
 
@@ -36,10 +44,24 @@ var destroyFrontendCmd = &mamba.Command{
 	Run:   destroyFrontend,
 }
 
+var (
+	destroyDryRun   bool
+	destroyYes      bool
+	destroyNoBackup bool
+)
+
 func init() {
 	rootCmd.AddCommand(destroyCmd)
+	destroyCmd.PersistentFlags().BoolVar(&destroyDryRun, "dry-run", false, "List the paths that would be deleted without deleting them")
+	destroyCmd.PersistentFlags().BoolVarP(&destroyYes, "yes", "y", false, "Skip the confirmation prompt")
+	destroyCmd.PersistentFlags().BoolVar(&destroyNoBackup, "no-backup", false, "Delete files permanently instead of moving them to .bui/backups")
 	destroyCmd.AddCommand(destroyBackendCmd)
 	destroyCmd.AddCommand(destroyFrontendCmd)
+
+	// Run the pre_destroy and post_destroy hooks from .bui.yaml
+	for _, c := range []*mamba.Command{destroyCmd, destroyBackendCmd, destroyFrontendCmd} {
+		c.Run = withHooks("destroy", c.Run)
+	}
 }
 
 func destroyBothModules(cmd *mamba.Command, args []string) {
@@ -49,84 +71,23 @@ func destroyBothModules(cmd *mamba.Command, args []string) {
 		os.Exit(1)
 	}
 
-	moduleName := args[0]
-	naming := utils.NewNamingConvention(moduleName)
-
-	cmd.PrintWarning("Destroying module: " + naming.Model + " (backend + frontend)")
-
-	// Ask for confirmation
-	confirmed, err := interactive.AskConfirm("Are you sure you want to destroy this module?", false)
-	if err != nil || !confirmed {
-		cmd.PrintInfo("Operation cancelled")
-		return
-	}
+	naming := utils.NewNamingConvention(args[0])
 
 	// Detect project structure
 	backendDir, frontendDir := detectProjectDirs()
 
-	// Destroy backend
-	backendPaths := []string{
-		filepath.Join(backendDir, "app", "models", naming.ModelSnake+".go"),
-		filepath.Join(backendDir, "app", naming.DirName),
-	}
-
-	backendDeleted := 0
-	if Verbose {
-		cmd.PrintHeader("Destroying Backend Files")
-	}
-	for _, path := range backendPaths {
-		if _, err := os.Stat(path); err == nil {
-			if err := os.RemoveAll(path); err != nil {
-				cmd.PrintError("Failed to delete: " + path)
-			} else {
-				if Verbose {
-					cmd.PrintBullet(path)
-				}
-				backendDeleted++
-			}
-		}
-	}
-
-	// Destroy frontend
-	frontendPaths := []string{
-		filepath.Join(frontendDir, "app", "modules", naming.PluralSnake),
-		filepath.Join(frontendDir, "app", "pages", "app", naming.PluralKebab),
-	}
-
-	frontendDeleted := 0
-	if Verbose {
-		cmd.PrintHeader("Destroying Frontend Files")
-	}
-	for _, path := range frontendPaths {
-		if _, err := os.Stat(path); err == nil {
-			if err := os.RemoveAll(path); err != nil {
-				cmd.PrintError("Failed to delete: " + path)
-			} else {
-				if Verbose {
-					cmd.PrintBullet(path)
-				}
-				frontendDeleted++
-			}
-		}
-	}
-
-	if backendDeleted == 0 && frontendDeleted == 0 {
-		cmd.PrintWarning("No module found: " + naming.Model)
-		return
-	}
-
-	if backendDeleted > 0 {
-		cmd.PrintSuccess("Backend module destroyed: " + naming.Model)
-		cmd.PrintInfo("Remember to remove from app/init.go if needed")
-	}
-
-	if frontendDeleted > 0 {
-		cmd.PrintSuccess("Frontend module destroyed: " + naming.Model)
-	}
+	destroyModule(cmd, naming, backendDir, frontendDir, "backend + frontend")
 }
 
 // detectProjectDirs detects backend and frontend directories
 func detectProjectDirs() (backend, frontend string) {
+	// Directories named in .bui.yaml win over the naming heuristics
+	backend, frontend = utils.Project.BackendDir(), utils.Project.FrontendDir()
+	if backend != "" && frontend != "" {
+		return backend, frontend
+	}
+	configuredBackend, configuredFrontend := backend != "", frontend != ""
+
 	// Check if we're in project root with separate backend/frontend dirs
 	entries, err := os.ReadDir(".")
 	if err == nil {
@@ -136,14 +97,14 @@ func detectProjectDirs() (backend, frontend string) {
 			}
 			name := entry.Name()
 			// Look for *-api or *-backend directories
-			if filepath.Ext(name) == "" && (contains(name, "-api") || contains(name, "-backend") || name == "backend" || name == "api") {
+			if !configuredBackend && filepath.Ext(name) == "" && (contains(name, "-api") || contains(name, "-backend") || name == "backend" || name == "api") {
 				// Check if it has app/ directory
 				if _, err := os.Stat(filepath.Join(name, "app")); err == nil {
 					backend = name
 				}
 			}
 			// Look for *-app or *-frontend directories
-			if filepath.Ext(name) == "" && (contains(name, "-app") || contains(name, "-frontend") || name == "frontend" || name == "app") {
+			if !configuredFrontend && filepath.Ext(name) == "" && (contains(name, "-app") || contains(name, "-frontend") || name == "frontend" || name == "app") {
 				// Check if it has app/ directory
 				if _, err := os.Stat(filepath.Join(name, "app")); err == nil {
 					frontend = name
@@ -169,80 +130,225 @@ func contains(s, substr string) bool {
 }
 
 func destroyBackend(cmd *mamba.Command, args []string) {
-	moduleName := args[0]
-	naming := utils.NewNamingConvention(moduleName)
+	destroyModule(cmd, utils.NewNamingConvention(args[0]), ".", "", "backend")
+}
 
-	cmd.PrintWarning("Destroying backend module: " + naming.Model)
+func destroyFrontend(cmd *mamba.Command, args []string) {
+	destroyModule(cmd, utils.NewNamingConvention(args[0]), "", ".", "frontend")
+}
 
-	// Ask for confirmation
-	confirmed, err := interactive.AskConfirm("Are you sure you want to destroy this backend module?", false)
-	if err != nil || !confirmed {
-		cmd.PrintInfo("Operation cancelled")
+// destroyModule lists the module's files, asks for confirmation and deletes them.
+// An empty backendDir or frontendDir skips that side.
+func destroyModule(cmd *mamba.Command, naming *utils.NamingConvention, backendDir, frontendDir, scope string) {
+	var backendPaths, frontendPaths []string
+	registered := false
+	var navigation *utils.NavigationItem
+	var widgets []utils.DashboardWidget
+	var reports []utils.NavigationItem
+
+	if backendDir != "" {
+		backendPaths = existingPaths(
+			filepath.Join(backendDir, "app", "models", naming.ModelSnake+".go"),
+			filepath.Join(backendDir, "app", naming.DirName),
+		)
+		registered = isRegisteredInInit(backendDir, naming.DirName)
+	}
+	if frontendDir != "" {
+		frontendPaths = existingPaths(
+			filepath.Join(frontendDir, "app", "modules", naming.PluralSnake),
+			filepath.Join(frontendDir, "app", "pages", "app", naming.PluralKebab),
+		)
+		navigation = utils.FindNavigationItem(frontendDir, "/app/"+naming.PluralKebab)
+		widgets = utils.FindDashboardWidgets(frontendDir, naming)
+		reports = reportNavigation(frontendDir, naming)
+	}
+
+	if len(backendPaths) == 0 && len(frontendPaths) == 0 && !registered && navigation == nil && len(widgets) == 0 {
+		cmd.PrintWarning("No module found: " + naming.Model)
 		return
 	}
 
-	// Paths to delete
-	paths := []string{
-		filepath.Join("app", "models", naming.ModelSnake+".go"),
-		filepath.Join("app", naming.DirName),
+	cmd.PrintWarning(fmt.Sprintf("Destroying module: %s (%s)", naming.Model, scope))
+	if destroyNoBackup {
+		cmd.PrintInfo("The following paths will be permanently deleted:")
+	} else {
+		cmd.PrintInfo("The following paths will be moved to " + utils.BackupRoot + ":")
+	}
+	for _, path := range append(backendPaths, frontendPaths...) {
+		cmd.PrintBullet(path)
+	}
+	if registered {
+		cmd.PrintInfo("The module will be removed from " + filepath.Join(backendDir, "app", "init.go"))
+	}
+	if navigation != nil || len(reports) > 0 {
+		cmd.PrintInfo("The module will be removed from the sidebar in " + filepath.Join(frontendDir, utils.NavigationFile))
+	}
+	if len(widgets) > 0 {
+		cmd.PrintInfo("The module's widgets will be removed from the dashboard in " + filepath.Join(frontendDir, utils.DashboardFile))
 	}
 
-	deleted := 0
-	for _, path := range paths {
-		if _, err := os.Stat(path); err == nil {
-			if err := os.RemoveAll(path); err != nil {
-				cmd.PrintError("Failed to delete: " + path)
-			} else {
-				cmd.PrintInfo("Deleted: " + path)
-				deleted++
-			}
+	if destroyDryRun {
+		cmd.PrintInfo("Dry run: nothing was deleted")
+		return
+	}
+
+	// Ask for confirmation unless --yes was given
+	if !destroyYes {
+		confirmed, err := interactive.AskConfirm("Are you sure you want to destroy this module?", false)
+		if err != nil || !confirmed {
+			cmd.PrintInfo("Operation cancelled")
+			return
 		}
 	}
 
-	if deleted == 0 {
-		cmd.PrintWarning("No backend module found: " + naming.Model)
-		return
+	var backup *utils.Backup
+	if !destroyNoBackup {
+		var err error
+		backup, err = utils.NewBackup(naming.Original, "destroy")
+		if err != nil {
+			cmd.PrintError(err.Error())
+			cmd.PrintInfo("Use --no-backup to delete without a backup")
+			return
+		}
+		backup.Manifest.Registered = registered
+		backup.Manifest.Namespace = naming.Namespace
+		backup.Manifest.BackendDir = backendDir
 	}
 
-	cmd.PrintSuccess("Backend module destroyed: " + naming.Model)
-	cmd.PrintInfo("Remember to remove from app/init.go if needed")
-}
+	backendDeleted := removePaths(cmd, backup, backendPaths)
+	frontendDeleted := removePaths(cmd, backup, frontendPaths)
 
-func destroyFrontend(cmd *mamba.Command, args []string) {
-	moduleName := args[0]
-	naming := utils.NewNamingConvention(moduleName)
+	if registered {
+		unregisterBackendModule(cmd, backendDir, naming)
+	}
+	if navigation != nil {
+		removed, err := utils.RemoveNavigationItem(frontendDir, navigation.To)
+		if err != nil {
+			cmd.PrintWarning(fmt.Sprintf("Could not update %s: %v", utils.NavigationFile, err))
+		} else if backup != nil {
+			backup.Manifest.Navigation = removed
+			backup.Manifest.FrontendDir = frontendDir
+		}
+	}
+	for _, report := range reports {
+		removed, err := utils.RemoveNavigationItem(frontendDir, report.To)
+		if err != nil {
+			cmd.PrintWarning(fmt.Sprintf("Could not update %s: %v", utils.NavigationFile, err))
+		} else if backup != nil && removed != nil {
+			backup.Manifest.Reports = append(backup.Manifest.Reports, *removed)
+			backup.Manifest.FrontendDir = frontendDir
+		}
+	}
+	if len(widgets) > 0 {
+		removed, err := utils.RemoveDashboardWidgets(frontendDir, naming)
+		if err != nil {
+			cmd.PrintWarning(fmt.Sprintf("Could not update %s: %v", utils.DashboardFile, err))
+		} else if backup != nil {
+			backup.Manifest.Widgets = removed
+			backup.Manifest.FrontendDir = frontendDir
+		}
+	}
 
-	cmd.PrintWarning("Destroying frontend module: " + naming.Model)
+	// Record the removal, so regenerate and the overwrite checks know the files are gone
+	if dir, err := os.Getwd(); err == nil {
+		removed := append(slices.Clone(backendPaths), frontendPaths...)
+		if err := utils.RecordDestroy(utils.ProjectRoot(dir), cmd, []string{naming.Original}, removed); err != nil {
+			cmd.PrintWarning(fmt.Sprintf("Could not update %s: %v", utils.ManifestFile, err))
+		}
+	}
 
-	// Ask for confirmation
-	confirmed, err := interactive.AskConfirm("Are you sure you want to destroy this frontend module?", false)
-	if err != nil || !confirmed {
-		cmd.PrintInfo("Operation cancelled")
-		return
+	if backup != nil {
+		if err := backup.Save(); err != nil {
+			cmd.PrintWarning("Failed to save backup manifest: " + err.Error())
+		}
+		cmd.PrintInfo(fmt.Sprintf("Files moved to %s (restore with: bui restore %s)", backup.Dir, utils.BackupName(naming.Original)))
+	}
+
+	if backendDeleted > 0 {
+		cmd.PrintSuccess("Backend module destroyed: " + naming.Model)
+	}
+	if frontendDeleted > 0 {
+		cmd.PrintSuccess("Frontend module destroyed: " + naming.Model)
 	}
+}
 
-	// Paths to delete
-	paths := []string{
-		filepath.Join("app", "modules", naming.PluralSnake),
-		filepath.Join("app", "pages", "app", naming.PluralKebab),
+// existingPaths filters paths down to the ones present on disk
+func existingPaths(paths ...string) []string {
+	var existing []string
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			existing = append(existing, path)
+		}
 	}
+	return existing
+}
 
+// reportNavigation returns the sidebar entries of the module's report pages
+func reportNavigation(frontendDir string, naming *utils.NamingConvention) []utils.NavigationItem {
+	pages, _ := filepath.Glob(filepath.Join(frontendDir, "app", "pages", "app", naming.PluralKebab, "reports", "*.vue"))
+	var items []utils.NavigationItem
+	for _, page := range pages {
+		route := "/app/" + naming.PluralKebab + "/reports/" + strings.TrimSuffix(filepath.Base(page), ".vue")
+		if item := utils.FindNavigationItem(frontendDir, route); item != nil {
+			items = append(items, *item)
+		}
+	}
+	return items
+}
+
+// removePaths moves each path into backup (or deletes it when backup is nil) and returns how many were removed
+func removePaths(cmd *mamba.Command, backup *utils.Backup, paths []string) int {
 	deleted := 0
 	for _, path := range paths {
-		if _, err := os.Stat(path); err == nil {
-			if err := os.RemoveAll(path); err != nil {
-				cmd.PrintError("Failed to delete: " + path)
-			} else {
-				cmd.PrintInfo("Deleted: " + path)
-				deleted++
-			}
+		var err error
+		if backup != nil {
+			err = backup.Move(path)
+		} else {
+			err = os.RemoveAll(path)
+		}
+		if err != nil {
+			cmd.PrintError("Failed to delete: " + path)
+			continue
 		}
+		if Verbose {
+			cmd.PrintInfo("Deleted: " + path)
+		}
+		deleted++
 	}
+	return deleted
+}
 
-	if deleted == 0 {
-		cmd.PrintWarning("No frontend module found: " + naming.Model)
+// isRegisteredInInit reports whether app/init.go initializes the module
+func isRegisteredInInit(backendDir, dirName string) bool {
+	content, err := os.ReadFile(filepath.Join(backendDir, "app", "init.go"))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(content), fmt.Sprintf(`modules["%s"]`, dirName))
+}
+
+// unregisterBackendModule removes the module's import and initializer from app/init.go and reformats the file
+func unregisterBackendModule(cmd *mamba.Command, backendDir string, naming *utils.NamingConvention) {
+	initGoPath := filepath.Join(backendDir, "app", "init.go")
+	if _, err := os.Stat(initGoPath); err != nil {
 		return
 	}
 
-	cmd.PrintSuccess("Frontend module destroyed: " + naming.Model)
+	changed, err := utils.RemoveFromInitFile(backendDir, naming.DirName)
+	if err != nil {
+		cmd.PrintWarning("Could not update app/init.go: " + err.Error())
+		cmd.PrintInfo(fmt.Sprintf("Manually remove from app/init.go: modules[\"%s\"] = %s.Init(deps)", naming.DirName, utils.InitPackage(naming.DirName)))
+		return
+	}
+	if !changed {
+		return
+	}
+
+	if err := exec.Command("gofmt", "-w", initGoPath).Run(); err != nil {
+		if Verbose {
+			cmd.PrintWarning("Failed to format app/init.go")
+		}
+	}
+
+	cmd.PrintSuccess("Removed module from app/init.go")
 }
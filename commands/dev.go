@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"fmt"
 	"net/http"
 	"os"
 	"os/exec"
@@ -9,60 +10,98 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/base-al/bui/utils"
 	"github.com/base-go/mamba"
 )
 
+var (
+	// devQuiet hides the servers' output
+	devQuiet bool
+
+	// devOnly starts just the backend or the frontend
+	devOnly string
+
+	// devAPIPort and devAppPort override the backend and frontend ports
+	devAPIPort int
+	devAppPort int
+
+	// devDocker starts the database, Redis and Mailpit containers alongside the servers
+	devDocker bool
+
+	// devInteractive enables the restart keys and status header in a terminal
+	devInteractive bool
+
+	// devTunnel exposes the servers on public URLs with cloudflared or ngrok
+	devTunnel string
+)
+
 var devCmd = &mamba.Command{
 	Use:   "dev",
 	Short: "Start both backend and frontend development servers",
-	Long:  `Start both backend (admin-api) and frontend (admin) development servers concurrently.`,
-	Run:   runDev,
+	Long: `Start both backend (admin-api) and frontend (admin) development servers concurrently.
+
+Server output is shown with an [api] or [app] prefix on every line. If either server
+exits, the other is stopped too and bui dev exits with an error.
+
+In a terminal, press r to restart the backend, f to restart the frontend, s to
+regenerate the Swagger docs, h to show the status (uptime, ports, restarts) and
+q to quit. Use --interactive=false to turn the keys off.
+
+--tunnel exposes both servers on public HTTPS URLs through cloudflared or ngrok,
+whichever is on PATH (or the one named, e.g. --tunnel=ngrok), for demos and webhooks.
+The frontend still calls the API URL it is configured with.
+
+Ports come from --api-port/--app-port, then ports in .bui.yaml, then SERVER_PORT/PORT
+in the backend's .env and NUXT_PORT/PORT in the frontend's, then 8000 and 3030.
+A port that is taken is replaced by the next free one unless it was given as a flag.
+
+With --docker, the database (PostgreSQL or MySQL), Redis and Mailpit run in containers
+from docker-compose.dev.yml in the project root (generated on first use from the backend's
+DB_* settings) and are stopped again when the servers exit.
+
+Examples:
+  bui dev                                # Start both servers
+  bui dev --only backend                 # Start just the API
+  bui dev --quiet                        # Hide server output
+  bui dev --docker                       # Also run PostgreSQL, Redis and Mailpit
+  bui dev --tunnel                       # Share public URLs via cloudflared or ngrok
+  bui dev --api-port 9000 --app-port 3001`,
+	Run: runDev,
 }
 
 func init() {
 	rootCmd.AddCommand(devCmd)
+	devCmd.Flags().BoolVarP(&devQuiet, "quiet", "q", false, "Hide backend and frontend output")
+	devCmd.Flags().StringVar(&devOnly, "only", "", "Start only one server: backend or frontend")
+	devCmd.Flags().IntVar(&devAPIPort, "api-port", 0, "Port for the backend server")
+	devCmd.Flags().IntVar(&devAppPort, "app-port", 0, "Port for the frontend dev server")
+	devCmd.Flags().BoolVar(&devDocker, "docker", false, "Run the database, Redis and Mailpit with docker compose")
+	devCmd.Flags().BoolVar(&devInteractive, "interactive", true, "Enable the r/f/s keys and status header in a terminal")
+	devCmd.Flags().StringVar(&devTunnel, "tunnel", "", "Expose the servers on public URLs: cloudflared or ngrok (default: whichever is installed)")
+	devCmd.Flags().Lookup("tunnel").NoOptDefVal = "auto"
 }
 
 func runDev(cmd *mamba.Command, args []string) {
-	// Check for backend and frontend directories
-	// Support both standalone directories and monorepo structure
-	backendDir := ""
-	frontendDir := ""
+	if devOnly != "" && devOnly != "backend" && devOnly != "frontend" {
+		cmd.PrintError(fmt.Sprintf("Invalid --only value: %s", devOnly))
+		cmd.PrintInfo("Use --only backend or --only frontend")
+		os.Exit(1)
+	}
 
-	// Check for standalone structure (running from individual project directory)
-	if fileExists("main.go") {
-		backendDir = "."
-	} else {
-		// Look for directories ending with -api (new structure)
-		backendDir = findDirWithSuffix("-api")
-		// Fallback to old structure
-		if backendDir == "" {
-			if dirExists("admin-api-template") {
-				backendDir = "admin-api-template"
-			} else if dirExists("admin-api") {
-				backendDir = "admin-api"
-			}
-		}
+	backendDir := findBackendDir()
+	if devOnly == "frontend" {
+		backendDir = ""
 	}
 
 	// Generate Swagger docs if backend is found
 	if backendDir != "" {
+		// Not critical for dev, so failures are ignored
 		generateSwaggerDocs(cmd, backendDir)
 	}
 
-	if fileExists("nuxt.config.ts") {
-		frontendDir = "."
-	} else {
-		// Look for directories ending with -app (new structure)
-		frontendDir = findDirWithSuffix("-app")
-		// Fallback to old structure
-		if frontendDir == "" {
-			if dirExists("admin-template") {
-				frontendDir = "admin-template"
-			} else if dirExists("admin") {
-				frontendDir = "admin"
-			}
-		}
+	frontendDir := findFrontendDir()
+	if devOnly == "backend" {
+		frontendDir = ""
 	}
 
 	if backendDir == "" && frontendDir == "" {
@@ -71,75 +110,177 @@ func runDev(cmd *mamba.Command, args []string) {
 		os.Exit(1)
 	}
 
+	// Pick free ports before starting anything
+	apiPort, appPort := 0, 0
+	configured := configuredPorts()
+	if backendDir != "" {
+		apiPort = resolveDevPort(cmd, "Backend", devAPIPort,
+			preferredPort(configured.Backend, backendDir, backendPortEnvKeys, utils.DefaultBackendPort))
+	}
+	if frontendDir != "" {
+		appPort = resolveDevPort(cmd, "Frontend", devAppPort,
+			preferredPort(configured.Frontend, frontendDir, frontendPortEnvKeys, utils.DefaultFrontendPort))
+	}
+
+	var tunnelTool *tunnelProvider
+	if devTunnel != "" {
+		var err error
+		if tunnelTool, err = findTunnelProvider(devTunnel); err != nil {
+			cmd.PrintError("Can't open a tunnel: " + err.Error())
+			os.Exit(1)
+		}
+	}
+
 	// Create channel to handle shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
-	var processes []*exec.Cmd
-
-	// Start backend
-	if backendDir != "" {
-		cmd.PrintInfo("Starting backend server...")
-		backendCmd := exec.Command("go", "run", "main.go")
-		if backendDir != "." {
-			backendCmd.Dir = backendDir
-		}
-		// Pipe output to terminal
-		backendCmd.Stdout = os.Stdout
-		backendCmd.Stderr = os.Stderr
-
-		if err := backendCmd.Start(); err != nil {
-			cmd.PrintError("Error starting backend: " + err.Error())
-		} else {
-			processes = append(processes, backendCmd)
-			// Wait a bit for backend to initialize
-			waitForBackend(cmd)
-			cmd.PrintSuccess("Backend server ready (http://localhost:8000)")
+	// Start the containers the backend depends on
+	var services *devServices
+	if devDocker {
+		var err error
+		if services, err = startDevServices(cmd, backendDir); err != nil {
+			cmd.PrintError(err.Error())
+			os.Exit(1)
 		}
 	}
 
-	// Start frontend
+	// Buffered so a server that exits after bui dev stopped listening doesn't block
+	exited := make(chan *devProcess, 2)
+	var servers []*devServer
+
+	if backendDir != "" {
+		servers = append(servers, newBackendServer(backendDir, apiPort))
+	}
 	if frontendDir != "" {
-		cmd.PrintInfo("Starting frontend server...")
-		frontendCmd := exec.Command("bun", "dev")
-		if frontendDir != "." {
-			frontendCmd.Dir = frontendDir
-		}
-		// Pipe output to terminal
-		frontendCmd.Stdout = os.Stdout
-		frontendCmd.Stderr = os.Stderr
-
-		if err := frontendCmd.Start(); err != nil {
-			cmd.PrintError("Error starting frontend: " + err.Error())
-		} else {
-			processes = append(processes, frontendCmd)
-			// Wait a bit for frontend to initialize
-			waitForFrontend(cmd)
-			cmd.PrintSuccess("Frontend server ready (http://localhost:3030)")
+		servers = append(servers, newFrontendServer(frontendDir, appPort))
+	}
+
+	var running []*devServer
+	for _, server := range servers {
+		cmd.PrintInfo(fmt.Sprintf("Starting %s server...", strings.ToLower(server.name)))
+		if err := server.start(cmd, exited); err != nil {
+			cmd.PrintError(fmt.Sprintf("Error starting %s: %v", strings.ToLower(server.name), err))
+			continue
 		}
+		running = append(running, server)
+		cmd.PrintSuccess(fmt.Sprintf("%s server ready (%s)", server.name, server.url()))
 	}
 
-	if len(processes) == 0 {
+	if len(running) == 0 {
 		cmd.PrintError("No servers started")
+		if services != nil {
+			services.stop(cmd)
+		}
 		os.Exit(1)
 	}
 
+	// Expose the servers publicly
+	var tunnels []*devProcess
+	if tunnelTool != nil {
+		for _, server := range running {
+			cmd.PrintInfo(fmt.Sprintf("Opening %s tunnel to the %s...", tunnelTool.name, strings.ToLower(server.name)))
+			tunnel, err := startTunnel(tunnelTool, server)
+			if err != nil {
+				cmd.PrintWarning(fmt.Sprintf("%s tunnel failed: %v", server.name, err))
+				continue
+			}
+			tunnels = append(tunnels, tunnel)
+			cmd.PrintSuccess(fmt.Sprintf("%s public URL: %s", server.name, server.publicURL))
+		}
+	}
+
+	// Read single key presses when attached to a terminal
+	var keys <-chan byte
+	restoreTerminal := func() {}
+	if devInteractive {
+		keys, restoreTerminal = readDevKeys()
+	}
+	startedAt := time.Now()
+
 	cmd.PrintSuccess("All servers running. Press Ctrl+C to stop.")
+	if keys != nil {
+		printDevStatus(cmd, running, startedAt)
+	}
 
-	// Wait for interrupt signal
-	<-sigChan
+	// Wait for an interrupt or for a server to exit on its own, handling keys in between
+	var crashed *devProcess
+	for quit := false; !quit; {
+		select {
+		case <-sigChan:
+			quit = true
+		case p := <-exited:
+			// Processes stopped for a restart exit too; only an unexpected exit ends bui dev
+			if !p.stopped {
+				crashed, quit = p, true
+			}
+		case key := <-keys:
+			quit = handleDevKey(cmd, key, running, backendDir, startedAt, exited)
+		}
+	}
+	restoreTerminal()
 
-	// Stop all processes
+	// Stop the servers along with the processes they started, so their ports are released
 	cmd.PrintInfo("Stopping servers...")
-	for _, p := range processes {
-		if p.Process != nil {
-			p.Process.Kill()
-		}
+	for _, tunnel := range tunnels {
+		tunnel.stop()
+	}
+	for _, server := range running {
+		server.stop()
+	}
+
+	if services != nil {
+		services.stop(cmd)
+	}
+
+	if crashed != nil {
+		cmd.PrintError(crashed.exitError().Error())
+		os.Exit(1)
 	}
 
 	cmd.PrintSuccess("All servers stopped")
 }
 
+// findBackendDir returns the backend directory: the configured one, the current directory, a
+// directory ending in -api, or the old admin-api layout. It returns "" when there is none.
+func findBackendDir() string {
+	if dir := utils.Project.BackendDir(); dir != "" {
+		return dir
+	}
+	if fileExists("main.go") {
+		return "."
+	}
+	if dir := findDirWithSuffix("-api"); dir != "" {
+		return dir
+	}
+	for _, dir := range []string{"admin-api-template", "admin-api"} {
+		if dirExists(dir) {
+			return dir
+		}
+	}
+	return ""
+}
+
+// findFrontendDir returns the frontend directory: the configured one, the current directory, a
+// directory ending in -app, or the old admin layout. It returns "" when there is none.
+func findFrontendDir() string {
+	if dir := utils.Project.FrontendDir(); dir != "" {
+		return dir
+	}
+	if fileExists("nuxt.config.ts") {
+		return "."
+	}
+	if dir := findDirWithSuffix("-app"); dir != "" {
+		return dir
+	}
+	for _, dir := range []string{"admin-template", "admin"} {
+		if dirExists(dir) {
+			return dir
+		}
+	}
+	return ""
+}
+
 func dirExists(path string) bool {
 	info, err := os.Stat(path)
 	if os.IsNotExist(err) {
@@ -174,11 +315,72 @@ func findDirWithSuffix(suffix string) string {
 	return ""
 }
 
+// newBackendServer returns the backend dev server, run with go run on port
+func newBackendServer(dir string, port int) *devServer {
+	return &devServer{
+		name:  "Backend",
+		label: "api",
+		color: colorCyan,
+		dir:   dir,
+		port:  port,
+		command: func() *exec.Cmd {
+			backendCmd := exec.Command("go", "run", "main.go")
+			// BUI_DEV turns on routes meant for development only, such as the mail previews
+			backendCmd.Env = append(os.Environ(), fmt.Sprintf("SERVER_PORT=%d", port), fmt.Sprintf("PORT=%d", port), "BUI_DEV=true")
+			return backendCmd
+		},
+		ready: waitForBackend,
+	}
+}
+
+// newFrontendServer returns the frontend dev server, run with the dev script on port
+func newFrontendServer(dir string, port int) *devServer {
+	return &devServer{
+		name:  "Frontend",
+		label: "app",
+		color: colorMagenta,
+		dir:   dir,
+		port:  port,
+		command: func() *exec.Cmd {
+			frontendCmd := packageScript("dev", "--port", fmt.Sprint(port))
+			frontendCmd.Env = append(os.Environ(), fmt.Sprintf("NUXT_PORT=%d", port), fmt.Sprintf("PORT=%d", port))
+			return frontendCmd
+		},
+		ready: waitForFrontend,
+	}
+}
+
+// packageBinary returns a command that runs a binary of the project's node_modules with its
+// package manager, e.g. npx playwright
+func packageBinary(binary string, args ...string) *exec.Cmd {
+	switch manager := utils.Project.PackageManagerName(); manager {
+	case "pnpm":
+		return exec.Command(manager, append([]string{"exec", binary}, args...)...)
+	case "yarn":
+		return exec.Command(manager, append([]string{binary}, args...)...)
+	case "bun":
+		return exec.Command("bunx", append([]string{binary}, args...)...)
+	default:
+		return exec.Command("npx", append([]string{binary}, args...)...)
+	}
+}
+
+// packageScript returns a command that runs a package.json script with the project's package manager
+func packageScript(script string, args ...string) *exec.Cmd {
+	manager := utils.Project.PackageManagerName()
+	scriptArgs := []string{"run", script}
+	if len(args) > 0 && manager == "npm" {
+		// npm only forwards arguments that follow --
+		scriptArgs = append(scriptArgs, "--")
+	}
+	return exec.Command(manager, append(scriptArgs, args...)...)
+}
+
 // waitForBackend waits for the backend server to be ready
-func waitForBackend(cmd *mamba.Command) {
+func waitForBackend(cmd *mamba.Command, port int) {
 	client := &http.Client{Timeout: 1 * time.Second}
 	for i := 0; i < 50; i++ {
-		resp, err := client.Get("http://localhost:8000/health")
+		resp, err := client.Get(fmt.Sprintf("http://localhost:%d/health", port))
 		if err == nil && resp.StatusCode == 200 {
 			resp.Body.Close()
 			return
@@ -188,10 +390,10 @@ func waitForBackend(cmd *mamba.Command) {
 }
 
 // waitForFrontend waits for the frontend server to be ready
-func waitForFrontend(cmd *mamba.Command) {
+func waitForFrontend(cmd *mamba.Command, port int) {
 	client := &http.Client{Timeout: 1 * time.Second}
 	for i := 0; i < 50; i++ {
-		resp, err := client.Get("http://localhost:3030")
+		resp, err := client.Get(fmt.Sprintf("http://localhost:%d", port))
 		if err == nil {
 			resp.Body.Close()
 			return
@@ -201,11 +403,11 @@ func waitForFrontend(cmd *mamba.Command) {
 }
 
 // generateSwaggerDocs generates Swagger documentation for the backend
-func generateSwaggerDocs(cmd *mamba.Command, backendDir string) {
+func generateSwaggerDocs(cmd *mamba.Command, backendDir string) error {
 	// Find go executable
 	goPath, err := exec.LookPath("go")
 	if err != nil {
-		return
+		return err
 	}
 
 	// Ensure swag is installed
@@ -213,7 +415,7 @@ func generateSwaggerDocs(cmd *mamba.Command, backendDir string) {
 		installCmd := exec.Command(goPath, "install", "github.com/swaggo/swag/cmd/swag@latest")
 		// Suppress output
 		if err := installCmd.Run(); err != nil {
-			return
+			return fmt.Errorf("failed to install swag: %w", err)
 		}
 	}
 
@@ -221,9 +423,5 @@ func generateSwaggerDocs(cmd *mamba.Command, backendDir string) {
 	swagCmd := exec.Command("swag", "init", "--dir", "./", "--output", "./swagger", "--parseDependency", "--parseInternal", "--parseVendor", "--parseDepth", "1", "--generatedTime", "false", "--quiet")
 	swagCmd.Dir = backendDir
 	// Don't pipe output to suppress all swagger logs
-
-	if err := swagCmd.Run(); err != nil {
-		// Silently fail - not critical for dev
-		return
-	}
+	return swagCmd.Run()
 }
@@ -0,0 +1,195 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// devComposeFile is the compose file bui dev --docker generates in the project root
+const devComposeFile = "docker-compose.dev.yml"
+
+// devComposeTemplate describes the services the generated backend expects in development
+var devComposeTemplate = template.Must(template.New(devComposeFile).Parse(`# Development services for bui dev --docker.
+# Generated by bui; edit freely, bui won't overwrite it.
+name: {{.Name}}
+
+services:
+{{- if eq .Database "postgres"}}
+  postgres:
+    image: postgres:16-alpine
+    environment:
+      POSTGRES_USER: {{printf "%q" .DBUser}}
+      POSTGRES_PASSWORD: {{printf "%q" .DBPassword}}
+      POSTGRES_DB: {{printf "%q" .DBName}}
+    ports:
+      - "{{.DBPort}}:5432"
+    volumes:
+      - postgres-data:/var/lib/postgresql/data
+    healthcheck:
+      test: ["CMD-SHELL", "pg_isready -U {{.DBUser}} -d {{.DBName}}"]
+      interval: 2s
+      timeout: 5s
+      retries: 15
+{{- else if eq .Database "mysql"}}
+  mysql:
+    image: mysql:8.4
+    environment:
+{{- if eq .DBUser "root"}}
+      MYSQL_ROOT_PASSWORD: {{printf "%q" .DBPassword}}
+{{- else}}
+      MYSQL_RANDOM_ROOT_PASSWORD: "yes"
+      MYSQL_USER: {{printf "%q" .DBUser}}
+      MYSQL_PASSWORD: {{printf "%q" .DBPassword}}
+{{- end}}
+      MYSQL_DATABASE: {{printf "%q" .DBName}}
+    ports:
+      - "{{.DBPort}}:3306"
+    volumes:
+      - mysql-data:/var/lib/mysql
+    healthcheck:
+      test: ["CMD", "mysqladmin", "ping", "-h", "127.0.0.1"]
+      interval: 2s
+      timeout: 5s
+      retries: 30
+{{- end}}
+
+  redis:
+    image: redis:7-alpine
+    ports:
+      - "6379:6379"
+    healthcheck:
+      test: ["CMD", "redis-cli", "ping"]
+      interval: 2s
+      timeout: 5s
+      retries: 15
+
+  mailpit:
+    image: axllent/mailpit
+    ports:
+      - "1025:1025" # SMTP
+      - "8025:8025" # Web UI
+
+{{- if ne .Database "sqlite"}}
+
+volumes:
+  {{.Database}}-data:
+{{- end}}
+`))
+
+// devComposeData fills in devComposeTemplate
+type devComposeData struct {
+	Name       string
+	Database   string // postgres or mysql get a container; SQLite needs none
+	DBUser     string
+	DBPassword string
+	DBName     string
+	DBPort     string
+}
+
+// composeNameInvalid matches characters docker compose doesn't allow in a project name
+var composeNameInvalid = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// devServices manages the containers started by bui dev --docker
+type devServices struct {
+	dir  string
+	file string
+}
+
+// startDevServices writes the compose file when missing and starts the containers, waiting until they are healthy
+func startDevServices(cmd *mamba.Command, backendDir string) (*devServices, error) {
+	if err := exec.Command("docker", "compose", "version").Run(); err != nil {
+		return nil, fmt.Errorf("docker compose is not available; install Docker with the compose plugin")
+	}
+
+	dir, _ := os.Getwd()
+	if root := utils.Project.Root(); root != "" {
+		dir = root
+	}
+	services := &devServices{dir: dir, file: filepath.Join(dir, devComposeFile)}
+
+	if !fileExists(services.file) {
+		if err := writeDevComposeFile(services.file, backendDir); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", devComposeFile, err)
+		}
+		cmd.PrintSuccess("Created " + devComposeFile)
+	}
+
+	containers := "Redis and Mailpit"
+	if database := utils.Database(); database != utils.SQLite {
+		containers = utils.DatabaseLabel(database) + ", " + containers
+	}
+	cmd.PrintInfo(fmt.Sprintf("Starting %s containers...", containers))
+	if err := services.compose("up", "-d", "--wait").Run(); err != nil {
+		return nil, fmt.Errorf("docker compose up failed: %w", err)
+	}
+	cmd.PrintSuccess("Services ready (Mailpit: http://localhost:8025)")
+	return services, nil
+}
+
+// stop removes the containers; the database volume is kept
+func (s *devServices) stop(cmd *mamba.Command) {
+	cmd.PrintInfo("Stopping containers...")
+	if err := s.compose("down").Run(); err != nil {
+		cmd.PrintWarning("docker compose down failed: " + err.Error())
+	}
+}
+
+// compose returns a docker compose command for the dev compose file
+func (s *devServices) compose(args ...string) *exec.Cmd {
+	composeCmd := exec.Command("docker", append([]string{"compose", "-f", s.file}, args...)...)
+	composeCmd.Dir = s.dir
+	if Verbose {
+		composeCmd.Stdout = os.Stdout
+	}
+	composeCmd.Stderr = os.Stderr
+	return composeCmd
+}
+
+// writeDevComposeFile generates the compose file, taking database settings from the backend's .env
+func writeDevComposeFile(path, backendDir string) error {
+	name := composeNameInvalid.ReplaceAllString(strings.ToLower(filepath.Base(filepath.Dir(path))), "-")
+	name = strings.Trim(name, "-_")
+	if name == "" {
+		name = "bui"
+	}
+
+	env := map[string]string{}
+	if backendDir != "" {
+		env = readEnvFile(filepath.Join(backendDir, ".env"))
+	}
+	data := devComposeData{
+		Name:       name + "-dev",
+		Database:   utils.Database(),
+		DBUser:     envOr(env, "DB_USER", "postgres"),
+		DBPassword: envOr(env, "DB_PASSWORD", "postgres"),
+		DBName:     envOr(env, "DB_NAME", strings.ReplaceAll(name, "-", "_")),
+		DBPort:     envOr(env, "DB_PORT", "5432"),
+	}
+	if data.Database == utils.MySQL {
+		data.DBUser = envOr(env, "DB_USER", "root")
+		data.DBPort = envOr(env, "DB_PORT", "3306")
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return devComposeTemplate.Execute(file, data)
+}
+
+// envOr returns env[key], or fallback when it is unset or empty
+func envOr(env map[string]string, key, fallback string) string {
+	if value := env[key]; value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/base-go/mamba"
+)
+
+// devKeyHelp lists the keys bui dev reacts to
+const devKeyHelp = "r restart api · f restart app · s regenerate swagger · h status · q quit"
+
+// readDevKeys switches the terminal to unbuffered input without echo and returns the keys pressed,
+// along with a function that restores the terminal. Keys is nil when stdin isn't a terminal.
+func readDevKeys() (<-chan byte, func()) {
+	noop := func() {}
+	if runtime.GOOS == "windows" || !stdinIsTerminal() {
+		return nil, noop
+	}
+
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, noop
+	}
+	// Leave signal handling on so Ctrl+C still stops bui dev
+	if _, err := stty("-icanon", "-echo", "min", "1"); err != nil {
+		return nil, noop
+	}
+
+	keys := make(chan byte)
+	go func() {
+		buf := make([]byte, 1)
+		for {
+			if n, err := os.Stdin.Read(buf); err != nil {
+				return
+			} else if n == 1 {
+				keys <- buf[0]
+			}
+		}
+	}()
+	return keys, func() { stty(strings.TrimSpace(saved)) }
+}
+
+// stty runs stty against the terminal on stdin
+func stty(args ...string) (string, error) {
+	sttyCmd := exec.Command("stty", args...)
+	sttyCmd.Stdin = os.Stdin
+	out, err := sttyCmd.Output()
+	return string(out), err
+}
+
+// stdinIsTerminal reports whether stdin is an interactive terminal
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// handleDevKey acts on a key pressed during bui dev and reports whether it asked to quit
+func handleDevKey(cmd *mamba.Command, key byte, servers []*devServer, backendDir string, startedAt time.Time, exited chan<- *devProcess) bool {
+	switch key {
+	case 'r', 'R':
+		restartDevServer(cmd, servers, "api", exited)
+	case 'f', 'F':
+		restartDevServer(cmd, servers, "app", exited)
+	case 's', 'S':
+		if backendDir == "" {
+			cmd.PrintWarning("No backend to generate Swagger docs for")
+			break
+		}
+		cmd.PrintInfo("Regenerating Swagger docs...")
+		if err := generateSwaggerDocs(cmd, backendDir); err != nil {
+			cmd.PrintError("Swagger generation failed: " + err.Error())
+			break
+		}
+		cmd.PrintSuccess("Swagger docs regenerated")
+	case 'h', 'H', '?', '\n':
+		printDevStatus(cmd, servers, startedAt)
+	case 'q', 'Q':
+		return true
+	}
+	return false
+}
+
+// restartDevServer restarts the server with the given output label
+func restartDevServer(cmd *mamba.Command, servers []*devServer, label string, exited chan<- *devProcess) {
+	for _, server := range servers {
+		if server.label != label {
+			continue
+		}
+		name := strings.ToLower(server.name)
+		cmd.PrintInfo(fmt.Sprintf("Restarting %s server...", name))
+		if err := server.restart(cmd, exited); err != nil {
+			cmd.PrintError(fmt.Sprintf("Error restarting %s: %v", name, err))
+			return
+		}
+		cmd.PrintSuccess(fmt.Sprintf("%s server ready (%s)", server.name, server.url()))
+		return
+	}
+	cmd.PrintWarning(fmt.Sprintf("No [%s] server is running under bui dev", label))
+}
+
+// printDevStatus shows uptime, ports and restarts for each server
+func printDevStatus(cmd *mamba.Command, servers []*devServer, startedAt time.Time) {
+	var lines []string
+	for _, server := range servers {
+		state := "running"
+		if server.process == nil || !server.process.running() {
+			state = "stopped"
+		}
+		line := fmt.Sprintf("%-4s %-23s %s", server.label, server.url(), state)
+		if server.publicURL != "" {
+			line += " · " + server.publicURL
+		}
+		if server.restarts > 0 {
+			line += fmt.Sprintf(" · restarted %d× (last %s)", server.restarts, server.lastRestart.Format("15:04:05"))
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "", devKeyHelp)
+
+	devOutputMu.Lock()
+	defer devOutputMu.Unlock()
+	cmd.PrintBox(fmt.Sprintf("bui dev · up %s", time.Since(startedAt).Round(time.Second)), strings.Join(lines, "\n"))
+}
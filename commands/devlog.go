@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+)
+
+// ANSI colors for the server log prefixes
+const (
+	colorCyan    = "\033[36m"
+	colorMagenta = "\033[35m"
+	colorReset   = "\033[0m"
+)
+
+// devOutputMu keeps lines from the backend and frontend from interleaving mid-line
+var devOutputMu sync.Mutex
+
+// prefixWriter writes each line it receives to out with a (colored) prefix such as [api]
+type prefixWriter struct {
+	out    io.Writer
+	prefix []byte
+	buf    []byte
+}
+
+// newPrefixWriter returns a writer that prefixes every line with label, colored when stdout is a terminal
+func newPrefixWriter(out io.Writer, label, color string) *prefixWriter {
+	prefix := "[" + label + "] "
+	if useColor() {
+		prefix = color + "[" + label + "]" + colorReset + " "
+	}
+	return &prefixWriter{out: out, prefix: []byte(prefix)}
+}
+
+// Write buffers p and writes every complete line with the prefix
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		if err := w.writeLine(w.buf[:i+1]); err != nil {
+			return len(p), err
+		}
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes a trailing partial line, e.g. once the process has exited
+func (w *prefixWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	line := append(w.buf, '\n')
+	w.buf = nil
+	return w.writeLine(line)
+}
+
+// writeLine writes one prefixed line while holding the shared output lock
+func (w *prefixWriter) writeLine(line []byte) error {
+	devOutputMu.Lock()
+	defer devOutputMu.Unlock()
+	if _, err := w.out.Write(w.prefix); err != nil {
+		return err
+	}
+	_, err := w.out.Write(line)
+	return err
+}
+
+// useColor reports whether stdout is a terminal and NO_COLOR is unset
+func useColor() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// portSearchRange is how many ports above the preferred one bui dev tries when it is taken
+const portSearchRange = 20
+
+// Keys a server's port is read from in its .env file, in order of preference
+var (
+	backendPortEnvKeys  = []string{"SERVER_PORT", "PORT"}
+	frontendPortEnvKeys = []string{"NUXT_PORT", "PORT"}
+)
+
+// configuredPorts returns the ports set in .bui.yaml, zero where unset
+func configuredPorts() utils.PortsConfig {
+	if utils.Project == nil {
+		return utils.PortsConfig{}
+	}
+	return utils.Project.Ports
+}
+
+// resolveDevPort picks a free port for a server, exiting when the requested one can't be used
+func resolveDevPort(cmd *mamba.Command, server string, flagValue, preferred int) int {
+	port, err := devPort(flagValue, preferred)
+	if err != nil {
+		cmd.PrintError(fmt.Sprintf("%s port: %v", server, err))
+		cmd.PrintInfo("Stop the process using it or pick another port with --api-port/--app-port")
+		os.Exit(1)
+	}
+	if port != preferred && flagValue == 0 {
+		cmd.PrintWarning(fmt.Sprintf("%s port %d is in use; using %d", server, preferred, port))
+	}
+	return port
+}
+
+// preferredPort returns the port set in .bui.yaml, else the one in the server's .env, else fallback
+func preferredPort(configured int, dir string, envKeys []string, fallback int) int {
+	if configured != 0 {
+		return configured
+	}
+	if dir != "" {
+		if port := envPort(filepath.Join(dir, ".env"), envKeys); port != 0 {
+			return port
+		}
+	}
+	return fallback
+}
+
+// devPort returns the port a dev server should listen on. A port given with a flag must be free;
+// otherwise the first free port from preferred upwards is used.
+func devPort(flagValue, preferred int) (int, error) {
+	if flagValue != 0 {
+		if !portFree(flagValue) {
+			return 0, fmt.Errorf("port %d is already in use", flagValue)
+		}
+		return flagValue, nil
+	}
+
+	for port := preferred; port <= preferred+portSearchRange; port++ {
+		if portFree(port) {
+			return port, nil
+		}
+	}
+	return 0, fmt.Errorf("ports %d-%d are all in use", preferred, preferred+portSearchRange)
+}
+
+// portFree reports whether nothing is listening on port
+func portFree(port int) bool {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return false
+	}
+	listener.Close()
+	return true
+}
+
+// envPort reads a port from the first of keys set in a .env file, or returns 0
+func envPort(path string, keys []string) int {
+	values := readEnvFile(path)
+	for _, key := range keys {
+		// Accept both 8000 and :8000
+		if port, err := strconv.Atoi(strings.TrimPrefix(values[key], ":")); err == nil && port > 0 {
+			return port
+		}
+	}
+	return 0
+}
+
+// readEnvFile returns the KEY=value pairs of a .env file; a missing file gives an empty map
+func readEnvFile(path string) map[string]string {
+	values := map[string]string{}
+	file, err := os.Open(path)
+	if err != nil {
+		return values
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if ok {
+			values[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	return values
+}
@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/base-go/mamba"
+)
+
+// devStopTimeout is how long a dev server gets to exit after SIGTERM before it is killed
+const devStopTimeout = 5 * time.Second
+
+// devProcess is a dev server started by bui dev, together with its exit status
+type devProcess struct {
+	name    string
+	cmd     *exec.Cmd
+	done    chan struct{}
+	err     error
+	stopped bool // Set when bui dev stopped it, as opposed to it exiting on its own
+}
+
+// startDevProcess starts cmd in its own process group and reports its exit on exited, when given
+func startDevProcess(name string, cmd *exec.Cmd, exited chan<- *devProcess) (*devProcess, error) {
+	setProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	p := &devProcess{name: name, cmd: cmd, done: make(chan struct{})}
+	go func() {
+		p.err = cmd.Wait()
+		close(p.done)
+		if exited != nil {
+			exited <- p
+		}
+	}()
+	return p, nil
+}
+
+// running reports whether the process has not exited yet
+func (p *devProcess) running() bool {
+	select {
+	case <-p.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// stop terminates the process and everything it started, killing the group when it doesn't exit in time
+func (p *devProcess) stop() {
+	p.stopped = true
+	if !p.running() {
+		// The server is gone, but processes it started may still hold its port
+		terminateProcessGroup(p.cmd)
+		return
+	}
+	terminateProcessGroup(p.cmd)
+	select {
+	case <-p.done:
+	case <-time.After(devStopTimeout):
+		killProcessGroup(p.cmd)
+		<-p.done
+	}
+}
+
+// exitError describes why a server stopped on its own
+func (p *devProcess) exitError() error {
+	if p.err != nil {
+		return fmt.Errorf("%s server exited: %w", p.name, p.err)
+	}
+	return fmt.Errorf("%s server exited unexpectedly", p.name)
+}
+
+// devServer is one of the servers bui dev supervises; it can be restarted with the same settings
+type devServer struct {
+	name    string // Backend or Frontend
+	label   string // Output prefix
+	color   string
+	dir     string
+	port    int
+	command func() *exec.Cmd
+	ready   func(cmd *mamba.Command, port int)
+
+	publicURL   string // Set when --tunnel exposes the server
+	writer      *prefixWriter
+	process     *devProcess
+	restarts    int
+	lastRestart time.Time
+}
+
+// start launches the server and waits until it responds
+func (s *devServer) start(cmd *mamba.Command, exited chan<- *devProcess) error {
+	serverCmd := s.command()
+	if s.dir != "." {
+		serverCmd.Dir = s.dir
+	}
+	// Pipe output to terminal with the server's prefix
+	if !devQuiet {
+		if s.writer == nil {
+			s.writer = newPrefixWriter(os.Stdout, s.label, s.color)
+		}
+		serverCmd.Stdout, serverCmd.Stderr = s.writer, s.writer
+		// Bounds Wait if a child process still holds the output pipe
+		serverCmd.WaitDelay = 2 * time.Second
+	}
+
+	process, err := startDevProcess(s.name, serverCmd, exited)
+	if err != nil {
+		return err
+	}
+	s.process = process
+	s.ready(cmd, s.port)
+	return nil
+}
+
+// restart stops the server and starts it again on the same port
+func (s *devServer) restart(cmd *mamba.Command, exited chan<- *devProcess) error {
+	s.stop()
+	s.restarts++
+	s.lastRestart = time.Now()
+	return s.start(cmd, exited)
+}
+
+// stop stops the server and writes out any output it left unterminated
+func (s *devServer) stop() {
+	if s.process != nil {
+		s.process.stop()
+	}
+	if s.writer != nil {
+		s.writer.Flush()
+	}
+}
+
+// url is where the server can be reached
+func (s *devServer) url() string {
+	return fmt.Sprintf("http://localhost:%d", s.port)
+}
@@ -0,0 +1,25 @@
+package commands
+
+import (
+	"fmt"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts cmd in a new process group so console signals for bui don't reach it
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// terminateProcessGroup stops cmd and its child processes; Windows has no SIGTERM, so this
+// is the same as killProcessGroup
+func terminateProcessGroup(cmd *exec.Cmd) {
+	killProcessGroup(cmd)
+}
+
+// killProcessGroup kills cmd and every process it started
+func killProcessGroup(cmd *exec.Cmd) {
+	if err := exec.Command("taskkill", "/T", "/F", "/PID", fmt.Sprint(cmd.Process.Pid)).Run(); err != nil {
+		cmd.Process.Kill()
+	}
+}
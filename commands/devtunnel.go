@@ -0,0 +1,134 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tunnelStartTimeout is how long a tunnel gets to report its public URL
+const tunnelStartTimeout = 30 * time.Second
+
+// tunnelProvider is a tunneling tool bui dev can expose a local port with
+type tunnelProvider struct {
+	name string
+	args func(port int) []string
+	url  *regexp.Regexp // Matches the public URL in the tool's log output
+}
+
+// tunnelProviders are tried in order when --tunnel doesn't name one
+var tunnelProviders = []tunnelProvider{
+	{
+		name: "cloudflared",
+		args: func(port int) []string {
+			return []string{"tunnel", "--no-autoupdate", "--url", fmt.Sprintf("http://localhost:%d", port)}
+		},
+		url: regexp.MustCompile(`https://[a-z0-9-]+\.trycloudflare\.com`),
+	},
+	{
+		name: "ngrok",
+		args: func(port int) []string {
+			return []string{"http", fmt.Sprint(port), "--log", "stdout", "--log-format", "logfmt"}
+		},
+		url: regexp.MustCompile(`url=(https://[^\s"]+)`),
+	},
+}
+
+// findTunnelProvider returns the provider for --tunnel: the one named, or the first installed one
+func findTunnelProvider(name string) (*tunnelProvider, error) {
+	var names []string
+	for i, provider := range tunnelProviders {
+		names = append(names, provider.name)
+		if name != "auto" && name != provider.name {
+			continue
+		}
+		if _, err := exec.LookPath(provider.name); err != nil {
+			if name == provider.name {
+				return nil, fmt.Errorf("%s is not installed", provider.name)
+			}
+			continue
+		}
+		return &tunnelProviders[i], nil
+	}
+	if name != "auto" {
+		return nil, fmt.Errorf("unknown tunnel %q (use %s)", name, strings.Join(names, " or "))
+	}
+	return nil, fmt.Errorf("no tunnel tool found; install %s", strings.Join(names, " or "))
+}
+
+// startTunnel exposes a dev server's port and returns the tunnel process once its public URL is known
+func startTunnel(provider *tunnelProvider, server *devServer) (*devProcess, error) {
+	finder := &urlFinder{pattern: provider.url, found: make(chan string, 1)}
+	tunnelCmd := exec.Command(provider.name, provider.args(server.port)...)
+	tunnelCmd.Stdout, tunnelCmd.Stderr = finder, finder
+	tunnelCmd.WaitDelay = 2 * time.Second
+
+	process, err := startDevProcess(server.name+" tunnel", tunnelCmd, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case url := <-finder.found:
+		server.publicURL = url
+		return process, nil
+	case <-process.done:
+		return nil, fmt.Errorf("%s exited: %s", provider.name, finder.lastLine())
+	case <-time.After(tunnelStartTimeout):
+		process.stop()
+		return nil, fmt.Errorf("%s didn't report a public URL within %s", provider.name, tunnelStartTimeout)
+	}
+}
+
+// urlFinder scans a tunnel's output for its public URL
+type urlFinder struct {
+	pattern *regexp.Regexp
+	found   chan string
+
+	mu   sync.Mutex
+	buf  []byte
+	last string
+	done bool
+}
+
+// Write looks for the URL in every complete line
+func (f *urlFinder) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.buf = append(f.buf, p...)
+	for {
+		i := bytes.IndexByte(f.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := strings.TrimSpace(string(f.buf[:i]))
+		f.buf = f.buf[i+1:]
+		if line != "" {
+			f.last = line
+		}
+
+		if f.done {
+			continue
+		}
+		if match := f.pattern.FindStringSubmatch(line); match != nil {
+			f.found <- match[len(match)-1]
+			f.done = true
+		}
+	}
+	return len(p), nil
+}
+
+// lastLine returns the last line of output, usually the reason a tunnel failed
+func (f *urlFinder) lastLine() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.last == "" {
+		return "no output"
+	}
+	return f.last
+}
@@ -0,0 +1,231 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// defaultDocsPort is the port bui docs generate --serve listens on when --port isn't given
+const defaultDocsPort = 8091
+
+var (
+	// docsOutput is the directory the module pages are written to
+	docsOutput string
+
+	// docsServe serves the pages on a local port after writing them
+	docsServe bool
+	docsPort  int
+
+	// docsOpen opens the served pages in the browser
+	docsOpen bool
+)
+
+var docsCmd = &mamba.Command{
+	Use:   "docs",
+	Short: "Document the project's modules",
+	Long: `Write markdown pages that describe the project's modules for teammates who didn't
+run the generator.
+
+Examples:
+  bui docs generate
+  bui docs g --serve --open`,
+}
+
+var docsGenerateCmd = &mamba.Command{
+	Use:     "generate",
+	Aliases: []string{"g"},
+	Short:   "Write a markdown page for each module and an index",
+	Long: `Write a markdown page for each backend module to docs/modules/ in the project root, with
+an index.md that links to them.
+
+Each page lists the model's fields and types, its relations (including the modules that
+refer to it), the API endpoints the module registers with their permissions, and the
+admin pages of the frontend. Everything is read from the generated files, so edits to
+them show up; regenerate the pages instead of editing them.
+
+Examples:
+  bui docs generate                     # Write docs/modules/*.md
+  bui docs g -o wiki/modules            # Write them elsewhere
+  bui docs g --serve --open             # Write and view them in the browser`,
+	Run: generateDocs,
+}
+
+func init() {
+	rootCmd.AddCommand(docsCmd)
+	docsCmd.AddCommand(docsGenerateCmd)
+	docsGenerateCmd.Flags().StringVarP(&docsOutput, "output", "o", "", "Directory to write the pages to (default docs/modules in the project root)")
+	docsGenerateCmd.Flags().BoolVar(&docsServe, "serve", false, "Serve the pages on a local port after writing them")
+	docsGenerateCmd.Flags().IntVar(&docsPort, "port", 0, fmt.Sprintf("Port for --serve (default %d)", defaultDocsPort))
+	docsGenerateCmd.Flags().BoolVar(&docsOpen, "open", false, "Open the served pages in the browser")
+}
+
+// generateDocs writes the module pages and the index
+func generateDocs(cmd *mamba.Command, args []string) {
+	backendDir, frontendDir := detectProjectDirs()
+	docs := collectModuleDocs(backendDir, frontendDir)
+	if len(docs) == 0 {
+		cmd.PrintWarning("No modules found")
+		cmd.PrintInfo("Run bui docs generate from the project root, the backend or the frontend directory")
+		return
+	}
+
+	outputDir := docsOutput
+	if outputDir == "" {
+		root := utils.Project.Root()
+		if root == "" {
+			root = "."
+		}
+		outputDir = filepath.Join(root, "docs", "modules")
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to create %s: %v", outputDir, err))
+		os.Exit(1)
+	}
+
+	pages := map[string]string{"index.md": utils.ModuleIndexMarkdown(docs)}
+	for _, doc := range docs {
+		pages[utils.DocFileName(doc.Name)] = doc.Markdown(docs)
+	}
+	for name, content := range pages {
+		if err := os.WriteFile(filepath.Join(outputDir, name), []byte(content), 0644); err != nil {
+			cmd.PrintError(fmt.Sprintf("Failed to write %s: %v", name, err))
+			os.Exit(1)
+		}
+	}
+	cmd.PrintSuccess(fmt.Sprintf("Wrote %d module pages and index.md to %s", len(docs), outputDir))
+
+	if docsServe {
+		serveDocs(cmd, outputDir)
+	}
+}
+
+// collectModuleDocs reads the backend modules (app/<module> and app/<namespace>/<module>
+// directories with a module.go) and the admin pages of the frontend
+func collectModuleDocs(backendDir, frontendDir string) []utils.ModuleDoc {
+	dirNames := utils.FindModuleDirs(backendDir, "module.go")
+	if len(dirNames) == 0 {
+		return nil
+	}
+	pages := adminPages(frontendDir)
+
+	var docs []utils.ModuleDoc
+	for _, dirName := range dirNames {
+		doc := utils.ReadModuleDoc(backendDir, dirName)
+		doc.Pages = pages[path.Base(utils.NewNamingConvention(utils.Singularize(dirName)).PluralKebab)]
+		docs = append(docs, doc)
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Name < docs[j].Name })
+	return docs
+}
+
+// adminPages returns the routes of the frontend's admin pages (app/pages/app) by the module
+// they belong to, which is the last directory of the route that isn't a parameter, so
+// posts/[id]/comments belongs to comments
+func adminPages(frontendDir string) map[string][]string {
+	pages := map[string][]string{}
+	if frontendDir == "" {
+		return pages
+	}
+	pagesDir := filepath.Join(frontendDir, "app", "pages", "app")
+	filepath.WalkDir(pagesDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || filepath.Ext(path) != ".vue" {
+			return nil
+		}
+		rel, err := filepath.Rel(pagesDir, strings.TrimSuffix(path, ".vue"))
+		if err != nil {
+			return nil
+		}
+		segments := strings.Split(filepath.ToSlash(rel), "/")
+		if segments[len(segments)-1] == "index" {
+			segments = segments[:len(segments)-1]
+		}
+
+		owner := ""
+		route := "/app"
+		for _, segment := range segments {
+			if strings.HasPrefix(segment, "[") {
+				route += "/:" + strings.Trim(segment, "[].")
+				continue
+			}
+			route += "/" + segment
+			owner = segment
+		}
+		if owner != "" {
+			pages[owner] = append(pages[owner], route)
+		}
+		return nil
+	})
+	for owner := range pages {
+		sort.Strings(pages[owner])
+	}
+	return pages
+}
+
+// serveDocs serves the markdown pages in dir as HTML, rendered in the browser
+func serveDocs(cmd *mamba.Command, dir string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/")
+		if name == "" {
+			name = "index.md"
+		}
+		if strings.Contains(name, "/") || filepath.Ext(name) != ".md" {
+			http.NotFound(w, r)
+			return
+		}
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, docsPage(name, content))
+	})
+
+	port := resolveDevPort(cmd, "Docs", docsPort, defaultDocsPort)
+	url := fmt.Sprintf("http://localhost:%d", port)
+	cmd.PrintSuccess(fmt.Sprintf("Serving the module docs at %s", url))
+	cmd.PrintInfo("Pages are read on each request, so rerun bui docs generate and reload to update them")
+	cmd.PrintInfo("Press Ctrl+C to stop")
+
+	if docsOpen {
+		if err := openBrowser(url); err != nil {
+			cmd.PrintWarning("Could not open the browser: " + err.Error())
+		}
+	}
+	if err := http.ListenAndServe(fmt.Sprintf(":%d", port), mux); err != nil {
+		cmd.PrintError("Docs server stopped: " + err.Error())
+		os.Exit(1)
+	}
+}
+
+// docsPage returns an HTML page that renders a markdown page with marked, loaded from the jsDelivr CDN
+func docsPage(name string, content []byte) string {
+	markdown, _ := json.Marshal(string(content))
+	return `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>` + html.EscapeString(name) + `</title>
+  <link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/github-markdown-css@5/github-markdown.min.css">
+  <style>.markdown-body { max-width: 980px; margin: 0 auto; padding: 32px }</style>
+</head>
+<body>
+  <article id="content" class="markdown-body"></article>
+  <script src="https://cdn.jsdelivr.net/npm/marked@12/marked.min.js"></script>
+  <script>document.getElementById('content').innerHTML = marked.parse(` + string(markdown) + `)</script>
+</body>
+</html>
+`
+}
@@ -0,0 +1,207 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+	"github.com/base-go/mamba/pkg/interactive"
+)
+
+// fieldTypeChoice is an entry in the interactive type picker
+type fieldTypeChoice struct {
+	Type        string
+	Description string
+}
+
+// fieldTypeChoices lists the field types offered by the interactive builder
+var fieldTypeChoices = []fieldTypeChoice{
+	{"string", "Short text"},
+	{"text", "Long text (textarea)"},
+	{"int", "Whole number"},
+	{"float", "Floating-point number"},
+	{"decimal", "Exact decimal number"},
+	{"money", "Amount of money, stored in cents"},
+	{"bool", "True/false"},
+	{"date", "Date"},
+	{"datetime", "Date and time"},
+	{"email", "Email address"},
+	{"url", "Link"},
+	{"json", "JSON data"},
+	{"point", "Map location (latitude and longitude)"},
+	{"encrypted", "Sensitive text, encrypted at rest and masked in lists"},
+	{"computed", "Read-only text derived from other fields"},
+	{"enum", "Single choice from fixed options, with Go constants"},
+	{"state", "Workflow state that only moves along its transitions"},
+	{"select", "Single choice from options"},
+	{"radio", "Single choice shown as radio buttons"},
+	{"checkbox", "Multiple choices from options"},
+	{"image", "Image attachment"},
+	{"file", "File attachment"},
+	{"media", "Media library reference"},
+	{"translation", "Translatable text"},
+	{"belongsTo", "Belongs to another model"},
+	{"hasMany", "Has many of another model"},
+	{"hasOne", "Has one of another model"},
+	{"manyToMany", "Many to many with another model"},
+	{"morphTo", "Belongs to one of several models (polymorphic)"},
+	{"morphMany", "Has many of a polymorphic model"},
+}
+
+// isInteractiveInput reports whether stdin is a terminal
+func isInteractiveInput() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// promptFields asks for fields one at a time and returns them as field:type definitions
+func promptFields(cmd *mamba.Command, moduleName string) ([]string, error) {
+	reader := bufio.NewReader(os.Stdin)
+	var fields []string
+
+	cmd.PrintHeader(fmt.Sprintf("Fields for %s", utils.ToPascalCase(moduleName)))
+	cmd.PrintInfo("Leave the field name empty when you are done")
+
+	for {
+		name, err := promptLine(reader, "Field name: ")
+		if err != nil {
+			return nil, err
+		}
+		if name == "" {
+			break
+		}
+
+		fieldType, err := promptFieldType(cmd, reader)
+		if err != nil {
+			return nil, err
+		}
+
+		parts := []string{name, fieldType}
+
+		switch {
+		case utils.IsRelationshipType(fieldType) && utils.GetCanonicalRelationship(fieldType) != "morph_to":
+			related, err := promptLine(reader, "Related model (e.g. User): ")
+			if err != nil {
+				return nil, err
+			}
+			if related != "" {
+				parts = append(parts, utils.ToPascalCase(related))
+			}
+		case fieldType == "enum" || fieldType == "select" || fieldType == "radio" || fieldType == "checkbox":
+			options, err := promptLine(reader, "Options (comma separated): ")
+			if err != nil {
+				return nil, err
+			}
+			if options != "" {
+				parts = append(parts, strings.ReplaceAll(options, " ", ""))
+			}
+		case fieldType == "state":
+			states, err := promptLine(reader, "Transitions (e.g. draft>review>published,review>draft): ")
+			if err != nil {
+				return nil, err
+			}
+			if states != "" {
+				parts = append(parts, strings.ReplaceAll(states, " ", ""))
+			}
+		case fieldType == "computed":
+			sources, err := promptLine(reader, "Derived from (comma separated, optional): ")
+			if err != nil {
+				return nil, err
+			}
+			if sources != "" {
+				parts = append(parts, strings.ReplaceAll(sources, " ", ""))
+			}
+		case fieldType == "money":
+			currency, err := promptLine(reader, "Currency (default USD): ")
+			if err != nil {
+				return nil, err
+			}
+			if currency != "" {
+				parts = append(parts, strings.ToUpper(currency))
+			}
+		case fieldType == "decimal":
+			size, err := promptLine(reader, "Precision,scale (default 10,2): ")
+			if err != nil {
+				return nil, err
+			}
+			if size != "" {
+				parts = append(parts, strings.ReplaceAll(size, " ", ""))
+			}
+		}
+
+		// Computed fields are never set, so they're neither required nor defaulted
+		if !utils.IsRelationshipType(fieldType) && fieldType != "computed" {
+			required, err := interactive.AskConfirm("Required?", false)
+			if err != nil {
+				return nil, err
+			}
+			if required {
+				parts = append(parts, "required")
+			}
+
+			defaultValue, err := promptLine(reader, "Default value (optional): ")
+			if err != nil {
+				return nil, err
+			}
+			if defaultValue != "" {
+				parts = append(parts, "default="+defaultValue)
+			}
+		}
+
+		field := strings.Join(parts, ":")
+		fields = append(fields, field)
+		cmd.PrintSuccess(fmt.Sprintf("Added %s", field))
+	}
+
+	return fields, nil
+}
+
+// promptFieldType shows the numbered type picker and returns the chosen type
+func promptFieldType(cmd *mamba.Command, reader *bufio.Reader) (string, error) {
+	for i, choice := range fieldTypeChoices {
+		fmt.Printf("  %2d) %-12s %s\n", i+1, choice.Type, choice.Description)
+	}
+
+	for {
+		answer, err := promptLine(reader, "Type [1]: ")
+		if err != nil {
+			return "", err
+		}
+		if answer == "" {
+			return fieldTypeChoices[0].Type, nil
+		}
+
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(fieldTypeChoices) {
+			return fieldTypeChoices[n-1].Type, nil
+		}
+
+		// Accept a type typed by name, including any alias the parser understands
+		for _, choice := range fieldTypeChoices {
+			if strings.EqualFold(choice.Type, answer) {
+				return choice.Type, nil
+			}
+		}
+		if utils.ResolveFieldType(answer).Category != "custom" {
+			return answer, nil
+		}
+
+		cmd.PrintWarning(fmt.Sprintf("Unknown type %q, pick a number from the list", answer))
+	}
+}
+
+// promptLine prints a prompt and reads a trimmed line from stdin
+func promptLine(reader *bufio.Reader, prompt string) (string, error) {
+	fmt.Print(prompt)
+	line, err := reader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
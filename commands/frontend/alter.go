@@ -0,0 +1,169 @@
+package frontend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// alterFrontendModule adds fields to an existing frontend module: the types, form modal and
+// list and detail pages get the new fields in place. The existing fields are read from the
+// backend model, since the Nuxt files don't record the original field definitions. It returns
+// the template data of the altered module, nil when nothing was altered.
+func alterFrontendModule(cmd *mamba.Command, w *utils.Writer, adminPath string, naming *utils.NamingConvention, newDefs []string) *TemplateData {
+	modelPath := findBackendModel(w.Dir, naming.ModelSnake)
+	if modelPath == "" {
+		cmd.PrintError(fmt.Sprintf("Cannot alter %s: the backend model app/models/%s.go was not found", naming.Model, naming.ModelSnake))
+		cmd.PrintInfo("--alter reads the existing fields from the backend model; run it from the project root")
+		return nil
+	}
+	source, err := os.ReadFile(modelPath)
+	if err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to read %s: %v", modelPath, err))
+		return nil
+	}
+	existingDefs, err := utils.RecoverFieldDefs(source, naming.Model)
+	if err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to read the fields of %s: %v", modelPath, err))
+		return nil
+	}
+
+	// A combined "bui g --alter" run has already added the new fields to the backend model
+	var baseDefs []string
+	for _, def := range existingDefs {
+		if !containsField(newDefs, utils.ParseField(def).Name) {
+			baseDefs = append(baseDefs, def)
+		}
+	}
+
+	before, _ := newTemplateData(adminPath, naming, baseDefs)
+	after, _ := newTemplateData(adminPath, naming, append(baseDefs, newDefs...))
+
+	moduleBasePath := filepath.Join(adminPath, "modules", naming.PluralSnake)
+	pagesPath := filepath.Join(adminPath, "pages", "app", naming.PluralKebab)
+	files := []struct{ path, template string }{
+		{filepath.Join(moduleBasePath, "types", naming.ModelSnake+".ts"), "nuxt/types.ts.tmpl"},
+		{filepath.Join(moduleBasePath, "components", naming.Model+"FormModal.vue"), "nuxt/form-modal.vue.tmpl"},
+		{filepath.Join(moduleBasePath, "stores", naming.PluralSnake+".ts"), "nuxt/store.ts.tmpl"},
+		{filepath.Join(pagesPath, "index.vue"), "nuxt/index.vue.tmpl"},
+		{filepath.Join(pagesPath, "[id].vue"), "nuxt/detail.vue.tmpl"},
+	}
+
+	// A first belongs_to field needs the shared RelationSelect component
+	if err := generateRelationSelect(cmd, w, adminPath, after); err != nil {
+		cmd.PrintWarning(fmt.Sprintf("Failed to generate relation select: %v", err))
+	}
+
+	// A first file field needs the shared upload composable and component
+	if err := generateUploads(cmd, w, adminPath, after); err != nil {
+		cmd.PrintWarning(fmt.Sprintf("Failed to generate uploads: %v", err))
+	}
+
+	// A first field with rules= needs the shared rules composables
+	if err := generateRules(cmd, w, adminPath, after); err != nil {
+		cmd.PrintWarning(fmt.Sprintf("Failed to generate rules: %v", err))
+	}
+
+	// A first JSON field needs the shared JSON components
+	if err := generateJSONComponents(cmd, w, adminPath, after); err != nil {
+		cmd.PrintWarning(fmt.Sprintf("Failed to generate JSON components: %v", err))
+	}
+
+	// A first money field needs the shared MoneyInput component
+	if err := generateMoneyInput(cmd, w, adminPath, after); err != nil {
+		cmd.PrintWarning(fmt.Sprintf("Failed to generate money input: %v", err))
+	}
+
+	// A first point field needs the shared map components
+	if err := generateMapComponents(cmd, w, adminPath, after); err != nil {
+		cmd.PrintWarning(fmt.Sprintf("Failed to generate map components: %v", err))
+	}
+
+	// A first encrypted field needs the shared SecretValue
+	if err := generateSecretValue(cmd, w, adminPath, after); err != nil {
+		cmd.PrintWarning(fmt.Sprintf("Failed to generate SecretValue: %v", err))
+	}
+
+	// New manyToMany fields with pivot columns get their link editors; the new fields come last
+	if len(after.Pivots) > len(before.Pivots) {
+		added := *after
+		added.Pivots = after.Pivots[len(before.Pivots):]
+		if err := generatePivots(cmd, w, moduleBasePath, &added); err != nil {
+			cmd.PrintWarning(fmt.Sprintf("Failed to generate pivot editor: %v", err))
+		}
+	}
+
+	altered := 0
+	for _, file := range files {
+		if _, err := os.Stat(file.path); err != nil {
+			continue
+		}
+		base, err := utils.RenderNuxtTemplateIn(w.Dir, file.template, before)
+		if err == nil {
+			var updated []byte
+			if updated, err = utils.RenderNuxtTemplateIn(w.Dir, file.template, after); err == nil {
+				var missed int
+				if missed, err = w.AlterFile(file.path, base, updated); err == nil && missed > 0 {
+					err = fmt.Errorf("%d changes did not match the file's layout; add them by hand", missed)
+				}
+			}
+		}
+		if err != nil {
+			cmd.PrintWarning(fmt.Sprintf("Could not update %s: %v", file.path, err))
+			continue
+		}
+		altered++
+		if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess(fmt.Sprintf("Updated %s", file.path))
+		}
+	}
+
+	if utils.DryRun {
+		cmd.PrintInfo(fmt.Sprintf("Dry run: frontend module %s was not altered", naming.Model))
+		return nil
+	}
+	if altered == 0 {
+		cmd.PrintWarning(fmt.Sprintf("No files of frontend module %s were found to alter", naming.Model))
+		return nil
+	}
+
+	cmd.PrintSuccess(fmt.Sprintf("Added fields to frontend module: %s", naming.Model))
+	return after
+}
+
+// addInverseRelations adds the hasMany fields the backend generator of the run added to related
+// models (--with-inverse) to their frontend modules
+func addInverseRelations(cmd *mamba.Command, w *utils.Writer) {
+	nested := utils.NestedForms
+	defer func() { utils.NestedForms = nested }()
+	for _, inverse := range w.Run.Inverses {
+		utils.NestedForms = ""
+		alterFrontendModule(cmd, w, w.Path("app"), utils.NewNamingConvention(inverse.Model), []string{inverse.Def})
+	}
+}
+
+// findBackendModel returns the path of a backend model file next to the frontend in dir, or ""
+func findBackendModel(dir, modelSnake string) string {
+	for _, pattern := range []string{
+		filepath.Join(dir, "..", "*", "app", "models", modelSnake+".go"),
+		filepath.Join(dir, "*", "app", "models", modelSnake+".go"),
+	} {
+		if matches, _ := filepath.Glob(pattern); len(matches) > 0 {
+			return matches[0]
+		}
+	}
+	return ""
+}
+
+// containsField reports whether defs defines a field with the given Go name
+func containsField(defs []string, name string) bool {
+	for _, def := range defs {
+		if utils.ParseField(def).Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,536 @@
+package frontend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+var (
+	// apiClient writes a typed API client for the module from the backend's swagger.json
+	apiClient bool
+
+	// apiClientSpec overrides where the swagger.json is read from
+	apiClientSpec string
+)
+
+func init() {
+	GenerateFrontendCmd.Flags().BoolVar(&apiClient, "api-client", false, "Generate a typed API client from the backend's swagger.json")
+	GenerateFrontendCmd.Flags().StringVar(&apiClientSpec, "swagger", "", "Path to the swagger.json for --api-client (defaults to the backend's swag/ output)")
+}
+
+// swaggerLocations are where swag writes swagger.json: bui build uses swag/, bui dev swagger/
+var swaggerLocations = []string{"swag", "swagger", "docs"}
+
+// tsIdentifier matches property names that need no quotes in TypeScript
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// apiClientData fills in the nuxt/api-client.ts.tmpl template
+type apiClientData struct {
+	*utils.NamingConvention
+	Source     string
+	UsesQuery  bool // Whether any operation takes query parameters, which need withQuery
+	Interfaces []apiClientInterface
+	Operations []apiClientOperation
+}
+
+// apiClientInterface is a TypeScript interface, or a type alias when Alias is set
+type apiClientInterface struct {
+	Name        string
+	Description string
+	Alias       string
+	Properties  []apiClientProperty
+}
+
+// apiClientProperty is a property of an interface or of a query object
+type apiClientProperty struct {
+	Name        string
+	Type        string
+	Optional    bool
+	Description string
+}
+
+// apiClientOperation is one function of the generated client
+type apiClientOperation struct {
+	Name     string
+	Summary  string
+	Method   string // useApi method: get, post, put, patch or delete
+	Route    string // Route as written in the spec, e.g. GET /products/{id}
+	Args     string
+	URL      string
+	Body     bool
+	Response string
+}
+
+// findSwaggerSpec returns the swagger.json --api-client reads. The backend is looked up from the
+// directory bui was run in, usually the project root.
+func findSwaggerSpec() (string, error) {
+	if apiClientSpec != "" {
+		if _, err := os.Stat(apiClientSpec); err != nil {
+			return "", fmt.Errorf("swagger spec %s not found", apiClientSpec)
+		}
+		return filepath.Abs(apiClientSpec)
+	}
+
+	var backends []string
+	if dir := utils.Project.BackendDir(); dir != "" {
+		backends = append(backends, dir)
+	}
+	for _, parent := range []string{".", ".."} {
+		entries, err := os.ReadDir(parent)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && strings.HasSuffix(entry.Name(), "-api") {
+				backends = append(backends, filepath.Join(parent, entry.Name()))
+			}
+		}
+	}
+	backends = append(backends, ".")
+
+	for _, backend := range backends {
+		for _, location := range swaggerLocations {
+			path := filepath.Join(backend, location, "swagger.json")
+			if _, err := os.Stat(path); err == nil {
+				return filepath.Abs(path)
+			}
+		}
+	}
+	return "", fmt.Errorf("no swagger.json found in the backend's swag/ or swagger/ directory; run bui dev or bui build to generate it, or pass --swagger")
+}
+
+// loadAPIClient reads the spec and builds the client for the module, so a spec without the
+// module's routes is reported before any file is written
+func loadAPIClient(specPath string, naming *utils.NamingConvention) (*apiClientData, error) {
+	spec, err := utils.LoadOpenAPISpec(specPath)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := newAPIClientData(spec, naming)
+	if err != nil {
+		return nil, err
+	}
+	data.Source = specPath
+	return data, nil
+}
+
+// generateAPIClient writes app/modules/<plural>/api/<model>.ts with an interface for every schema
+// the module's routes use and a function per route
+func generateAPIClient(cmd *mamba.Command, w *utils.Writer, moduleBasePath string, data *apiClientData) error {
+	// The header names the spec relative to the frontend, so it doesn't depend on where bui ran
+	if dir, err := filepath.Abs(w.Dir); err == nil && filepath.IsAbs(data.Source) {
+		if rel, err := filepath.Rel(dir, data.Source); err == nil {
+			data.Source = filepath.ToSlash(rel)
+		}
+	}
+	if err := w.GenerateNuxtFile(filepath.Join(moduleBasePath, "api"), data.ModelSnake+".ts", "nuxt/api-client.ts.tmpl", data); err != nil {
+		return err
+	}
+	if Verbose != nil && *Verbose && !utils.DryRun {
+		cmd.PrintSuccess(fmt.Sprintf("Generated api/%s.ts (%d routes, %d types)", data.ModelSnake, len(data.Operations), len(data.Interfaces)))
+	}
+	return nil
+}
+
+// apiClientBuilder collects the schemas the generated operations refer to
+type apiClientBuilder struct {
+	spec  *utils.OpenAPISpec
+	names map[string]string // Schema name to TypeScript name
+	used  map[string]bool
+	queue []string
+}
+
+// newAPIClientData builds the client for the routes under /<plural-kebab>, including nested ones
+func newAPIClientData(spec *utils.OpenAPISpec, naming *utils.NamingConvention) (*apiClientData, error) {
+	b := &apiClientBuilder{spec: spec, names: tsSchemaNames(spec.Schemas()), used: map[string]bool{}}
+	data := &apiClientData{NamingConvention: naming}
+
+	taken := map[string]int{}
+	for _, route := range spec.Routes() {
+		segments := strings.Split(strings.Trim(route, "/"), "/")
+		index := -1
+		for i, segment := range segments {
+			if segment == naming.PluralKebab {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			continue
+		}
+
+		for _, method := range utils.OpenAPIMethods {
+			op := spec.Paths[route][method]
+			if op == nil {
+				continue
+			}
+			operation := b.operation(route, method, op, segments, index, naming)
+			if taken[operation.Name]++; taken[operation.Name] > 1 {
+				operation.Name = fmt.Sprintf("%s%d", operation.Name, taken[operation.Name])
+			}
+			data.Operations = append(data.Operations, operation)
+			data.UsesQuery = data.UsesQuery || strings.HasPrefix(operation.URL, "withQuery(")
+		}
+	}
+	if len(data.Operations) == 0 {
+		return nil, fmt.Errorf("the swagger spec has no /%s routes; generate the backend module and rerun bui dev to refresh swagger.json", naming.PluralKebab)
+	}
+
+	// Interfaces are emitted for every schema reachable from the operations
+	for len(b.queue) > 0 {
+		name := b.queue[0]
+		b.queue = b.queue[1:]
+		data.Interfaces = append(data.Interfaces, b.schemaInterface(name))
+	}
+	sort.Slice(data.Interfaces, func(i, j int) bool { return data.Interfaces[i].Name < data.Interfaces[j].Name })
+
+	return data, nil
+}
+
+// operation describes one route as a client function. The standard CRUD routes get the names the
+// store uses (list, get, create, update, delete); other routes are named after their path.
+func (b *apiClientBuilder) operation(route, method string, op *utils.OpenAPIOperation, segments []string, index int, naming *utils.NamingConvention) apiClientOperation {
+	var args, scope []string
+	url := ""
+	for _, segment := range segments {
+		if name, ok := routeParam(segment); ok {
+			param := toTSIdentifier(name)
+			args = append(args, fmt.Sprintf("%s: %s", param, b.paramType(op, name)))
+			url += "/${" + param + "}"
+			continue
+		}
+		url += "/" + segment
+	}
+	for _, segment := range segments[:index] {
+		if name, ok := routeParam(segment); ok {
+			scope = append(scope, utils.ToPascalCase(name))
+		}
+	}
+
+	rest := segments[index+1:]
+	var name string
+	switch {
+	case op.OperationID != "":
+		name = utils.ToCamelCase(op.OperationID)
+	case len(rest) == 0 && method == "get":
+		name = "list" + naming.Plural
+	case len(rest) == 0 && method == "post":
+		name = "create" + naming.Model
+	case len(rest) == 1 && isRouteParam(rest[0]):
+		verbs := map[string]string{"get": "get", "post": "post", "put": "update", "patch": "patch", "delete": "delete"}
+		name = verbs[method] + naming.Model
+	default:
+		name = method + naming.Plural
+		for _, segment := range rest {
+			if param, ok := routeParam(segment); ok {
+				name += "By" + utils.ToPascalCase(param)
+			} else {
+				name += utils.ToPascalCase(segment)
+			}
+		}
+	}
+	if op.OperationID == "" && len(scope) > 0 {
+		name += "By" + strings.Join(scope, "")
+	}
+
+	operation := apiClientOperation{
+		Name:     name,
+		Summary:  firstLine(op.Summary),
+		Method:   method,
+		Route:    strings.ToUpper(method) + " " + route,
+		URL:      "`" + url + "`",
+		Response: "void",
+	}
+	if !strings.Contains(url, "${") {
+		operation.URL = "'" + url + "'"
+	}
+
+	if body, required := op.BodySchema(); body != nil {
+		optional := ""
+		if !required {
+			optional = "?"
+		}
+		args = append(args, fmt.Sprintf("body%s: %s", optional, b.tsType(body)))
+		operation.Body = true
+	}
+
+	var query []apiClientProperty
+	queryRequired := false
+	for _, param := range op.Parameters {
+		if param.In != "query" {
+			continue
+		}
+		query = append(query, apiClientProperty{Name: tsPropertyName(param.Name), Type: b.tsType(param.ParameterSchema()), Optional: !param.Required})
+		queryRequired = queryRequired || param.Required
+	}
+	if len(query) > 0 {
+		fields := make([]string, 0, len(query))
+		for _, property := range query {
+			optional := ""
+			if property.Optional {
+				optional = "?"
+			}
+			fields = append(fields, fmt.Sprintf("%s%s: %s", property.Name, optional, property.Type))
+		}
+		optional := "?"
+		if queryRequired {
+			optional = ""
+		}
+		args = append(args, fmt.Sprintf("query%s: { %s }", optional, strings.Join(fields, "; ")))
+		operation.URL = "withQuery(" + operation.URL + ", query)"
+	}
+
+	if response := op.ResponseSchema(); response != nil {
+		operation.Response = b.tsType(response)
+	}
+	operation.Args = strings.Join(args, ", ")
+	return operation
+}
+
+// paramType returns the TypeScript type of a path parameter
+func (b *apiClientBuilder) paramType(op *utils.OpenAPIOperation, name string) string {
+	for _, param := range op.Parameters {
+		if param.In == "path" && param.Name == name {
+			return b.tsType(param.ParameterSchema())
+		}
+	}
+	return "string | number"
+}
+
+// schemaInterface converts a named schema into an interface, or a type alias for non-objects
+func (b *apiClientBuilder) schemaInterface(name string) apiClientInterface {
+	schema := b.spec.Schemas()[name]
+	result := apiClientInterface{Name: b.names[name], Description: firstLine(schema.Description)}
+
+	properties, required := b.objectProperties(schema)
+	if properties == nil {
+		result.Alias = b.inlineType(schema)
+		return result
+	}
+
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		property := properties[key]
+		tsType := b.tsType(property)
+		if property.IsNullable() {
+			tsType += " | null"
+		}
+		result.Properties = append(result.Properties, apiClientProperty{
+			Name:        tsPropertyName(key),
+			Type:        tsType,
+			Optional:    !required[key],
+			Description: firstLine(property.Description),
+		})
+	}
+	return result
+}
+
+// objectProperties returns the properties of an object schema, merging allOf parts; nil when it is not an object
+func (b *apiClientBuilder) objectProperties(schema *utils.OpenAPISchema) (map[string]*utils.OpenAPISchema, map[string]bool) {
+	if schema == nil {
+		return nil, nil
+	}
+	var properties map[string]*utils.OpenAPISchema
+	required := map[string]bool{}
+	parts := append([]*utils.OpenAPISchema{schema}, schema.AllOf...)
+	for _, part := range parts {
+		part = b.spec.Resolve(part)
+		if part == nil || part.Properties == nil {
+			continue
+		}
+		if properties == nil {
+			properties = map[string]*utils.OpenAPISchema{}
+		}
+		for key, property := range part.Properties {
+			properties[key] = property
+		}
+		for _, key := range part.Required {
+			required[key] = true
+		}
+	}
+	return properties, required
+}
+
+// tsType returns the TypeScript type of a schema, queueing the named schemas it refers to
+func (b *apiClientBuilder) tsType(schema *utils.OpenAPISchema) string {
+	if schema == nil {
+		return "unknown"
+	}
+	if schema.Ref != "" {
+		name := utils.OpenAPIRefName(schema.Ref)
+		if _, ok := b.spec.Schemas()[name]; !ok {
+			return "unknown"
+		}
+		if !b.used[name] {
+			b.used[name] = true
+			b.queue = append(b.queue, name)
+		}
+		return b.names[name]
+	}
+	// swag wraps a referenced type in allOf to attach a description
+	if len(schema.AllOf) == 1 && len(schema.Properties) == 0 {
+		return b.tsType(schema.AllOf[0])
+	}
+	return b.inlineType(schema)
+}
+
+// inlineType converts an unnamed schema into a TypeScript type expression
+func (b *apiClientBuilder) inlineType(schema *utils.OpenAPISchema) string {
+	if len(schema.Enum) > 0 {
+		values := make([]string, 0, len(schema.Enum))
+		for _, value := range schema.Enum {
+			if s, ok := value.(string); ok {
+				values = append(values, "'"+strings.ReplaceAll(s, "'", "\\'")+"'")
+			} else {
+				values = append(values, fmt.Sprint(value))
+			}
+		}
+		return strings.Join(values, " | ")
+	}
+
+	var variants []*utils.OpenAPISchema
+	if len(schema.OneOf) > 0 {
+		variants = schema.OneOf
+	} else if len(schema.AnyOf) > 0 {
+		variants = schema.AnyOf
+	}
+	if len(variants) > 0 {
+		types := make([]string, 0, len(variants))
+		for _, variant := range variants {
+			types = append(types, b.tsType(variant))
+		}
+		return strings.Join(types, " | ")
+	}
+
+	switch schema.Type.Name {
+	case "string":
+		if schema.Format == "binary" {
+			return "Blob"
+		}
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		item := b.tsType(schema.Items)
+		if strings.Contains(item, " ") {
+			return "Array<" + item + ">"
+		}
+		return item + "[]"
+	}
+
+	if properties, required := b.objectProperties(schema); properties != nil {
+		keys := make([]string, 0, len(properties))
+		for key := range properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fields := make([]string, 0, len(keys))
+		for _, key := range keys {
+			optional := "?"
+			if required[key] {
+				optional = ""
+			}
+			fields = append(fields, fmt.Sprintf("%s%s: %s", tsPropertyName(key), optional, b.tsType(properties[key])))
+		}
+		return "{ " + strings.Join(fields, "; ") + " }"
+	}
+	if schema.AdditionalProperties != nil {
+		return "Record<string, " + b.tsType(schema.AdditionalProperties) + ">"
+	}
+	if schema.Type.Name == "" {
+		return "any"
+	}
+	return "Record<string, any>"
+}
+
+// tsSchemaNames maps schema names such as models.ProductResponse to TypeScript names. The package
+// prefix is dropped unless two schemas would end up with the same name.
+func tsSchemaNames(schemas map[string]*utils.OpenAPISchema) map[string]string {
+	short := func(name string) string {
+		return toTSIdentifier(utils.ToPascalCase(name[strings.LastIndex(name, ".")+1:]))
+	}
+
+	counts := map[string]int{}
+	for name := range schemas {
+		counts[short(name)]++
+	}
+	names := map[string]string{}
+	for name := range schemas {
+		if counts[short(name)] > 1 {
+			names[name] = toTSIdentifier(utils.ToPascalCase(strings.NewReplacer(".", "_", "/", "_").Replace(name)))
+		} else if tsIdentifier.MatchString(name) {
+			names[name] = name
+		} else {
+			names[name] = short(name)
+		}
+	}
+	return names
+}
+
+// toTSIdentifier strips the characters a TypeScript identifier can't contain
+func toTSIdentifier(name string) string {
+	var result strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+			result.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				result.WriteRune('_')
+			}
+			result.WriteRune(r)
+		}
+	}
+	if result.Len() == 0 {
+		return "_"
+	}
+	return result.String()
+}
+
+// tsPropertyName quotes property names that aren't identifiers
+func tsPropertyName(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+	return "'" + strings.ReplaceAll(name, "'", "\\'") + "'"
+}
+
+// routeParam returns the parameter name of a {param} or :param path segment
+func routeParam(segment string) (string, bool) {
+	if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+		return segment[1 : len(segment)-1], true
+	}
+	if strings.HasPrefix(segment, ":") {
+		return segment[1:], true
+	}
+	return "", false
+}
+
+// isRouteParam reports whether a path segment is a parameter
+func isRouteParam(segment string) bool {
+	_, ok := routeParam(segment)
+	return ok
+}
+
+// firstLine keeps the first line of a description for a doc comment
+func firstLine(text string) string {
+	text = strings.TrimSpace(text)
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		text = text[:i]
+	}
+	return strings.ReplaceAll(text, "*/", "* /")
+}
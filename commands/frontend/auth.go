@@ -0,0 +1,37 @@
+package frontend
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// GenerateAuthPages writes the admin store, pages and login buttons for the bui g auth features,
+// in the frontend w writes to
+func GenerateAuthPages(cmd *mamba.Command, w *utils.Writer, features utils.AuthFeatures) error {
+	adminPath := w.Path("app")
+	files := []struct {
+		dir      string
+		name     string
+		template string
+		enabled  bool
+	}{
+		{filepath.Join(adminPath, "stores"), "auth-extras.ts", "nuxt/auth/store.ts.tmpl", true},
+		{filepath.Join(adminPath, "components"), "AuthExtraButtons.vue", "nuxt/auth/buttons.vue.tmpl", features.OAuth() || features.MagicLink},
+		{filepath.Join(adminPath, "pages", "auth"), "callback.vue", "nuxt/auth/callback.vue.tmpl", features.OAuth()},
+		{filepath.Join(adminPath, "pages", "auth"), "magic-link.vue", "nuxt/auth/magic-link.vue.tmpl", features.MagicLink},
+		{filepath.Join(adminPath, "pages", "auth"), "two-factor.vue", "nuxt/auth/two-factor.vue.tmpl", features.TOTP},
+		{filepath.Join(adminPath, "pages", "app", "account"), "security.vue", "nuxt/auth/security.vue.tmpl", features.TOTP},
+	}
+	for _, file := range files {
+		if !file.enabled {
+			continue
+		}
+		if err := w.GenerateNuxtFile(file.dir, file.name, file.template, features); err != nil {
+			return fmt.Errorf("failed to generate %s: %w", file.name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package frontend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// e2eData fills in the nuxt/e2e.spec.ts.tmpl template
+type e2eData struct {
+	*TemplateData
+	Name      e2eInput   // Text field the spec finds its record by, filled with a unique name
+	Inputs    []e2eInput // Other form fields the spec fills
+	Relations []string   // Labels of required belongsTo fields, set to their first option
+}
+
+// e2eInput is a form field the spec fills, with its value as a TypeScript expression
+type e2eInput struct {
+	Label string
+	Value string
+}
+
+// GenerateE2E writes the Playwright spec of a module's admin pages to the frontend's e2e
+// directory, with the Playwright config and sign-in setup the first time. The fields are read
+// from the backend model, like --alter does. Paths are those of the frontend w writes to.
+func GenerateE2E(cmd *mamba.Command, w *utils.Writer, naming *utils.NamingConvention) error {
+	adminPath := w.Path("app")
+	if _, err := os.Stat(filepath.Join(adminPath, "pages", "app", naming.PluralKebab, "index.vue")); err != nil {
+		return fmt.Errorf("the admin pages of %s were not found; generate the module first", naming.Model)
+	}
+	modelPath := findBackendModel(w.Dir, naming.ModelSnake)
+	if modelPath == "" {
+		return fmt.Errorf("the backend model app/models/%s.go was not found", naming.ModelSnake)
+	}
+	source, err := os.ReadFile(modelPath)
+	if err != nil {
+		return err
+	}
+	defs, err := utils.RecoverFieldDefs(source, naming.Model)
+	if err != nil {
+		return fmt.Errorf("failed to read the fields of %s: %w", modelPath, err)
+	}
+	templateData, _ := newTemplateData(adminPath, naming, defs)
+	data, err := newE2EData(templateData)
+	if err != nil {
+		return err
+	}
+
+	e2eDir := "e2e"
+	if err := w.GenerateNuxtFile(w.Path(e2eDir), naming.Slug+".spec.ts", "nuxt/e2e.spec.ts.tmpl", data); err != nil {
+		return err
+	}
+	if !utils.DryRun {
+		cmd.PrintSuccess(fmt.Sprintf("Generated e2e/%s.spec.ts", naming.Slug))
+	}
+
+	// The config and sign-in setup are shared by the specs of every module
+	shared := []struct{ dir, name, template string }{
+		{".", "playwright.config.ts", "nuxt/playwright.config.ts.tmpl"},
+		{e2eDir, "auth.setup.ts", "nuxt/e2e-auth.setup.ts.tmpl"},
+	}
+	for _, file := range shared {
+		if _, err := os.Stat(w.Path(file.dir, file.name)); !os.IsNotExist(err) {
+			continue
+		}
+		if err := w.GenerateNuxtFile(w.Path(file.dir), file.name, file.template, data); err != nil {
+			return err
+		}
+		if !utils.DryRun {
+			cmd.PrintSuccess(fmt.Sprintf("Generated %s", filepath.Join(file.dir, file.name)))
+		}
+	}
+	return nil
+}
+
+// newE2EData picks the form fields the spec fills. The display field, or else the first text
+// field, gets the unique name the spec finds the record by.
+func newE2EData(templateData *TemplateData) (*e2eData, error) {
+	data := &e2eData{TemplateData: templateData}
+	named := false
+	for _, field := range templateData.Fields {
+		if !field.ShowInForm {
+			continue
+		}
+		if field.Relationship == "belongs_to" && field.IsRequired {
+			data.Relations = append(data.Relations, field.RelationLabel)
+			continue
+		}
+
+		input := e2eInput{Label: field.Label}
+		switch field.FormType {
+		case "text", "textarea":
+			if !named && (field.JSONName == templateData.DisplayField || templateData.DisplayField == "id") {
+				data.Name, named = input, true
+				continue
+			}
+			input.Value = fmt.Sprintf("'E2E %s'", field.Label)
+		case "email":
+			input.Value = "`e2e-${stamp}@example.com`"
+		case "url":
+			input.Value = "`https://example.com/${stamp}`"
+		case "password":
+			input.Value = "'E2e-password-1'"
+		case "number":
+			input.Value = "'42'"
+		default:
+			continue
+		}
+		// Fields with rules= get the value the backend tests create with, which passes them
+		if len(field.Rules) > 0 && field.TestValue != "" {
+			value, err := strconv.Unquote(field.TestValue)
+			if err != nil {
+				value = field.TestValue
+			}
+			input.Value = quoteTS(value)
+		}
+		data.Inputs = append(data.Inputs, input)
+	}
+	if !named {
+		return nil, fmt.Errorf("%s has no text field in its form for the spec to find the record by", templateData.Model)
+	}
+	return data, nil
+}
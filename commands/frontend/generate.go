@@ -16,37 +16,185 @@ var GenerateFrontendCmd = &mamba.Command{
 	Use:     "frontend [name] [field:type...]",
 	Aliases: []string{"fe", "ui"},
 	Short:   "Generate a frontend Nuxt module",
-	Long:    `Generate a Nuxt module with TypeScript types, Pinia store, Vue components, and pages.`,
-	Args:    mamba.MinimumNArgs(1),
-	Run:     generateFrontendModule,
+	Long: `Generate a Nuxt module with TypeScript types, Pinia store, Vue components, and pages.
+
+With --api-client, app/modules/<name>/api/<model>.ts is generated from the backend's
+swagger.json: an interface for every request and response schema of the module's routes
+and a typed function per route. Run it without fields on an existing module to regenerate
+only the client after the backend changes.`,
+	Args: mamba.MinimumNArgs(1),
+	Run:  generateFrontendModule,
+}
+
+func init() {
+	GenerateFrontendCmd.Flags().BoolVar(&utils.DryRun, "dry-run", false, "Show the files that would be written without touching disk")
+	GenerateFrontendCmd.Flags().BoolVar(&utils.ShowDiff, "diff", false, "Print a diff for each file during a dry run")
+	GenerateFrontendCmd.Flags().BoolVarP(&utils.Force, "force", "f", false, "Overwrite existing files without asking")
+	GenerateFrontendCmd.Flags().BoolVar(&utils.Nested, "nested", false, "Scope routes and admin pages under the first belongsTo parent")
+	GenerateFrontendCmd.Flags().StringVar(&utils.PrimaryKey, "pk", "uint", "Primary key type: uint or uuid (uuid ids are strings)")
+	GenerateFrontendCmd.Flags().BoolVar(&utils.Alter, "alter", false, "Add the fields to an existing module's types, form and pages")
+	GenerateFrontendCmd.Flags().BoolVar(&utils.GraphQL, "graphql", false, "Add GraphQL queries and mutations to the module store")
+	GenerateFrontendCmd.Flags().BoolVar(&utils.Realtime, "realtime", false, "Keep the list page live with the backend's websocket events")
+	GenerateFrontendCmd.Flags().BoolVar(&utils.Tenant, "tenant", false, "Reset the module store when the admin switches organization")
+	GenerateFrontendCmd.Flags().BoolVar(&utils.Audited, "audited", false, "Add an Activity tab with the audit log to the detail page")
+	GenerateFrontendCmd.Flags().BoolVar(&utils.Versioned, "versioned", false, "Add a Revisions tab that compares and restores revisions to the detail page")
+	GenerateFrontendCmd.Flags().BoolVar(&utils.LockVersion, "lock-version", false, "Send lock_version with updates and prompt to reload when the item changed since it was opened")
+	GenerateFrontendCmd.Flags().BoolVar(&utils.ImportExport, "import-export", false, "Add Import/Export buttons and an import preview modal to the list page")
+	GenerateFrontendCmd.Flags().BoolVar(&utils.PDF, "pdf", false, "Add a Download PDF button to the detail page")
+	GenerateFrontendCmd.Flags().BoolVar(&utils.Bulk, "bulk", false, "Add row selection with bulk delete and status update to the list page")
+	GenerateFrontendCmd.Flags().StringVar(&utils.Searchable, "searchable", "", "Search these comma-separated text columns from the list page and add a ranked search store action")
+	GenerateFrontendCmd.Flags().StringVar(&utils.NestedForms, "nested-form", "", "Edit the rows of comma-separated hasMany fields inside the form modal")
+	GenerateFrontendCmd.Flags().StringArrayVar(&utils.FieldsRead, "fields-read", nil, "Only show these comma-separated columns, on the list= or detail= page or both (repeatable)")
+	GenerateFrontendCmd.Flags().StringArrayVar(&utils.FieldsWrite, "fields-write", nil, "Only edit these comma-separated columns, in the create= or update= form or both (repeatable)")
+	GenerateFrontendCmd.Flags().StringVar(&utils.NavIcon, "icon", "", "Icon of the module's sidebar entry and config, e.g. i-lucide-shopping-cart or shopping-cart (default "+utils.DefaultNavIcon+")")
+	GenerateFrontendCmd.Flags().StringVar(&utils.ModuleLabel, "label", "", "Display name of the module in titles, breadcrumbs and the sidebar (e.g. \"Sales Orders\")")
+	GenerateFrontendCmd.Flags().StringVar(&utils.ModuleDescription, "description", "", "Description of the module in module.config.ts and under the list page title")
+	GenerateFrontendCmd.Flags().StringVar(&utils.NavGroup, "nav-group", "", "Sidebar group the module is listed in (default the namespace, else "+utils.DefaultNavGroup+")")
 }
 
 // generateFrontendModule generates a new frontend module with the specified name and fields
 func generateFrontendModule(cmd *mamba.Command, args []string) {
-	singularName := args[0]
-	fields := args[1:]
+	// The changelog and manifest are in the project root, found from the directory bui was run in
+	startDir, _ := os.Getwd()
+	w := utils.NewRun(utils.ProjectRoot(startDir), args[0]).Writer(DetectFrontendDir())
+
+	if !checkModule(cmd, w, args) {
+		return
+	}
 
-	// Detect frontend directory
-	frontendDir := detectFrontendDir()
-	if frontendDir != "" && frontendDir != "." {
-		// Change to frontend directory
-		if err := os.Chdir(frontendDir); err != nil {
-			cmd.PrintError(fmt.Sprintf("Failed to change to frontend directory: %v", err))
+	// Without fields, --api-client on an existing module only regenerates the client
+	if apiClient && len(args) == 1 && !utils.Alter {
+		naming := newNaming(args[0])
+		moduleBasePath := w.Path("app", "modules", naming.PluralSnake)
+		if _, err := os.Stat(moduleBasePath); err == nil {
+			client, err := loadModuleAPIClient(naming)
+			if err == nil {
+				err = generateAPIClient(cmd, w, moduleBasePath, client)
+			}
+			if err != nil {
+				cmd.PrintError(fmt.Sprintf("Failed to generate API client: %v", err))
+				return
+			}
+			if !utils.DryRun {
+				printWriteSummary(cmd, w)
+				cmd.PrintSuccess(fmt.Sprintf("Generated API client: %s/api/%s.ts", moduleBasePath, naming.ModelSnake))
+			}
 			return
 		}
-		if Verbose != nil && *Verbose {
-			cmd.PrintInfo(fmt.Sprintf("Working in: %s", frontendDir))
+	}
+
+	if err := GenerateModule(cmd, w, args); err != nil {
+		cmd.PrintError(err.Error())
+		return
+	}
+	FinishModule(cmd, w, args)
+}
+
+// checkModule checks the flags and fields of the module args describe, in the frontend w writes
+// to, before anything is written, printing what's wrong
+func checkModule(cmd *mamba.Command, w *utils.Writer, args []string) bool {
+	if err := utils.CheckPrimaryKey(); err != nil {
+		cmd.PrintError(err.Error())
+		return false
+	}
+	if err := utils.CheckModuleMetadata(); err != nil {
+		cmd.PrintError(err.Error())
+		return false
+	}
+	if err := utils.CheckPayloadFields(); err != nil {
+		cmd.PrintError(err.Error())
+		return false
+	}
+	if err := utils.CheckFieldRules(args[1:]); err != nil {
+		cmd.PrintError(err.Error())
+		return false
+	}
+
+	naming := utils.NewNamingConvention(args[0])
+	if _, err := os.Stat(w.Path("app", "modules", naming.PluralSnake)); err != nil {
+		return true
+	}
+	if other := utils.NamespaceConflict(findPageDirs(w), utils.ToKebabCase(naming.Plural), naming.PluralKebab); other != "" {
+		cmd.PrintError(fmt.Sprintf("pages/app/%s already has a %s module, which app/modules/%s belongs to", other, naming.Model, naming.PluralSnake))
+		cmd.PrintInfo("Modules in different namespaces share app/modules, so give this module another name")
+		return false
+	}
+	return true
+}
+
+// findPageDirs returns the list pages of the frontend w writes to, as their directories in
+// pages/app: top-level modules (products) and the modules of a namespace (shop/products)
+func findPageDirs(w *utils.Writer) []string {
+	pagesDir := w.Path("app", "pages", "app")
+	var dirs []string
+	for _, pattern := range []string{
+		filepath.Join(pagesDir, "*", "index.vue"),
+		filepath.Join(pagesDir, "*", "*", "index.vue"),
+	} {
+		matches, _ := filepath.Glob(pattern)
+		for _, match := range matches {
+			if rel, err := filepath.Rel(pagesDir, filepath.Dir(match)); err == nil {
+				dirs = append(dirs, filepath.ToSlash(rel))
+			}
 		}
 	}
+	return dirs
+}
 
-	// Create naming convention from the input name
-	naming := utils.NewNamingConvention(singularName)
+// newNaming returns the naming of the module being generated, with its --label and --description
+func newNaming(name string) *utils.NamingConvention {
+	naming := utils.NewNamingConvention(name)
+	utils.ApplyModuleMetadata(naming)
+	return naming
+}
+
+// loadModuleAPIClient reads the routes of a module from the backend's swagger.json, which is
+// looked up from the directory bui was run in
+func loadModuleAPIClient(naming *utils.NamingConvention) (*apiClientData, error) {
+	specPath, err := findSwaggerSpec()
+	if err != nil {
+		return nil, err
+	}
+	return loadAPIClient(specPath, naming)
+}
+
+// GenerateModule generates the frontend module args describe, with the fields as arguments after
+// its name, in the frontend directory of w. Each path is resolved against that directory, so it
+// can run alongside the backend generator; FinishModule adds what changes other modules.
+func GenerateModule(cmd *mamba.Command, w *utils.Writer, args []string) error {
+	naming := newNaming(args[0])
+	fields := args[1:]
+	if w.Dir != "." && Verbose != nil && *Verbose {
+		cmd.PrintInfo(fmt.Sprintf("Working in: %s", w.Dir))
+	}
 
 	// Base path for app directory
-	adminPath := "app"
+	adminPath := w.Path("app")
 
-	// Create directories
 	moduleBasePath := filepath.Join(adminPath, "modules", naming.PluralSnake)
+
+	if utils.Alter {
+		if altered := alterFrontendModule(cmd, w, adminPath, naming, fields); altered != nil {
+			writeModuleReadme(cmd, w, adminPath, moduleBasePath, altered, utils.GenerationCommand(cmd, args))
+		}
+		return nil
+	}
+
+	var client *apiClientData
+	if apiClient {
+		var err error
+		if client, err = loadModuleAPIClient(naming); err != nil {
+			return err
+		}
+	}
+
+	// --nested-form reads the child models from the backend
+	var nestedModels string
+	if utils.NestedForms != "" {
+		nestedModels = findBackendModels()
+	}
+
+	// Create directories
 	dirs := []string{
 		filepath.Join(moduleBasePath, "types"),
 		filepath.Join(moduleBasePath, "stores"),
@@ -56,162 +204,571 @@ func generateFrontendModule(cmd *mamba.Command, args []string) {
 	}
 
 	for _, dir := range dirs {
+		if utils.DryRun {
+			break
+		}
 		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
-			cmd.PrintError(fmt.Sprintf("Failed to create directory %s: %v", dir, err))
-			return
+			return fmt.Errorf("failed to create directory %s: %w", dir, err)
 		}
 		if Verbose != nil && *Verbose {
 			cmd.PrintInfo(fmt.Sprintf("Created directory: %s", dir))
 		}
 	}
 
-	// Parse fields
-	parsedFields := make([]utils.Field, 0, len(fields))
-	for _, fieldDef := range fields {
-		parsedFields = append(parsedFields, utils.ParseField(fieldDef))
+	templateData, parsedFields := newTemplateData(adminPath, naming, fields)
+	if utils.NestedForms != "" {
+		templateData.NestedForms = nestedForms(cmd, naming.Model, parsedFields, nestedModels)
 	}
-
-	// Convert to Nuxt fields with TypeScript types
-	nuxtFields := make([]utils.NuxtField, 0, len(parsedFields))
-	for _, field := range parsedFields {
-		nf := utils.ConvertToNuxtField(field)
-
-		// For belongs_to relations, fetch the display field from the related model's type file
-		if field.IsRelation && field.Relationship == "belongs_to" && field.RelatedModel != "" {
-			relatedDisplayField := getRelatedModelDisplayField(adminPath, field.RelatedModel)
-			nf.RelationDisplayField = relatedDisplayField
-		}
-
-		nuxtFields = append(nuxtFields, nf)
-	}
-
-	// Determine display field (first non-relation string field)
-	displayField := "id" // fallback
-	for _, field := range parsedFields {
-		if !field.IsRelation && !field.IsMediaFK && (field.Type == "string" || field.Type == "translation.Field") {
-			displayField = field.JSONName
-			break
-		}
-	}
-
-	// Template data combining naming and fields
-	type TemplateData struct {
-		*utils.NamingConvention
-		Fields       []utils.NuxtField
-		DisplayField string
-	}
-
-	templateData := &TemplateData{
-		NamingConvention: naming,
-		Fields:           nuxtFields,
-		DisplayField:     displayField,
+	viewList, err := parseViews(views, templateData.Fields, templateData.DisplayField)
+	if err != nil {
+		return err
 	}
+	templateData.Views = viewList
 
 	// Generate module.config.ts
-	if err := utils.GenerateNuxtFile(
+	if err := w.GenerateNuxtFile(
 		moduleBasePath,
 		"module.config.ts",
 		"nuxt/module.config.ts.tmpl",
 		templateData,
 	); err != nil {
-		cmd.PrintError(fmt.Sprintf("Failed to generate module.config.ts: %v", err))
-		return
+		return fmt.Errorf("failed to generate module.config.ts: %w", err)
 	}
-	if Verbose != nil && *Verbose {
+	if Verbose != nil && *Verbose && !utils.DryRun {
 		cmd.PrintSuccess("Generated module.config.ts")
 	}
 
 	// Generate types file
-	if err := utils.GenerateNuxtFile(
+	if err := w.GenerateNuxtFile(
 		filepath.Join(moduleBasePath, "types"),
 		naming.ModelSnake+".ts",
 		"nuxt/types.ts.tmpl",
 		templateData,
 	); err != nil {
-		cmd.PrintError(fmt.Sprintf("Failed to generate types: %v", err))
-		return
+		return fmt.Errorf("failed to generate types: %w", err)
 	}
-	if Verbose != nil && *Verbose {
+	if Verbose != nil && *Verbose && !utils.DryRun {
 		cmd.PrintSuccess(fmt.Sprintf("Generated types/%s.ts", naming.ModelSnake))
 	}
 
 	// Generate store
-	if err := utils.GenerateNuxtFile(
+	if err := w.GenerateNuxtFile(
 		filepath.Join(moduleBasePath, "stores"),
 		naming.PluralSnake+".ts",
 		"nuxt/store.ts.tmpl",
 		templateData,
 	); err != nil {
-		cmd.PrintError(fmt.Sprintf("Failed to generate store: %v", err))
-		return
+		return fmt.Errorf("failed to generate store: %w", err)
 	}
-	if Verbose != nil && *Verbose {
+	if Verbose != nil && *Verbose && !utils.DryRun {
 		cmd.PrintSuccess(fmt.Sprintf("Generated stores/%s.ts", naming.PluralSnake))
 	}
 
 	// Generate form modal component
-	if err := utils.GenerateNuxtFile(
+	if err := w.GenerateNuxtFile(
 		filepath.Join(moduleBasePath, "components"),
 		naming.Model+"FormModal.vue",
 		"nuxt/form-modal.vue.tmpl",
 		templateData,
 	); err != nil {
-		cmd.PrintError(fmt.Sprintf("Failed to generate form modal: %v", err))
-		return
+		return fmt.Errorf("failed to generate form modal: %w", err)
 	}
-	if Verbose != nil && *Verbose {
+	if Verbose != nil && *Verbose && !utils.DryRun {
 		cmd.PrintSuccess(fmt.Sprintf("Generated components/%sFormModal.vue", naming.Model))
 	}
 
+	// Generate the revision history shown on the detail page
+	if utils.Versioned {
+		if err := w.GenerateNuxtFile(
+			filepath.Join(moduleBasePath, "components"),
+			naming.Model+"Revisions.vue",
+			"nuxt/revisions.vue.tmpl",
+			templateData,
+		); err != nil {
+			return fmt.Errorf("failed to generate revision history: %w", err)
+		}
+		if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess(fmt.Sprintf("Generated components/%sRevisions.vue", naming.Model))
+		}
+	}
+
+	// Generate the link editors of the manyToMany relations with pivot columns
+	if err := generatePivots(cmd, w, moduleBasePath, templateData); err != nil {
+		return fmt.Errorf("failed to generate pivot editor: %w", err)
+	}
+
+	// Generate the activity timeline shown on the detail page
+	if utils.Audited {
+		if err := w.GenerateNuxtFile(
+			filepath.Join(moduleBasePath, "components"),
+			naming.Model+"Activity.vue",
+			"nuxt/activity.vue.tmpl",
+			templateData,
+		); err != nil {
+			return fmt.Errorf("failed to generate activity timeline: %w", err)
+		}
+		if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess(fmt.Sprintf("Generated components/%sActivity.vue", naming.Model))
+		}
+	}
+
+	// Generate the import preview modal opened from the list page
+	if utils.ImportExport {
+		if err := w.GenerateNuxtFile(
+			filepath.Join(moduleBasePath, "components"),
+			naming.Model+"ImportModal.vue",
+			"nuxt/import-modal.vue.tmpl",
+			templateData,
+		); err != nil {
+			return fmt.Errorf("failed to generate import modal: %w", err)
+		}
+		if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess(fmt.Sprintf("Generated components/%sImportModal.vue", naming.Model))
+		}
+	}
+
 	// Generate formatters utils
-	if err := utils.GenerateNuxtFile(
+	if err := w.GenerateNuxtFile(
 		filepath.Join(moduleBasePath, "utils"),
 		"formatters.ts",
 		"nuxt/formatters.ts.tmpl",
 		templateData,
 	); err != nil {
-		cmd.PrintError(fmt.Sprintf("Failed to generate formatters: %v", err))
-		return
+		return fmt.Errorf("failed to generate formatters: %w", err)
 	}
-	if Verbose != nil && *Verbose {
+	if Verbose != nil && *Verbose && !utils.DryRun {
 		cmd.PrintSuccess("Generated utils/formatters.ts")
 	}
 
+	// Generate the shared websocket composable the realtime stores subscribe through
+	if utils.Realtime {
+		composablesDir := filepath.Join(adminPath, "composables")
+		if _, err := os.Stat(filepath.Join(composablesDir, "useRealtime.ts")); os.IsNotExist(err) {
+			if err := w.GenerateNuxtFile(composablesDir, "useRealtime.ts", "nuxt/realtime.ts.tmpl", templateData); err != nil {
+				return fmt.Errorf("failed to generate realtime composable: %w", err)
+			}
+			if Verbose != nil && *Verbose && !utils.DryRun {
+				cmd.PrintSuccess("Generated composables/useRealtime.ts")
+			}
+		}
+	}
+
+	// Generate the shared organization composable the tenant stores reset through
+	if utils.Tenant {
+		composablesDir := filepath.Join(adminPath, "composables")
+		if _, err := os.Stat(filepath.Join(composablesDir, "useOrganization.ts")); os.IsNotExist(err) {
+			if err := w.GenerateNuxtFile(composablesDir, "useOrganization.ts", "nuxt/organization.ts.tmpl", templateData); err != nil {
+				return fmt.Errorf("failed to generate organization composable: %w", err)
+			}
+			if Verbose != nil && *Verbose && !utils.DryRun {
+				cmd.PrintSuccess("Generated composables/useOrganization.ts")
+			}
+		}
+	}
+
+	// Generate the shared component the belongs_to selects search their related module through
+	if err := generateRelationSelect(cmd, w, adminPath, templateData); err != nil {
+		return fmt.Errorf("failed to generate relation select: %w", err)
+	}
+
+	// Generate the shared upload composable and component the file fields upload through
+	if err := generateUploads(cmd, w, adminPath, templateData); err != nil {
+		return fmt.Errorf("failed to generate uploads: %w", err)
+	}
+
+	// Generate the shared composables the form checks rules= modifiers with
+	if err := generateRules(cmd, w, adminPath, templateData); err != nil {
+		return fmt.Errorf("failed to generate rules: %w", err)
+	}
+
+	// Generate the shared components JSON fields are edited and shown with
+	if err := generateJSONComponents(cmd, w, adminPath, templateData); err != nil {
+		return fmt.Errorf("failed to generate JSON components: %w", err)
+	}
+
+	// Generate the shared currency input money fields are edited with
+	if err := generateMoneyInput(cmd, w, adminPath, templateData); err != nil {
+		return fmt.Errorf("failed to generate money input: %w", err)
+	}
+
+	// Generate the shared maps point fields are picked and shown on
+	if err := generateMapComponents(cmd, w, adminPath, templateData); err != nil {
+		return fmt.Errorf("failed to generate map components: %w", err)
+	}
+
+	// Generate the shared component encrypted fields are revealed with
+	if err := generateSecretValue(cmd, w, adminPath, templateData); err != nil {
+		return fmt.Errorf("failed to generate SecretValue: %w", err)
+	}
+
 	// Generate index page
-	if err := utils.GenerateNuxtFile(
+	if err := w.GenerateNuxtFile(
 		filepath.Join(adminPath, "pages", "app", naming.PluralKebab),
 		"index.vue",
 		"nuxt/index.vue.tmpl",
 		templateData,
 	); err != nil {
-		cmd.PrintError(fmt.Sprintf("Failed to generate index page: %v", err))
-		return
+		return fmt.Errorf("failed to generate index page: %w", err)
 	}
-	if Verbose != nil && *Verbose {
+	if Verbose != nil && *Verbose && !utils.DryRun {
 		cmd.PrintSuccess(fmt.Sprintf("Generated pages/app/%s/index.vue", naming.PluralKebab))
 	}
 
 	// Generate detail page
-	if err := utils.GenerateNuxtFile(
+	if err := w.GenerateNuxtFile(
 		filepath.Join(adminPath, "pages", "app", naming.PluralKebab),
 		"[id].vue",
 		"nuxt/detail.vue.tmpl",
 		templateData,
 	); err != nil {
-		cmd.PrintError(fmt.Sprintf("Failed to generate detail page: %v", err))
-		return
+		return fmt.Errorf("failed to generate detail page: %w", err)
 	}
-	if Verbose != nil && *Verbose {
+	if Verbose != nil && *Verbose && !utils.DryRun {
 		cmd.PrintSuccess(fmt.Sprintf("Generated pages/app/%s/[id].vue", naming.PluralKebab))
 	}
 
+	// Generate the kanban, calendar and gallery pages
+	if err := generateViews(cmd, w, adminPath, moduleBasePath, templateData); err != nil {
+		return fmt.Errorf("failed to generate view pages: %w", err)
+	}
+
+	// Generate the list page scoped to the parent for nested modules
+	if parent := utils.NestedParentFor(parsedFields); parent != nil {
+		nestedData := *templateData
+		nestedData.Parent = parent
+		nestedData.Views = nil // The views list every record, not a parent's
+		if err := generateNestedIndexPage(cmd, w, adminPath, naming, parent, &nestedData); err != nil {
+			return fmt.Errorf("failed to generate nested index page: %w", err)
+		}
+	} else if utils.Nested {
+		cmd.PrintWarning("--nested needs a belongsTo field; generating top-level pages only")
+	}
+
+	// List the module in the admin sidebar
+	if err := registerNavigation(cmd, w, templateData.Navigation); err != nil {
+		cmd.PrintWarning(fmt.Sprintf("Could not add the module to %s: %v", utils.NavigationFile, err))
+	}
+
+	// Generate the mock records the stories and tests share
+	if stories || tests {
+		if err := generateMocks(cmd, w, moduleBasePath, templateData); err != nil {
+			return fmt.Errorf("failed to generate mocks: %w", err)
+		}
+	}
+
+	// Generate the Storybook stories of the table and form modal
+	if stories {
+		if err := generateStories(cmd, w, moduleBasePath, templateData); err != nil {
+			return fmt.Errorf("failed to generate stories: %w", err)
+		}
+	}
+
+	// Generate the Vitest tests of the store, form modal and list page
+	if tests {
+		if err := generateTests(cmd, w, moduleBasePath, templateData); err != nil {
+			return fmt.Errorf("failed to generate tests: %w", err)
+		}
+	}
+
+	// Generate the typed API client from the backend's swagger.json
+	if apiClient {
+		if err := generateAPIClient(cmd, w, moduleBasePath, client); err != nil {
+			return fmt.Errorf("failed to generate API client: %w", err)
+		}
+	}
+
+	if utils.DryRun {
+		return nil
+	}
+
+	// Document the module as generated in its README.md
+	writeModuleReadme(cmd, w, adminPath, moduleBasePath, templateData, utils.GenerationCommand(cmd, args))
+	return nil
+}
+
+// FinishModule completes the frontend module GenerateModule generated from args: the related
+// modules get the hasMany fields the backend generator of the run added, and the generation is
+// recorded. bui g runs it after the backend's FinishModule, which adds those fields.
+func FinishModule(cmd *mamba.Command, w *utils.Writer, args []string) {
+	naming := newNaming(args[0])
+
+	// The related modules list the records that belong to them
+	addInverseRelations(cmd, w)
+
+	if utils.Alter {
+		recordGeneration(cmd, w, args, "Frontend module "+naming.Model)
+		return
+	}
+
+	if utils.DryRun {
+		cmd.PrintInfo(fmt.Sprintf("Dry run: frontend module %s was not written", naming.Model))
+		return
+	}
+
+	printWriteSummary(cmd, w)
+	recordGeneration(cmd, w, args, "Frontend module "+naming.Model)
+
 	if Verbose == nil || !*Verbose {
 		cmd.PrintSuccess(fmt.Sprintf("Generated frontend module: %s", naming.Model))
 	}
 }
 
-// detectFrontendDir finds the frontend directory in the current working directory
-func detectFrontendDir() string {
+// TemplateData combines naming and fields for the Nuxt templates
+type TemplateData struct {
+	*utils.NamingConvention
+	Fields       []utils.NuxtField
+	DisplayField string
+	Parent       *utils.NestedParent
+	TreeParent   string // Parent relation of a self-referencing model (e.g., "parent")
+	IDType       string // TypeScript type of ids: number, or string for --pk uuid
+	UUIDKey      bool
+	Realtime     bool // Subscribe the list page to the backend's websocket events
+	Policy       bool // Guard the pages with the abilities bui g policy generated
+	Tenant       bool // Reset the store when the admin switches organization
+	Audited      bool // Show the audit log in an Activity tab on the detail page
+	Versioned    bool // Compare and restore revisions in a Revisions tab on the detail page
+	LockVersion  bool // Send lock_version with updates and prompt to reload refused ones
+	ImportExport bool // Add Import/Export buttons and the import preview modal to the list page
+	PDF          bool // Add a Download PDF button to the detail page
+	Bulk         bool // Add row selection with bulk delete and status update to the list page
+	Searchable   bool // The list endpoint accepts ?search=, so the list page gets a search box
+	FullText     bool // The backend has the --searchable /search endpoint
+
+	// --bulk status updates set this select field; nil leaves bulk delete only
+	BulkStatus *utils.NuxtField
+
+	// State fields, which the detail page moves along their transitions instead of the form
+	States []utils.NuxtField
+
+	// hasMany relations whose rows are edited in the form modal
+	NestedForms []NestedForm
+
+	// manyToMany relations with pivot columns, whose links the detail page edits
+	Pivots []Pivot
+
+	// The module's sidebar entry (--icon and --nav-group)
+	Navigation utils.NavigationItem
+
+	// Kanban, calendar and gallery pages linked from the list page, and the one being generated
+	Views []View
+	View  View
+
+	// File fields: Uploads is set when the form modal has media or attachment fields, whose
+	// Attachments the store uploads once the record is saved
+	Uploads     bool
+	Attachments []utils.NuxtField
+	MediaLists  []utils.NuxtField // media[] fields, which list rows don't carry
+	Unlisted    []utils.NuxtField // Form fields --fields-read leaves out of list rows
+	RuleFields  []utils.NuxtField // Form fields with rules= modifiers, checked before submitting
+
+	// --fields-write gives the create and update requests different fields, so they get their own types
+	SplitPayloads bool
+
+	// --graphql store actions
+	GraphQL        bool
+	GraphQLFields  []utils.GraphQLField
+	GraphQLFilters []string // Columns the list query accepts as filters
+}
+
+// newTemplateData parses field definitions into the Nuxt template data and the parsed fields
+func newTemplateData(adminPath string, naming *utils.NamingConvention, fields []string) (*TemplateData, []utils.Field) {
+	// Parse fields
+	parsedFields := make([]utils.Field, 0, len(fields))
+	for _, fieldDef := range fields {
+		// morphTo fields become their <name>_id and <name>_type columns, points their <name>_lat
+		// and <name>_lng columns
+		field := utils.ParseField(fieldDef)
+		if field.IsPoint {
+			parsedFields = append(parsedFields, utils.ExpandPoint(field)...)
+			continue
+		}
+		parsedFields = append(parsedFields, utils.ExpandMorphTo(field)...)
+	}
+	parsedFields = utils.ResolveSelfRelations(naming.Model, parsedFields)
+	utils.ApplyPayloadFields(parsedFields)
+
+	// Determine display field (first non-relation string field)
+	displayField := "id" // fallback
+	for _, field := range parsedFields {
+		if !field.IsRelation && !field.IsMediaFK && !field.IsEncrypted && (field.Type == "string" || field.Type == "translation.Field") {
+			displayField = field.JSONName
+			break
+		}
+	}
+
+	// Convert to Nuxt fields with TypeScript types
+	nuxtFields := make([]utils.NuxtField, 0, len(parsedFields))
+	treeParent := ""
+	for _, field := range parsedFields {
+		nf := utils.ConvertToNuxtField(field)
+
+		// For belongs_to relations, fetch the display field from the related model's type file
+		if field.IsRelation && field.Relationship == "belongs_to" && field.RelatedModel != "" {
+			if field.IsSelfRef {
+				// The model's own type file may not exist yet
+				nf.RelationDisplayField = displayField
+				if treeParent == "" {
+					treeParent = nf.RelationObjectName
+				}
+			} else {
+				nf.RelationDisplayField = getRelatedModelDisplayField(adminPath, field.RelatedModel)
+			}
+		}
+
+		// The detail page lists hasMany children by their display field
+		if field.Relationship == "has_many" || field.Relationship == "morph_many" {
+			if field.IsSelfRef {
+				nf.RelationDisplayField = displayField
+			} else {
+				nf.RelationDisplayField = getRelatedModelDisplayField(adminPath, field.RelatedModel)
+			}
+		}
+
+		nuxtFields = append(nuxtFields, nf)
+	}
+
+	idType := "number"
+	if utils.UUIDKey() {
+		idType = "string"
+	}
+
+	data := &TemplateData{
+		NamingConvention: naming,
+		Fields:           nuxtFields,
+		DisplayField:     displayField,
+		TreeParent:       treeParent,
+		IDType:           idType,
+		UUIDKey:          utils.UUIDKey(),
+		Realtime:         utils.Realtime,
+		Policy:           hasPolicyGuards(adminPath, naming),
+		Tenant:           utils.Tenant,
+		Audited:          utils.Audited,
+		Versioned:        utils.Versioned,
+		LockVersion:      utils.LockVersion,
+		ImportExport:     utils.ImportExport,
+		PDF:              utils.PDF,
+		Bulk:             utils.Bulk,
+		Searchable:       len(utils.SearchColumns(parsedFields)) > 0,
+		FullText:         utils.Searchable != "" && len(utils.SearchColumns(parsedFields)) > 0,
+		GraphQL:          utils.GraphQL,
+		Pivots:           newPivots(adminPath, parsedFields),
+		Navigation:       utils.NewNavigationItem(naming),
+	}
+	if i := utils.BulkStatusIndex(parsedFields); i != -1 {
+		data.BulkStatus = &nuxtFields[i]
+	}
+	for _, field := range nuxtFields {
+		if field.IsState {
+			data.States = append(data.States, field)
+		}
+		if field.NoCreate != field.NoUpdate {
+			data.SplitPayloads = true
+		}
+		if !field.ShowInForm {
+			continue
+		}
+		if field.HideInList && !field.HideInDetail && !field.IsMedia && !field.IsAttachment && !field.IsEncrypted {
+			data.Unlisted = append(data.Unlisted, field)
+		}
+		switch {
+		case field.IsMediaList:
+			data.MediaLists = append(data.MediaLists, field)
+		case field.IsAttachment:
+			data.Attachments = append(data.Attachments, field)
+		}
+		if field.IsMedia || field.IsAttachment {
+			data.Uploads = true
+		}
+		if len(field.Rules) > 0 {
+			data.RuleFields = append(data.RuleFields, field)
+		}
+	}
+	if utils.GraphQL {
+		data.GraphQLFields, _ = utils.GraphQLFields(parsedFields)
+		for _, field := range data.GraphQLFields {
+			if field.Filter {
+				data.GraphQLFilters = append(data.GraphQLFilters, field.Name)
+			}
+		}
+	}
+	return data, parsedFields
+}
+
+// generateNestedIndexPage writes pages/app/<parents>/[id]/<children>/index.vue.
+// The parent's [id].vue moves to [id]/index.vue so Nuxt treats both as sibling routes
+// instead of making the detail page a layout for the nested one.
+func generateNestedIndexPage(cmd *mamba.Command, w *utils.Writer, adminPath string, naming *utils.NamingConvention, parent *utils.NestedParent, data interface{}) error {
+	parentPagesDir := filepath.Join(adminPath, "pages", "app", parent.PluralKebab)
+	detailPage := filepath.Join(parentPagesDir, "[id].vue")
+	nestedDetailPage := filepath.Join(parentPagesDir, "[id]", "index.vue")
+
+	if _, err := os.Stat(detailPage); err == nil {
+		if _, err := os.Stat(nestedDetailPage); os.IsNotExist(err) {
+			if utils.DryRun {
+				fmt.Printf("  move      %s -> %s\n", detailPage, nestedDetailPage)
+			} else {
+				if err := os.MkdirAll(filepath.Dir(nestedDetailPage), os.ModePerm); err != nil {
+					return err
+				}
+				if err := os.Rename(detailPage, nestedDetailPage); err != nil {
+					return err
+				}
+				if Verbose != nil && *Verbose {
+					cmd.PrintInfo(fmt.Sprintf("Moved %s to %s", detailPage, nestedDetailPage))
+				}
+			}
+		}
+	}
+
+	pageDir := filepath.Join(parentPagesDir, "[id]", naming.PluralKebab)
+	if err := w.GenerateNuxtFile(pageDir, "index.vue", "nuxt/index.vue.tmpl", data); err != nil {
+		return err
+	}
+	if Verbose != nil && *Verbose && !utils.DryRun {
+		cmd.PrintSuccess(fmt.Sprintf("Generated pages/app/%s/[id]/%s/index.vue", parent.PluralKebab, naming.PluralKebab))
+	}
+
+	return nil
+}
+
+// printWriteSummary reports which generated files w wrote and which existing files it kept
+func printWriteSummary(cmd *mamba.Command, w *utils.Writer) {
+	if backupDir := w.OverwriteBackupDir(); backupDir != "" {
+		cmd.PrintInfo("Previous versions of overwritten files saved to " + backupDir)
+		if w.Run.Module != "" {
+			cmd.PrintInfo(fmt.Sprintf("Put them back with: bui restore %s --reason overwrite", utils.BackupName(w.Run.Module)))
+		}
+	}
+
+	merged, conflicted := w.MergedFiles()
+	if len(merged) > 0 {
+		cmd.PrintInfo(fmt.Sprintf("Merged the edits made since generation into %d files", len(merged)))
+	}
+	if len(conflicted) > 0 {
+		cmd.PrintWarning("Resolve the conflict markers where the edits and the templates changed the same lines:")
+		for _, path := range conflicted {
+			cmd.PrintBullet(path)
+		}
+	}
+
+	written, skipped := w.GeneratedFiles()
+	if len(skipped) == 0 {
+		return
+	}
+
+	cmd.PrintWarning(fmt.Sprintf("Wrote %d files, kept %d existing files:", len(written), len(skipped)))
+	for _, path := range skipped {
+		cmd.PrintBullet(path)
+	}
+	cmd.PrintInfo("Re-run with --force to overwrite them")
+}
+
+// DetectFrontendDir finds the frontend directory in the current working directory
+func DetectFrontendDir() string {
+	if dir := utils.Project.FrontendDir(); dir != "" {
+		return dir
+	}
+
 	// Check if we're already in a frontend directory
 	if _, err := os.Stat("nuxt.config.ts"); err == nil {
 		if _, err := os.Stat(filepath.Join("app", "pages")); err == nil {
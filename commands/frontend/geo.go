@@ -0,0 +1,47 @@
+package frontend
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// generateMapComponents writes the shared useLeaflet composable, the MapPicker the form modals
+// edit point fields with and the MapView the detail pages show them with. Existing files are kept.
+func generateMapComponents(cmd *mamba.Command, w *utils.Writer, adminPath string, data *TemplateData) error {
+	picker, view := false, false
+	for _, field := range data.Fields {
+		if field.FormType != "point" {
+			continue
+		}
+		picker = picker || field.ShowInForm
+		view = view || field.ShowInDetail
+	}
+
+	files := []struct {
+		needed              bool
+		dir, name, template string
+	}{
+		{picker || view, "composables", "useLeaflet.ts", "nuxt/leaflet.ts.tmpl"},
+		{picker, "components", "MapPicker.vue", "nuxt/map-picker.vue.tmpl"},
+		{view, "components", "MapView.vue", "nuxt/map-view.vue.tmpl"},
+	}
+	for _, file := range files {
+		if !file.needed {
+			continue
+		}
+		dir := filepath.Join(adminPath, file.dir)
+		if _, err := os.Stat(filepath.Join(dir, file.name)); !os.IsNotExist(err) {
+			continue
+		}
+		if err := w.GenerateNuxtFile(dir, file.name, file.template, data); err != nil {
+			return err
+		}
+		if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess("Generated " + file.dir + "/" + file.name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package frontend
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// generateJSONComponents writes the shared JsonEditor the form modals edit JSON fields with and
+// the JsonView the detail pages show them with. Existing components are kept.
+func generateJSONComponents(cmd *mamba.Command, w *utils.Writer, adminPath string, data *TemplateData) error {
+	editor, view := false, false
+	for _, field := range data.Fields {
+		if field.FormType != "json" {
+			continue
+		}
+		editor = editor || field.ShowInForm
+		view = view || field.ShowInDetail
+	}
+
+	components := []struct {
+		needed         bool
+		name, template string
+	}{
+		{editor, "JsonEditor.vue", "nuxt/json-editor.vue.tmpl"},
+		{view, "JsonView.vue", "nuxt/json-view.vue.tmpl"},
+	}
+	componentsDir := filepath.Join(adminPath, "components")
+	for _, component := range components {
+		if !component.needed {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(componentsDir, component.name)); !os.IsNotExist(err) {
+			continue
+		}
+		if err := w.GenerateNuxtFile(componentsDir, component.name, component.template, data); err != nil {
+			return err
+		}
+		if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess("Generated components/" + component.name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package frontend
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// mockRecords is how many mock records the stories and tests use
+const mockRecords = 3
+
+// mockData fills in the nuxt/mocks.ts.tmpl template
+type mockData struct {
+	*TemplateData
+	Records []mockRecord
+}
+
+// mockRecord is one mock record, as TypeScript properties in the order of the model's fields
+type mockRecord struct {
+	ID         string
+	Properties []mockProperty
+}
+
+// mockProperty is a property of a mock record with its value as a TypeScript expression
+type mockProperty struct {
+	Name  string
+	Value string
+}
+
+// generateMocks writes the mock records the stories and tests share to the module's mocks
+// directory
+func generateMocks(cmd *mamba.Command, w *utils.Writer, moduleBasePath string, data *TemplateData) error {
+	mocks := &mockData{TemplateData: data}
+	for n := 1; n <= mockRecords; n++ {
+		mocks.Records = append(mocks.Records, newMockRecord(data, n))
+	}
+
+	if err := w.GenerateNuxtFile(filepath.Join(moduleBasePath, "mocks"), data.ModelSnake+".ts", "nuxt/mocks.ts.tmpl", mocks); err != nil {
+		return err
+	}
+	if Verbose != nil && *Verbose && !utils.DryRun {
+		cmd.PrintSuccess(fmt.Sprintf("Generated mocks/%s.ts", data.ModelSnake))
+	}
+	return nil
+}
+
+// newMockRecord returns the nth mock record of the model. Optional relations, files and fields
+// responses leave out (--fields-read) are left out.
+func newMockRecord(data *TemplateData, n int) mockRecord {
+	record := mockRecord{ID: mockID(data.UUIDKey, n)}
+	add := func(name, value string) {
+		record.Properties = append(record.Properties, mockProperty{Name: name, Value: value})
+	}
+
+	for _, field := range data.Fields {
+		switch {
+		case field.HideInDetail, field.IsMediaList, field.IsAttachment:
+		case field.IsMedia:
+			add(field.MediaFKJSONName, "null")
+		case field.Relationship == "belongs_to":
+			if field.IsSelfRef {
+				add(field.JSONName, mockID(data.UUIDKey, 1))
+				continue
+			}
+			add(field.JSONName, strconv.Itoa(n))
+			add(field.RelationObjectName, fmt.Sprintf("{ id: %d, %s: '%s %d' }", n, field.RelationDisplayField, field.RelationLabel, n))
+		case field.IsRelation:
+		default:
+			add(field.JSONName, mockValue(field, n))
+		}
+	}
+	if data.Tenant {
+		add("organization_id", "1")
+	}
+	if data.LockVersion {
+		add("lock_version", "0")
+	}
+	return record
+}
+
+// mockID returns the id of the nth mock record
+func mockID(uuidKey bool, n int) string {
+	if uuidKey {
+		return fmt.Sprintf("'00000000-0000-4000-8000-%012d'", n)
+	}
+	return strconv.Itoa(n)
+}
+
+// mockValue returns a value of field for the nth mock record, as a TypeScript expression
+func mockValue(field utils.NuxtField, n int) string {
+	name := strings.ToLower(field.JSONName)
+	switch {
+	case len(field.Options) > 0:
+		return quoteTS(field.Options[(n-1)%len(field.Options)])
+	case field.IsEncrypted:
+		return quoteTS("••••" + strconv.Itoa(1000+n))
+	case field.IsMoney:
+		return strconv.Itoa(n * 1250)
+	case field.IsDecimal:
+		return quoteTS(fmt.Sprintf("%d.50", n*10))
+	case field.PointAxis == "lat":
+		return strconv.FormatFloat(52.52+float64(n)/100, 'f', 3, 64)
+	case field.PointAxis == "lng":
+		return strconv.FormatFloat(13.405+float64(n)/100, 'f', 3, 64)
+	case field.Type == "bool":
+		return strconv.FormatBool(n%2 == 1)
+	case field.Type == "time.Time" || field.Type == "types.DateTime":
+		return quoteTS(fmt.Sprintf("2026-01-%02dT09:00:00Z", n))
+	case field.Type == "datatypes.JSON" || field.Type == "json.RawMessage":
+		return fmt.Sprintf("{ key: 'value %d' }", n)
+	case field.TypeScriptType == "number":
+		return strconv.Itoa(n * 10)
+	case strings.Contains(name, "email"):
+		return quoteTS(fmt.Sprintf("user%d@example.com", n))
+	case strings.Contains(name, "url") || strings.Contains(name, "website"):
+		return quoteTS(fmt.Sprintf("https://example.com/%d", n))
+	case field.TypeScriptType == "string" || field.IsTranslation:
+		return quoteTS(fmt.Sprintf("%s %d", field.Label, n))
+	default:
+		return "null"
+	}
+}
+
+// quoteTS returns value as a single-quoted TypeScript string
+func quoteTS(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "\\'") + "'"
+}
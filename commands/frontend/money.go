@@ -0,0 +1,33 @@
+package frontend
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// generateMoneyInput writes the shared MoneyInput component the form modals edit money fields
+// with. An existing component is kept.
+func generateMoneyInput(cmd *mamba.Command, w *utils.Writer, adminPath string, data *TemplateData) error {
+	needed := false
+	for _, field := range data.Fields {
+		needed = needed || (field.ShowInForm && field.FormType == "money")
+	}
+	if !needed {
+		return nil
+	}
+
+	componentsDir := filepath.Join(adminPath, "components")
+	if _, err := os.Stat(filepath.Join(componentsDir, "MoneyInput.vue")); !os.IsNotExist(err) {
+		return nil
+	}
+	if err := w.GenerateNuxtFile(componentsDir, "MoneyInput.vue", "nuxt/money-input.vue.tmpl", data); err != nil {
+		return err
+	}
+	if Verbose != nil && *Verbose && !utils.DryRun {
+		cmd.PrintSuccess("Generated components/MoneyInput.vue")
+	}
+	return nil
+}
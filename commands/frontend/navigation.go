@@ -0,0 +1,21 @@
+package frontend
+
+import (
+	"fmt"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// registerNavigation adds the module's entry to the sidebar configuration of the frontend w
+// writes to; a module that's already listed keeps its entry
+func registerNavigation(cmd *mamba.Command, w *utils.Writer, item utils.NavigationItem) error {
+	added, err := w.AddNavigationItem(item)
+	if err != nil {
+		return err
+	}
+	if added && Verbose != nil && *Verbose && !utils.DryRun {
+		cmd.PrintSuccess(fmt.Sprintf("Added %s to the %s group of %s", item.Label, item.Group, utils.NavigationFile))
+	}
+	return nil
+}
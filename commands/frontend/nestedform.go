@@ -0,0 +1,80 @@
+package frontend
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// NestedForm is a --nested-form hasMany whose rows are edited in the form modal
+type NestedForm struct {
+	Name       string // hasMany field, e.g. Items
+	JSONName   string // e.g. items
+	Label      string // e.g. Items
+	ChildLabel string // e.g. Order Item
+	IDType     string // TypeScript type of the rows' ids: number, or string for uuid children
+	Fields     []utils.NuxtField
+}
+
+// findBackendModels returns the backend's app/models directory, which --nested-form reads the
+// child models from. The backend is looked up from the directory bui was run in.
+func findBackendModels() string {
+	var backends []string
+	if dir := utils.Project.BackendDir(); dir != "" {
+		backends = append(backends, dir)
+	}
+	for _, parent := range []string{".", ".."} {
+		entries, err := os.ReadDir(parent)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && strings.HasSuffix(entry.Name(), "-api") {
+				backends = append(backends, filepath.Join(parent, entry.Name()))
+			}
+		}
+	}
+	backends = append(backends, ".")
+
+	for _, backend := range backends {
+		dir := filepath.Join(backend, "app", "models")
+		if _, err := os.Stat(dir); err == nil {
+			if abs, err := filepath.Abs(dir); err == nil {
+				return abs
+			}
+		}
+	}
+	return ""
+}
+
+// nestedForms returns the --nested-form relations edited in the form modal. Problems with them
+// are reported by the backend generator, so only a missing backend is reported here.
+func nestedForms(cmd *mamba.Command, model string, fields []utils.Field, modelsDir string) []NestedForm {
+	if modelsDir == "" {
+		cmd.PrintWarning("--nested-form reads the child models from the backend's app/models, which wasn't found")
+		return nil
+	}
+
+	resolved, _ := utils.ResolveNestedForms(model, fields, modelsDir)
+	forms := make([]NestedForm, 0, len(resolved))
+	for _, form := range resolved {
+		nested := NestedForm{
+			Name:       form.Name,
+			JSONName:   form.JSONName,
+			Label:      utils.ToCapitalCase(form.JSONName),
+			ChildLabel: utils.ToCapitalCase(utils.ToSnakeCase(form.Child)),
+			IDType:     "number",
+		}
+		if form.UUIDKey {
+			nested.IDType = "string"
+		}
+		for _, field := range form.Fields {
+			nested.Fields = append(nested.Fields, utils.ConvertToNuxtField(field))
+		}
+		forms = append(forms, nested)
+	}
+	return forms
+}
@@ -0,0 +1,33 @@
+package frontend
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// GenerateNotificationsBell writes the admin's notifications store, the NotificationBell
+// dropdown and the page listing all of the user's notifications, in the frontend w writes to
+func GenerateNotificationsBell(cmd *mamba.Command, w *utils.Writer, options utils.Notifications) error {
+	adminPath := "app"
+	files := []struct {
+		dir      string
+		name     string
+		template string
+	}{
+		{filepath.Join(adminPath, "stores"), "notifications.ts", "nuxt/notifications/store.ts.tmpl"},
+		{filepath.Join(adminPath, "components"), "NotificationBell.vue", "nuxt/notifications/bell.vue.tmpl"},
+		{filepath.Join(adminPath, "pages", "app"), "notifications.vue", "nuxt/notifications/page.vue.tmpl"},
+	}
+	for _, file := range files {
+		if err := w.GenerateNuxtFile(w.Path(file.dir), file.name, file.template, options); err != nil {
+			return fmt.Errorf("failed to generate %s: %w", file.name, err)
+		}
+		if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess("Generated " + filepath.Join(file.dir, file.name))
+		}
+	}
+	return nil
+}
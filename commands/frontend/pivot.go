@@ -0,0 +1,67 @@
+package frontend
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// Pivot is a manyToMany relation with pivot columns, whose links the detail page edits
+type Pivot struct {
+	Name          string // manyToMany field, e.g. Tags
+	Label         string // e.g. Tags
+	RelatedModel  string // e.g. Tag
+	RelatedLabel  string // e.g. Tag
+	RelatedKey    string // Column of the related id in a link, e.g. tag_id
+	RelatedObject string // Key of the related record in a link, e.g. tag
+	RelatedKebab  string // Route of the related module, e.g. tags
+	DisplayField  string // Field of the related model links are shown by
+	Columns       []utils.NuxtField
+}
+
+// newPivots returns the manyToMany fields with pivot columns
+func newPivots(adminPath string, fields []utils.Field) []Pivot {
+	var pivots []Pivot
+	for _, field := range utils.PivotRelations(fields) {
+		pivot := Pivot{
+			Name:          field.Name,
+			Label:         utils.ToCapitalCase(field.JSONName),
+			RelatedModel:  field.RelatedModel,
+			RelatedLabel:  utils.ToCapitalCase(utils.ToSnakeCase(field.RelatedModel)),
+			RelatedKey:    utils.ToSnakeCase(field.RelatedModel) + "_id",
+			RelatedObject: utils.ToSnakeCase(field.RelatedModel),
+			RelatedKebab:  utils.ToKebabCase(utils.ToPlural(field.RelatedModel)),
+			DisplayField:  getRelatedModelDisplayField(adminPath, field.RelatedModel),
+		}
+		for _, column := range field.Pivot {
+			pivot.Columns = append(pivot.Columns, utils.ConvertToNuxtField(column))
+		}
+		pivots = append(pivots, pivot)
+	}
+	return pivots
+}
+
+// generatePivots writes a component for each manyToMany relation with pivot columns that lists
+// the links of a record and attaches, updates and detaches them
+func generatePivots(cmd *mamba.Command, w *utils.Writer, moduleBasePath string, data *TemplateData) error {
+	for _, pivot := range data.Pivots {
+		name := data.Model + pivot.Name + ".vue"
+		if err := w.GenerateNuxtFile(
+			filepath.Join(moduleBasePath, "components"),
+			name,
+			"nuxt/pivot.vue.tmpl",
+			struct {
+				*TemplateData
+				Pivot Pivot
+			}{data, pivot},
+		); err != nil {
+			return err
+		}
+		if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess(fmt.Sprintf("Generated components/%s", name))
+		}
+	}
+	return nil
+}
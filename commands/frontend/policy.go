@@ -0,0 +1,116 @@
+package frontend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// policyEdit inserts a guard into a generated page above or after an anchor. Guards added
+// above take the indentation of the anchor's line.
+type policyEdit struct {
+	anchor string
+	insert string
+	before bool
+}
+
+// GeneratePolicyGuards writes the abilities composable and route middleware of a module's
+// policy and adds the ability checks to its existing list and detail pages, in the frontend w
+// writes to
+func GeneratePolicyGuards(cmd *mamba.Command, w *utils.Writer, naming *utils.NamingConvention) error {
+	adminPath := w.Path("app")
+	data := &TemplateData{NamingConvention: naming, Policy: true}
+	moduleBasePath := filepath.Join(adminPath, "modules", naming.PluralSnake)
+	if err := w.GenerateNuxtFile(filepath.Join(moduleBasePath, "composables"), "use"+naming.Model+"Abilities.ts", "nuxt/abilities.ts.tmpl", data); err != nil {
+		return err
+	}
+	if err := w.GenerateNuxtFile(filepath.Join(adminPath, "middleware"), naming.Slug+"-policy.ts", "nuxt/policy-middleware.ts.tmpl", data); err != nil {
+		return err
+	}
+
+	pagesDir := filepath.Join(adminPath, "pages", "app", naming.PluralKebab)
+	detailPage := filepath.Join(pagesDir, "[id].vue")
+	if _, err := os.Stat(detailPage); os.IsNotExist(err) {
+		detailPage = filepath.Join(pagesDir, "[id]", "index.vue")
+	}
+	pages := map[string][]policyEdit{
+		filepath.Join(pagesDir, "index.vue"): {
+			{fmt.Sprintf("permission=\"%s:create\"", naming.ModelSnake), "v-if=\"can('create')\"\n", true},
+			{"    click: () => handleEdit(row),\n", "    disabled: !can('update'),\n", false},
+			{"    click: () => handleDelete(row),\n", "    disabled: !can('delete'),\n", false},
+		},
+		detailPage: {
+			{fmt.Sprintf("permission=\"%s:update\"", naming.ModelSnake), "v-if=\"can('update')\"\n", true},
+			{fmt.Sprintf("permission=\"%s:delete\"", naming.ModelSnake), "v-if=\"can('delete')\"\n", true},
+		},
+	}
+	for path, edits := range pages {
+		if err := addPolicyGuards(cmd, w, path, naming, edits); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addPolicyGuards adds the composable, the route middleware and edits to a generated page.
+// Pages that already use the composable are left alone.
+func addPolicyGuards(cmd *mamba.Command, w *utils.Writer, path string, naming *utils.NamingConvention, edits []policyEdit) error {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	composable := "use" + naming.Model + "Abilities"
+	page := string(content)
+	if strings.Contains(page, composable) {
+		return nil
+	}
+
+	edits = append(edits,
+		policyEdit{anchor: fmt.Sprintf("import %sFormModal from '~/modules/%s/components/%sFormModal.vue'\n", naming.Model, naming.PluralSnake, naming.Model),
+			insert: fmt.Sprintf("import { %s } from '~/modules/%s/composables/%s'\n", composable, naming.PluralSnake, composable)},
+		policyEdit{anchor: "definePageMeta({\n  layout: 'default',\n", insert: fmt.Sprintf("  middleware: ['%s-policy'],\n", naming.Slug)},
+		policyEdit{anchor: "const toast = useToast()\n", insert: fmt.Sprintf("const { can } = %s()\n", composable)},
+	)
+	// Only list pages generated with --bulk have bulk actions
+	for _, edit := range []policyEdit{
+		{":items=\"bulkStatusItems\"", "v-if=\"can('update')\"\n", true},
+		{"@click=\"showBulkDeleteModal = true\"", "v-if=\"can('delete')\"\n", true},
+	} {
+		if strings.Contains(page, edit.anchor) {
+			edits = append(edits, edit)
+		}
+	}
+
+	edited := false
+	for _, edit := range edits {
+		i := strings.Index(page, edit.anchor)
+		if i == -1 {
+			edited = true
+			continue
+		}
+		if edit.before {
+			lineStart := strings.LastIndex(page[:i], "\n") + 1
+			page = page[:lineStart] + page[lineStart:i] + edit.insert + page[lineStart:]
+			continue
+		}
+		i += len(edit.anchor)
+		page = page[:i] + edit.insert + page[i:]
+	}
+	if edited {
+		cmd.PrintWarning(fmt.Sprintf("%s has changed since it was generated; check its buttons and menu items use can()", path))
+	}
+	return w.UpdateProjectFile(path, []byte(page))
+}
+
+// hasPolicyGuards reports whether bui g policy generated the module's abilities composable,
+// so regenerated pages keep their guards
+func hasPolicyGuards(adminPath string, naming *utils.NamingConvention) bool {
+	_, err := os.Stat(filepath.Join(adminPath, "modules", naming.PluralSnake, "composables", "use"+naming.Model+"Abilities.ts"))
+	return err == nil
+}
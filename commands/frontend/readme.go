@@ -0,0 +1,128 @@
+package frontend
+
+import (
+	"cmp"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// moduleFileRoles says what the files of a frontend module are for, by their directory in
+// app/modules/<module>, or their name for the files at its top
+var moduleFileRoles = map[string]string{
+	"module.config.ts": "Title, description, icon and sidebar entry of the module",
+	"README.md":        "This file, regenerated with the module",
+	"types":            "TypeScript types of the records and of the create and update requests",
+	"stores":           "The Pinia store calling the API; add actions here",
+	"components":       "Components of the pages, such as the form modal",
+	"utils":            "How values are shown in the table and the detail page",
+	"composables":      "Composables of the pages",
+	"api":              "Typed API client, from the backend's swagger.json",
+	"mocks":            "Mock records the stories and tests share",
+	"stories":          "Storybook stories",
+	"tests":            "Vitest tests",
+}
+
+// writeModuleReadme writes app/modules/<module>/README.md, documenting the pages and fields of
+// the module as generated and where to customize it. It lists the written files, so a dry run
+// skips it.
+func writeModuleReadme(cmd *mamba.Command, w *utils.Writer, adminPath, moduleBasePath string, data *TemplateData, command string) {
+	if utils.DryRun {
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", data.Label)
+	fmt.Fprintf(&b, "Admin pages of the `%s` model.\n\n", data.Model)
+
+	pagesDir := filepath.Join(adminPath, "pages", "app", data.PluralKebab)
+	pages, _ := filepath.Glob(filepath.Join(pagesDir, "*.vue"))
+	if len(pages) > 0 {
+		b.WriteString("## Pages\n\n")
+		b.WriteString("| Route | File |\n")
+		b.WriteString("|---|---|\n")
+		routes := map[string]string{}
+		for _, page := range pages {
+			route := "/app/" + data.PluralKebab
+			switch name := strings.TrimSuffix(filepath.Base(page), ".vue"); name {
+			case "index":
+			case "[id]":
+				route += "/:id"
+			default:
+				route += "/" + name
+			}
+			// Relative to the frontend, like the paths in the tables below
+			if rel, err := filepath.Rel(w.Dir, page); err == nil {
+				page = rel
+			}
+			routes[route] = filepath.ToSlash(page)
+		}
+		// By route, so the list page comes first
+		for _, route := range slices.Sorted(maps.Keys(routes)) {
+			fmt.Fprintf(&b, "| `%s` | `%s` |\n", route, routes[route])
+		}
+		b.WriteString("\n")
+	}
+
+	if len(data.Fields) > 0 {
+		b.WriteString("## Fields\n\n")
+		b.WriteString("| Field | TypeScript type | Form input |\n")
+		b.WriteString("|---|---|---|\n")
+		for _, field := range data.Fields {
+			input := "-"
+			if field.ShowInForm {
+				input = cmp.Or(field.FormType, "-")
+			}
+			// Union types such as 'draft' | 'live' would split the table's cells
+			tsType := strings.ReplaceAll(field.TypeScriptType, "|", `\|`)
+			fmt.Fprintf(&b, "| `%s` | `%s` | %s |\n", strings.TrimSuffix(field.JSONName, ",omitempty"), tsType, input)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Customizing\n\n")
+	fmt.Fprintf(&b, "Last generated by `%s`; the %s in the project root lists every command that changed the module.\n\n", command, utils.ChangelogFile)
+	b.WriteString("| File | What it's for |\n")
+	b.WriteString("|---|---|\n")
+	files := []string{"README.md"}
+	filepath.WalkDir(moduleBasePath, func(path string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			if rel, err := filepath.Rel(moduleBasePath, path); err == nil && rel != "README.md" {
+				files = append(files, filepath.ToSlash(rel))
+			}
+		}
+		return nil
+	})
+	for _, file := range files {
+		dir, _, _ := strings.Cut(file, "/")
+		fmt.Fprintf(&b, "| `%s` | %s |\n", file, cmp.Or(moduleFileRoles[dir], "-"))
+	}
+
+	b.WriteString("\nRe-running the command asks before replacing files that changed, and `--force` replaces them. ")
+	fmt.Fprintf(&b, "Add fields in place with `bui g %s <field:type...> --alter`.\n", data.ModelSnake)
+
+	path := filepath.Join(moduleBasePath, "README.md")
+	if err := w.WriteGeneratedFile(path, []byte(b.String())); err != nil {
+		cmd.PrintWarning(fmt.Sprintf("Could not write %s: %v", path, err))
+		return
+	}
+	if Verbose != nil && *Verbose {
+		cmd.PrintSuccess("Generated " + filepath.ToSlash(path))
+	}
+}
+
+// recordGeneration records the files w wrote in the project's CHANGELOG.md and manifest
+func recordGeneration(cmd *mamba.Command, w *utils.Writer, args []string, part string) {
+	if utils.DryRun {
+		return
+	}
+	if err := w.Record(cmd, args, part); err != nil {
+		cmd.PrintWarning(fmt.Sprintf("Could not record the generation: %v", err))
+	}
+}
@@ -0,0 +1,38 @@
+package frontend
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// generateRelationSelect writes the shared RelationSelect component the belongs_to fields of the
+// form modals, and the attach forms of pivot editors, search their related module through. An
+// existing component is kept.
+func generateRelationSelect(cmd *mamba.Command, w *utils.Writer, adminPath string, data *TemplateData) error {
+	belongsTo := len(data.Pivots) > 0
+	for _, field := range data.Fields {
+		if field.IsRelation && field.Relationship == "belongs_to" {
+			belongsTo = true
+			break
+		}
+	}
+	if !belongsTo {
+		return nil
+	}
+
+	componentsDir := filepath.Join(adminPath, "components")
+	if _, err := os.Stat(filepath.Join(componentsDir, "RelationSelect.vue")); !os.IsNotExist(err) {
+		return nil
+	}
+
+	if err := w.GenerateNuxtFile(componentsDir, "RelationSelect.vue", "nuxt/relation-select.vue.tmpl", data); err != nil {
+		return err
+	}
+	if Verbose != nil && *Verbose && !utils.DryRun {
+		cmd.PrintSuccess("Generated components/RelationSelect.vue")
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package frontend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// reportNavGroup is the sidebar group of the report pages
+const reportNavGroup = "Reports"
+
+// reportPageData fills in report.vue.tmpl
+type reportPageData struct {
+	*utils.Report
+	Policy bool // The module's pages are guarded by bui g policy
+}
+
+// GenerateReportPage writes the admin page of a report, a chart and a table of its rows between
+// two dates, and lists it in the Reports group of the sidebar, in the frontend w writes to
+func GenerateReportPage(cmd *mamba.Command, w *utils.Writer, report *utils.Report) error {
+	adminPath := w.Path("app")
+	moduleBasePath := filepath.Join(adminPath, "modules", report.PluralSnake)
+	if _, err := os.Stat(moduleBasePath); os.IsNotExist(err) {
+		return fmt.Errorf("no %s module in %s: generate it first with bui g fe %s", report.Model, moduleBasePath, report.ModelSnake)
+	}
+
+	data := &reportPageData{Report: report, Policy: hasPolicyGuards(adminPath, report.NamingConvention)}
+	pagesDir := filepath.Join(adminPath, "pages", "app", report.PluralKebab, "reports")
+	if err := w.GenerateNuxtFile(pagesDir, report.Slug+".vue", "nuxt/report.vue.tmpl", data); err != nil {
+		return err
+	}
+	if Verbose != nil && *Verbose && !utils.DryRun {
+		cmd.PrintSuccess(fmt.Sprintf("Generated pages/app/%s/reports/%s.vue", report.PluralKebab, report.Slug))
+	}
+
+	return registerNavigation(cmd, w, utils.NavigationItem{
+		Group:      reportNavGroup,
+		Label:      report.Title,
+		Icon:       "i-lucide-chart-column",
+		To:         report.PagePath(),
+		Permission: report.ModelSnake + ":list",
+	})
+}
@@ -0,0 +1,78 @@
+package frontend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// generateRules writes the shared useRules and customRules composables the form modals check
+// rules= modifiers with, once, and adds a rule that passes everything to customRules.ts for each
+// new custom rule
+func generateRules(cmd *mamba.Command, w *utils.Writer, adminPath string, data *TemplateData) error {
+	if len(data.RuleFields) == 0 {
+		return nil
+	}
+
+	composablesDir := filepath.Join(adminPath, "composables")
+	files := []struct{ name, template string }{
+		{"useRules.ts", "nuxt/rules.ts.tmpl"},
+		{"customRules.ts", "nuxt/custom-rules.ts.tmpl"},
+	}
+	for _, file := range files {
+		if _, err := os.Stat(filepath.Join(composablesDir, file.name)); !os.IsNotExist(err) {
+			continue
+		}
+		if err := w.GenerateNuxtFile(composablesDir, file.name, file.template, data); err != nil {
+			return err
+		}
+		if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess("Generated composables/" + file.name)
+		}
+	}
+
+	fields := make([]utils.Field, len(data.RuleFields))
+	for i, field := range data.RuleFields {
+		fields[i] = field.Field
+	}
+	customPath := filepath.Join(composablesDir, "customRules.ts")
+	for _, name := range utils.CustomRules(fields) {
+		added, err := addCustomRule(w, customPath, name, data.Model)
+		if err != nil {
+			return err
+		}
+		if added && !utils.DryRun {
+			cmd.PrintInfo(fmt.Sprintf("Added the custom rule %s to %s; it passes every value until you fill it in", name, customPath))
+		}
+	}
+	return nil
+}
+
+// addCustomRule adds a rule that passes everything to customRules, reporting whether the file
+// changed. A rule that is already there is kept.
+func addCustomRule(w *utils.Writer, path, name, model string) (bool, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) && utils.DryRun {
+		return false, nil // customRules.ts is only reported during a dry run
+	}
+	if err != nil {
+		return false, err
+	}
+	contentStr := string(content)
+	if strings.Contains(contentStr, "\n  "+name+": ") {
+		return false, nil
+	}
+
+	end := strings.LastIndex(contentStr, "\n}")
+	if end == -1 {
+		return false, fmt.Errorf("could not find the end of customRules in %s", path)
+	}
+	entry := fmt.Sprintf("  // %s is a rule of %s; return a message when value breaks it\n  %s: (_value, _param) => undefined,", name, model, name)
+	contentStr = contentStr[:end+1] + entry + contentStr[end:]
+
+	return true, w.UpdateProjectFile(path, []byte(contentStr))
+}
@@ -0,0 +1,33 @@
+package frontend
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// generateSecretValue writes the shared SecretValue component the detail pages reveal encrypted
+// fields with. An existing component is kept.
+func generateSecretValue(cmd *mamba.Command, w *utils.Writer, adminPath string, data *TemplateData) error {
+	needed := false
+	for _, field := range data.Fields {
+		needed = needed || (field.ShowInDetail && field.FormType == "encrypted")
+	}
+	if !needed {
+		return nil
+	}
+
+	componentsDir := filepath.Join(adminPath, "components")
+	if _, err := os.Stat(filepath.Join(componentsDir, "SecretValue.vue")); !os.IsNotExist(err) {
+		return nil
+	}
+	if err := w.GenerateNuxtFile(componentsDir, "SecretValue.vue", "nuxt/secret-value.vue.tmpl", data); err != nil {
+		return err
+	}
+	if Verbose != nil && *Verbose && !utils.DryRun {
+		cmd.PrintSuccess("Generated components/SecretValue.vue")
+	}
+	return nil
+}
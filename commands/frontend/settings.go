@@ -0,0 +1,43 @@
+package frontend
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// settingsNavGroup is the sidebar group of the settings page
+const settingsNavGroup = "System"
+
+// GenerateSettingsPage writes the admin's settings store and the settings page, a tab per
+// group, and lists the page in the sidebar, in the frontend w writes to
+func GenerateSettingsPage(cmd *mamba.Command, w *utils.Writer, settings []utils.Setting) error {
+	adminPath := "app"
+	data := utils.SettingsModule{Settings: settings}
+	if err := generateUploads(cmd, w, w.Path(adminPath), &TemplateData{Uploads: data.Uploads()}); err != nil {
+		return err
+	}
+
+	files := []struct{ dir, name, template string }{
+		{filepath.Join(adminPath, "stores"), "settings.ts", "nuxt/settings/store.ts.tmpl"},
+		{filepath.Join(adminPath, "pages", "app"), "settings.vue", "nuxt/settings/page.vue.tmpl"},
+	}
+	for _, file := range files {
+		if err := w.GenerateNuxtFile(w.Path(file.dir), file.name, file.template, data); err != nil {
+			return fmt.Errorf("failed to generate %s: %w", file.name, err)
+		}
+		if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess("Generated " + filepath.Join(file.dir, file.name))
+		}
+	}
+
+	return registerNavigation(cmd, w, utils.NavigationItem{
+		Group:      settingsNavGroup,
+		Label:      "Settings",
+		Icon:       "i-lucide-settings",
+		To:         "/app/settings",
+		Permission: "settings:update",
+	})
+}
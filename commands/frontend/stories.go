@@ -0,0 +1,34 @@
+package frontend
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// stories writes Storybook stories for the module's list table and form modal
+var stories bool
+
+func init() {
+	GenerateFrontendCmd.Flags().BoolVar(&stories, "stories", false, "Generate Storybook stories for the table and form modal with mock data")
+}
+
+// generateStories writes the stories of the table and form modal to the module's stories
+// directory
+func generateStories(cmd *mamba.Command, w *utils.Writer, moduleBasePath string, data *TemplateData) error {
+	files := []struct{ name, template string }{
+		{data.Model + "Table.stories.ts", "nuxt/table.stories.ts.tmpl"},
+		{data.Model + "FormModal.stories.ts", "nuxt/form-modal.stories.ts.tmpl"},
+	}
+	for _, file := range files {
+		if err := w.GenerateNuxtFile(filepath.Join(moduleBasePath, "stories"), file.name, file.template, data); err != nil {
+			return err
+		}
+		if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess(fmt.Sprintf("Generated stories/%s", file.name))
+		}
+	}
+	return nil
+}
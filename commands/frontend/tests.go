@@ -0,0 +1,47 @@
+package frontend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// tests writes Vitest tests for the module's store, form modal and list page
+var tests bool
+
+func init() {
+	GenerateFrontendCmd.Flags().BoolVar(&tests, "tests", false, "Generate Vitest tests for the store, form modal and list page")
+}
+
+// generateTests writes the module's tests to its tests directory, and the Vitest config the
+// first time
+func generateTests(cmd *mamba.Command, w *utils.Writer, moduleBasePath string, data *TemplateData) error {
+	files := []struct{ name, template string }{
+		{data.PluralSnake + ".store.test.ts", "nuxt/store.test.ts.tmpl"},
+		{data.Model + "FormModal.test.ts", "nuxt/form-modal.test.ts.tmpl"},
+		{data.Model + "List.test.ts", "nuxt/list.test.ts.tmpl"},
+	}
+	for _, file := range files {
+		if err := w.GenerateNuxtFile(filepath.Join(moduleBasePath, "tests"), file.name, file.template, data); err != nil {
+			return err
+		}
+		if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess(fmt.Sprintf("Generated tests/%s", file.name))
+		}
+	}
+
+	// The config lives in the frontend root, the directory above app/
+	if _, err := os.Stat(w.Path("vitest.config.ts")); !os.IsNotExist(err) {
+		return nil
+	}
+	if err := w.GenerateNuxtFile(w.Dir, "vitest.config.ts", "nuxt/vitest.config.ts.tmpl", data); err != nil {
+		return err
+	}
+	if !utils.DryRun {
+		cmd.PrintInfo("Generated vitest.config.ts; install the test tools with: npm i -D vitest @vue/test-utils @nuxt/test-utils happy-dom")
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package frontend
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// generateUploads writes the shared useUpload composable and FileUpload component the media and
+// attachment fields of the form modals upload through. Existing files are kept.
+func generateUploads(cmd *mamba.Command, w *utils.Writer, adminPath string, data *TemplateData) error {
+	if !data.Uploads {
+		return nil
+	}
+
+	files := []struct{ dir, name, template string }{
+		{"composables", "useUpload.ts", "nuxt/upload.ts.tmpl"},
+		{"components", "FileUpload.vue", "nuxt/file-upload.vue.tmpl"},
+	}
+	for _, file := range files {
+		dir := filepath.Join(adminPath, file.dir)
+		if _, err := os.Stat(filepath.Join(dir, file.name)); !os.IsNotExist(err) {
+			continue
+		}
+		if err := w.GenerateNuxtFile(dir, file.name, file.template, data); err != nil {
+			return err
+		}
+		if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess("Generated " + file.dir + "/" + file.name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package frontend
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// views are the list pages generated next to the table, each optionally on a field
+// (--view kanban:stage,calendar,gallery)
+var views string
+
+func init() {
+	GenerateFrontendCmd.Flags().StringVar(&views, "view", "", "Also generate comma-separated kanban, calendar or gallery list pages, optionally on a field (e.g. kanban:stage)")
+}
+
+// View is a list page generated next to the table and the field it's laid out by
+type View struct {
+	Name  string // kanban, calendar or gallery, also the page's file and route
+	Label string
+	Icon  string
+
+	// The select or state field a kanban groups by, the date a calendar places records on, or
+	// the media or image a gallery shows
+	Field utils.NuxtField
+
+	// Int sort_order field a kanban keeps the order of its cards in; nil leaves the cards in
+	// their list order
+	Order *utils.NuxtField
+
+	// Fields shown on a kanban or gallery card under the display field
+	Details []utils.NuxtField
+}
+
+// viewKinds are the supported views, with the label and icon of their switcher button
+var viewKinds = map[string][2]string{
+	"kanban":   {"Board", "i-lucide-square-kanban"},
+	"calendar": {"Calendar", "i-lucide-calendar"},
+	"gallery":  {"Gallery", "i-lucide-layout-grid"},
+}
+
+// parseViews reads the --view list. A view without a field takes the first field that suits it.
+func parseViews(spec string, fields []utils.NuxtField, displayField string) ([]View, error) {
+	if spec == "" {
+		return nil, nil
+	}
+
+	var result []View
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(spec, ",") {
+		name, fieldName, _ := strings.Cut(strings.TrimSpace(entry), ":")
+		kind, ok := viewKinds[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown view %q: use kanban, calendar or gallery", name)
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		field, err := viewField(name, fieldName, fields)
+		if err != nil {
+			return nil, err
+		}
+		view := View{Name: name, Label: kind[0], Icon: kind[1], Field: *field}
+		if name != "calendar" {
+			view.Details = cardDetails(fields, displayField, field.JSONName)
+		}
+		if name == "kanban" {
+			for i := range fields {
+				if fields[i].JSONName == "sort_order" && (fields[i].Type == "int" || fields[i].Type == "uint" || fields[i].Type == "int64") {
+					view.Order = &fields[i]
+				}
+			}
+		}
+		result = append(result, view)
+	}
+	return result, nil
+}
+
+// viewField returns the field named by --view, or the first one the view can use
+func viewField(view, name string, fields []utils.NuxtField) (*utils.NuxtField, error) {
+	suits := map[string]func(utils.NuxtField) bool{
+		"kanban":   groupable,
+		"calendar": func(f utils.NuxtField) bool { return f.FormType == "date" || f.FormType == "datetime" },
+		"gallery":  pictured,
+	}[view]
+	needs := map[string]string{
+		"kanban":   "a select, enum or state field",
+		"calendar": "a date or datetime field",
+		"gallery":  "a media or image field",
+	}[view]
+
+	if name != "" {
+		for i := range fields {
+			if fields[i].JSONName != utils.ToSnakeCase(name) {
+				continue
+			}
+			if !suits(fields[i]) {
+				return nil, fmt.Errorf("%s view on %s: the field must be %s", view, name, needs)
+			}
+			return &fields[i], nil
+		}
+		return nil, fmt.Errorf("%s view on %s: no such field", view, name)
+	}
+
+	// Kanbans prefer a state field, whose columns follow its transitions
+	if view == "kanban" {
+		for i := range fields {
+			if fields[i].IsState && len(fields[i].Options) > 0 {
+				return &fields[i], nil
+			}
+		}
+	}
+	for i := range fields {
+		if suits(fields[i]) {
+			return &fields[i], nil
+		}
+	}
+	return nil, fmt.Errorf("the %s view needs %s", view, needs)
+}
+
+// groupable reports whether a kanban can have a column per option of field
+func groupable(field utils.NuxtField) bool {
+	return field.IsSelect && field.SelectType != "checkbox" && len(field.Options) > 0
+}
+
+// pictured reports whether a gallery card can show field as its picture; media[] fields
+// aren't in list rows
+func pictured(field utils.NuxtField) bool {
+	if field.IsMedia {
+		return !field.IsMediaList && (field.MediaType == "" || field.MediaType == "image")
+	}
+	return field.IsAttachment && field.IsImage
+}
+
+// cardDetails returns up to two plain fields a card shows under the display field; a
+// sort_order only orders the cards
+func cardDetails(fields []utils.NuxtField, displayField, skip string) []utils.NuxtField {
+	var details []utils.NuxtField
+	for _, field := range fields {
+		if len(details) == 2 {
+			break
+		}
+		if !field.ShowInTable || field.IsRelation || field.IsTranslation || field.JSONName == displayField || field.JSONName == skip || field.JSONName == "sort_order" {
+			continue
+		}
+		switch field.FormType {
+		case "text", "email", "url", "number", "select", "radio", "date", "datetime", "checkbox":
+			details = append(details, field)
+		}
+	}
+	return details
+}
+
+// generateViews writes the view pages next to the module's index page and the switcher that
+// links them
+func generateViews(cmd *mamba.Command, w *utils.Writer, adminPath, moduleBasePath string, data *TemplateData) error {
+	if len(data.Views) == 0 {
+		return nil
+	}
+
+	if err := w.GenerateNuxtFile(filepath.Join(moduleBasePath, "components"), data.Model+"ViewSwitcher.vue", "nuxt/view-switcher.vue.tmpl", data); err != nil {
+		return err
+	}
+	if Verbose != nil && *Verbose && !utils.DryRun {
+		cmd.PrintSuccess(fmt.Sprintf("Generated components/%sViewSwitcher.vue", data.Model))
+	}
+
+	pagesDir := filepath.Join(adminPath, "pages", "app", data.PluralKebab)
+	for _, view := range data.Views {
+		viewData := *data
+		viewData.View = view
+		if err := w.GenerateNuxtFile(pagesDir, view.Name+".vue", "nuxt/"+view.Name+".vue.tmpl", &viewData); err != nil {
+			return err
+		}
+		if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess(fmt.Sprintf("Generated pages/app/%s/%s.vue", data.PluralKebab, view.Name))
+		}
+	}
+	return nil
+}
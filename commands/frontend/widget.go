@@ -0,0 +1,120 @@
+package frontend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// dashboardPages are where the admin's dashboard page may be, relative to the frontend
+var dashboardPages = []string{
+	filepath.Join("app", "pages", "app", "index.vue"),
+	filepath.Join("app", "pages", "app", "dashboard.vue"),
+	filepath.Join("app", "pages", "dashboard.vue"),
+}
+
+// moduleConfigPattern matches the display name and icon lines of a module.config.ts
+var moduleConfigPattern = regexp.MustCompile(`(?m)^  (displayName|icon): '((?:[^'\\]|\\.)*)',$`)
+
+// widgetData fills in the widget templates
+type widgetData struct {
+	*utils.NamingConvention
+	Icon         string
+	DisplayField string
+}
+
+// GenerateWidget writes a dashboard widget of a module, lists it in the dashboard configuration
+// and puts the shared DashboardWidgets component on the dashboard page, in the frontend w writes to
+func GenerateWidget(cmd *mamba.Command, w *utils.Writer, naming *utils.NamingConvention, widgetType string) error {
+	adminPath := w.Path("app")
+	moduleBasePath := filepath.Join(adminPath, "modules", naming.PluralSnake)
+	if _, err := os.Stat(moduleBasePath); os.IsNotExist(err) {
+		return fmt.Errorf("no %s module in %s: generate it first with bui g fe %s", naming.Model, moduleBasePath, naming.ModelSnake)
+	}
+
+	// The widget takes the module's label and icon from its config
+	data := &widgetData{
+		NamingConvention: naming,
+		Icon:             utils.DefaultNavIcon,
+		DisplayField:     getRelatedModelDisplayField(adminPath, naming.Model),
+	}
+	if config, err := os.ReadFile(filepath.Join(moduleBasePath, "module.config.ts")); err == nil {
+		for _, match := range moduleConfigPattern.FindAllStringSubmatch(string(config), -1) {
+			value := strings.ReplaceAll(match[2], `\'`, "'")
+			if match[1] == "displayName" {
+				naming.Label = value
+			} else {
+				data.Icon = value
+			}
+		}
+	}
+
+	widget := utils.NewDashboardWidget(naming, widgetType)
+	filename := filepath.Base(widget.Component)
+	if err := w.GenerateNuxtFile(filepath.Join(moduleBasePath, "components"), filename, "nuxt/"+widgetType+"-widget.vue.tmpl", data); err != nil {
+		return err
+	}
+	if Verbose != nil && *Verbose && !utils.DryRun {
+		cmd.PrintSuccess("Generated components/" + filename)
+	}
+
+	// The shared component that shows the listed widgets is written once
+	componentsDir := filepath.Join(adminPath, "components")
+	if _, err := os.Stat(filepath.Join(componentsDir, "DashboardWidgets.vue")); os.IsNotExist(err) {
+		if err := w.GenerateNuxtFile(componentsDir, "DashboardWidgets.vue", "nuxt/dashboard-widgets.vue.tmpl", data); err != nil {
+			return err
+		}
+		if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess("Generated components/DashboardWidgets.vue")
+		}
+	}
+
+	if _, err := w.AddDashboardWidget(widget); err != nil {
+		return fmt.Errorf("could not add the widget to %s: %w", utils.DashboardFile, err)
+	}
+	return addDashboardWidgets(cmd, w)
+}
+
+// addDashboardWidgets puts <DashboardWidgets /> at the top of the body of the dashboard page w
+// writes to, unless the page already shows it
+func addDashboardWidgets(cmd *mamba.Command, w *utils.Writer) error {
+	for _, page := range dashboardPages {
+		content, err := os.ReadFile(w.Path(page))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if strings.Contains(string(content), "<DashboardWidgets") {
+			return nil
+		}
+
+		anchor := "<template #body>"
+		i := strings.Index(string(content), anchor)
+		if i == -1 {
+			cmd.PrintWarning("Could not find the body of the dashboard page " + page)
+			cmd.PrintInfo("Manually add <DashboardWidgets /> where the widgets should be shown")
+			return nil
+		}
+		lineStart := strings.LastIndex(string(content[:i]), "\n") + 1
+		indent := string(content[lineStart:i])
+		end := i + len(anchor)
+		updated := string(content[:end]) + "\n" + indent + "  <DashboardWidgets />" + string(content[end:])
+		if err := w.UpdateProjectFile(w.Path(page), []byte(updated)); err != nil {
+			return err
+		}
+		if Verbose != nil && *Verbose && !utils.DryRun {
+			cmd.PrintSuccess("Added DashboardWidgets to " + page)
+		}
+		return nil
+	}
+
+	cmd.PrintWarning("No dashboard page found at " + strings.Join(dashboardPages, ", "))
+	cmd.PrintInfo("Manually add <DashboardWidgets /> to the page that should show the widgets")
+	return nil
+}
@@ -1,13 +1,19 @@
 package commands
 
 import (
+	"fmt"
 	"os"
+	"strings"
+	"sync"
 
 	"github.com/base-al/bui/commands/backend"
 	"github.com/base-al/bui/commands/frontend"
+	"github.com/base-al/bui/utils"
 	"github.com/base-go/mamba"
 )
 
+var schemaFile string
+
 var generateCmd = &mamba.Command{
 	Use:     "generate [module] [field:type...]",
 	Aliases: []string{"g"},
@@ -16,53 +22,242 @@ var generateCmd = &mamba.Command{
 
 Examples:
   bui g product name:string price:float          # Generate both backend and frontend
+  bui g product                                  # Build the field list interactively
+  bui g product title:string:required views:int:default=0
+  bui g customer email:string:rules=email,max=255 age:int:rules=gte=18  # Checked by the API and the form
+  bui g post title:string status:enum:draft,published,archived
   bui g backend product name:string              # Backend only
-  bui g frontend product name:string             # Frontend only`,
+  bui g model audit_log action:string            # GORM model only
+  bui g product name:string --no-tests           # Skip generated backend tests
+  bui g product name:string --dry-run --diff     # Preview changes without writing
+  bui g product name:string --force              # Overwrite existing module files
+  bui g frontend product name:string             # Frontend only
+  bui g comment post:belongsTo --nested          # Routes under /posts/:post_id/comments
+  bui g comment body:text post:belongsTo --with-inverse  # Also add comments to Post
+  bui g product name:string tenant_id:belongsTo:Tenant --unique name,tenant_id --table product_catalog
+  bui g product name:string --pk uuid            # UUID ids instead of auto-increment
+  bui g product sku:string weight:float --alter  # Add fields to an existing module
+  bui g product name:string --graphql            # Also gqlgen schema, resolvers and store queries
+  bui g order total:float --realtime             # Live admin table over websockets
+  bui g invoice total:float --tenant             # Scope rows to the request's organization
+  bui g contract title:string --audited          # Change history and an Activity tab
+  bui g invoice total:float --lock-version       # Refuse stale updates with 409
+  bui g product name:string --import-export      # CSV/XLSX export, import with a preview
+  bui g invoice number:string total:money --pdf  # Download each invoice as a PDF
+  bui g product name:string --rate-limit 100/min --cache 60s  # Per-client limits, cached reads
+  bui g product name:string cost:money --fields-read list=name --fields-write create=name,cost  # Choose each payload's fields
+  bui g ticket status:select:open,closed --bulk  # Select rows to delete or change status together
+  bui g article title:string body:text --searchable title,body  # Ranked full-text search
+  bui g order number:string items:hasMany:OrderItem --nested-form items  # Edit order items in the order form
+  bui g task cleanup_expired_tokens --cron "0 3 * * *"  # Scheduled task in app/scheduler
+  bui g webhook order.created                    # Signed outgoing webhooks with a delivery log
+  bui g auth --oauth google,github --totp        # OAuth, magic-link or TOTP sign-in
+  bui g policy product --roles admin,editor,viewer  # Role permissions in the API and admin
+  bui g --from schema.yaml                       # Generate every model in a schema file
+  bui g from-openapi openapi.yaml                # Generate modules from an OpenAPI spec
+  bui g graphql product name:string              # Run the bui-gen-graphql plugin (see bui plugins)
+
+Schema file (YAML or JSON):
+  models:
+    - name: Category
+      fields: [name:string]
+    - name: Product
+      table: product_catalog                   # Optional, like --table
+      unique: ["name,category_id"]            # Optional, like --unique
+      label: Catalog Products                  # Optional, like --label, --description and --icon
+      icon: package
+      fields:
+        - name:string
+        - price:float
+        - name: category_id
+          type: belongs_to
+          model: Category`,
 	Run: generateBothModules,
 }
 
 // generateBothModules generates both backend and frontend modules
 func generateBothModules(cmd *mamba.Command, args []string) {
+	if schemaFile != "" {
+		generateFromSchema(cmd, schemaFile)
+		return
+	}
+
 	if len(args) < 1 {
 		cmd.PrintError("Module name required")
 		cmd.PrintInfo("Usage: bui g [module] [field:type...]")
 		os.Exit(1)
 	}
 
-	// Save the original working directory
-	originalDir, err := os.Getwd()
+	// bui g <generator> runs a bui-gen-<generator> plugin when one is installed
+	if path := findPlugin(generatorPluginPrefix, args[0]); path != "" {
+		runGeneratorPlugin(cmd, path, args)
+		return
+	}
+
+	// No fields given: build them interactively when attached to a terminal
+	if len(args) == 1 && isInteractiveInput() {
+		fields, err := promptFields(cmd, args[0])
+		if err != nil {
+			cmd.PrintError(fmt.Sprintf("Failed to read fields: %v", err))
+			os.Exit(1)
+		}
+		args = append(args, fields...)
+	}
+
+	if !generateModule(cmd, args) {
+		os.Exit(1)
+	}
+}
+
+// generateFromSchema generates every model in a schema file, dependencies first
+func generateFromSchema(cmd *mamba.Command, path string) {
+	schema, err := utils.LoadSchema(path)
 	if err != nil {
-		cmd.PrintError("Failed to get current directory")
+		cmd.PrintError(err.Error())
 		os.Exit(1)
 	}
 
+	checkSchemaRelations(cmd, schema)
+
+	models := schema.OrderedModels()
+	cmd.PrintInfo(fmt.Sprintf("Generating %d models from %s", len(models), path))
+
+	var skipped []string
+	for _, model := range models {
+		cmd.PrintHeader(utils.ToPascalCase(model.Name))
+		utils.TableOverride = model.Table
+		utils.UniqueIndexes = model.Unique
+		utils.ModuleLabel, utils.ModuleDescription, utils.NavIcon = model.Label, model.Description, model.Icon
+		if !generateModule(cmd, model.Args()) {
+			skipped = append(skipped, model.Name)
+		}
+	}
+
+	if len(skipped) > 0 {
+		cmd.PrintError(fmt.Sprintf("Generated %d of %d models from %s; skipped %s", len(models)-len(skipped), len(models), path, strings.Join(skipped, ", ")))
+		os.Exit(1)
+	}
+	cmd.PrintSuccess(fmt.Sprintf("Generated %d models from %s", len(models), path))
+}
+
+// checkSchemaRelations stops a schema run before anything is written when its relations refer to
+// models that are neither in the schema nor in app/models, and lets the modules refer to each
+// other in any order
+func checkSchemaRelations(cmd *mamba.Command, schema *utils.Schema) {
+	backendDir := detectBackendDir()
+	if backendDir == "" {
+		backendDir = "."
+	}
+	missing := schema.MissingRelations(utils.FindModels(backendDir))
+	if len(missing) > 0 && !backend.AllowMissing {
+		cmd.PrintError("Relations refer to models that are neither in the schema nor in app/models:")
+		for _, relation := range missing {
+			cmd.PrintBullet(relation.String())
+		}
+		cmd.PrintInfo("Add the models to the schema, or pass --allow-missing to generate the relations anyway")
+		os.Exit(1)
+	}
+
+	backend.PendingModels = nil
+	for _, model := range schema.Models {
+		backend.PendingModels = append(backend.PendingModels, utils.ToPascalCase(model.Name))
+	}
+}
+
+// generateModule runs the backend and frontend generators for one module. Both write through
+// writers of one run, in their own directories, so they generate side by side; then the backend
+// adds the inverse relations to the related modules, and the frontend adds them to theirs. It
+// returns false when the checks stopped the module before anything was written.
+func generateModule(cmd *mamba.Command, args []string) bool {
 	// Set verbose pointers for subcommands
 	backend.Verbose = &Verbose
 	frontend.Verbose = &Verbose
 
-	// Generate backend (subcommand handles its own logging)
-	backend.GenerateBackendCmd.Run(cmd, args)
+	// The changelog and manifest are in the project root, found from the directory bui was run in
+	startDir, _ := os.Getwd()
+	run := utils.NewRun(utils.ProjectRoot(startDir), args[0])
+	backendWriter := run.Writer(backend.DetectBackendDir())
+	frontendWriter := run.Writer(frontend.DetectFrontendDir())
 
-	// Return to original directory before generating frontend
-	if err := os.Chdir(originalDir); err != nil {
-		cmd.PrintError("Failed to return to original directory")
-		os.Exit(1)
+	// The backend checks every flag and field the frontend does, so a bad value is reported once
+	if !backend.CheckModule(cmd, backendWriter, args) {
+		return false
 	}
 
-	// Generate frontend (subcommand handles its own logging)
-	frontend.GenerateFrontendCmd.Run(cmd, args)
+	// Generate backend and frontend together (subcommands handle their own logging)
+	var backendErr, frontendErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		backendErr = backend.GenerateModule(cmd, backendWriter, args)
+	}()
+	go func() {
+		defer wg.Done()
+		frontendErr = frontend.GenerateModule(cmd, frontendWriter, args)
+	}()
+	wg.Wait()
 
-	// Return to original directory after both generations
-	if err := os.Chdir(originalDir); err != nil {
-		cmd.PrintError("Failed to return to original directory")
-		os.Exit(1)
+	if backendErr != nil {
+		cmd.PrintError(backendErr.Error())
+	} else {
+		backend.FinishModule(cmd, backendWriter, args)
 	}
+	if frontendErr != nil {
+		cmd.PrintError(frontendErr.Error())
+	} else {
+		frontend.FinishModule(cmd, frontendWriter, args)
+	}
+	return true
 }
 
 func init() {
 	rootCmd.AddCommand(generateCmd)
 
+	generateCmd.Flags().StringVar(&schemaFile, "from", "", "Generate all models defined in a YAML or JSON schema file")
+	generateCmd.Flags().BoolVar(&backend.NoTests, "no-tests", false, "Skip generating backend module tests")
+	generateCmd.Flags().BoolVar(&backend.AllowMissing, "allow-missing", false, "Generate relations to models that don't exist yet")
+	generateCmd.Flags().BoolVar(&utils.WithInverse, "with-inverse", false, "Add the hasMany side of belongsTo fields to the related modules")
+	generateCmd.Flags().BoolVar(&utils.DryRun, "dry-run", false, "Show the files that would be written without touching disk")
+	generateCmd.Flags().BoolVar(&utils.ShowDiff, "diff", false, "Print a diff for each file during a dry run")
+	generateCmd.Flags().BoolVarP(&utils.Force, "force", "f", false, "Overwrite existing files without asking")
+	generateCmd.Flags().BoolVar(&utils.Nested, "nested", false, "Scope routes and admin pages under the first belongsTo parent")
+	generateCmd.Flags().StringArrayVar(&utils.UniqueIndexes, "unique", nil, "Add a composite unique index over comma-separated columns (repeatable)")
+	generateCmd.Flags().StringVar(&utils.TableOverride, "table", "", "Use a custom table name for the backend model")
+	generateCmd.Flags().StringVar(&utils.PrimaryKey, "pk", "uint", "Primary key type: uint or uuid")
+	generateCmd.Flags().StringVar(&utils.DatabaseFlag, "db", "", "Database to generate for: postgres, mysql or sqlite (default: database in .bui.yaml, else postgres)")
+	generateCmd.Flags().BoolVar(&utils.NoSoftDelete, "no-soft-delete", false, "Omit the DeletedAt soft-delete column")
+	generateCmd.Flags().BoolVar(&utils.Audit, "audit", false, "Add created_by/updated_by columns set from the authenticated user")
+	generateCmd.Flags().BoolVar(&utils.Alter, "alter", false, "Add the fields to an existing module and write an ALTER migration")
+	generateCmd.Flags().BoolVar(&utils.GraphQL, "graphql", false, "Also generate gqlgen schema, resolvers and GraphQL store actions")
+	generateCmd.Flags().BoolVar(&utils.Realtime, "realtime", false, "Publish changes over websockets and keep the admin list live")
+	generateCmd.Flags().BoolVar(&utils.Tenant, "tenant", false, "Scope the module to the organization of the request and reset the admin store on a switch")
+	generateCmd.Flags().BoolVar(&utils.Audited, "audited", false, "Record change history in audit_logs and add an Activity tab to the detail page")
+	generateCmd.Flags().BoolVar(&utils.Versioned, "versioned", false, "Keep a revision on every update with a restore endpoint, and add a Revisions tab to the detail page")
+	generateCmd.Flags().BoolVar(&utils.LockVersion, "lock-version", false, "Add a lock_version column so stale updates answer 409, and prompt the form to reload them")
+	generateCmd.Flags().BoolVar(&utils.ImportExport, "import-export", false, "Add CSV/XLSX export and import endpoints and Import/Export buttons to the list page")
+	generateCmd.Flags().BoolVar(&utils.PDF, "pdf", false, "Add a PDF download of each record and a Download PDF button to the detail page")
+	generateCmd.Flags().StringVar(&utils.RateLimit, "rate-limit", "", "Limit the requests each client makes to the module's API routes, e.g. 100/min")
+	generateCmd.Flags().StringVar(&utils.Cache, "cache", "", "Cache the responses of the module's API reads for a duration, e.g. 60s; changes invalidate them")
+	generateCmd.Flags().StringArrayVar(&utils.FieldsRead, "fields-read", nil, "Only return these comma-separated columns, in list= or detail= responses or both (repeatable)")
+	generateCmd.Flags().StringArrayVar(&utils.FieldsWrite, "fields-write", nil, "Only accept these comma-separated columns, in create= or update= requests or both (repeatable)")
+	generateCmd.Flags().BoolVar(&utils.Bulk, "bulk", false, "Add bulk delete and status update endpoints and row selection to the list page")
+	generateCmd.Flags().StringVar(&utils.Searchable, "searchable", "", "Add full-text search over comma-separated text columns to the API and the list page")
+	generateCmd.Flags().BoolVar(&utils.PostGIS, "postgis", false, "Add PostGIS geography columns for point fields, which nearby searches use on PostgreSQL")
+	generateCmd.Flags().StringVar(&utils.NestedForms, "nested-form", "", "Edit the rows of comma-separated hasMany fields inside the form and save them with the parent")
+	generateCmd.Flags().StringVar(&utils.NavIcon, "icon", "", "Icon of the module's sidebar entry and config, e.g. i-lucide-shopping-cart or shopping-cart (default "+utils.DefaultNavIcon+")")
+	generateCmd.Flags().StringVar(&utils.ModuleLabel, "label", "", "Display name of the module in titles, breadcrumbs, the sidebar and swagger tags (e.g. \"Sales Orders\")")
+	generateCmd.Flags().StringVar(&utils.ModuleDescription, "description", "", "Description of the module in module.config.ts and under the list page title")
+	generateCmd.Flags().StringVar(&utils.NavGroup, "nav-group", "", "Sidebar group the module is listed in (default the namespace, else "+utils.DefaultNavGroup+")")
+
 	// Add backend and frontend subcommands
 	generateCmd.AddCommand(backend.GenerateBackendCmd)
 	generateCmd.AddCommand(frontend.GenerateFrontendCmd)
+	generateCmd.AddCommand(backend.GenerateModelCmd)
+	generateCmd.AddCommand(backend.GenerateTaskCmd)
+
+	// Run the pre_generate and post_generate hooks from .bui.yaml
+	for _, c := range []*mamba.Command{generateCmd, backend.GenerateBackendCmd, frontend.GenerateFrontendCmd, backend.GenerateModelCmd, backend.GenerateTaskCmd} {
+		c.Run = withHooks("generate", c.Run)
+	}
 }
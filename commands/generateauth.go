@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/base-al/bui/commands/backend"
+	"github.com/base-al/bui/commands/frontend"
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// authOAuth lists the OAuth providers for bui g auth, e.g. google,github
+var authOAuth string
+
+// authMagicLink adds emailed one-time sign-in links
+var authMagicLink bool
+
+// authTOTP adds authenticator app two-factor authentication
+var authTOTP bool
+
+var generateAuthCmd = &mamba.Command{
+	Use:   "auth",
+	Short: "Add OAuth, magic-link or TOTP sign-in to the template's auth",
+	Long: `Add sign-in options next to the template's email/password login.
+
+The backend gets an app/auth_extras module and the admin gets the matching store, pages and
+an <AuthExtraButtons /> component for the login page:
+  --oauth google,github   Sign in with Google or GitHub (pages/auth/callback.vue)
+  --magic-link            Emailed one-time sign-in links (pages/auth/magic-link.vue)
+  --totp                  Authenticator app 2FA (pages/auth/two-factor.vue, pages/app/account/security.vue)
+
+These sign in existing users by their verified email; they never create accounts. Settings
+(provider credentials, SMTP, TOTP issuer) are added to .env and .env.sample as placeholders.
+Running it again keeps the features generated before.
+
+Examples:
+  bui g auth --oauth google,github
+  bui g auth --magic-link --totp`,
+	Args: mamba.NoArgs,
+	Run:  generateAuth,
+}
+
+func init() {
+	generateAuthCmd.Flags().StringVar(&authOAuth, "oauth", "", "OAuth providers to add: google, github")
+	generateAuthCmd.Flags().BoolVar(&authMagicLink, "magic-link", false, "Add magic-link sign-in")
+	generateAuthCmd.Flags().BoolVar(&authTOTP, "totp", false, "Add TOTP two-factor authentication")
+	generateAuthCmd.Flags().BoolVar(&utils.DryRun, "dry-run", false, "Show the files that would be written without touching disk")
+	generateAuthCmd.Flags().BoolVar(&utils.ShowDiff, "diff", false, "Print a diff for each file during a dry run")
+	generateAuthCmd.Flags().BoolVarP(&utils.Force, "force", "f", false, "Overwrite existing files without asking")
+
+	generateCmd.AddCommand(generateAuthCmd)
+	generateAuthCmd.Run = withHooks("generate", generateAuthCmd.Run)
+}
+
+// generateAuth generates the backend module and admin pages for the selected sign-ins
+func generateAuth(cmd *mamba.Command, args []string) {
+	providers, err := utils.ParseOAuthProviders(authOAuth)
+	if err != nil {
+		cmd.PrintError(err.Error())
+		os.Exit(1)
+	}
+	features := utils.AuthFeatures{Providers: providers, MagicLink: authMagicLink, TOTP: authTOTP}
+	if features.Empty() {
+		cmd.PrintError("Choose at least one of --oauth, --magic-link or --totp")
+		os.Exit(1)
+	}
+
+	startDir, _ := os.Getwd()
+	run := utils.NewRun(utils.ProjectRoot(startDir), "auth_extras")
+
+	cmd.PrintHeader("Backend")
+	features, err = backend.GenerateAuthExtras(cmd, run.Writer(backend.DetectBackendDir()), features)
+	if err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to generate auth extras: %v", err))
+		os.Exit(1)
+	}
+
+	cmd.PrintHeader("Frontend")
+	if frontendDir := frontend.DetectFrontendDir(); frontendDir == "" {
+		cmd.PrintWarning("No frontend directory found, skipping the admin pages")
+	} else if err := frontend.GenerateAuthPages(cmd, run.Writer(frontendDir), features); err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to generate auth pages: %v", err))
+		os.Exit(1)
+	}
+
+	if utils.DryRun {
+		cmd.PrintInfo("Dry run: auth extras were not written")
+		return
+	}
+
+	cmd.PrintSuccess("Added " + features.Summary())
+	cmd.PrintInfo("Next steps:")
+	if features.OAuth() {
+		cmd.PrintBullet("go get golang.org/x/oauth2 github.com/golang-jwt/jwt/v5")
+		for _, provider := range features.Providers {
+			cmd.PrintBullet(fmt.Sprintf("Register $OAUTH_CALLBACK_URL/%s/callback as the %s redirect URL", provider, provider))
+		}
+	} else {
+		cmd.PrintBullet("go get github.com/golang-jwt/jwt/v5")
+	}
+	cmd.PrintBullet("Fill in the placeholders added to .env")
+	cmd.PrintBullet("Keep the /auth/oauth, /auth/magic-link and /auth/2fa/verify routes public, like /auth/login")
+	if features.OAuth() || features.MagicLink {
+		cmd.PrintBullet("Add <AuthExtraButtons /> under the form in app/pages/index.vue")
+	}
+	if features.TOTP {
+		cmd.PrintBullet("Link /app/account/security from the user menu")
+		cmd.PrintWarning("Password logins don't ask for the 2FA code: /auth/login is the template's own and was left as is")
+	}
+}
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/base-al/bui/commands/frontend"
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+var generateE2ECmd = &mamba.Command{
+	Use:   "e2e [module]",
+	Short: "Generate Playwright specs for a module's admin pages",
+	Long: `Generate a Playwright spec that signs in, creates a record from the form modal, finds it with
+the search box, edits it and deletes it on the module's admin pages.
+
+The spec goes to e2e/<module>.spec.ts in the frontend, with playwright.config.ts and
+e2e/auth.setup.ts the first time. Its form values are picked from the fields of the backend
+model. Run the specs against the dev servers with bui test e2e.
+
+Examples:
+  bui g e2e product
+  E2E_EMAIL=admin@example.com E2E_PASSWORD=secret bui test e2e`,
+	Args: mamba.ExactArgs(1),
+	Run:  generateE2E,
+}
+
+func init() {
+	generateE2ECmd.Flags().BoolVar(&utils.DryRun, "dry-run", false, "Show the files that would be written without touching disk")
+	generateE2ECmd.Flags().BoolVar(&utils.ShowDiff, "diff", false, "Print a diff for each file during a dry run")
+	generateE2ECmd.Flags().BoolVarP(&utils.Force, "force", "f", false, "Overwrite existing files without asking")
+
+	generateCmd.AddCommand(generateE2ECmd)
+	generateE2ECmd.Run = withHooks("generate", generateE2ECmd.Run)
+}
+
+// generateE2E generates the Playwright spec of a module's admin pages
+func generateE2E(cmd *mamba.Command, args []string) {
+	naming := utils.NewNamingConvention(args[0])
+
+	frontendDir := frontend.DetectFrontendDir()
+	if frontendDir == "" {
+		cmd.PrintError("Failed to generate e2e spec: no frontend directory found")
+		os.Exit(1)
+	}
+	startDir, _ := os.Getwd()
+	w := utils.NewRun(utils.ProjectRoot(startDir), args[0]).Writer(frontendDir)
+	if err := frontend.GenerateE2E(cmd, w, naming); err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to generate e2e spec: %v", err))
+		os.Exit(1)
+	}
+
+	if utils.DryRun {
+		cmd.PrintInfo("Dry run: e2e spec was not written")
+		return
+	}
+	cmd.PrintInfo("Install Playwright in the frontend with: npm i -D @playwright/test && npx playwright install")
+	cmd.PrintInfo("Then run the specs with: bui test e2e")
+}
@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/base-al/bui/commands/backend"
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+var generateMailCmd = &mamba.Command{
+	Use:   "mail [name] [field:type]...",
+	Short: "Generate an email template pair and its typed sender",
+	Long: `Generate an email: templates/mail/<name>.html and <name>.txt in the backend, and a typed
+Send<Name> function in app/mailer that renders both and sends them through SMTP.
+
+Fields are the mail's data, given as for bui g: a belongsTo or hasOne field is a record of its
+model, a hasMany or manyToMany field a list of them, and money fields are formatted from cents.
+The subject is the "subject" template defined in the .txt file. bui build copies the templates
+directory next to the binary.
+
+The mailer reads SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD and MAIL_FROM, added to .env
+and .env.sample; it returns mailer.ErrNotConfigured while SMTP_HOST is empty. bui dev --docker
+runs Mailpit, which takes mail on localhost:1025.
+
+While the API runs under bui dev, GET /api/mail/previews lists the mails and
+GET /api/mail/previews/<name> renders one with records from the database (?format=text for the
+text version).
+
+Examples:
+  bui g mail order_confirmation order:belongsTo:Order
+  bui g mail welcome name:string
+  bui g mail invoice customer:belongsTo:Customer items:hasMany:InvoiceItem total:money due_at:datetime`,
+	Args: mamba.MinimumNArgs(1),
+	Run:  generateMail,
+}
+
+func init() {
+	generateMailCmd.Flags().BoolVar(&utils.DryRun, "dry-run", false, "Show the files that would be written without touching disk")
+	generateMailCmd.Flags().BoolVar(&utils.ShowDiff, "diff", false, "Print a diff for each file during a dry run")
+	generateMailCmd.Flags().BoolVarP(&utils.Force, "force", "f", false, "Overwrite existing files without asking")
+
+	generateCmd.AddCommand(generateMailCmd)
+	generateMailCmd.Run = withHooks("generate", generateMailCmd.Run)
+}
+
+// generateMail generates the templates and sender of a mail
+func generateMail(cmd *mamba.Command, args []string) {
+	mail, err := utils.NewMail(args[0], args[1:])
+	if err != nil {
+		cmd.PrintError(err.Error())
+		os.Exit(1)
+	}
+
+	startDir, _ := os.Getwd()
+	w := utils.NewRun(utils.ProjectRoot(startDir), "mailer").Writer(backend.DetectBackendDir())
+
+	cmd.PrintHeader("Backend")
+	if err := backend.GenerateMail(cmd, w, mail); err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to generate the %s mail: %v", mail.Name, err))
+		os.Exit(1)
+	}
+
+	if utils.DryRun {
+		cmd.PrintInfo("Dry run: the mail was not written")
+		return
+	}
+
+	cmd.PrintSuccess(fmt.Sprintf("Generated the %s mail", mail.Name))
+	cmd.PrintBullet(filepath.Join(utils.MailTemplatesDir, mail.Name+".html") + " and " + mail.Name + ".txt")
+	cmd.PrintBullet(fmt.Sprintf("mailer.Send%s(to, mailer.%sData{...})", mail.Func, mail.Func))
+	cmd.PrintInfo("Preview it under bui dev at /api/mail/previews/" + mail.Name)
+}
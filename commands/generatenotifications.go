@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/base-al/bui/commands/backend"
+	"github.com/base-al/bui/commands/frontend"
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+var (
+	// notificationsTransport is how the bell gets new notifications (--transport sse)
+	notificationsTransport string
+
+	// notificationsPollInterval is the seconds between the bell's polls (--poll-interval 30)
+	notificationsPollInterval int
+)
+
+var generateNotificationsCmd = &mamba.Command{
+	Use:   "notifications",
+	Short: "Generate in-app notifications with an admin bell",
+	Long: `Add notifications for the signed-in user: a notifications table, an app/notifications module
+and a bell dropdown in the admin.
+
+The backend gets the Notification model, a service that creates them, marks them read and
+counts the unread ones, and these endpoints:
+  GET    /notifications              The user's notifications, newest first (?unread=true)
+  GET    /notifications/unread-count
+  GET    /notifications/stream       New notifications as server-sent events (--transport sse)
+  POST   /notifications/read-all
+  POST   /notifications/:id/read
+  DELETE /notifications/:id
+
+Other modules notify a user with notifications.Send. With --transport sse the bell listens on
+the stream and polls only while it's down. Streams live in the API process, so an API running
+several instances should use --transport polling, where the bell asks for the unread count
+every --poll-interval seconds.
+
+The admin gets a Pinia store (stores/notifications.ts), a <NotificationBell /> component and
+pages/app/notifications.vue.
+
+Examples:
+  bui g notifications
+  bui g notifications --transport polling --poll-interval 60`,
+	Args: mamba.NoArgs,
+	Run:  generateNotifications,
+}
+
+func init() {
+	generateNotificationsCmd.Flags().StringVar(&notificationsTransport, "transport", "sse", "How the bell gets new notifications: "+strings.Join(utils.NotificationTransports, " or "))
+	generateNotificationsCmd.Flags().IntVar(&notificationsPollInterval, "poll-interval", 30, "Seconds between the bell's polls")
+	generateNotificationsCmd.Flags().StringVar(&utils.DatabaseFlag, "db", "", "Database to generate for: postgres, mysql or sqlite (default: database in .bui.yaml, else postgres)")
+	generateNotificationsCmd.Flags().BoolVar(&utils.DryRun, "dry-run", false, "Show the files that would be written without touching disk")
+	generateNotificationsCmd.Flags().BoolVar(&utils.ShowDiff, "diff", false, "Print a diff for each file during a dry run")
+	generateNotificationsCmd.Flags().BoolVarP(&utils.Force, "force", "f", false, "Overwrite existing files without asking")
+
+	generateCmd.AddCommand(generateNotificationsCmd)
+	generateNotificationsCmd.Run = withHooks("generate", generateNotificationsCmd.Run)
+}
+
+// generateNotifications generates the notifications module and the admin bell
+func generateNotifications(cmd *mamba.Command, args []string) {
+	if !slices.Contains(utils.NotificationTransports, notificationsTransport) {
+		cmd.PrintError(fmt.Sprintf("Unknown --transport %q: use %s", notificationsTransport, strings.Join(utils.NotificationTransports, " or ")))
+		os.Exit(1)
+	}
+	if notificationsPollInterval < 1 {
+		cmd.PrintError("--poll-interval must be at least 1 second")
+		os.Exit(1)
+	}
+	if err := utils.CheckDatabase(); err != nil {
+		cmd.PrintError(err.Error())
+		os.Exit(1)
+	}
+	options := utils.Notifications{Stream: notificationsTransport == "sse", PollSeconds: notificationsPollInterval}
+
+	startDir, _ := os.Getwd()
+	run := utils.NewRun(utils.ProjectRoot(startDir), "notifications")
+
+	cmd.PrintHeader("Backend")
+	if err := backend.GenerateNotifications(cmd, run.Writer(backend.DetectBackendDir()), options); err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to generate notifications: %v", err))
+		os.Exit(1)
+	}
+
+	cmd.PrintHeader("Frontend")
+	if frontendDir := frontend.DetectFrontendDir(); frontendDir == "" {
+		cmd.PrintWarning("No frontend directory found, skipping the notification bell")
+	} else if err := frontend.GenerateNotificationsBell(cmd, run.Writer(frontendDir), options); err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to generate the notification bell: %v", err))
+		os.Exit(1)
+	}
+
+	if utils.DryRun {
+		cmd.PrintInfo("Dry run: notifications were not written")
+		return
+	}
+
+	cmd.PrintSuccess("Generated notifications")
+	cmd.PrintInfo("Next steps:")
+	cmd.PrintBullet("Add <NotificationBell /> to the navbar of app/layouts/default.vue")
+	cmd.PrintBullet("Send notifications from other modules with notifications.Send(&notifications.Notification{UserId: id, Title: \"...\", Link: \"/app/...\"})")
+	if options.Stream {
+		cmd.PrintBullet("Turn off response buffering of the notifications/stream route in any proxy in front of the API")
+	}
+}
@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/base-al/bui/commands/backend"
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+var generateFromOpenAPICmd = &mamba.Command{
+	Use:   "from-openapi [spec]",
+	Short: "Generate modules from an OpenAPI spec",
+	Long: `Generate backend and frontend modules from an existing OpenAPI 3 (or Swagger 2.0) spec,
+in JSON or YAML.
+
+Every top-level collection (/products with /products/{id}) becomes a module. Its fields come
+from the schema the routes return, with the create request body's required list merged in:
+  string, integer, number, boolean   -> string, int, float, bool
+  format date-time, date, email, uri -> datetime, date, email, url
+  enum                               -> enum (select when values aren't identifiers)
+  $ref to another module's schema    -> belongsTo
+  array of another module's schema   -> hasMany, or manyToMany without a back reference
+
+Everything else is reported at the end rather than dropped silently: custom actions, nested
+routes, PATCH, inline objects and arrays (stored as json), oneOf/anyOf and unused schemas.
+
+Examples:
+  bui g from-openapi openapi.yaml --dry-run
+  bui g from-openapi api.json --pk uuid`,
+	Args: mamba.ExactArgs(1),
+	Run:  generateFromOpenAPI,
+}
+
+func init() {
+	generateFromOpenAPICmd.Flags().BoolVar(&backend.NoTests, "no-tests", false, "Skip generating backend module tests")
+	generateFromOpenAPICmd.Flags().BoolVar(&backend.AllowMissing, "allow-missing", false, "Generate relations to models that don't exist yet")
+	generateFromOpenAPICmd.Flags().BoolVar(&utils.DryRun, "dry-run", false, "Show the files that would be written without touching disk")
+	generateFromOpenAPICmd.Flags().BoolVar(&utils.ShowDiff, "diff", false, "Print a diff for each file during a dry run")
+	generateFromOpenAPICmd.Flags().BoolVarP(&utils.Force, "force", "f", false, "Overwrite existing files without asking")
+	generateFromOpenAPICmd.Flags().StringVar(&utils.PrimaryKey, "pk", "uint", "Primary key type: uint or uuid")
+	generateFromOpenAPICmd.Flags().StringVar(&utils.DatabaseFlag, "db", "", "Database to generate for: postgres, mysql or sqlite (default: database in .bui.yaml, else postgres)")
+	generateFromOpenAPICmd.Flags().BoolVar(&utils.GraphQL, "graphql", false, "Also generate gqlgen schema, resolvers and GraphQL store actions")
+	generateFromOpenAPICmd.Flags().BoolVar(&utils.Realtime, "realtime", false, "Publish changes over websockets and keep the admin lists live")
+	generateFromOpenAPICmd.Flags().BoolVar(&utils.Tenant, "tenant", false, "Scope the modules to the organization of the request")
+	generateFromOpenAPICmd.Flags().BoolVar(&utils.Audited, "audited", false, "Record change history in audit_logs and add Activity tabs to the detail pages")
+	generateFromOpenAPICmd.Flags().BoolVar(&utils.Versioned, "versioned", false, "Keep revisions on every update and add Revisions tabs to the detail pages")
+	generateFromOpenAPICmd.Flags().BoolVar(&utils.LockVersion, "lock-version", false, "Add lock_version columns so stale updates answer 409 and the forms prompt to reload")
+	generateFromOpenAPICmd.Flags().BoolVar(&utils.ImportExport, "import-export", false, "Add CSV/XLSX export and import endpoints and Import/Export buttons to the list pages")
+	generateFromOpenAPICmd.Flags().BoolVar(&utils.PDF, "pdf", false, "Add PDF downloads of records and Download PDF buttons to the detail pages")
+	generateFromOpenAPICmd.Flags().StringVar(&utils.RateLimit, "rate-limit", "", "Limit the requests each client makes to the modules' API routes, e.g. 100/min")
+	generateFromOpenAPICmd.Flags().StringVar(&utils.Cache, "cache", "", "Cache the responses of the modules' API reads for a duration, e.g. 60s; changes invalidate them")
+	generateFromOpenAPICmd.Flags().BoolVar(&utils.Bulk, "bulk", false, "Add bulk delete and status update endpoints and row selection to the list pages")
+
+	generateCmd.AddCommand(generateFromOpenAPICmd)
+	generateFromOpenAPICmd.Run = withHooks("generate", generateFromOpenAPICmd.Run)
+}
+
+// generateFromOpenAPI maps the spec onto modules and generates them, dependencies first
+func generateFromOpenAPI(cmd *mamba.Command, args []string) {
+	path := args[0]
+	spec, err := utils.LoadOpenAPISpec(path)
+	if err != nil {
+		cmd.PrintError(err.Error())
+		os.Exit(1)
+	}
+
+	imported := utils.ImportOpenAPI(spec)
+	if len(imported.Schema.Models) == 0 {
+		cmd.PrintError("No modules found in " + path + ": it needs collection paths such as /products that return an object schema")
+		printOpenAPIUnsupported(cmd, imported)
+		os.Exit(1)
+	}
+
+	checkSchemaRelations(cmd, &imported.Schema)
+
+	models := imported.Schema.OrderedModels()
+	cmd.PrintInfo(fmt.Sprintf("Generating %d modules from %s", len(models), path))
+	for _, model := range models {
+		cmd.PrintBullet(strings.Join(model.Args(), " "))
+	}
+
+	var skipped []string
+	for _, model := range models {
+		cmd.PrintHeader(utils.ToPascalCase(model.Name))
+		utils.TableOverride = ""
+		utils.UniqueIndexes = nil
+		if !generateModule(cmd, model.Args()) {
+			skipped = append(skipped, model.Name)
+		}
+	}
+
+	if len(skipped) > 0 {
+		cmd.PrintError(fmt.Sprintf("Generated %d of %d modules from %s; skipped %s", len(models)-len(skipped), len(models), path, strings.Join(skipped, ", ")))
+		printOpenAPIUnsupported(cmd, imported)
+		os.Exit(1)
+	}
+
+	cmd.PrintSuccess(fmt.Sprintf("Generated %d modules with %d routes from %s", len(models), len(imported.Routes), path))
+	printOpenAPIUnsupported(cmd, imported)
+}
+
+// printOpenAPIUnsupported lists what the import skipped or approximated
+func printOpenAPIUnsupported(cmd *mamba.Command, imported *utils.OpenAPIImport) {
+	if len(imported.Unsupported) == 0 {
+		return
+	}
+	cmd.PrintWarning(fmt.Sprintf("%d constructs were skipped or approximated:", len(imported.Unsupported)))
+	for _, note := range imported.Unsupported {
+		cmd.PrintBullet(note)
+	}
+}
@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/base-al/bui/commands/backend"
+	"github.com/base-al/bui/commands/frontend"
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// policyRoles are the roles bui g policy grants permissions to
+var policyRoles string
+
+var generatePolicyCmd = &mamba.Command{
+	Use:   "policy [module]",
+	Short: "Generate role permissions for a module",
+	Long: `Enforce role permissions on a generated module, in the API and the admin.
+
+The backend gets app/<module>/policy.go with the module's permission constants, a check on
+every controller route (401 without a user, 403 when the user's role lacks the permission),
+GET /<module>/abilities for the current user, and a seed granting each role its actions.
+The admin gets a use<Model>Abilities() composable, route middleware that keeps users out of
+pages they can't open, and v-if guards on the create, edit and delete actions.
+
+Each role gets list, read, create, update and delete (owner, admin, manager), everything but
+delete (editor, author) or list and read (any other name). Choose them with role=actions:
+  --roles admin,editor=list+read+update,viewer
+
+Examples:
+  bui g policy product --roles admin,editor,viewer
+  bui g policy order --roles manager,support=list+read+update`,
+	Args: mamba.ExactArgs(1),
+	Run:  generatePolicy,
+}
+
+func init() {
+	generatePolicyCmd.Flags().StringVar(&policyRoles, "roles", "", "Roles to grant, e.g. admin,editor,viewer or editor=list+read+update")
+	generatePolicyCmd.Flags().BoolVar(&utils.DryRun, "dry-run", false, "Show the files that would be written without touching disk")
+	generatePolicyCmd.Flags().BoolVar(&utils.ShowDiff, "diff", false, "Print a diff for each file during a dry run")
+	generatePolicyCmd.Flags().BoolVarP(&utils.Force, "force", "f", false, "Overwrite existing files without asking")
+
+	generateCmd.AddCommand(generatePolicyCmd)
+	generatePolicyCmd.Run = withHooks("generate", generatePolicyCmd.Run)
+}
+
+// generatePolicy generates the permission checks of a module for the backend and the admin
+func generatePolicy(cmd *mamba.Command, args []string) {
+	if policyRoles == "" {
+		cmd.PrintError("--roles is required, e.g. --roles admin,editor,viewer")
+		os.Exit(1)
+	}
+	roles, err := utils.ParsePolicyRoles(policyRoles)
+	if err != nil {
+		cmd.PrintError(err.Error())
+		os.Exit(1)
+	}
+	naming := utils.NewNamingConvention(args[0])
+
+	startDir, _ := os.Getwd()
+	run := utils.NewRun(utils.ProjectRoot(startDir), args[0])
+
+	cmd.PrintHeader("Backend")
+	if err := backend.GeneratePolicy(cmd, run.Writer(backend.DetectBackendDir()), naming, roles); err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to generate policy: %v", err))
+		os.Exit(1)
+	}
+
+	cmd.PrintHeader("Frontend")
+	if frontendDir := frontend.DetectFrontendDir(); frontendDir == "" {
+		cmd.PrintWarning("No frontend directory found, skipping the admin guards")
+	} else if err := frontend.GeneratePolicyGuards(cmd, run.Writer(frontendDir), naming); err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to generate admin guards: %v", err))
+		os.Exit(1)
+	}
+
+	if utils.DryRun {
+		cmd.PrintInfo("Dry run: policy was not written")
+		return
+	}
+
+	cmd.PrintSuccess(fmt.Sprintf("Generated %s policy", naming.Model))
+	for _, role := range roles {
+		cmd.PrintBullet(fmt.Sprintf("%s: %s", role.Name, strings.Join(role.Actions, ", ")))
+	}
+	cmd.PrintInfo("Roles and grants are seeded when the app starts; the superadmin role keeps every permission")
+}
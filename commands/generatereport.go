@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/base-al/bui/commands/backend"
+	"github.com/base-al/bui/commands/frontend"
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+var (
+	// reportModel is the module a report reads (--model order)
+	reportModel string
+
+	// reportGroupBy is a period or a field the report groups by (--group-by month)
+	reportGroupBy string
+
+	// reportSum lists the fields a report totals (--sum total,tax)
+	reportSum string
+
+	// reportDateField is the date the period and the date range apply to (--date-field paid_at)
+	reportDateField string
+)
+
+var generateReportCmd = &mamba.Command{
+	Use:   "report [name]",
+	Short: "Generate a report over a module",
+	Long: `Add a report to a generated module: an API endpoint that groups its records with a GORM
+group query, counting them and summing numeric fields, and an admin page with a chart and a
+table of the result.
+
+--group-by takes a period of --date-field (created_at by default), day, week, month or year,
+bucketed with the date functions of the project's database, or a field such as status or
+customer_id. --sum takes number, money and decimal fields.
+
+The backend gets app/<module>/<name>_report.go with GET /<module>/reports/<name>?from=&to=, and
+the admin gets pages/app/<module>/reports/<name>.vue, listed in the Reports group of the sidebar.
+
+Examples:
+  bui g report sales --model order --group-by month --sum total
+  bui g report signups --model user --group-by week
+  bui g report revenue_by_status --model order --group-by status --sum total,tax --date-field paid_at`,
+	Args: mamba.ExactArgs(1),
+	Run:  generateReport,
+}
+
+func init() {
+	generateReportCmd.Flags().StringVar(&reportModel, "model", "", "Module to report on, e.g. order")
+	generateReportCmd.Flags().StringVar(&reportGroupBy, "group-by", "month", "Period ("+strings.Join(utils.ReportPeriods, ", ")+") or field to group by")
+	generateReportCmd.Flags().StringVar(&reportSum, "sum", "", "Fields to total, e.g. total,tax")
+	generateReportCmd.Flags().StringVar(&reportDateField, "date-field", "", "Date field of the period and the date range (default created_at)")
+	generateReportCmd.Flags().StringVar(&utils.DatabaseFlag, "db", "", "Database to generate for: postgres, mysql or sqlite (default: database in .bui.yaml, else postgres)")
+	generateReportCmd.Flags().BoolVar(&utils.DryRun, "dry-run", false, "Show the files that would be written without touching disk")
+	generateReportCmd.Flags().BoolVar(&utils.ShowDiff, "diff", false, "Print a diff for each file during a dry run")
+	generateReportCmd.Flags().BoolVarP(&utils.Force, "force", "f", false, "Overwrite existing files without asking")
+
+	generateCmd.AddCommand(generateReportCmd)
+	generateReportCmd.Run = withHooks("generate", generateReportCmd.Run)
+}
+
+// generateReport generates a report endpoint of a module and its admin page
+func generateReport(cmd *mamba.Command, args []string) {
+	if reportModel == "" {
+		cmd.PrintError("--model is required, e.g. --model order")
+		os.Exit(1)
+	}
+	if err := utils.CheckDatabase(); err != nil {
+		cmd.PrintError(err.Error())
+		os.Exit(1)
+	}
+	var sums []string
+	for _, field := range strings.Split(reportSum, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			sums = append(sums, field)
+		}
+	}
+	naming := utils.NewNamingConvention(reportModel)
+
+	startDir, _ := os.Getwd()
+	run := utils.NewRun(utils.ProjectRoot(startDir), reportModel)
+
+	cmd.PrintHeader("Backend")
+	report, err := backend.GenerateReport(cmd, run.Writer(backend.DetectBackendDir()), naming, args[0], reportGroupBy, sums, reportDateField)
+	if err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to generate report: %v", err))
+		os.Exit(1)
+	}
+
+	cmd.PrintHeader("Frontend")
+	if frontendDir := frontend.DetectFrontendDir(); frontendDir == "" {
+		cmd.PrintWarning("No frontend directory found, skipping the report page")
+	} else if err := frontend.GenerateReportPage(cmd, run.Writer(frontendDir), report); err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to generate report page: %v", err))
+		os.Exit(1)
+	}
+
+	if utils.DryRun {
+		cmd.PrintInfo("Dry run: report was not written")
+		return
+	}
+
+	cmd.PrintSuccess(fmt.Sprintf("Generated %s report", report.Title))
+	cmd.PrintBullet("GET " + report.Route())
+	cmd.PrintBullet(report.PagePath())
+}
@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/base-al/bui/commands/backend"
+	"github.com/base-al/bui/commands/frontend"
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+var generateSettingsCmd = &mamba.Command{
+	Use:   "settings [name:group] key:type...",
+	Short: "Generate a key-value settings module and its admin page",
+	Long: `Add application settings: a key-value settings table, an app/settings module with typed
+accessors and an admin settings page with a tab per group.
+
+name:group starts a group, and the settings after it are on its tab; settings given before
+any group are in the "general" group. Types: ` + strings.Join(utils.SettingTypes, ", ") + `.
+Image and file settings are uploaded, and their value is the file's URL.
+
+The backend gets app/settings with GET /settings, PUT /settings and, for image and file
+settings, POST and DELETE /settings/:key/file. Any signed-in user reads the settings; changing
+them takes the settings update permission, granted to the superadmin role. Other modules read
+them through accessors such as settings.SiteName(), served from a cache that's refreshed every
+minute and after each change.
+
+The admin gets a Pinia store (stores/settings.ts) and pages/app/settings.vue, listed in the
+System group of the sidebar. Running it again adds to the settings generated before.
+
+Examples:
+  bui g settings branding:group site_name:string logo:image primary_color:color
+  bui g settings mail:group sender_email:email daily_digest:bool max_recipients:int`,
+	Args: mamba.MinimumNArgs(1),
+	Run:  generateSettings,
+}
+
+func init() {
+	generateSettingsCmd.Flags().StringVar(&utils.DatabaseFlag, "db", "", "Database to generate for: postgres, mysql or sqlite (default: database in .bui.yaml, else postgres)")
+	generateSettingsCmd.Flags().BoolVar(&utils.DryRun, "dry-run", false, "Show the files that would be written without touching disk")
+	generateSettingsCmd.Flags().BoolVar(&utils.ShowDiff, "diff", false, "Print a diff for each file during a dry run")
+	generateSettingsCmd.Flags().BoolVarP(&utils.Force, "force", "f", false, "Overwrite existing files without asking")
+
+	generateCmd.AddCommand(generateSettingsCmd)
+	generateSettingsCmd.Run = withHooks("generate", generateSettingsCmd.Run)
+}
+
+// generateSettings generates the settings module and the admin settings page
+func generateSettings(cmd *mamba.Command, args []string) {
+	settings, err := utils.ParseSettings(args)
+	if err != nil {
+		cmd.PrintError(err.Error())
+		os.Exit(1)
+	}
+	if err := utils.CheckDatabase(); err != nil {
+		cmd.PrintError(err.Error())
+		os.Exit(1)
+	}
+
+	startDir, _ := os.Getwd()
+	run := utils.NewRun(utils.ProjectRoot(startDir), "settings")
+
+	cmd.PrintHeader("Backend")
+	settings, err = backend.GenerateSettings(cmd, run.Writer(backend.DetectBackendDir()), settings)
+	if err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to generate settings: %v", err))
+		os.Exit(1)
+	}
+
+	cmd.PrintHeader("Frontend")
+	if frontendDir := frontend.DetectFrontendDir(); frontendDir == "" {
+		cmd.PrintWarning("No frontend directory found, skipping the settings page")
+	} else if err := frontend.GenerateSettingsPage(cmd, run.Writer(frontendDir), settings); err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to generate the settings page: %v", err))
+		os.Exit(1)
+	}
+
+	if utils.DryRun {
+		cmd.PrintInfo("Dry run: settings were not written")
+		return
+	}
+
+	groups := utils.GroupSettings(settings)
+	cmd.PrintSuccess(fmt.Sprintf("Generated %d settings in %d groups", len(settings), len(groups)))
+	for _, group := range groups {
+		keys := make([]string, len(group.Settings))
+		for i, setting := range group.Settings {
+			keys[i] = setting.Key
+		}
+		cmd.PrintBullet(fmt.Sprintf("%s: %s", group.Label(), strings.Join(keys, ", ")))
+	}
+	cmd.PrintInfo(fmt.Sprintf("Read them in Go with settings.%s() and in the admin with useSettingsStore()", settings[0].Accessor()))
+}
@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/base-al/bui/commands/backend"
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+var generateWebhookCmd = &mamba.Command{
+	Use:   "webhook [event...]",
+	Short: "Generate outgoing webhooks for events",
+	Long: `Send events to external systems as signed webhooks.
+
+The first run generates the webhook_endpoint and webhook_delivery modules (backend and admin
+pages, to manage endpoints and browse the delivery log) and the app/webhooks dispatcher. Each
+event is added to app/webhooks/events.go:
+  order.created, order.updated, order.deleted   -> sent when the orders service creates,
+                                                   updates or deletes an order
+  anything else, e.g. order.shipped             -> sent when the app emits that event itself
+
+Deliveries are POSTed as JSON with X-Webhook-Event, X-Webhook-Delivery, X-Webhook-Timestamp
+and X-Webhook-Signature (sha256=HMAC of "<timestamp>.<body>" with the endpoint's secret),
+and retried with backoff for about 15 hours before they're marked failed. An endpoint's
+secret is set when it's created or updated but never returned by the API.
+
+Examples:
+  bui g webhook order.created
+  bui g webhook order.updated order.deleted invoice.paid`,
+	Args: mamba.MinimumNArgs(1),
+	Run:  generateWebhook,
+}
+
+func init() {
+	generateWebhookCmd.Flags().BoolVar(&utils.DryRun, "dry-run", false, "Show the files that would be written without touching disk")
+	generateWebhookCmd.Flags().BoolVar(&utils.ShowDiff, "diff", false, "Print a diff for each file during a dry run")
+	generateWebhookCmd.Flags().BoolVarP(&utils.Force, "force", "f", false, "Overwrite existing files without asking")
+	generateWebhookCmd.Flags().StringVar(&utils.PrimaryKey, "pk", "uint", "Primary key type of the webhook modules: uint or uuid")
+	generateWebhookCmd.Flags().StringVar(&utils.DatabaseFlag, "db", "", "Database to generate for: postgres, mysql or sqlite (default: database in .bui.yaml, else postgres)")
+
+	generateCmd.AddCommand(generateWebhookCmd)
+	generateWebhookCmd.Run = withHooks("generate", generateWebhookCmd.Run)
+}
+
+// generateWebhook generates the webhook modules on first use and registers each event
+func generateWebhook(cmd *mamba.Command, args []string) {
+	if err := utils.CheckPrimaryKey(); err != nil {
+		cmd.PrintError(err.Error())
+		os.Exit(1)
+	}
+	if err := utils.CheckDatabase(); err != nil {
+		cmd.PrintError(err.Error())
+		os.Exit(1)
+	}
+	for _, event := range args {
+		if err := backend.ValidateWebhookEvent(event); err != nil {
+			cmd.PrintError(err.Error())
+			os.Exit(1)
+		}
+	}
+
+	backendDir := detectBackendDir()
+	if backendDir == "" {
+		backendDir = "."
+	}
+	if _, err := os.Stat(filepath.Join(backendDir, "app", "webhook_endpoints")); os.IsNotExist(err) {
+		backend.PendingModels = []string{"WebhookEndpoint", "WebhookDelivery"}
+		cmd.PrintHeader("WebhookEndpoint")
+		utils.FieldsRead = []string{backend.WebhookEndpointResponseFields}
+		generated := generateModule(cmd, append([]string{"webhook_endpoint"}, backend.WebhookEndpointFields...))
+		utils.FieldsRead = nil
+		if generated {
+			cmd.PrintHeader("WebhookDelivery")
+			generated = generateModule(cmd, append([]string{"webhook_delivery"}, backend.WebhookDeliveryFields...))
+		}
+		if !generated {
+			os.Exit(1)
+		}
+	}
+
+	startDir, _ := os.Getwd()
+	w := utils.NewRun(utils.ProjectRoot(startDir), "webhooks").Writer(backend.DetectBackendDir())
+	for _, event := range args {
+		if err := backend.AddWebhookEvent(cmd, w, event); err != nil {
+			cmd.PrintError(fmt.Sprintf("Failed to add webhook %s: %v", event, err))
+			os.Exit(1)
+		}
+	}
+
+	if utils.DryRun {
+		cmd.PrintInfo("Dry run: webhooks were not written")
+		return
+	}
+
+	for _, event := range args {
+		source, _ := backend.WebhookSource(event)
+		cmd.PrintSuccess(fmt.Sprintf("Webhook %s sends on %s", event, source))
+	}
+	cmd.PrintInfo("Add endpoints under Webhook Endpoints in the admin, listing the events each one receives (or *)")
+}
@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/base-al/bui/commands/backend"
+	"github.com/base-al/bui/commands/frontend"
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// widgetType is the kind of dashboard widget bui g widget generates
+var widgetType string
+
+var generateWidgetCmd = &mamba.Command{
+	Use:   "widget [module]",
+	Short: "Generate a dashboard widget for a module",
+	Long: `Add a card for a generated module to the admin dashboard.
+
+Widget types:
+  stat    The number of records, with how many were created this week against last week
+  chart   Bars of the records created on each of the last 30 days
+  recent  The five records created last, linking to their pages
+
+stat and chart widgets read GET /<module>/stats, which is added to the module's API in
+app/<module>/stats.go; recent widgets read the list endpoint. The widget is listed in
+app/config/dashboard.ts and shown by <DashboardWidgets />, which is added to the dashboard page.
+
+Examples:
+  bui g widget product
+  bui g widget order --type chart
+  bui g widget customer --type recent`,
+	Args: mamba.ExactArgs(1),
+	Run:  generateWidget,
+}
+
+func init() {
+	generateWidgetCmd.Flags().StringVar(&widgetType, "type", "stat", "Widget type: "+strings.Join(utils.WidgetTypes, ", "))
+	generateWidgetCmd.Flags().BoolVar(&utils.DryRun, "dry-run", false, "Show the files that would be written without touching disk")
+	generateWidgetCmd.Flags().BoolVar(&utils.ShowDiff, "diff", false, "Print a diff for each file during a dry run")
+	generateWidgetCmd.Flags().BoolVarP(&utils.Force, "force", "f", false, "Overwrite existing files without asking")
+
+	generateCmd.AddCommand(generateWidgetCmd)
+	generateWidgetCmd.Run = withHooks("generate", generateWidgetCmd.Run)
+}
+
+// generateWidget generates a dashboard widget of a module, and the stats endpoint it reads
+func generateWidget(cmd *mamba.Command, args []string) {
+	if !slices.Contains(utils.WidgetTypes, widgetType) {
+		cmd.PrintError(fmt.Sprintf("Unknown widget type %q. Use one of: %s", widgetType, strings.Join(utils.WidgetTypes, ", ")))
+		os.Exit(1)
+	}
+	naming := utils.NewNamingConvention(args[0])
+
+	// Overwrite backups go in the project root, found from the directory bui was run in
+	startDir, _ := os.Getwd()
+	run := utils.NewRun(utils.ProjectRoot(startDir), args[0])
+
+	// Recent widgets read the list endpoint the module already has
+	if widgetType != "recent" {
+		cmd.PrintHeader("Backend")
+		if err := backend.GenerateStatsEndpoint(cmd, run.Writer(backend.DetectBackendDir()), naming); err != nil {
+			cmd.PrintError(fmt.Sprintf("Failed to generate stats endpoint: %v", err))
+			os.Exit(1)
+		}
+	}
+
+	cmd.PrintHeader("Frontend")
+	if frontendDir := frontend.DetectFrontendDir(); frontendDir == "" {
+		cmd.PrintWarning("No frontend directory found, skipping the widget")
+	} else if err := frontend.GenerateWidget(cmd, run.Writer(frontendDir), naming, widgetType); err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to generate widget: %v", err))
+		os.Exit(1)
+	}
+
+	if utils.DryRun {
+		cmd.PrintInfo("Dry run: widget was not written")
+		return
+	}
+
+	cmd.PrintSuccess(fmt.Sprintf("Generated %s %s widget", naming.Model, widgetType))
+	if widgetType != "recent" {
+		cmd.PrintBullet(fmt.Sprintf("GET %s/stats", naming.RoutePath))
+	}
+	cmd.PrintBullet(utils.DashboardFile)
+	cmd.PrintInfo("Reorder or remove widgets in " + utils.DashboardFile)
+}
@@ -4,4 +4,7 @@ package commands
 var (
 	// Verbose enables detailed output
 	Verbose bool
+
+	// NoUpdateCheck skips the check for a newer release
+	NoUpdateCheck bool
 )
@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// hookDirs holds the absolute directories hooks can run in
+type hookDirs struct {
+	root, backend, frontend string
+}
+
+// hooksActive is set while a hooked command runs, so generators it calls don't run the hooks again
+var hooksActive bool
+
+// currentHookDirs resolves the project directories before a command changes the working directory
+func currentHookDirs() hookDirs {
+	wd, _ := os.Getwd()
+	dirs := hookDirs{root: wd}
+
+	// Detect from the project root, so hooks find both halves when run from inside one
+	if root := utils.Project.Root(); root != "" && os.Chdir(root) == nil {
+		dirs.root = root
+		defer os.Chdir(wd)
+	}
+	backendDir, frontendDir := detectProjectDirs()
+	dirs.backend, _ = filepath.Abs(backendDir)
+	dirs.frontend, _ = filepath.Abs(frontendDir)
+	return dirs
+}
+
+// withHooks wraps a command's Run with the pre_<event> and post_<event> hooks from .bui.yaml.
+// A failing pre hook stops the command; a failing post hook is reported.
+func withHooks(event string, run func(*mamba.Command, []string)) func(*mamba.Command, []string) {
+	return func(cmd *mamba.Command, args []string) {
+		if hooksActive || utils.DryRun || destroyDryRun {
+			run(cmd, args)
+			return
+		}
+		hooksActive = true
+		defer func() { hooksActive = false }()
+
+		dirs := currentHookDirs()
+		if !runHooks(cmd, "pre_"+event, dirs, args) {
+			os.Exit(1)
+		}
+		run(cmd, args)
+		runHooks(cmd, "post_"+event, dirs, args)
+	}
+}
+
+// runHooks runs the hooks configured for an event and reports whether all of them succeeded
+func runHooks(cmd *mamba.Command, event string, dirs hookDirs, args []string) bool {
+	hooks := utils.Project.Hooks(event)
+	for _, hook := range hooks {
+		dir := dirs.root
+		switch hook.Dir {
+		case "":
+		case "backend":
+			dir = dirs.backend
+		case "frontend":
+			dir = dirs.frontend
+		default:
+			dir = filepath.Join(dirs.root, hook.Dir)
+		}
+
+		if Verbose {
+			cmd.PrintInfo(fmt.Sprintf("%s: %s (in %s)", event, hook.Run, dir))
+		}
+
+		hookCmd := shellCommand(hook.Run)
+		hookCmd.Dir = dir
+		hookCmd.Stdout = os.Stdout
+		hookCmd.Stderr = os.Stderr
+		hookCmd.Env = append(os.Environ(), "BUI_HOOK="+event, "BUI_ARGS="+strings.Join(args, " "))
+		if len(args) > 0 {
+			hookCmd.Env = append(hookCmd.Env, "BUI_MODULE="+args[0])
+		}
+
+		if err := hookCmd.Run(); err != nil {
+			if strings.HasPrefix(event, "pre_") {
+				cmd.PrintError(fmt.Sprintf("%s hook %q failed: %v", event, hook.Run, err))
+			} else {
+				cmd.PrintWarning(fmt.Sprintf("%s hook %q failed: %v", event, hook.Run, err))
+			}
+			return false
+		}
+	}
+	return true
+}
+
+// shellCommand returns a command that runs line through the platform shell
+func shellCommand(line string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", line)
+	}
+	return exec.Command("sh", "-c", line)
+}
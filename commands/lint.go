@@ -0,0 +1,281 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+var (
+	// lintFix lets the linters fix what they can
+	lintFix bool
+
+	// lintOnly lints just the backend or the frontend
+	lintOnly string
+
+	// lintInstall installs golangci-lint and vue-tsc when they are missing
+	lintInstall bool
+)
+
+var lintCmd = &mamba.Command{
+	Use:   "lint",
+	Short: "Run the backend and frontend linters",
+	Long: `Run the linters of the backend and frontend and print a single summary.
+
+The backend is checked with gofmt, go vet and golangci-lint, the frontend with eslint
+and vue-tsc (through nuxi typecheck). Linters that aren't installed are skipped with a
+hint; --install adds golangci-lint with go install and vue-tsc to the frontend's dev
+dependencies. ESLint needs a config, so add it with: npx nuxi module add eslint
+
+With --fix, gofmt rewrites the files it would list and golangci-lint and eslint fix
+what they can. bui lint exits with status 1 when any check fails.
+
+Examples:
+  bui lint                    # Lint the backend and frontend
+  bui lint --fix              # Fix what the linters can
+  bui lint --only backend     # Lint just the backend
+  bui lint --install          # Install missing linters first`,
+	Run: runLint,
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+	lintCmd.Flags().BoolVar(&lintFix, "fix", false, "Fix the problems the linters can")
+	lintCmd.Flags().StringVar(&lintOnly, "only", "", "Lint only one side: backend or frontend")
+	lintCmd.Flags().BoolVar(&lintInstall, "install", false, "Install golangci-lint and vue-tsc when they are missing")
+}
+
+// lintCheck is one linter run in the backend or frontend directory
+type lintCheck struct {
+	name string
+	side string
+	cmd  *exec.Cmd
+	// failsOnOutput marks tools such as gofmt -l that list problems but exit with status 0
+	failsOnOutput bool
+	// skip says why the check isn't run, e.g. because the linter isn't installed
+	skip string
+}
+
+// lintResult is the outcome of a check for the summary
+type lintResult struct {
+	check    *lintCheck
+	status   string
+	duration time.Duration
+}
+
+func runLint(cmd *mamba.Command, args []string) {
+	if lintOnly != "" && lintOnly != "backend" && lintOnly != "frontend" {
+		cmd.PrintError(fmt.Sprintf("Invalid --only value: %s", lintOnly))
+		cmd.PrintInfo("Use --only backend or --only frontend")
+		os.Exit(1)
+	}
+
+	var checks []*lintCheck
+	backendDir, frontendDir := findBackendDir(), findFrontendDir()
+	if backendDir != "" && lintOnly != "frontend" {
+		checks = append(checks, backendLintChecks(cmd, backendDir)...)
+	}
+	if frontendDir != "" && lintOnly != "backend" {
+		checks = append(checks, frontendLintChecks(cmd, frontendDir)...)
+	}
+	if len(checks) == 0 {
+		cmd.PrintError("No backend or frontend directory found")
+		cmd.PrintInfo("Run bui lint from your project root, the backend or the frontend directory")
+		os.Exit(1)
+	}
+
+	cmd.PrintHeader("Linting")
+	results := make([]lintResult, 0, len(checks))
+	failed, skipped := 0, 0
+	for _, check := range checks {
+		result := runLintCheck(cmd, check)
+		switch result.status {
+		case "failed":
+			failed++
+		case "skipped":
+			skipped++
+		}
+		results = append(results, result)
+	}
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHECK\tSIDE\tRESULT\tTIME")
+	for _, result := range results {
+		duration := "-"
+		if result.status != "skipped" {
+			duration = result.duration.Round(100 * time.Millisecond).String()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", result.check.name, result.check.side, result.status, duration)
+	}
+	w.Flush()
+	fmt.Println()
+
+	if failed > 0 {
+		cmd.PrintError(fmt.Sprintf("%d of %d checks failed", failed, len(results)))
+		if !lintFix {
+			cmd.PrintInfo("Run bui lint --fix to fix what the linters can")
+		}
+		os.Exit(1)
+	}
+	if skipped == len(results) {
+		cmd.PrintWarning("No checks were run")
+		return
+	}
+	cmd.PrintSuccess("All checks passed")
+}
+
+// runLintCheck runs a check and prints its output when it fails
+func runLintCheck(cmd *mamba.Command, check *lintCheck) lintResult {
+	result := lintResult{check: check}
+	if check.skip != "" {
+		cmd.PrintWarning(fmt.Sprintf("Skipping %s: %s", check.name, check.skip))
+		result.status = "skipped"
+		return result
+	}
+
+	cmd.PrintInfo(fmt.Sprintf("Running %s (%s)...", check.name, check.side))
+	start := time.Now()
+	output, err := check.cmd.CombinedOutput()
+	result.duration = time.Since(start)
+	output = []byte(strings.TrimSpace(string(output)))
+
+	if err != nil || (check.failsOnOutput && len(output) > 0) {
+		result.status = "failed"
+		if check.failsOnOutput && err == nil {
+			cmd.PrintError(fmt.Sprintf("%s: these files need formatting", check.name))
+		} else {
+			cmd.PrintError(fmt.Sprintf("%s failed", check.name))
+		}
+		if len(output) > 0 {
+			fmt.Println(string(output))
+		}
+		return result
+	}
+	result.status = "passed"
+	return result
+}
+
+// backendLintChecks returns the gofmt, go vet and golangci-lint checks of the backend
+func backendLintChecks(cmd *mamba.Command, dir string) []*lintCheck {
+	gofmt := &lintCheck{name: "gofmt", side: "backend", cmd: exec.Command("gofmt", "-l", "."), failsOnOutput: true}
+	if lintFix {
+		gofmt.cmd, gofmt.failsOnOutput = exec.Command("gofmt", "-w", "."), false
+	}
+	vet := &lintCheck{name: "go vet", side: "backend", cmd: exec.Command("go", "vet", "./...")}
+
+	golangci := &lintCheck{name: "golangci-lint", side: "backend"}
+	if binary := findGolangciLint(cmd); binary != "" {
+		golangciArgs := []string{"run"}
+		if lintFix {
+			golangciArgs = append(golangciArgs, "--fix")
+		}
+		golangci.cmd = exec.Command(binary, append(golangciArgs, "./...")...)
+	} else {
+		golangci.skip = "not installed (run bui lint --install)"
+	}
+
+	checks := []*lintCheck{gofmt, vet, golangci}
+	for _, check := range checks {
+		if check.cmd != nil {
+			check.cmd.Dir = dir
+		}
+	}
+	return checks
+}
+
+// findGolangciLint returns the golangci-lint binary, installing it with go install when --install
+// is set. It returns "" when it isn't available.
+func findGolangciLint(cmd *mamba.Command) string {
+	if path, err := exec.LookPath("golangci-lint"); err == nil {
+		return path
+	}
+	if !lintInstall {
+		return ""
+	}
+
+	cmd.PrintInfo("Installing golangci-lint...")
+	install := exec.Command("go", "install", "github.com/golangci/golangci-lint/v2/cmd/golangci-lint@latest")
+	if output, err := install.CombinedOutput(); err != nil {
+		cmd.PrintWarning(fmt.Sprintf("Failed to install golangci-lint: %v", err))
+		fmt.Println(strings.TrimSpace(string(output)))
+		return ""
+	}
+	if path, err := exec.LookPath("golangci-lint"); err == nil {
+		return path
+	}
+	// go install puts it in GOPATH/bin, which may not be on PATH
+	gopath, err := exec.Command("go", "env", "GOPATH").Output()
+	if err != nil {
+		return ""
+	}
+	path := filepath.Join(strings.TrimSpace(string(gopath)), "bin", "golangci-lint")
+	if !fileExists(path) {
+		return ""
+	}
+	return path
+}
+
+// frontendLintChecks returns the eslint and vue-tsc checks of the frontend. Both are run from the
+// frontend's node_modules, so they are skipped when it isn't installed there.
+func frontendLintChecks(cmd *mamba.Command, dir string) []*lintCheck {
+	eslint := &lintCheck{name: "eslint", side: "frontend"}
+	typecheck := &lintCheck{name: "vue-tsc", side: "frontend"}
+	manager := utils.Project.PackageManagerName()
+	if !dirExists(filepath.Join(dir, "node_modules")) {
+		hint := fmt.Sprintf("dependencies not installed (run %s install)", manager)
+		eslint.skip, typecheck.skip = hint, hint
+		return []*lintCheck{eslint, typecheck}
+	}
+
+	if hasPackageBinary(dir, "eslint") {
+		eslintArgs := []string{"."}
+		if lintFix {
+			eslintArgs = append(eslintArgs, "--fix")
+		}
+		eslint.cmd = packageBinary("eslint", eslintArgs...)
+		eslint.cmd.Dir = dir
+	} else {
+		eslint.skip = "not installed (add it with: npx nuxi module add eslint)"
+	}
+
+	if !hasPackageBinary(dir, "vue-tsc") && lintInstall {
+		cmd.PrintInfo("Installing vue-tsc...")
+		install := packageDevInstall("vue-tsc", "typescript")
+		install.Dir = dir
+		if output, err := install.CombinedOutput(); err != nil {
+			cmd.PrintWarning(fmt.Sprintf("Failed to install vue-tsc: %v", err))
+			fmt.Println(strings.TrimSpace(string(output)))
+		}
+	}
+	if hasPackageBinary(dir, "vue-tsc") {
+		// nuxi typecheck runs vue-tsc with the tsconfig Nuxt generates
+		typecheck.cmd = packageBinary("nuxi", "typecheck")
+		typecheck.cmd.Dir = dir
+	} else {
+		typecheck.skip = "not installed (run bui lint --install)"
+	}
+	return []*lintCheck{eslint, typecheck}
+}
+
+// hasPackageBinary reports whether binary is installed in the frontend's node_modules
+func hasPackageBinary(dir, binary string) bool {
+	return fileExists(filepath.Join(dir, "node_modules", ".bin", binary))
+}
+
+// packageDevInstall returns a command that adds packages to the dev dependencies with the
+// project's package manager
+func packageDevInstall(packages ...string) *exec.Cmd {
+	manager := utils.Project.PackageManagerName()
+	if manager == "npm" {
+		return exec.Command(manager, append([]string{"install", "-D"}, packages...)...)
+	}
+	return exec.Command(manager, append([]string{"add", "-D"}, packages...)...)
+}
@@ -0,0 +1,145 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+var listCmd = &mamba.Command{
+	Use:     "list",
+	Aliases: []string{"modules", "ls"},
+	Short:   "List the project's modules",
+	Long: `List backend modules (app/<module>) and frontend modules (app/modules/<module>)
+with their fields, which side of the stack has them and whether they are
+registered in the backend's app/init.go.
+
+Fields are read from the backend model structs in app/models.`,
+	Run: listModules,
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+}
+
+// moduleInfo describes one module found in the project
+type moduleInfo struct {
+	name       string
+	backend    bool
+	frontend   bool
+	registered bool
+	fields     []string
+}
+
+// initModulePattern matches module registrations such as modules["products"] in app/init.go
+var initModulePattern = regexp.MustCompile(`modules\["([^"]+)"\]`)
+
+// listModules prints a table of the backend and frontend modules in the project
+func listModules(cmd *mamba.Command, args []string) {
+	backendDir, frontendDir := detectProjectDirs()
+	modules := map[string]*moduleInfo{}
+	module := func(name string) *moduleInfo {
+		if modules[name] == nil {
+			modules[name] = &moduleInfo{name: name}
+		}
+		return modules[name]
+	}
+
+	// Backend modules are app/ directories with a module.go, namespaced ones app/<namespace>/ directories
+	for _, dirName := range utils.FindModuleDirs(backendDir, "module.go") {
+		info := module(dirName)
+		info.backend = true
+		info.fields = moduleFields(backendDir, info.name)
+	}
+
+	// The frontend module of a namespaced backend module is app/modules/<module>, without the namespace
+	namespaced := map[string]string{}
+	for name := range modules {
+		if strings.Contains(name, "/") {
+			namespaced[path.Base(name)] = name
+		}
+	}
+	if entries, err := os.ReadDir(filepath.Join(frontendDir, "app", "modules")); err == nil {
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			name := entry.Name()
+			if backend, ok := namespaced[name]; ok && modules[name] == nil {
+				name = backend
+			}
+			module(name).frontend = true
+		}
+	}
+
+	// Registrations without a directory show up too, so stale entries are visible
+	if content, err := os.ReadFile(filepath.Join(backendDir, "app", "init.go")); err == nil {
+		for _, match := range initModulePattern.FindAllStringSubmatch(string(content), -1) {
+			module(match[1]).registered = true
+		}
+	}
+
+	if len(modules) == 0 {
+		cmd.PrintWarning("No modules found")
+		cmd.PrintInfo("Run bui list from the project root, the backend or the frontend directory")
+		return
+	}
+
+	names := make([]string, 0, len(modules))
+	for name := range modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	cmd.PrintHeader(fmt.Sprintf("Modules (%d)", len(names)))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MODULE\tSIDE\tREGISTERED\tFIELDS")
+	for _, name := range names {
+		info := modules[name]
+		side := "missing"
+		switch {
+		case info.backend && info.frontend:
+			side = "both"
+		case info.backend:
+			side = "backend"
+		case info.frontend:
+			side = "frontend"
+		}
+
+		registered := "no"
+		if info.registered {
+			registered = "yes"
+		} else if !info.backend {
+			registered = "-"
+		}
+
+		fields := "-"
+		if len(info.fields) > 0 {
+			fields = strings.Join(info.fields, " ")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, side, registered, fields)
+	}
+	w.Flush()
+}
+
+// moduleFields returns the field definitions of a backend module's model, or nil when it can't be read
+func moduleFields(backendDir, dirName string) []string {
+	naming := utils.NewNamingConvention(utils.Singularize(dirName))
+	source, err := os.ReadFile(filepath.Join(backendDir, "app", "models", naming.ModelSnake+".go"))
+	if err != nil {
+		return nil
+	}
+	fields, err := utils.RecoverFieldDefs(source, naming.Model)
+	if err != nil {
+		return nil
+	}
+	return fields
+}
@@ -0,0 +1,282 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+var migrateCmd = &mamba.Command{
+	Use:   "migrate",
+	Short: "Manage SQL database migrations",
+	Long: `Create, apply and roll back SQL migrations stored in the backend's migrations/ directory.
+
+Each migration is a <timestamp>_<name>.up.sql and .down.sql pair. "bui g backend"
+writes a create_<table> migration for every new module. Applied versions are
+recorded in the schema_migrations table of the database configured in .env.
+
+Examples:
+  bui migrate new add_sku_to_products  # Create an empty migration pair
+  bui migrate up                       # Apply all pending migrations
+  bui migrate down                     # Roll back the last migration
+  bui migrate down 3                   # Roll back the last three migrations
+  bui migrate status                   # List applied and pending migrations`,
+}
+
+var migrateNewCmd = &mamba.Command{
+	Use:   "new [name]",
+	Short: "Create an empty migration",
+	Args:  mamba.ExactArgs(1),
+	Run:   newMigration,
+}
+
+var migrateUpCmd = &mamba.Command{
+	Use:   "up",
+	Short: "Apply all pending migrations",
+	Run: func(cmd *mamba.Command, args []string) {
+		runMigrations(cmd, "up")
+	},
+}
+
+var migrateDownCmd = &mamba.Command{
+	Use:   "down [steps]",
+	Short: "Roll back applied migrations (default 1)",
+	Run: func(cmd *mamba.Command, args []string) {
+		steps := "1"
+		if len(args) > 0 {
+			if n, err := strconv.Atoi(args[0]); err != nil || n < 1 {
+				cmd.PrintError(fmt.Sprintf("Invalid number of steps: %s", args[0]))
+				return
+			}
+			steps = args[0]
+		}
+		runMigrations(cmd, "down", steps)
+	},
+}
+
+var migrateStatusCmd = &mamba.Command{
+	Use:   "status",
+	Short: "Show applied and pending migrations",
+	Run: func(cmd *mamba.Command, args []string) {
+		runMigrations(cmd, "status")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.AddCommand(migrateNewCmd)
+	migrateCmd.AddCommand(migrateUpCmd)
+	migrateCmd.AddCommand(migrateDownCmd)
+	migrateCmd.AddCommand(migrateStatusCmd)
+}
+
+// migrationsBackendDir returns the backend directory, or "" when no Base project is found
+func migrationsBackendDir(cmd *mamba.Command) string {
+	backendDir, _ := detectProjectDirs()
+	if _, err := os.Stat(filepath.Join(backendDir, "main.go")); os.IsNotExist(err) {
+		cmd.PrintError("Base project structure not found")
+		cmd.PrintInfo("Run bui migrate from the project root or the backend directory")
+		return ""
+	}
+	return backendDir
+}
+
+// newMigration writes an empty up/down migration pair
+func newMigration(cmd *mamba.Command, args []string) {
+	backendDir := migrationsBackendDir(cmd)
+	if backendDir == "" {
+		return
+	}
+
+	name := utils.ToSnakeCase(args[0])
+	upPath, err := utils.WriteMigration(
+		filepath.Join(backendDir, utils.MigrationsDir),
+		name,
+		fmt.Sprintf("-- %s: write the schema change here\n", name),
+		fmt.Sprintf("-- %s: write the statements that undo the up migration here\n", name),
+	)
+	if err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to create migration: %v", err))
+		return
+	}
+
+	cmd.PrintSuccess(fmt.Sprintf("Created %s", upPath))
+	cmd.PrintBullet(strings.TrimSuffix(upPath, ".up.sql") + ".down.sql")
+}
+
+// runMigrations runs the migration runner with the given action in the backend module
+func runMigrations(cmd *mamba.Command, args ...string) {
+	backendDir := migrationsBackendDir(cmd)
+	if backendDir == "" {
+		return
+	}
+
+	source := strings.ReplaceAll(migrateRunnerSource, "{{module}}", utils.GetGoModuleNameIn(backendDir))
+	if err := runBackendProgram(backendDir, source, args...); err != nil {
+		cmd.PrintError(fmt.Sprintf("Migration failed: %v", err))
+	}
+}
+
+// migrateRunnerSource applies, rolls back and lists migrations using the project's database connection.
+// It runs from the backend directory, so migrations are read from ./migrations.
+const migrateRunnerSource = `// Code generated by bui migrate. DO NOT EDIT.
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"{{module}}/core/config"
+	"{{module}}/core/database"
+
+	"gorm.io/gorm"
+)
+
+type appliedMigration struct {
+	Version   string
+	AppliedAt time.Time
+}
+
+func main() {
+	db, err := database.InitDB(config.NewConfig())
+	if err != nil {
+		fail("failed to connect to database: %v", err)
+	}
+	conn := db.DB
+
+	if err := conn.Exec("CREATE TABLE IF NOT EXISTS schema_migrations (version VARCHAR(32) PRIMARY KEY, applied_at TIMESTAMP NOT NULL)").Error; err != nil {
+		fail("failed to create schema_migrations: %v", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join("migrations", "*.up.sql"))
+	if err != nil {
+		fail("failed to read migrations: %v", err)
+	}
+	sort.Strings(files)
+
+	var applied []appliedMigration
+	if err := conn.Raw("SELECT version, applied_at FROM schema_migrations ORDER BY version").Scan(&applied).Error; err != nil {
+		fail("failed to read schema_migrations: %v", err)
+	}
+	appliedAt := map[string]time.Time{}
+	for _, migration := range applied {
+		appliedAt[migration.Version] = migration.AppliedAt
+	}
+
+	switch os.Args[1] {
+	case "up":
+		count := 0
+		for _, file := range files {
+			version := versionOf(file)
+			if _, ok := appliedAt[version]; ok {
+				continue
+			}
+			if err := run(conn, file, func(tx *gorm.DB) error {
+				return tx.Exec("INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)", version, time.Now()).Error
+			}); err != nil {
+				fail("%s: %v", filepath.Base(file), err)
+			}
+			fmt.Println("Applied", filepath.Base(file))
+			count++
+		}
+		if count == 0 {
+			fmt.Println("No pending migrations")
+		}
+	case "down":
+		steps, _ := strconv.Atoi(os.Args[2])
+		for i := len(applied) - 1; i >= 0 && steps > 0; i-- {
+			version := applied[i].Version
+			matches, _ := filepath.Glob(filepath.Join("migrations", version+"_*.down.sql"))
+			if len(matches) == 0 {
+				fail("no down migration found for version %s", version)
+			}
+			if err := run(conn, matches[0], func(tx *gorm.DB) error {
+				return tx.Exec("DELETE FROM schema_migrations WHERE version = ?", version).Error
+			}); err != nil {
+				fail("%s: %v", filepath.Base(matches[0]), err)
+			}
+			fmt.Println("Rolled back", filepath.Base(matches[0]))
+			steps--
+		}
+	case "status":
+		known := map[string]bool{}
+		for _, file := range files {
+			version := versionOf(file)
+			known[version] = true
+			name := strings.TrimSuffix(filepath.Base(file), ".up.sql")
+			if at, ok := appliedAt[version]; ok {
+				fmt.Printf("applied  %s  %s\n", at.Format("2006-01-02 15:04:05"), name)
+			} else {
+				fmt.Printf("pending  %-19s  %s\n", "", name)
+			}
+		}
+		for _, migration := range applied {
+			if !known[migration.Version] {
+				fmt.Printf("missing  %s  %s (file not found)\n", migration.AppliedAt.Format("2006-01-02 15:04:05"), migration.Version)
+			}
+		}
+	}
+}
+
+// run executes the statements in file and record inside one transaction
+func run(conn *gorm.DB, file string, record func(tx *gorm.DB) error) error {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	return conn.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range splitStatements(string(content)) {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return record(tx)
+	})
+}
+
+// splitStatements splits SQL on semicolons that end a line and drops comment-only statements
+func splitStatements(sql string) []string {
+	var statements []string
+	var current strings.Builder
+	hasCode := false
+	for _, line := range strings.Split(sql, "\n") {
+		trimmed := strings.TrimSpace(line)
+		current.WriteString(line + "\n")
+		if trimmed != "" && !strings.HasPrefix(trimmed, "--") {
+			hasCode = true
+		}
+		if strings.HasSuffix(trimmed, ";") {
+			if hasCode {
+				statements = append(statements, strings.TrimSpace(current.String()))
+			}
+			current.Reset()
+			hasCode = false
+		}
+	}
+	if hasCode {
+		statements = append(statements, strings.TrimSpace(current.String()))
+	}
+	return statements
+}
+
+// versionOf returns the timestamp prefix of a migration file name
+func versionOf(file string) string {
+	version, _, _ := strings.Cut(filepath.Base(file), "_")
+	return version
+}
+
+func fail(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
+`
@@ -9,7 +9,9 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/base-al/bui/utils"
 	"github.com/base-go/mamba"
+	"golang.org/x/mod/module"
 )
 
 var newCmd = &mamba.Command{
@@ -24,14 +26,43 @@ This command will:
   4. Initialize git repository
   5. Set up configuration files
 
-Example:
-  bui new my-awesome-project`,
+Examples:
+  bui new my-awesome-project
+  bui new my-awesome-project --https          # Clone over HTTPS (no SSH key needed)
+  bui new my-awesome-project --backend-only   # API only
+  bui new my-awesome-project --frontend-only  # Nuxt admin only
+  bui new my-awesome-project --module github.com/acme/my-awesome-project-api
+  bui new my-awesome-project --db sqlite      # postgres (default), mysql or sqlite`,
 	Args: mamba.ExactArgs(1),
 	Run:  createNewProject,
 }
 
+// Template repositories, addressed by path so either SSH or HTTPS can be used.
+// templates.backend and templates.frontend in .bui.yaml or ~/.bui/config.yaml override them.
+const (
+	backendTemplateRepo  = "base-al/admin-api-template"
+	frontendTemplateRepo = "base-al/admin-template"
+)
+
+var (
+	// forceHTTPS skips SSH detection and clones templates over HTTPS
+	forceHTTPS bool
+
+	// backendOnly and frontendOnly scaffold just one half of the stack
+	backendOnly  bool
+	frontendOnly bool
+
+	// goModulePath overrides the backend Go module path (defaults to the project name)
+	goModulePath string
+)
+
 func init() {
 	rootCmd.AddCommand(newCmd)
+	newCmd.Flags().BoolVar(&forceHTTPS, "https", false, "Clone templates over HTTPS instead of SSH")
+	newCmd.Flags().BoolVar(&backendOnly, "backend-only", false, "Only create the backend API project")
+	newCmd.Flags().BoolVar(&frontendOnly, "frontend-only", false, "Only create the frontend admin project")
+	newCmd.Flags().StringVar(&goModulePath, "module", "", "Go module path for the backend (e.g. github.com/acme/myproj-api)")
+	newCmd.Flags().StringVar(&utils.DatabaseFlag, "db", "", "Database of the project: postgres, mysql or sqlite (default postgres)")
 }
 
 func createNewProject(cmd *mamba.Command, args []string) {
@@ -44,12 +75,39 @@ func createNewProject(cmd *mamba.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if backendOnly && frontendOnly {
+		cmd.PrintError("--backend-only and --frontend-only cannot be used together")
+		os.Exit(1)
+	}
+
+	if err := utils.CheckDatabase(); err != nil {
+		cmd.PrintError(err.Error())
+		os.Exit(1)
+	}
+
+	// Resolve the Go module path used for go.mod and import rewrites
+	modulePath := projectName
+	if goModulePath != "" {
+		if err := module.CheckPath(goModulePath); err != nil {
+			cmd.PrintError(err.Error())
+			cmd.PrintInfo("Module path must look like example.com/org/project")
+			os.Exit(1)
+		}
+		modulePath = goModulePath
+	}
+
 	// Check if directory already exists
 	if _, err := os.Stat(projectName); !os.IsNotExist(err) {
 		cmd.PrintError(fmt.Sprintf("Directory '%s' already exists", projectName))
 		os.Exit(1)
 	}
 
+	// pre_new hooks come from a .bui.yaml enclosing the directory the project is created in
+	wd, _ := os.Getwd()
+	if !runHooks(cmd, "pre_new", hookDirs{root: wd}, args) {
+		os.Exit(1)
+	}
+
 	cmd.PrintInfo(fmt.Sprintf("Creating new Base Stack project: %s", projectName))
 
 	// Create project directory
@@ -64,20 +122,45 @@ func createNewProject(cmd *mamba.Command, args []string) {
 		os.Exit(1)
 	}
 
-	// Clone backend template with spinner
-	backendDir := projectName + "-api"
-	if err := cloneWithSpinner(cmd, "backend", "git@github.com:base-al/admin-api-template.git", backendDir); err != nil {
-		cmd.PrintError(fmt.Sprintf("Failed to clone backend template: %v", err))
-		cleanup(projectName)
-		os.Exit(1)
+	// Decide between SSH and HTTPS before cloning
+	useSSH := templateCloneSSH()
+	if Verbose {
+		if useSSH {
+			cmd.PrintInfo("Using SSH to clone templates")
+		} else {
+			cmd.PrintInfo("Using HTTPS to clone templates")
+		}
 	}
 
-	// Clone frontend template with spinner
-	frontendDir := projectName + "-app"
-	if err := cloneWithSpinner(cmd, "frontend", "git@github.com:base-al/admin-template.git", frontendDir); err != nil {
-		cmd.PrintError(fmt.Sprintf("Failed to clone frontend template: %v", err))
-		cleanup(projectName)
-		os.Exit(1)
+	// Clone backend template with spinner (empty dir means skipped)
+	backendDir := ""
+	if !frontendOnly {
+		backendDir = projectName + "-api"
+		if err := cloneWithSpinner(cmd, "backend", utils.Project.BackendTemplate(utils.User.BackendTemplate(backendTemplateRepo)), backendDir, useSSH, true); err != nil {
+			cmd.PrintError(fmt.Sprintf("Failed to clone backend template: %v", err))
+			cleanup(projectName)
+			os.Exit(1)
+		}
+	}
+
+	// Clone frontend template with spinner (empty dir means skipped)
+	frontendDir := ""
+	if !backendOnly {
+		frontendDir = projectName + "-app"
+		if err := cloneWithSpinner(cmd, "frontend", utils.Project.FrontendTemplate(utils.User.FrontendTemplate(frontendTemplateRepo)), frontendDir, useSSH, true); err != nil {
+			cmd.PrintError(fmt.Sprintf("Failed to clone frontend template: %v", err))
+			cleanup(projectName)
+			os.Exit(1)
+		}
+	}
+
+	// Remember the template commits so bui update templates can bring in later changes
+	commits := utils.TemplateCommitsConfig{}
+	if backendDir != "" {
+		commits.Backend, _ = gitOutput(backendDir, "rev-parse", "HEAD")
+	}
+	if frontendDir != "" {
+		commits.Frontend, _ = gitOutput(frontendDir, "rev-parse", "HEAD")
 	}
 
 	// Cleanup and initialize
@@ -85,8 +168,13 @@ func createNewProject(cmd *mamba.Command, args []string) {
 		cmd.PrintWarning(fmt.Sprintf("Setup incomplete: %v", err))
 	}
 
+	// Record the directory names so commands don't have to guess them
+	if err := writeNewProjectConfig(backendDir, frontendDir, commits); err != nil {
+		cmd.PrintWarning(fmt.Sprintf("Failed to write %s: %v", utils.ProjectConfigFile, err))
+	}
+
 	// Update configuration files
-	if err := updateProjectFiles(cmd, projectName, backendDir, frontendDir); err != nil {
+	if err := updateProjectFiles(cmd, projectName, modulePath, backendDir, frontendDir); err != nil {
 		cmd.PrintWarning(fmt.Sprintf("Failed to update project files: %v", err))
 	}
 
@@ -95,12 +183,30 @@ func createNewProject(cmd *mamba.Command, args []string) {
 		cmd.PrintWarning(fmt.Sprintf("Failed to copy .env.example to .env: %v", err))
 	}
 
+	// Point the backend's env files at the chosen database
+	if err := setDatabaseEnv(cmd, projectName, backendDir); err != nil {
+		cmd.PrintWarning(fmt.Sprintf("Failed to set the database in the backend's .env: %v", err))
+	}
+
 	// Print success message and next steps
-	printSuccessMessage(cmd, projectName)
+	printSuccessMessage(cmd, projectName, backendDir, frontendDir)
+
+	// post_new hooks run inside the new project
+	projectDir, _ := os.Getwd()
+	runHooks(cmd, "post_new", hookDirs{
+		root:     projectDir,
+		backend:  filepath.Join(projectDir, backendDir),
+		frontend: filepath.Join(projectDir, frontendDir),
+	}, args)
 }
 
-func cloneTemplate(repoURL, targetDir string) error {
-	gitCmd := exec.Command("git", "clone", "--depth", "1", repoURL, targetDir)
+// cloneTemplate clones a template repository; a shallow clone only has the latest commit
+func cloneTemplate(repoURL, targetDir string, shallow bool) error {
+	args := []string{"clone", repoURL, targetDir}
+	if shallow {
+		args = append(args, "--depth", "1")
+	}
+	gitCmd := exec.Command("git", args...)
 	if Verbose {
 		gitCmd.Stdout = os.Stdout
 		gitCmd.Stderr = os.Stderr
@@ -108,28 +214,101 @@ func cloneTemplate(repoURL, targetDir string) error {
 	return gitCmd.Run()
 }
 
-func cloneWithSpinner(cmd *mamba.Command, name, repoURL, targetDir string) error {
-	if Verbose {
-		cmd.PrintInfo(fmt.Sprintf("Cloning %s template...", name))
-		if err := cloneTemplate(repoURL, targetDir); err != nil {
-			return err
-		}
-		cmd.PrintSuccess(fmt.Sprintf("%s template cloned", name))
-		return nil
+// writeNewProjectConfig writes .bui.yaml for a new project, carrying over settings from an enclosing config
+func writeNewProjectConfig(backendDir, frontendDir string, commits utils.TemplateCommitsConfig) error {
+	config := &utils.ProjectConfig{Backend: backendDir, Frontend: frontendDir, TemplateCommits: commits}
+	if utils.Database() != utils.Postgres {
+		config.Database = utils.Database()
+	}
+	if utils.Project != nil {
+		config.Ports = utils.Project.Ports
+		config.PackageManager = utils.Project.PackageManager
+		config.Flags = utils.Project.Flags
+		config.PreGenerate, config.PostGenerate = utils.Project.PreGenerate, utils.Project.PostGenerate
+		config.PreDestroy, config.PostDestroy = utils.Project.PreDestroy, utils.Project.PostDestroy
+	}
+	return utils.WriteProjectConfig(".", config)
+}
+
+// isCloneURL reports whether repo is a full clone URL rather than a GitHub repository path
+func isCloneURL(repo string) bool {
+	return strings.Contains(repo, "://") || strings.HasPrefix(repo, "git@")
+}
+
+// sshRepoURL returns the SSH clone URL for a GitHub repository path
+func sshRepoURL(repo string) string {
+	if isCloneURL(repo) {
+		return repo
+	}
+	return fmt.Sprintf("git@github.com:%s.git", repo)
+}
+
+// httpsRepoURL returns the HTTPS clone URL for a GitHub repository path
+func httpsRepoURL(repo string) string {
+	if isCloneURL(repo) {
+		return repo
+	}
+	return fmt.Sprintf("https://github.com/%s.git", repo)
+}
+
+// templateCloneSSH decides whether templates are cloned over SSH; --https wins over clone.protocol
+func templateCloneSSH() bool {
+	switch utils.User.Clone.Protocol {
+	case "https":
+		return false
+	case "ssh":
+		return !forceHTTPS
+	default:
+		return !forceHTTPS && sshAvailable()
 	}
+}
 
-	// Show spinner (non-blocking)
+// sshAvailable checks whether GitHub is reachable over SSH without prompting. The probe never
+// writes to known_hosts: a github.com host key that isn't already trusted means cloning over HTTPS.
+func sshAvailable() bool {
+	if _, err := exec.LookPath("ssh"); err != nil {
+		return false
+	}
+
+	// ls-remote fails fast when no key is loaded instead of asking for a password
+	checkCmd := exec.Command("git", "ls-remote", "--heads", sshRepoURL(backendTemplateRepo))
+	checkCmd.Env = append(os.Environ(),
+		"GIT_SSH_COMMAND=ssh -o BatchMode=yes -o ConnectTimeout=5 -o StrictHostKeyChecking=yes",
+		"GIT_TERMINAL_PROMPT=0",
+	)
+	return checkCmd.Run() == nil
+}
+
+func cloneWithSpinner(cmd *mamba.Command, name, repo, targetDir string, useSSH, shallow bool) error {
 	cmd.PrintInfo(fmt.Sprintf("Cloning %s template...", name))
 
 	// Clone without spinner wrapper to avoid deadlocks
-	if err := cloneTemplate(repoURL, targetDir); err != nil {
+	if useSSH && !isCloneURL(repo) {
+		if err := cloneTemplate(sshRepoURL(repo), targetDir, shallow); err == nil {
+			if Verbose {
+				cmd.PrintSuccess(fmt.Sprintf("%s template cloned", name))
+			}
+			return nil
+		}
+		// Fall back to HTTPS, clearing any partial clone first
+		cmd.PrintWarning(fmt.Sprintf("SSH clone of %s template failed, retrying over HTTPS", name))
+		os.RemoveAll(targetDir)
+	}
+
+	if err := cloneTemplate(httpsRepoURL(repo), targetDir, shallow); err != nil {
 		return fmt.Errorf("failed to clone %s: %w", name, err)
 	}
 
+	if Verbose {
+		cmd.PrintSuccess(fmt.Sprintf("%s template cloned", name))
+	}
+
 	return nil
 }
 
-func updateGoImports(dir, projectName string) error {
+// updateGoImports rewrites "base/..." imports to the new module path and
+// refreshes the Swagger annotations in main.go with the project name
+func updateGoImports(dir, projectName, modulePath string) error {
 	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -146,8 +325,8 @@ func updateGoImports(dir, projectName string) error {
 		}
 
 		contentStr := string(content)
-		// Replace all "base/" imports with "projectName/"
-		newContent := strings.ReplaceAll(contentStr, "\"base/", fmt.Sprintf("\"%s/", projectName))
+		// Replace all "base/" imports with the module path (may contain domain and slashes)
+		newContent := strings.ReplaceAll(contentStr, "\"base/", fmt.Sprintf("\"%s/", modulePath))
 
 		// Also update Swagger documentation comments in main.go
 		if strings.HasSuffix(path, "/main.go") || strings.HasSuffix(path, "/Main.go") {
@@ -254,7 +433,24 @@ func updateFrontendProjectStrings(dir, projectName string) error {
 	return nil
 }
 
-func updateProjectFiles(cmd *mamba.Command, projectName, backendDir, frontendDir string) error {
+func updateProjectFiles(cmd *mamba.Command, projectName, modulePath, backendDir, frontendDir string) error {
+	if backendDir != "" {
+		if err := updateBackendProjectFiles(cmd, projectName, modulePath, backendDir); err != nil {
+			return err
+		}
+	}
+
+	if frontendDir != "" {
+		if err := updateFrontendProjectFiles(cmd, projectName, frontendDir); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateBackendProjectFiles renames the Go module and rewrites imports
+func updateBackendProjectFiles(cmd *mamba.Command, projectName, modulePath, backendDir string) error {
 	// Update backend go.mod
 	goModPath := filepath.Join(backendDir, "go.mod")
 	if _, err := os.Stat(goModPath); err == nil {
@@ -267,8 +463,7 @@ func updateProjectFiles(cmd *mamba.Command, projectName, backendDir, frontendDir
 			return fmt.Errorf("failed to read go.mod: %w", err)
 		}
 
-		contentStr := string(content)
-		contentStr = strings.ReplaceAll(contentStr, "module base", fmt.Sprintf("module %s", projectName))
+		contentStr := rewriteModuleDirective(string(content), modulePath)
 
 		if err := os.WriteFile(goModPath, []byte(contentStr), 0644); err != nil {
 			return fmt.Errorf("failed to write go.mod: %w", err)
@@ -279,17 +474,22 @@ func updateProjectFiles(cmd *mamba.Command, projectName, backendDir, frontendDir
 		}
 	}
 
-	// Update all .go files in backend to replace "base/" imports with projectName
+	// Update all .go files in backend to replace "base/" imports with the module path
 	if Verbose {
 		cmd.PrintInfo("Updating Go import statements...")
 	}
-	if err := updateGoImports(backendDir, projectName); err != nil {
+	if err := updateGoImports(backendDir, projectName, modulePath); err != nil {
 		return fmt.Errorf("failed to update Go imports: %w", err)
 	}
 	if Verbose {
 		cmd.PrintSuccess("Updated Go import statements")
 	}
 
+	return nil
+}
+
+// updateFrontendProjectFiles renames the package and project-specific strings
+func updateFrontendProjectFiles(cmd *mamba.Command, projectName, frontendDir string) error {
 	// Update frontend package.json
 	packageJsonPath := filepath.Join(frontendDir, "package.json")
 	if _, err := os.Stat(packageJsonPath); err == nil {
@@ -330,6 +530,24 @@ func updateProjectFiles(cmd *mamba.Command, projectName, backendDir, frontendDir
 	return nil
 }
 
+// setDatabaseEnv writes the connection settings of a --db other than the template's PostgreSQL
+// to the backend's .env and .env.sample
+func setDatabaseEnv(cmd *mamba.Command, projectName, backendDir string) error {
+	database := utils.Database()
+	if backendDir == "" || database == utils.Postgres {
+		return nil
+	}
+	for _, name := range []string{".env.sample", ".env"} {
+		if err := utils.SetEnvValues(filepath.Join(backendDir, name), utils.DatabaseEnv(database, projectName)); err != nil {
+			return err
+		}
+	}
+	if Verbose {
+		cmd.PrintSuccess(fmt.Sprintf("Configured the backend for %s", utils.DatabaseLabel(database)))
+	}
+	return nil
+}
+
 func copyEnvFile(cmd *mamba.Command, backendDir, frontendDir string) error {
 	// Copy .env.sample to .env for backend (backend uses .env.sample)
 	if Verbose {
@@ -340,7 +558,7 @@ func copyEnvFile(cmd *mamba.Command, backendDir, frontendDir string) error {
 	backendEnv := filepath.Join(backendDir, ".env")
 
 	// Check if .env.sample exists (backend)
-	if _, err := os.Stat(backendEnvSample); err == nil {
+	if _, err := os.Stat(backendEnvSample); backendDir != "" && err == nil {
 		if err := copyFileNew(backendEnvSample, backendEnv); err != nil {
 			cmd.PrintWarning(fmt.Sprintf("Failed to copy backend .env: %v", err))
 		} else if Verbose {
@@ -352,7 +570,7 @@ func copyEnvFile(cmd *mamba.Command, backendDir, frontendDir string) error {
 	frontendEnvExample := filepath.Join(frontendDir, ".env.example")
 	frontendEnv := filepath.Join(frontendDir, ".env")
 
-	if _, err := os.Stat(frontendEnvExample); err == nil {
+	if _, err := os.Stat(frontendEnvExample); frontendDir != "" && err == nil {
 		if err := copyFileNew(frontendEnvExample, frontendEnv); err != nil {
 			cmd.PrintWarning(fmt.Sprintf("Failed to copy frontend .env: %v", err))
 		} else if Verbose {
@@ -364,25 +582,35 @@ func copyEnvFile(cmd *mamba.Command, backendDir, frontendDir string) error {
 		cmd.PrintSuccess("Environment setup complete")
 	}
 
-	// Check if bun is installed
-	if _, err := exec.LookPath("bun"); err != nil {
-		cmd.PrintWarning("Bun is not installed. Skipping frontend dependency installation.")
-		cmd.PrintInfo("Please install Bun from https://bun.sh and run 'bun install' in the frontend directory.")
+	// Nothing to install without a frontend
+	if frontendDir == "" {
 		return nil
 	}
 
-	// Run bun install
+	// Check if the package manager is installed
+	manager := utils.Project.PackageManagerName()
+	if _, err := exec.LookPath(manager); err != nil {
+		cmd.PrintWarning(fmt.Sprintf("%s is not installed. Skipping frontend dependency installation.", manager))
+		if manager == utils.DefaultPackageManager {
+			cmd.PrintInfo("Please install Bun from https://bun.sh and run 'bun install' in the frontend directory.")
+		} else {
+			cmd.PrintInfo(fmt.Sprintf("Please install %s and run '%s install' in the frontend directory.", manager, manager))
+		}
+		return nil
+	}
+
+	// Run the package manager's install
 	if Verbose {
 		cmd.PrintInfo("Installing frontend dependencies...")
 	}
-	bunInstallCmd := exec.Command("bun", "install")
-	bunInstallCmd.Dir = frontendDir
-	bunInstallCmd.Stdout = os.Stdout
-	bunInstallCmd.Stderr = os.Stderr
+	installCmd := exec.Command(manager, "install")
+	installCmd.Dir = frontendDir
+	installCmd.Stdout = os.Stdout
+	installCmd.Stderr = os.Stderr
 
-	if err := bunInstallCmd.Run(); err != nil {
-		cmd.PrintWarning(fmt.Sprintf("Failed to run bun install: %v", err))
-		cmd.PrintInfo(fmt.Sprintf("Please run 'bun install' manually in %s", frontendDir))
+	if err := installCmd.Run(); err != nil {
+		cmd.PrintWarning(fmt.Sprintf("Failed to run %s install: %v", manager, err))
+		cmd.PrintInfo(fmt.Sprintf("Please run '%s install' manually in %s", manager, frontendDir))
 		return nil
 	}
 
@@ -398,8 +626,12 @@ func cleanupAndInit(cmd *mamba.Command, projectName, backendDir, frontendDir str
 	if Verbose {
 		cmd.PrintInfo("Cleaning up template git histories...")
 	}
-	os.RemoveAll(filepath.Join(backendDir, ".git"))
-	os.RemoveAll(filepath.Join(frontendDir, ".git"))
+	if backendDir != "" {
+		os.RemoveAll(filepath.Join(backendDir, ".git"))
+	}
+	if frontendDir != "" {
+		os.RemoveAll(filepath.Join(frontendDir, ".git"))
+	}
 
 	// Initialize new git repository
 	if !Verbose {
@@ -414,10 +646,13 @@ func cleanupAndInit(cmd *mamba.Command, projectName, backendDir, frontendDir str
 		cmd.PrintSuccess("Git repository initialized")
 	}
 
-	if Verbose {
-		cmd.PrintInfo("Creating project README...")
+	// The combined README only makes sense when both halves exist
+	if backendDir != "" && frontendDir != "" {
+		if Verbose {
+			cmd.PrintInfo("Creating project README...")
+		}
+		createProjectReadme(projectName, backendDir, frontendDir)
 	}
-	createProjectReadme(projectName, backendDir, frontendDir)
 
 	return nil
 }
@@ -477,7 +712,7 @@ Base Stack project created with [Bui CLI](https://github.com/base-al/bui).
 
 - Go 1.24+
 - Bun (for frontend)
-- PostgreSQL
+- %s
 - Redis (optional)
 
 ### Backend Setup
@@ -536,15 +771,16 @@ bui g frontend product name:string price:float
 ## License
 
 MIT
-`, projectName, backendDir, frontendDir, backendDir, frontendDir)
+`, projectName, backendDir, frontendDir, utils.DatabaseLabel(utils.Database()), backendDir, frontendDir)
+
+	if author := utils.User.AuthorLine(); author != "" {
+		readme = strings.Replace(readme, "\n## License\n", fmt.Sprintf("\n## Author\n\n%s\n\n## License\n", author), 1)
+	}
 
 	os.WriteFile("README.md", []byte(readme), 0644)
 }
 
-func printSuccessMessage(cmd *mamba.Command, projectName string) {
-	backendDir := projectName + "-api"
-	frontendDir := projectName + "-app"
-
+func printSuccessMessage(cmd *mamba.Command, projectName, backendDir, frontendDir string) {
 	cmd.PrintInfo("")
 	cmd.PrintSuccess(fmt.Sprintf("Project '%s' created successfully!", projectName))
 	cmd.PrintInfo("")
@@ -555,23 +791,37 @@ func printSuccessMessage(cmd *mamba.Command, projectName string) {
 	cmd.PrintInfo(fmt.Sprintf("Navigate to project: cd %s", projectName))
 	cmd.PrintInfo("")
 
-	cmd.PrintHeader("Backend Setup")
-	cmd.PrintBullet(fmt.Sprintf("cd %s", backendDir))
-	cmd.PrintBullet("cp .env.sample .env")
-	cmd.PrintBullet("Edit .env with your database credentials")
-	cmd.PrintBullet("go mod tidy")
-	cmd.PrintBullet("bui start")
-	cmd.PrintInfo("")
+	if backendDir != "" {
+		cmd.PrintHeader("Backend Setup")
+		cmd.PrintBullet(fmt.Sprintf("cd %s", backendDir))
+		cmd.PrintBullet("cp .env.sample .env")
+		cmd.PrintBullet("Edit .env with your database credentials")
+		cmd.PrintBullet("go mod tidy")
+		cmd.PrintBullet("bui start")
+		cmd.PrintInfo("")
+	}
 
-	cmd.PrintHeader("Frontend Setup")
-	cmd.PrintBullet(fmt.Sprintf("cd %s", frontendDir))
-	cmd.PrintBullet("bun install")
-	cmd.PrintBullet("bun dev")
-	cmd.PrintInfo("")
+	if frontendDir != "" {
+		cmd.PrintHeader("Frontend Setup")
+		cmd.PrintBullet(fmt.Sprintf("cd %s", frontendDir))
+		manager := utils.Project.PackageManagerName()
+		cmd.PrintBullet(manager + " install")
+		cmd.PrintBullet(manager + " run dev")
+		cmd.PrintInfo("")
+	}
 
 	cmd.PrintHeader("Quick Start")
-	cmd.PrintBullet("Start both servers: bui dev")
-	cmd.PrintBullet("Generate module: bui g product name:string price:float")
+	switch {
+	case backendDir != "" && frontendDir != "":
+		cmd.PrintBullet("Start both servers: bui dev")
+		cmd.PrintBullet("Generate module: bui g product name:string price:float")
+	case backendDir != "":
+		cmd.PrintBullet("Start the API: bui dev")
+		cmd.PrintBullet("Generate module: bui g backend product name:string price:float")
+	default:
+		cmd.PrintBullet("Start the admin: bui dev")
+		cmd.PrintBullet("Generate module: bui g frontend product name:string price:float")
+	}
 	cmd.PrintInfo("")
 
 	cmd.PrintSuccess("Happy coding!")
@@ -593,6 +843,18 @@ func isValidProjectName(name string) bool {
 	return true
 }
 
+// rewriteModuleDirective replaces the module line of a go.mod file
+func rewriteModuleDirective(goMod, modulePath string) string {
+	lines := strings.Split(goMod, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "module ") {
+			lines[i] = "module " + modulePath
+			break
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 func cleanup(projectName string) {
 	os.Chdir("..")
 	os.RemoveAll(projectName)
@@ -0,0 +1,207 @@
+package commands
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// defaultOpenAPIPort is the port bui openapi --serve listens on when --port isn't given
+const defaultOpenAPIPort = 8090
+
+var (
+	// openapiOutput is the path the document is written to; a .yaml or .yml path writes YAML
+	openapiOutput string
+
+	// openapiServe serves the document with a viewer on a local port
+	openapiServe bool
+	openapiPort  int
+
+	// openapiViewer is the viewer --serve shows: redoc or swagger
+	openapiViewer string
+
+	// openapiOpen opens the viewer in the browser
+	openapiOpen bool
+)
+
+var openapiCmd = &mamba.Command{
+	Use:   "openapi",
+	Short: "Export the backend's API as an OpenAPI 3 document",
+	Long: `Generate the backend's Swagger docs with swag, convert them to a clean OpenAPI 3 document
+and write it to a file or serve it with a Redoc or Swagger UI viewer.
+
+swag writes Swagger 2.0, which is converted to OpenAPI 3.0: body and form parameters become
+request bodies, schemas move to components.schemas and lose their Go package prefix
+(models.Product becomes Product) and empty info fields are dropped.
+
+This works without starting the backend, unlike bui start --docs. With --serve, the document
+is written only when --output is given too.
+
+Examples:
+  bui openapi                           # Write openapi.json
+  bui openapi -o docs/openapi.yaml      # Write YAML
+  bui openapi --serve --open            # View it with Redoc
+  bui openapi --serve --viewer swagger --port 9000`,
+	Run: runOpenAPI,
+}
+
+func init() {
+	rootCmd.AddCommand(openapiCmd)
+	openapiCmd.Flags().StringVarP(&openapiOutput, "output", "o", "openapi.json", "Path to write the document to (.json, .yaml or .yml)")
+	openapiCmd.Flags().BoolVar(&openapiServe, "serve", false, "Serve the document with a viewer instead of writing it")
+	openapiCmd.Flags().IntVar(&openapiPort, "port", 0, fmt.Sprintf("Port for --serve (default %d)", defaultOpenAPIPort))
+	openapiCmd.Flags().StringVar(&openapiViewer, "viewer", "redoc", "Viewer for --serve: redoc or swagger")
+	openapiCmd.Flags().BoolVar(&openapiOpen, "open", false, "Open the viewer in the browser")
+}
+
+func runOpenAPI(cmd *mamba.Command, args []string) {
+	if openapiViewer != "redoc" && openapiViewer != "swagger" {
+		cmd.PrintError(fmt.Sprintf("Invalid --viewer value: %s", openapiViewer))
+		cmd.PrintInfo("Use --viewer redoc or --viewer swagger")
+		os.Exit(1)
+	}
+
+	backendDir := findBackendDir()
+	if backendDir == "" {
+		cmd.PrintError("No backend directory found")
+		cmd.PrintInfo("Run bui openapi from your project root or the backend directory")
+		os.Exit(1)
+	}
+
+	cmd.PrintInfo("Generating Swagger docs...")
+	if err := generateSwaggerDocs(cmd, backendDir); err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to generate Swagger docs: %v", err))
+		cmd.PrintInfo("Check the swag annotations, or run swag init in the backend to see its output")
+		os.Exit(1)
+	}
+	swaggerPath := filepath.Join(backendDir, "swagger", "swagger.json")
+	content, err := os.ReadFile(swaggerPath)
+	if err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to read %s: %v", swaggerPath, err))
+		os.Exit(1)
+	}
+	doc, err := utils.ConvertToOpenAPI3(content)
+	if err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to convert %s: %v", swaggerPath, err))
+		os.Exit(1)
+	}
+
+	if !openapiServe || cmd.Flags().Changed("output") {
+		ext := strings.ToLower(filepath.Ext(openapiOutput))
+		data, err := doc.Encode(ext == ".yaml" || ext == ".yml")
+		if err != nil {
+			cmd.PrintError(fmt.Sprintf("Failed to encode the document: %v", err))
+			os.Exit(1)
+		}
+		if dir := filepath.Dir(openapiOutput); dir != "." {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				cmd.PrintError(fmt.Sprintf("Failed to create %s: %v", dir, err))
+				os.Exit(1)
+			}
+		}
+		if err := os.WriteFile(openapiOutput, data, 0644); err != nil {
+			cmd.PrintError(fmt.Sprintf("Failed to write %s: %v", openapiOutput, err))
+			os.Exit(1)
+		}
+		cmd.PrintSuccess(fmt.Sprintf("Wrote OpenAPI %s document to %s (%d paths, %d schemas)",
+			doc.OpenAPI, openapiOutput, len(doc.Paths), doc.SchemaCount()))
+	}
+
+	if openapiServe {
+		serveOpenAPI(cmd, doc)
+	}
+}
+
+// serveOpenAPI serves the document at /openapi.json and /openapi.yaml and the viewer at /
+func serveOpenAPI(cmd *mamba.Command, doc *utils.OpenAPIDocument) {
+	jsonData, err := doc.Encode(false)
+	if err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to encode the document: %v", err))
+		os.Exit(1)
+	}
+	yamlData, err := doc.Encode(true)
+	if err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to encode the document: %v", err))
+		os.Exit(1)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(jsonData)
+	})
+	mux.HandleFunc("/openapi.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Write(yamlData)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, openAPIViewerPage(openapiViewer, doc))
+	})
+
+	port := resolveDevPort(cmd, "Docs", openapiPort, defaultOpenAPIPort)
+	url := fmt.Sprintf("http://localhost:%d", port)
+	cmd.PrintSuccess(fmt.Sprintf("Serving the API docs at %s", url))
+	cmd.PrintInfo(fmt.Sprintf("Document: %s/openapi.json and %s/openapi.yaml", url, url))
+	cmd.PrintInfo("Press Ctrl+C to stop")
+
+	if openapiOpen {
+		if err := openBrowser(url); err != nil {
+			cmd.PrintWarning("Could not open the browser: " + err.Error())
+		}
+	}
+	if err := http.ListenAndServe(fmt.Sprintf(":%d", port), mux); err != nil {
+		cmd.PrintError("Docs server stopped: " + err.Error())
+		os.Exit(1)
+	}
+}
+
+// openAPIViewerPage returns the HTML page of the viewer, loaded from the jsDelivr CDN
+func openAPIViewerPage(viewer string, doc *utils.OpenAPIDocument) string {
+	title := "API"
+	if info, ok := doc.Info.(map[string]any); ok {
+		if name, ok := info["title"].(string); ok {
+			title = name
+		}
+	}
+	title = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(title)
+
+	if viewer == "swagger" {
+		return `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>` + title + `</title>
+  <link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>SwaggerUIBundle({ url: '/openapi.json', dom_id: '#swagger-ui' })</script>
+</body>
+</html>
+`
+	}
+	return `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>` + title + `</title>
+  <style>body { margin: 0 }</style>
+</head>
+<body>
+  <redoc spec-url="/openapi.json"></redoc>
+  <script src="https://cdn.jsdelivr.net/npm/redoc@2/bundles/redoc.standalone.js"></script>
+</body>
+</html>
+`
+}
@@ -0,0 +1,207 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-al/bui/version"
+	"github.com/base-go/mamba"
+)
+
+// Plugins are executables on PATH. "bui <name> ..." runs bui-<name> when bui has no such command,
+// and "bui g <name> ..." runs the generator bui-gen-<name>.
+const (
+	pluginPrefix          = "bui-"
+	generatorPluginPrefix = "bui-gen-"
+)
+
+// pluginPayloadVersion is bumped whenever the JSON sent to plugins changes incompatibly
+const pluginPayloadVersion = 1
+
+// pluginPayload is the JSON a plugin receives on stdin
+type pluginPayload struct {
+	Version    int                     `json:"version"`
+	BuiVersion string                  `json:"buiVersion"`
+	Plugin     string                  `json:"plugin"`
+	Args       []string                `json:"args"`
+	Naming     *utils.NamingConvention `json:"naming,omitempty"`    // Generators: the module being generated
+	FieldDefs  []string                `json:"fieldDefs,omitempty"` // Generators: fields as given, e.g. price:float
+	Fields     []utils.Field           `json:"fields,omitempty"`    // Generators: parsed fields
+	Project    pluginProject           `json:"project"`
+	Options    pluginOptions           `json:"options"`
+}
+
+// pluginProject holds absolute project paths; directories that weren't found are empty
+type pluginProject struct {
+	Root     string `json:"root"`
+	Backend  string `json:"backend"`
+	Frontend string `json:"frontend"`
+	GoModule string `json:"goModule"`
+}
+
+// pluginOptions passes on global flags the plugin should honour
+type pluginOptions struct {
+	DryRun  bool `json:"dryRun"`
+	Force   bool `json:"force"`
+	Verbose bool `json:"verbose"`
+}
+
+var pluginsCmd = &mamba.Command{
+	Use:   "plugins",
+	Short: "List installed plugins",
+	Long: `List bui plugins found on PATH.
+
+A plugin is any executable named bui-<name>; "bui <name> [args]" runs it when bui
+has no built-in <name> command. Generators are named bui-gen-<name> and run with
+"bui g <name> [module] [field:type...]".
+
+Plugins receive JSON on stdin with the arguments, project paths (root, backend,
+frontend, Go module) and, for generators, the module's naming convention and
+parsed fields. Their output and exit code are passed through.`,
+	Run: listPlugins,
+}
+
+func init() {
+	rootCmd.AddCommand(pluginsCmd)
+}
+
+// findPlugin returns the path of the executable prefix+name on PATH, or ""
+func findPlugin(prefix, name string) string {
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, `/\`) {
+		return ""
+	}
+	path, err := exec.LookPath(prefix + name)
+	if err != nil {
+		return ""
+	}
+	return path
+}
+
+// dispatchPlugin runs a command plugin when args name no built-in command.
+// It reports whether a plugin handled the invocation.
+func dispatchPlugin(args []string) (bool, error) {
+	if len(args) == 0 {
+		return false, nil
+	}
+	if cmd, _, err := rootCmd.Find(args); err != nil || cmd != rootCmd {
+		return false, nil
+	}
+
+	path := findPlugin(pluginPrefix, args[0])
+	if path == "" {
+		return false, nil
+	}
+	return true, runPlugin(path, newPluginPayload(args[0], args[1:]))
+}
+
+// runGeneratorPlugin runs bui-gen-<name> for "bui g <name> [module] [field:type...]"
+func runGeneratorPlugin(cmd *mamba.Command, path string, args []string) {
+	payload := newPluginPayload(args[0], args[1:])
+	if len(args) > 1 {
+		data := utils.NewTemplateData(args[1], args[2:])
+		payload.Naming = data.NamingConvention
+		payload.FieldDefs = args[2:]
+		payload.Fields = data.Fields
+	}
+
+	if err := runPlugin(path, payload); err != nil {
+		cmd.PrintError(fmt.Sprintf("Generator %s failed: %v", args[0], err))
+		os.Exit(1)
+	}
+}
+
+// newPluginPayload fills in what every plugin receives
+func newPluginPayload(name string, args []string) *pluginPayload {
+	payload := &pluginPayload{
+		Version:    pluginPayloadVersion,
+		BuiVersion: version.Version,
+		Plugin:     name,
+		Args:       args,
+		Options:    pluginOptions{DryRun: utils.DryRun, Force: utils.Force, Verbose: Verbose},
+	}
+	if payload.Args == nil {
+		payload.Args = []string{}
+	}
+
+	payload.Project.Root, _ = os.Getwd()
+	if root := utils.Project.Root(); root != "" {
+		payload.Project.Root = root
+	}
+
+	backendDir, frontendDir := detectProjectDirs()
+	if _, err := os.Stat(filepath.Join(backendDir, "go.mod")); err == nil {
+		payload.Project.Backend, _ = filepath.Abs(backendDir)
+		payload.Project.GoModule = utils.GetGoModuleNameIn(backendDir)
+	}
+	if _, err := os.Stat(filepath.Join(frontendDir, "nuxt.config.ts")); err == nil {
+		payload.Project.Frontend, _ = filepath.Abs(frontendDir)
+	}
+	return payload
+}
+
+// runPlugin executes a plugin with the payload on stdin, exiting with the plugin's exit code on failure
+func runPlugin(path string, payload *pluginPayload) error {
+	input, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	pluginCmd := exec.Command(path, payload.Args...)
+	pluginCmd.Stdin = strings.NewReader(string(input))
+	pluginCmd.Stdout = os.Stdout
+	pluginCmd.Stderr = os.Stderr
+
+	var exitErr *exec.ExitError
+	if err := pluginCmd.Run(); errors.As(err, &exitErr) {
+		os.Exit(exitErr.ExitCode())
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// listPlugins prints the bui-* executables on PATH
+func listPlugins(cmd *mamba.Command, args []string) {
+	found := map[string]string{}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		matches, _ := filepath.Glob(filepath.Join(dir, pluginPrefix+"*"))
+		for _, match := range matches {
+			name := strings.TrimSuffix(filepath.Base(match), filepath.Ext(match))
+			if info, err := os.Stat(match); err != nil || info.IsDir() || info.Mode()&0111 == 0 {
+				continue
+			}
+			// The first match on PATH is the one that runs
+			if _, ok := found[name]; !ok {
+				found[name] = match
+			}
+		}
+	}
+
+	if len(found) == 0 {
+		cmd.PrintInfo("No plugins found on PATH")
+		cmd.PrintInfo("Install an executable named bui-<name> (or bui-gen-<name> for a generator)")
+		return
+	}
+
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	cmd.PrintHeader(fmt.Sprintf("Plugins (%d)", len(names)))
+	for _, name := range names {
+		if generator, ok := strings.CutPrefix(name, generatorPluginPrefix); ok {
+			cmd.PrintBullet(fmt.Sprintf("bui g %s  (%s)", generator, found[name]))
+		} else {
+			cmd.PrintBullet(fmt.Sprintf("bui %s  (%s)", strings.TrimPrefix(name, pluginPrefix), found[name]))
+		}
+	}
+}
@@ -2,23 +2,56 @@ package commands
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 
+	"github.com/base-al/bui/utils"
 	"github.com/base-go/mamba"
 )
 
+var (
+	// previewPort overrides the port the server listens on
+	previewPort int
+
+	// previewEnvFile is loaded on top of the dist .env
+	previewEnvFile string
+
+	// previewFrontend serves public/ with a built-in static file server
+	previewFrontend bool
+
+	// previewFrontendPort is the static file server's port
+	previewFrontendPort int
+
+	// previewOpen opens the browser once the server is up
+	previewOpen bool
+)
+
 var previewCmd = &mamba.Command{
 	Use:   "preview",
 	Short: "Preview the production build",
-	Long:  `Preview the production build by running the server binary from the dist directory.`,
-	Run:   runPreview,
+	Long: `Preview the production build by running the server binary from the dist directory.
+
+--env-file loads another env file on top of the dist .env (which is then optional), and
+--port overrides SERVER_PORT. --frontend serves the generated public/ directory on its
+own port, falling back to 200.html/index.html for client-side routes.
+
+Examples:
+  bui preview
+  bui preview --port 9000 --env-file .env.staging
+  bui preview --frontend --open`,
+	Run: runPreview,
 }
 
 func init() {
 	rootCmd.AddCommand(previewCmd)
+	previewCmd.Flags().IntVar(&previewPort, "port", 0, "Port for the server (default: SERVER_PORT from the env, else 8000)")
+	previewCmd.Flags().StringVar(&previewEnvFile, "env-file", "", "Env file to load on top of the dist .env")
+	previewCmd.Flags().BoolVar(&previewFrontend, "frontend", false, "Serve public/ with a built-in static file server")
+	previewCmd.Flags().IntVar(&previewFrontendPort, "frontend-port", 0, fmt.Sprintf("Port for --frontend (default %d)", utils.DefaultFrontendPort))
+	previewCmd.Flags().BoolVar(&previewOpen, "open", false, "Open the browser once the server is ready")
 }
 
 func runPreview(cmd *mamba.Command, args []string) {
@@ -29,31 +62,79 @@ func runPreview(cmd *mamba.Command, args []string) {
 		os.Exit(1)
 	}
 
-	// Check if server binary exists
-	serverPath := filepath.Join(distDir, "server")
+	// Check if server binary exists; a multi-target build names it after the platform
+	binary := "server"
+	if host := (buildTarget{}).binaryName(true); !fileExistsPreview(filepath.Join(distDir, binary)) && fileExistsPreview(filepath.Join(distDir, host)) {
+		binary = host
+	}
+	serverPath := filepath.Join(distDir, binary)
 	if !fileExistsPreview(serverPath) {
 		cmd.PrintError(fmt.Sprintf("Server binary not found at %s. Run 'bui build' first.", serverPath))
 		os.Exit(1)
 	}
 
-	// Check if .env exists
+	// The server reads the dist .env; an --env-file is passed in its environment and takes precedence
+	env := os.Environ()
 	envPath := filepath.Join(distDir, ".env")
-	if !fileExistsPreview(envPath) {
+	if previewEnvFile != "" {
+		if !fileExistsPreview(previewEnvFile) {
+			cmd.PrintError("Env file not found: " + previewEnvFile)
+			os.Exit(1)
+		}
+		for key, value := range readEnvFile(previewEnvFile) {
+			env = append(env, key+"="+value)
+		}
+		envPath = previewEnvFile
+	} else if !fileExistsPreview(envPath) {
 		cmd.PrintWarning("No .env file found in " + distDir)
-		cmd.PrintInfo("Copy .env.example to .env and configure it for preview")
+		cmd.PrintInfo("Copy .env.example to .env and configure it for preview, or pass --env-file")
 		os.Exit(1)
 	}
 
+	port := previewPort
+	if port != 0 {
+		env = append(env, fmt.Sprintf("SERVER_PORT=%d", port), fmt.Sprintf("PORT=%d", port))
+	} else if port = envPort(envPath, backendPortEnvKeys); port == 0 {
+		port = utils.DefaultBackendPort
+	}
+	openURL := fmt.Sprintf("http://localhost:%d", port)
+
+	if previewFrontend {
+		publicDir := filepath.Join(distDir, "public")
+		if !dirExistsPreview(publicDir) {
+			cmd.PrintError("No public/ directory in " + distDir + ". Build the frontend with 'bui build' first.")
+			os.Exit(1)
+		}
+		frontendPort := resolveDevPort(cmd, "Frontend", previewFrontendPort, utils.DefaultFrontendPort)
+		go func() {
+			if err := http.ListenAndServe(fmt.Sprintf(":%d", frontendPort), staticFileServer(publicDir)); err != nil {
+				cmd.PrintError("Static file server stopped: " + err.Error())
+			}
+		}()
+		openURL = fmt.Sprintf("http://localhost:%d", frontendPort)
+		cmd.PrintInfo("Serving " + publicDir + " at " + openURL)
+	}
+
 	cmd.PrintSuccess("Starting production preview server...")
 	cmd.PrintInfo(fmt.Sprintf("Running from: %s", distDir))
+	cmd.PrintInfo(fmt.Sprintf("Server: http://localhost:%d", port))
 	cmd.PrintInfo("Press Ctrl+C to stop\n")
 
+	if previewOpen {
+		go func() {
+			waitForBackend(cmd, port)
+			if err := openBrowser(openURL); err != nil {
+				cmd.PrintWarning("Could not open the browser: " + err.Error())
+			}
+		}()
+	}
+
 	// Run the server
-	serverCmd := exec.Command("./server")
+	serverCmd := exec.Command("./" + binary)
 	serverCmd.Dir = distDir
 	serverCmd.Stdout = os.Stdout
 	serverCmd.Stderr = os.Stderr
-	serverCmd.Env = os.Environ()
+	serverCmd.Env = env
 
 	if err := serverCmd.Run(); err != nil {
 		cmd.PrintError("Failed to run server: " + err.Error())
@@ -61,6 +142,26 @@ func runPreview(cmd *mamba.Command, args []string) {
 	}
 }
 
+// staticFileServer serves dir, answering unknown paths with 200.html or index.html so
+// client-side routes of a generated Nuxt app still load
+func staticFileServer(dir string) http.Handler {
+	files := http.FileServer(http.Dir(dir))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := filepath.Join(dir, filepath.FromSlash(filepath.Clean("/"+r.URL.Path)))
+		if _, err := os.Stat(path); err == nil {
+			files.ServeHTTP(w, r)
+			return
+		}
+		for _, fallback := range []string{"200.html", "index.html"} {
+			if fileExistsPreview(filepath.Join(dir, fallback)) {
+				http.ServeFile(w, r, filepath.Join(dir, fallback))
+				return
+			}
+		}
+		http.NotFound(w, r)
+	})
+}
+
 // findDistDir finds the dist directory (dist/ or *-dist/)
 func findDistDir() string {
 	// Check for "dist" first
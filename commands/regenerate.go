@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+var regenerateCmd = &mamba.Command{
+	Use:   "regenerate [module]",
+	Short: "Re-run the recorded generation of a module",
+	Long: `Re-run the generation of a module recorded in .bui/manifest.json, with the same fields and
+flags, e.g. after upgrading bui to pick up its new templates. Fields added with --alter since are
+generated too.
+
+The manifest keeps a hash of every file bui wrote, so files nobody edited since are replaced
+without asking. Edited files are merged with the new output from the copy of what bui wrote in
+.bui/generated, with conflict markers where both changed the same lines; --force replaces them
+instead. Replaced and merged files are saved to .bui/backups first.
+
+Examples:
+  bui regenerate product                # Regenerate app/products and its admin pages
+  bui regenerate product --dry-run      # List the files that would change
+  bui regenerate product --force        # Replace the files edited since instead of merging`,
+	Args: mamba.ExactArgs(1),
+	Run:  regenerateModule,
+}
+
+func init() {
+	rootCmd.AddCommand(regenerateCmd)
+	regenerateCmd.Flags().BoolVar(&utils.DryRun, "dry-run", false, "Show the files that would be written without touching disk")
+	regenerateCmd.Flags().BoolVar(&utils.ShowDiff, "diff", false, "Print a diff for each file during a dry run")
+	regenerateCmd.Flags().BoolVarP(&utils.Force, "force", "f", false, "Replace files edited since they were generated instead of merging them")
+}
+
+// regenerateModule re-runs the generator that last generated the module, from the project root
+func regenerateModule(cmd *mamba.Command, args []string) {
+	dir, err := os.Getwd()
+	if err != nil {
+		cmd.PrintError("Failed to get current directory")
+		os.Exit(1)
+	}
+	root := utils.ProjectRoot(dir)
+
+	manifest, err := utils.LoadManifest(root)
+	if err != nil {
+		cmd.PrintError(err.Error())
+		os.Exit(1)
+	}
+	generation, err := manifest.Latest(args[0])
+	if err != nil {
+		cmd.PrintError(err.Error())
+		cmd.PrintInfo(fmt.Sprintf("Generate the module with bui g %s first", args[0]))
+		os.Exit(1)
+	}
+
+	generator, _, err := rootCmd.Find(strings.Fields(generation.Generator))
+	if err != nil || generator == rootCmd {
+		cmd.PrintError(fmt.Sprintf("bui %s no longer exists", generation.Generator))
+		os.Exit(1)
+	}
+	for _, flag := range generation.Flags {
+		name, value, ok := strings.Cut(strings.TrimPrefix(flag, "--"), "=")
+		if !ok {
+			value = "true"
+		}
+		if name == "alter" {
+			continue
+		}
+		if err := generator.Flags().Set(name, value); err != nil {
+			cmd.PrintError(fmt.Sprintf("Cannot replay %s: %v", flag, err))
+			os.Exit(1)
+		}
+	}
+
+	// The generation ran from the project root, or from the directory it was recorded in
+	if err := os.Chdir(root); err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to change to %s: %v", root, err))
+		os.Exit(1)
+	}
+
+	cmd.PrintInfo(fmt.Sprintf("Regenerating %s, generated by bui %s on %s", generation.Module, generation.Version, generation.CreatedAt.Format("2006-01-02 15:04")))
+	if Verbose {
+		cmd.PrintInfo(generation.Command)
+	}
+	generator.Run(generator, append([]string{generation.Module}, generation.Fields...))
+}
@@ -0,0 +1,151 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/base-al/bui/commands/backend"
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+	"github.com/base-go/mamba/pkg/interactive"
+)
+
+var (
+	restoreList   bool
+	restoreYes    bool
+	restoreReason string
+)
+
+var restoreCmd = &mamba.Command{
+	Use:   "restore [module]",
+	Short: "Restore the last backup of a module",
+	Long: `Restore a module from its latest backup in .bui/backups: a module removed by
+"bui destroy", or the files of a module that a generator overwrote.
+
+Examples:
+  bui restore product                     # Restore the latest backup of the product module
+  bui restore product --reason overwrite  # Undo the last overwrite of product's files
+  bui restore --list                      # List available backups`,
+	Run: restoreModule,
+}
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+	restoreCmd.Flags().BoolVarP(&restoreList, "list", "l", false, "List available backups")
+	restoreCmd.Flags().BoolVarP(&restoreYes, "yes", "y", false, "Skip the confirmation prompt")
+	restoreCmd.Flags().StringVar(&restoreReason, "reason", "", "Only restore a backup made for this reason: destroy or overwrite (default the latest of either)")
+}
+
+// restoreModule moves the latest backed up copy of a module back into the project
+func restoreModule(cmd *mamba.Command, args []string) {
+	if restoreList {
+		listBackups(cmd)
+		return
+	}
+
+	if len(args) < 1 {
+		cmd.PrintError("module name required")
+		cmd.PrintInfo("Usage: bui restore [module] or bui restore --list")
+		os.Exit(1)
+	}
+
+	if restoreReason != "" && restoreReason != "destroy" && restoreReason != "overwrite" {
+		cmd.PrintError(fmt.Sprintf("invalid --reason %q: use destroy or overwrite", restoreReason))
+		os.Exit(1)
+	}
+
+	backup, err := utils.LatestBackup(args[0], restoreReason)
+	if err != nil {
+		cmd.PrintError(err.Error())
+		return
+	}
+
+	manifest := backup.Manifest
+	question := "Restore these files?"
+	if manifest.Reason == "overwrite" {
+		question = "Replace these files with their versions from before the overwrite?"
+	}
+	cmd.PrintInfo(fmt.Sprintf("Restoring %s %s at %s:", manifest.Module, restoreVerb(manifest.Reason), manifest.CreatedAt.Format("2006-01-02 15:04:05")))
+	for _, path := range manifest.Paths {
+		cmd.PrintBullet(path)
+	}
+
+	if !restoreYes {
+		confirmed, err := interactive.AskConfirm(question, true)
+		if err != nil || !confirmed {
+			cmd.PrintInfo("Operation cancelled")
+			return
+		}
+	}
+
+	if err := backup.Restore(); err != nil {
+		cmd.PrintError("Failed to restore: " + err.Error())
+		return
+	}
+
+	if manifest.Registered {
+		reregisterModule(cmd, manifest.BackendDir, utils.NewNamingConvention(path.Join(manifest.Namespace, manifest.Module)).DirName)
+	}
+	if manifest.Navigation != nil {
+		if _, err := utils.AddNavigationItem(manifest.FrontendDir, *manifest.Navigation); err != nil {
+			cmd.PrintWarning(fmt.Sprintf("Could not add the module back to %s: %v", utils.NavigationFile, err))
+		} else {
+			cmd.PrintSuccess("Added module back to the sidebar")
+		}
+	}
+	for _, report := range manifest.Reports {
+		if _, err := utils.AddNavigationItem(manifest.FrontendDir, report); err != nil {
+			cmd.PrintWarning(fmt.Sprintf("Could not add the %s report back to %s: %v", report.Label, utils.NavigationFile, err))
+		} else {
+			cmd.PrintSuccess("Added the " + report.Label + " report back to the sidebar")
+		}
+	}
+	for _, widget := range manifest.Widgets {
+		if _, err := utils.AddDashboardWidget(manifest.FrontendDir, widget); err != nil {
+			cmd.PrintWarning(fmt.Sprintf("Could not add the %s widget back to %s: %v", widget.ID, utils.DashboardFile, err))
+		} else {
+			cmd.PrintSuccess("Added the " + widget.ID + " widget back to the dashboard")
+		}
+	}
+
+	cmd.PrintSuccess("Module restored: " + manifest.Module)
+}
+
+// restoreVerb says what happened to the files of a backup made for reason
+func restoreVerb(reason string) string {
+	if reason == "overwrite" {
+		return "files overwritten"
+	}
+	return "destroyed"
+}
+
+// reregisterModule adds a restored module back to app/init.go
+func reregisterModule(cmd *mamba.Command, backendDir, dirName string) {
+	if err := backend.RegisterModule(cmd, backendDir, dirName); err != nil {
+		cmd.PrintWarning("Could not re-register module in app/init.go")
+		cmd.PrintInfo(fmt.Sprintf("Manually add to app/init.go: modules[\"%s\"] = %s.Init(deps)", dirName, utils.InitPackage(dirName)))
+		return
+	}
+
+	cmd.PrintSuccess("Added module back to app/init.go")
+}
+
+// listBackups prints every backup in .bui/backups, newest first
+func listBackups(cmd *mamba.Command) {
+	backups, err := utils.ListBackups()
+	if err != nil {
+		cmd.PrintError("Failed to read backups: " + err.Error())
+		return
+	}
+	if len(backups) == 0 {
+		cmd.PrintInfo("No backups found in " + utils.BackupRoot)
+		return
+	}
+
+	cmd.PrintHeader("Backups")
+	for _, backup := range backups {
+		m := backup.Manifest
+		cmd.PrintBullet(fmt.Sprintf("%s  %-9s %-20s %d paths  (%s)", m.CreatedAt.Format("2006-01-02 15:04"), m.Reason, m.Module, len(m.Paths), backup.Dir))
+	}
+}
@@ -2,10 +2,13 @@ package commands
 
 import (
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 
-	"github.com/base-al/bui/version"
+	"github.com/base-al/bui/utils"
 	"github.com/base-go/mamba"
+	"github.com/spf13/pflag"
 )
 
 var rootCmd = &mamba.Command{
@@ -13,31 +16,96 @@ var rootCmd = &mamba.Command{
 	Short: "Bui - Unified CLI for Base Stack",
 	Long: `Bui is a unified CLI tool for Base Stack development.
 Generate backend modules (Go), frontend modules (Nuxt/TypeScript), and manage your full-stack application.`,
-	PersistentPreRun: func(cmd *mamba.Command, args []string) {
-		// Skip version check for version and upgrade commands
-		if cmd.Name() != "version" && cmd.Name() != "upgrade" {
-			if release, err := version.CheckLatestVersion(); err == nil {
-				info := version.GetBuildInfo()
-				latestVersion := strings.TrimPrefix(release.TagName, "v")
-				// Only show update message if there's actually an update
-				if version.HasUpdate(info.Version, latestVersion) {
-					fmt.Print(version.FormatUpdateMessage(
-						info.Version,
-						latestVersion,
-						release.HTMLURL,
-						release.Body,
-					))
-				}
-			}
-		}
-	},
 }
 
 func Execute() error {
+	// Mamba only passes the root's persistent flags to its direct subcommands, so
+	// --no-update-check is taken out of the arguments here for commands such as bui g backend
+	args, noUpdateCheck := takeNoUpdateCheck(os.Args[1:])
+	os.Args = append(os.Args[:1], args...)
+	NoUpdateCheck = NoUpdateCheck || noUpdateCheck
+
+	loadConfig()
+	if handled, err := dispatchPlugin(os.Args[1:]); handled {
+		return err
+	}
+
+	// Mamba only runs the PersistentPreRun of the command being executed, so the update check
+	// runs here, once --no-update-check has been taken out. It runs before the command, so
+	// commands that fail or exit partway still show it. Version and upgrade check themselves.
+	if cmd, _, err := rootCmd.Find(os.Args[1:]); err == nil && cmd != versionCmd && cmd != upgradeCmd {
+		CheckForUpdate(cmd)
+	}
 	return rootCmd.Execute()
 }
 
+// takeNoUpdateCheck removes --no-update-check from args, which the command never sees, and
+// reports whether it was given. Arguments after -- are left alone.
+func takeNoUpdateCheck(args []string) ([]string, bool) {
+	rest := make([]string, 0, len(args))
+	given := false
+	for i, arg := range args {
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		name, value, hasValue := strings.Cut(arg, "=")
+		if name != "--no-update-check" {
+			rest = append(rest, arg)
+			continue
+		}
+		given = true
+		if hasValue {
+			given, _ = strconv.ParseBool(value)
+		}
+	}
+	return rest, given
+}
+
+// loadConfig reads ~/.bui/config.yaml and .bui.yaml before a command runs.
+// Mamba only runs the PersistentPreRun of the command being executed, so this can't live there.
+func loadConfig() {
+	if err := utils.LoadUserConfig(); err != nil {
+		rootCmd.PrintWarning(fmt.Sprintf("Ignoring user config: %v", err))
+	}
+	if err := utils.LoadProjectConfig(); err != nil {
+		rootCmd.PrintWarning(fmt.Sprintf("Ignoring project config: %v", err))
+		return
+	}
+
+	cmd, _, err := rootCmd.Find(os.Args[1:])
+	if err == nil {
+		applyFlagDefaults(cmd, utils.Project.FlagDefaults())
+	}
+}
+
+// applyFlagDefaults sets the .bui.yaml flag defaults on cmd before its flags are parsed,
+// so values given on the command line still win
+func applyFlagDefaults(cmd *mamba.Command, defaults map[string]string) {
+	for name, value := range defaults {
+		flag := cmd.Flags().Lookup(name)
+		for parent := cmd.Parent(); flag == nil && parent != nil; parent = parent.Parent() {
+			flag = parent.PersistentFlags().Lookup(name)
+		}
+		if flag == nil {
+			continue
+		}
+
+		var err error
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			// Replace keeps the flag unset, so a list on the command line replaces the default
+			err = slice.Replace(strings.Split(value, ","))
+		} else {
+			err = flag.Value.Set(value)
+		}
+		if err != nil {
+			cmd.PrintWarning(fmt.Sprintf("Invalid default for --%s in %s: %v", name, utils.ProjectConfigFile, err))
+		}
+	}
+}
+
 func init() {
 	// Add global verbose flag
 	rootCmd.PersistentFlags().BoolVarP(&Verbose, "verbose", "v", false, "Enable verbose output")
+	rootCmd.PersistentFlags().BoolVar(&NoUpdateCheck, "no-update-check", false, "Don't check for a newer bui release (also BUI_NO_UPDATE_CHECK=1)")
 }
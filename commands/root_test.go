@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestNoUpdateCheckOnNestedCommand(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+
+	args := os.Args
+	defer func() { os.Args, NoUpdateCheck = args, false }()
+
+	os.Args = []string{"bui", "config", "list", "--no-update-check"}
+	if err := Execute(); err != nil {
+		t.Fatalf("bui config list --no-update-check: %v", err)
+	}
+	if !NoUpdateCheck {
+		t.Error("--no-update-check on a nested command didn't turn off the update check")
+	}
+}
+
+func TestUpdateCheckOnFailingCommand(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("BUI_NO_UPDATE_CHECK", "")
+	t.Chdir(t.TempDir())
+
+	args := os.Args
+	defer func() { os.Args = args }()
+
+	// bui config get takes a key, so the command fails; the check records its attempt in the
+	// cache whether or not GitHub answers
+	os.Args = []string{"bui", "config", "get"}
+	if err := Execute(); err == nil {
+		t.Fatal("bui config get without a key succeeded")
+	}
+	if _, err := os.Stat(filepath.Join(home, ".bui", "cache", "latest-release.json")); err != nil {
+		t.Errorf("the update check didn't run for a failing command: %v", err)
+	}
+}
+
+func TestTakeNoUpdateCheck(t *testing.T) {
+	tests := []struct {
+		args  []string
+		rest  []string
+		given bool
+	}{
+		{[]string{"g", "backend", "post", "title:string"}, []string{"g", "backend", "post", "title:string"}, false},
+		{[]string{"g", "backend", "post", "--no-update-check"}, []string{"g", "backend", "post"}, true},
+		{[]string{"migrate", "status", "--no-update-check=false"}, []string{"migrate", "status"}, false},
+		{[]string{"run", "--", "--no-update-check"}, []string{"run", "--", "--no-update-check"}, false},
+	}
+	for _, tt := range tests {
+		rest, given := takeNoUpdateCheck(tt.args)
+		if given != tt.given || !slices.Equal(rest, tt.rest) {
+			t.Errorf("takeNoUpdateCheck(%q) = %q, %v; want %q, %v", tt.args, rest, given, tt.rest, tt.given)
+		}
+	}
+}
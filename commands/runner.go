@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// runnerDir is where temporary programs that run inside the backend module are written
+var runnerDir = filepath.Join(".bui", "run")
+
+// runBackendProgram writes source as a main package under the backend directory, runs it with
+// go run so it can import the project's packages, and removes it afterwards
+func runBackendProgram(backendDir, source string, args ...string) error {
+	runnerPath := filepath.Join(backendDir, runnerDir, "main.go")
+	if err := os.MkdirAll(filepath.Dir(runnerPath), 0755); err != nil {
+		return fmt.Errorf("failed to create runner: %w", err)
+	}
+	defer os.RemoveAll(filepath.Join(backendDir, runnerDir))
+
+	if err := os.WriteFile(runnerPath, []byte(source), 0644); err != nil {
+		return fmt.Errorf("failed to write runner: %w", err)
+	}
+
+	runCmd := exec.Command("go", append([]string{"run", "./" + filepath.ToSlash(runnerDir)}, args...)...)
+	runCmd.Dir = backendDir
+	runCmd.Stdin = os.Stdin
+	runCmd.Stdout = os.Stdout
+	runCmd.Stderr = os.Stderr
+	return runCmd.Run()
+}
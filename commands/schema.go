@@ -0,0 +1,125 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+var (
+	// diagramFormat is the format bui schema diagram writes: mermaid, dbml or dot
+	diagramFormat string
+
+	// diagramOutput is the file the diagram is written to instead of stdout
+	diagramOutput string
+)
+
+var schemaCmd = &mamba.Command{
+	Use:   "schema",
+	Short: "Inspect the schema of the project's models",
+	Long: `Inspect the tables and relations of the backend's models in app/models.
+
+Examples:
+  bui schema diagram
+  bui schema diagram --format dbml -o schema.dbml`,
+}
+
+var schemaDiagramCmd = &mamba.Command{
+	Use:   "diagram",
+	Short: "Export an entity-relationship diagram of the models",
+	Long: `Read the model structs in the backend's app/models and write an entity-relationship
+diagram of their tables, the join tables of many-to-many relations and the foreign keys
+of belongs_to, has_one, has_many and many_to_many relations.
+
+Formats:
+  mermaid   Mermaid erDiagram, which GitHub and GitLab render in markdown
+  dbml      DBML for dbdiagram.io
+  dot       Graphviz, e.g. dot -Tsvg schema.dot -o schema.svg
+
+Column types are the SQL types the migrations use for the project's database (--db).
+The diagram is printed unless -o is given; a .md output wraps Mermaid in a code block.
+
+Examples:
+  bui schema diagram                         # Mermaid on stdout
+  bui schema diagram -o docs/schema.md       # Markdown with a mermaid block
+  bui schema diagram --format dbml -o schema.dbml
+  bui schema diagram --format dot | dot -Tsvg -o schema.svg`,
+	Run: exportSchemaDiagram,
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+	schemaCmd.AddCommand(schemaDiagramCmd)
+	schemaDiagramCmd.Flags().StringVar(&diagramFormat, "format", "mermaid", "Diagram format: "+strings.Join(utils.DiagramFormats, ", "))
+	schemaDiagramCmd.Flags().StringVarP(&diagramOutput, "output", "o", "", "File to write the diagram to (default stdout)")
+	schemaDiagramCmd.Flags().StringVar(&utils.DatabaseFlag, "db", "", "Database whose column types to show: postgres, mysql or sqlite (default from .bui.yaml)")
+}
+
+// exportSchemaDiagram reads app/models and writes the diagram
+func exportSchemaDiagram(cmd *mamba.Command, args []string) {
+	if !slices.Contains(utils.DiagramFormats, diagramFormat) {
+		cmd.PrintError(fmt.Sprintf("Invalid --format value: %s", diagramFormat))
+		cmd.PrintInfo("Use --format " + strings.Join(utils.DiagramFormats, ", --format "))
+		os.Exit(1)
+	}
+	if err := utils.CheckDatabase(); err != nil {
+		cmd.PrintError(err.Error())
+		os.Exit(1)
+	}
+
+	backendDir, _ := detectProjectDirs()
+	files, _ := filepath.Glob(filepath.Join(backendDir, "app", "models", "*.go"))
+	var sources [][]byte
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		source, err := os.ReadFile(file)
+		if err != nil {
+			cmd.PrintError(fmt.Sprintf("Failed to read %s: %v", file, err))
+			os.Exit(1)
+		}
+		sources = append(sources, source)
+	}
+	if len(sources) == 0 {
+		cmd.PrintError("No models found in app/models")
+		cmd.PrintInfo("Run bui schema diagram from the project root or the backend directory")
+		os.Exit(1)
+	}
+
+	diagram, err := utils.BuildSchemaDiagram(sources)
+	if err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to read the models: %v", err))
+		os.Exit(1)
+	}
+	out, err := diagram.Render(diagramFormat)
+	if err != nil {
+		cmd.PrintError(err.Error())
+		os.Exit(1)
+	}
+
+	if diagramOutput == "" {
+		fmt.Print(out)
+		return
+	}
+	if diagramFormat == "mermaid" && strings.EqualFold(filepath.Ext(diagramOutput), ".md") {
+		out = "```mermaid\n" + out + "```\n"
+	}
+	if dir := filepath.Dir(diagramOutput); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			cmd.PrintError(fmt.Sprintf("Failed to create %s: %v", dir, err))
+			os.Exit(1)
+		}
+	}
+	if err := os.WriteFile(diagramOutput, []byte(out), 0644); err != nil {
+		cmd.PrintError(fmt.Sprintf("Failed to write %s: %v", diagramOutput, err))
+		os.Exit(1)
+	}
+	cmd.PrintSuccess(fmt.Sprintf("Wrote the diagram of %d tables and %d relations to %s",
+		len(diagram.Tables), len(diagram.Relations), diagramOutput))
+}
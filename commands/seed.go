@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+var seedCount int
+
+var seedCmd = &mamba.Command{
+	Use:   "seed [module]",
+	Short: "Insert fake data for generated modules",
+	Long: `Run the seed.go files generated for backend modules against the database
+configured in the project's .env.
+
+Examples:
+  bui seed                    # Seed every module that has app/<module>/seed.go
+  bui seed product            # Seed the products module only
+  bui seed product --count 50 # Insert 50 products
+
+Seed modules that others belong to first (e.g. categories before products), so
+belongsTo fields can point at existing rows.`,
+	Run: seedModules,
+}
+
+func init() {
+	rootCmd.AddCommand(seedCmd)
+	seedCmd.Flags().IntVarP(&seedCount, "count", "n", 10, "Number of rows to insert per module")
+}
+
+// seedModules runs a temporary program that calls each module's Seed function
+func seedModules(cmd *mamba.Command, args []string) {
+	backendDir, _ := detectProjectDirs()
+	if _, err := os.Stat(filepath.Join(backendDir, "main.go")); os.IsNotExist(err) {
+		cmd.PrintError("Base project structure not found")
+		cmd.PrintInfo("Run bui seed from the project root or the backend directory")
+		return
+	}
+
+	var modules []string
+	if len(args) > 0 {
+		dirName := utils.NewNamingConvention(args[0]).DirName
+		if _, err := os.Stat(filepath.Join(backendDir, "app", dirName, "seed.go")); err != nil {
+			cmd.PrintError(fmt.Sprintf("No seed file found at app/%s/seed.go", dirName))
+			cmd.PrintInfo(fmt.Sprintf("Regenerate the module with bui g backend %s to create one", args[0]))
+			return
+		}
+		modules = []string{dirName}
+	} else {
+		modules = utils.FindModuleDirs(backendDir, "seed.go")
+		if len(modules) == 0 {
+			cmd.PrintWarning("No modules with a seed.go file found in app/")
+			return
+		}
+	}
+
+	if Verbose {
+		cmd.PrintInfo(fmt.Sprintf("Seeding %d rows into: %s", seedCount, strings.Join(modules, ", ")))
+	}
+
+	runner := seedRunnerSource(utils.GetGoModuleNameIn(backendDir), modules, seedCount)
+	if err := runBackendProgram(backendDir, runner); err != nil {
+		cmd.PrintError(fmt.Sprintf("Seeding failed: %v", err))
+		return
+	}
+
+	cmd.PrintSuccess(fmt.Sprintf("Seeded %d modules", len(modules)))
+}
+
+// seedRunnerSource returns a main package that connects with the project's config and seeds each module
+func seedRunnerSource(goModule string, modules []string, count int) string {
+	var imports, calls strings.Builder
+	for _, module := range modules {
+		imports.WriteString(fmt.Sprintf("\t%s\n", utils.InitImport(goModule, module)))
+		calls.WriteString(fmt.Sprintf(`	if err := %s.Seed(db.DB, %d); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Println("Seeded %s")
+`, utils.InitPackage(module), count, module))
+	}
+
+	return fmt.Sprintf(`// Code generated by bui seed. DO NOT EDIT.
+package main
+
+import (
+	"fmt"
+	"os"
+
+%s
+	"%s/core/config"
+	"%s/core/database"
+)
+
+func main() {
+	cfg := config.NewConfig()
+	db, err := database.InitDB(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to connect to database:", err)
+		os.Exit(1)
+	}
+
+%s}
+`, imports.String(), goModule, goModule, calls.String())
+}
@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+var (
+	ejectOnly  string
+	ejectForce bool
+)
+
+var templateCmd = &mamba.Command{
+	Use:   "template",
+	Short: "Manage the templates generators use",
+	Long: `Manage the templates bui generates modules from.
+
+Templates in .bui/templates/ (in the project root or any parent directory)
+replace the embedded ones with the same name, so a project can customize the
+generated code.`,
+}
+
+var templateEjectCmd = &mamba.Command{
+	Use:   "eject",
+	Short: "Copy the embedded templates into .bui/templates",
+	Long: `Write the embedded backend and Nuxt templates into .bui/templates/ so they can be
+inspected and customized. Generators use these copies instead of the embedded
+templates; delete a file to go back to the built-in version.
+
+Examples:
+  bui template eject                        # All templates
+  bui template eject --only model,controller
+  bui template eject --only types,form-modal # Nuxt templates by short name
+  bui template eject --force                # Overwrite previously ejected copies`,
+	Run: ejectTemplates,
+}
+
+func init() {
+	rootCmd.AddCommand(templateCmd)
+	templateCmd.AddCommand(templateEjectCmd)
+	templateEjectCmd.Flags().StringVar(&ejectOnly, "only", "", "Comma-separated templates to eject (e.g. model,controller)")
+	templateEjectCmd.Flags().BoolVarP(&ejectForce, "force", "f", false, "Overwrite templates that were already ejected")
+}
+
+// ejectTemplates writes the selected embedded templates into the override directory
+func ejectTemplates(cmd *mamba.Command, args []string) {
+	names := utils.EmbeddedTemplates()
+	if ejectOnly != "" {
+		var selected []string
+		for _, want := range strings.Split(ejectOnly, ",") {
+			want = strings.TrimSpace(want)
+			matched := false
+			for _, name := range names {
+				if templateMatches(name, want) {
+					selected = append(selected, name)
+					matched = true
+				}
+			}
+			if !matched {
+				cmd.PrintError(fmt.Sprintf("Unknown template: %s", want))
+				cmd.PrintInfo("Available templates: " + strings.Join(names, ", "))
+				return
+			}
+		}
+		names = selected
+	}
+
+	dir := templateEjectDir()
+	utils.Force = ejectForce
+	utils.ResetGeneratedFiles()
+	for _, name := range names {
+		content, _ := utils.EmbeddedTemplate(name)
+		if err := utils.WriteGeneratedFile(filepath.Join(dir, filepath.FromSlash(name)), []byte(content)); err != nil {
+			cmd.PrintError(fmt.Sprintf("Failed to eject %s: %v", name, err))
+			return
+		}
+	}
+
+	written, skipped := utils.GeneratedFiles()
+	if len(written) > 0 {
+		cmd.PrintSuccess(fmt.Sprintf("Ejected %d templates to %s", len(written), dir))
+		if Verbose {
+			for _, path := range written {
+				cmd.PrintBullet(path)
+			}
+		}
+	}
+	if len(skipped) > 0 {
+		cmd.PrintWarning(fmt.Sprintf("Kept %d customized templates:", len(skipped)))
+		for _, path := range skipped {
+			cmd.PrintBullet(path)
+		}
+		cmd.PrintInfo("Re-run with --force to overwrite them")
+	}
+	if len(written) == 0 && len(skipped) == 0 {
+		cmd.PrintInfo(fmt.Sprintf("Templates in %s are already up to date", dir))
+	}
+}
+
+// templateMatches reports whether want names a template: "nuxt/types.ts.tmpl", "nuxt/types.ts" or "types"
+func templateMatches(name, want string) bool {
+	if want == name || want+".tmpl" == name {
+		return true
+	}
+	stem, _, _ := strings.Cut(filepath.Base(name), ".")
+	return want == stem
+}
+
+// templateEjectDir returns the existing override directory, or .bui/templates in the project root
+func templateEjectDir() string {
+	dir := utils.FindTemplateOverrideDir()
+	if dir == "" {
+		root := utils.Project.Root()
+		if root == "" {
+			return utils.TemplateOverrideDir
+		}
+		dir = filepath.Join(root, utils.TemplateOverrideDir)
+	}
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, dir); err == nil {
+			return rel
+		}
+	}
+	return dir
+}
@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+// testServerLogs shows the dev servers' output while the end-to-end tests run
+var testServerLogs bool
+
+var testCmd = &mamba.Command{
+	Use:   "test",
+	Short: "Run the project's tests",
+	Long: `Run the project's tests.
+
+Examples:
+  bui test e2e`,
+}
+
+var testE2ECmd = &mamba.Command{
+	Use:   "e2e [playwright args...]",
+	Short: "Run the Playwright specs against the dev servers",
+	Long: `Start the backend and frontend dev servers as bui dev does, run the Playwright specs
+bui g e2e wrote to the frontend's e2e/ directory, and stop the servers again.
+
+The specs sign in as E2E_EMAIL with E2E_PASSWORD, an admin user of the backend's database.
+E2E_BASE_URL is set to the frontend's URL. Arguments, and any flags after --, are passed on
+to playwright test, and bui test e2e exits with its status.
+
+Examples:
+  E2E_EMAIL=admin@example.com E2E_PASSWORD=secret bui test e2e
+  bui test e2e e2e/products.spec.ts
+  bui test e2e -- --headed --project=chromium
+  bui test e2e --server-logs`,
+	Run: runTestE2E,
+}
+
+func init() {
+	rootCmd.AddCommand(testCmd)
+	testCmd.AddCommand(testE2ECmd)
+	testE2ECmd.Flags().BoolVar(&testServerLogs, "server-logs", false, "Show the backend and frontend output while the tests run")
+	// Flags after the first argument belong to playwright
+	testE2ECmd.Flags().SetInterspersed(false)
+}
+
+func runTestE2E(cmd *mamba.Command, args []string) {
+	backendDir, frontendDir := findBackendDir(), findFrontendDir()
+	if backendDir == "" || frontendDir == "" {
+		cmd.PrintError("bui test e2e needs both the backend and the frontend directory")
+		cmd.PrintInfo("Run this command from your project root")
+		os.Exit(1)
+	}
+	if !fileExists(filepath.Join(frontendDir, "playwright.config.ts")) {
+		cmd.PrintError("No playwright.config.ts in " + frontendDir)
+		cmd.PrintInfo("Generate the specs first, e.g. bui g e2e product")
+		os.Exit(1)
+	}
+
+	configured := configuredPorts()
+	apiPort := resolveDevPort(cmd, "Backend", 0,
+		preferredPort(configured.Backend, backendDir, backendPortEnvKeys, utils.DefaultBackendPort))
+	appPort := resolveDevPort(cmd, "Frontend", 0,
+		preferredPort(configured.Frontend, frontendDir, frontendPortEnvKeys, utils.DefaultFrontendPort))
+
+	// Ctrl+C reaches playwright too; the servers are stopped once it exits
+	signal.Notify(make(chan os.Signal, 1), os.Interrupt, syscall.SIGTERM)
+
+	devQuiet = !testServerLogs
+	exited := make(chan *devProcess, 2)
+	servers := []*devServer{newBackendServer(backendDir, apiPort), newFrontendServer(frontendDir, appPort)}
+	var running []*devServer
+	stopServers := func() {
+		for _, server := range running {
+			server.stop()
+		}
+	}
+	for _, server := range servers {
+		cmd.PrintInfo(fmt.Sprintf("Starting %s server...", strings.ToLower(server.name)))
+		if err := server.start(cmd, exited); err != nil {
+			cmd.PrintError(fmt.Sprintf("Error starting %s: %v", strings.ToLower(server.name), err))
+			stopServers()
+			os.Exit(1)
+		}
+		running = append(running, server)
+		cmd.PrintSuccess(fmt.Sprintf("%s server ready (%s)", server.name, server.url()))
+	}
+
+	playwright := packageBinary("playwright", append([]string{"test"}, args...)...)
+	playwright.Dir = frontendDir
+	playwright.Stdin, playwright.Stdout, playwright.Stderr = os.Stdin, os.Stdout, os.Stderr
+	playwright.Env = append(os.Environ(), fmt.Sprintf("E2E_BASE_URL=http://localhost:%d", appPort))
+	err := playwright.Run()
+
+	cmd.PrintInfo("Stopping servers...")
+	stopServers()
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		cmd.PrintError("End-to-end tests failed")
+		os.Exit(exitErr.ExitCode())
+	case err != nil:
+		cmd.PrintError("Failed to run playwright: " + err.Error())
+		cmd.PrintInfo("Install it in the frontend with: npm i -D @playwright/test && npx playwright install")
+		os.Exit(1)
+	}
+	cmd.PrintSuccess("End-to-end tests passed")
+}
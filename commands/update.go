@@ -0,0 +1,376 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/base-al/bui/utils"
+	"github.com/base-go/mamba"
+)
+
+var (
+	// updateDryRun lists what a template update would change without writing anything
+	updateDryRun bool
+
+	// updateBackendBase and updateFrontendBase name the template commit a project was created
+	// from when .bui.yaml doesn't record it
+	updateBackendBase  string
+	updateFrontendBase string
+)
+
+// upstreamSuffix is appended to the template's version of a file that could not be merged
+const upstreamSuffix = ".upstream"
+
+var updateCmd = &mamba.Command{
+	Use:   "update",
+	Short: "Update parts of the project",
+}
+
+var updateTemplatesCmd = &mamba.Command{
+	Use:   "templates",
+	Short: "Bring upstream template changes into the project",
+	Long: `Apply changes made to admin-api-template and admin-template since the project was created.
+
+The template commits are recorded under templateCommits: in .bui.yaml by bui new and by
+each update. Every file the template changed since then is merged into the project:
+  - files the project never touched take the new version
+  - files changed on both sides are merged three ways, as git merge would
+  - when both sides changed the same lines, the project's file is kept and the template's
+    version is written next to it as <file>.upstream to merge by hand
+Replaced and deleted files are backed up to .bui/backups first.
+
+Projects created before commits were recorded need the template commit they started from:
+  bui update templates --backend-base 1a2b3c4 --frontend-base 5d6e7f8
+
+Examples:
+  bui update templates --dry-run   # List what would change
+  bui update templates`,
+	Run: updateTemplates,
+}
+
+func init() {
+	rootCmd.AddCommand(updateCmd)
+	updateCmd.AddCommand(updateTemplatesCmd)
+	updateTemplatesCmd.Flags().BoolVar(&updateDryRun, "dry-run", false, "List the changes without writing anything")
+	updateTemplatesCmd.Flags().StringVar(&updateBackendBase, "backend-base", "", "Backend template commit the project was created from (default: from .bui.yaml)")
+	updateTemplatesCmd.Flags().StringVar(&updateFrontendBase, "frontend-base", "", "Frontend template commit the project was created from (default: from .bui.yaml)")
+}
+
+// templateSource is one half of the project and the template it was created from
+type templateSource struct {
+	name string // backend or frontend
+	dir  string // Project directory, relative to the project root
+	repo string
+	base string // Template commit the project is up to date with
+	// rewrite applies the changes bui new makes to template files, so they compare equal
+	rewrite func(path string, content []byte) []byte
+}
+
+// templateChange is what happened to one file the template changed
+type templateChange struct {
+	path   string
+	action string // added, updated, merged, deleted, conflict or skipped
+	note   string
+}
+
+func updateTemplates(cmd *mamba.Command, args []string) {
+	root := utils.Project.Root()
+	if root == "" {
+		cmd.PrintError("No " + utils.ProjectConfigFile + " found. Run bui update templates inside a project created by bui new.")
+		os.Exit(1)
+	}
+	// Paths in backups and output are relative to the project root
+	if err := os.Chdir(root); err != nil {
+		cmd.PrintError(err.Error())
+		os.Exit(1)
+	}
+
+	commits := utils.Project.TemplateCommits
+	sources := []*templateSource{
+		{
+			name: "backend",
+			dir:  detectBackendDir(),
+			repo: utils.Project.BackendTemplate(utils.User.BackendTemplate(backendTemplateRepo)),
+			base: firstNonEmpty(updateBackendBase, commits.Backend),
+		},
+		{
+			name:    "frontend",
+			dir:     detectFrontendDir(),
+			repo:    utils.Project.FrontendTemplate(utils.User.FrontendTemplate(frontendTemplateRepo)),
+			base:    firstNonEmpty(updateFrontendBase, commits.Frontend),
+			rewrite: func(path string, content []byte) []byte { return content },
+		},
+	}
+	if sources[0].dir != "" {
+		sources[0].rewrite = goModuleRewrite(readModulePath(filepath.Join(sources[0].dir, "go.mod")))
+	}
+
+	useSSH := templateCloneSSH()
+	var backup *utils.Backup
+	changed := false
+	for _, source := range sources {
+		if source.dir == "" {
+			continue
+		}
+		if source.base == "" {
+			cmd.PrintWarning(fmt.Sprintf("No %s template commit recorded in %s; pass --%s-base <commit> with the template commit the project was created from",
+				source.name, utils.ProjectConfigFile, source.name))
+			continue
+		}
+
+		head, changes, err := updateFromTemplate(cmd, source, useSSH, &backup)
+		if err != nil {
+			cmd.PrintError(fmt.Sprintf("Updating the %s failed: %v", source.name, err))
+			continue
+		}
+		printTemplateChanges(cmd, source, head, changes)
+
+		if head != "" && !updateDryRun {
+			if source.name == "backend" {
+				commits.Backend = head
+			} else {
+				commits.Frontend = head
+			}
+			changed = true
+		}
+	}
+
+	if changed {
+		if err := utils.SetProjectConfigValue(".", "templateCommits", commits); err != nil {
+			cmd.PrintWarning(fmt.Sprintf("Failed to record the template commits in %s: %v", utils.ProjectConfigFile, err))
+		}
+	}
+	if backup != nil {
+		cmd.PrintInfo("Replaced files were backed up to " + backup.Dir)
+	}
+	if updateDryRun {
+		cmd.PrintInfo("Dry run: nothing was written")
+	}
+}
+
+// updateFromTemplate merges the template changes since source.base into the project and returns
+// the template commit it is now up to date with
+func updateFromTemplate(cmd *mamba.Command, source *templateSource, useSSH bool, backup **utils.Backup) (string, []templateChange, error) {
+	tmp, err := os.MkdirTemp("", "bui-template-*")
+	if err != nil {
+		return "", nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	clone := filepath.Join(tmp, "template")
+	if err := cloneWithSpinner(cmd, source.name, source.repo, clone, useSSH, false); err != nil {
+		return "", nil, err
+	}
+
+	base, err := gitOutput(clone, "rev-parse", "--verify", source.base+"^{commit}")
+	if err != nil {
+		return "", nil, fmt.Errorf("commit %s is not in %s", source.base, source.repo)
+	}
+	head, err := gitOutput(clone, "rev-parse", "HEAD")
+	if err != nil {
+		return "", nil, err
+	}
+	if base == head {
+		return head, nil, nil
+	}
+
+	diff, err := gitOutput(clone, "diff", "--name-status", "--no-renames", base, head)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var changes []templateChange
+	for _, line := range strings.Split(diff, "\n") {
+		status, path, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		change, err := applyTemplateChange(clone, source, base, head, status, path, backup)
+		if err != nil {
+			return "", changes, fmt.Errorf("%s: %w", path, err)
+		}
+		changes = append(changes, change)
+	}
+	return head, changes, nil
+}
+
+// applyTemplateChange brings one file the template changed between base and head into the project
+func applyTemplateChange(clone string, source *templateSource, base, head, status, path string, backup **utils.Backup) (templateChange, error) {
+	change := templateChange{path: filepath.Join(source.dir, filepath.FromSlash(path))}
+	current, readErr := os.ReadFile(change.path)
+	exists := readErr == nil
+
+	var baseContent, headContent []byte
+	if status != "A" {
+		baseContent = source.rewrite(path, templateFile(clone, base, path))
+	}
+	if status != "D" {
+		headContent = source.rewrite(path, templateFile(clone, head, path))
+	}
+
+	switch {
+	case status == "D":
+		if !exists {
+			change.action, change.note = "skipped", "already removed"
+		} else if !bytes.Equal(current, baseContent) {
+			change.action, change.note = "skipped", "removed upstream, kept because it was changed locally"
+		} else {
+			change.action = "deleted"
+		}
+	case !exists && status == "A":
+		change.action = "added"
+	case !exists:
+		change.action, change.note = "skipped", "removed from the project"
+	case bytes.Equal(current, headContent):
+		change.action, change.note = "skipped", "already up to date"
+	case status == "A":
+		change.action, change.note = "conflict", "added upstream but the project has its own version"
+	case bytes.Equal(current, baseContent):
+		change.action = "updated"
+	default:
+		// Conflicts, and files git can't merge, keep the project's version
+		merged, conflicts, err := utils.MergeFile(current, baseContent, headContent, "project", "template")
+		if err == nil && conflicts == 0 {
+			change.action = "merged"
+			headContent = merged
+		} else {
+			change.action, change.note = "conflict", "changed on both sides"
+		}
+	}
+
+	if updateDryRun {
+		return change, nil
+	}
+
+	switch change.action {
+	case "added":
+		if err := os.MkdirAll(filepath.Dir(change.path), 0755); err != nil {
+			return change, err
+		}
+		return change, os.WriteFile(change.path, headContent, 0644)
+	case "updated", "merged", "deleted":
+		if err := backupTemplateFile(backup, change.path); err != nil {
+			return change, err
+		}
+		if change.action == "deleted" {
+			return change, os.Remove(change.path)
+		}
+		return change, writeKeepingMode(change.path, headContent)
+	case "conflict":
+		change.note += "; template version in " + filepath.Base(change.path) + upstreamSuffix
+		return change, os.WriteFile(change.path+upstreamSuffix, headContent, 0644)
+	}
+	return change, nil
+}
+
+// templateFile returns a file as it was at commit, or nil when it didn't exist
+func templateFile(clone, commit, path string) []byte {
+	showCmd := exec.Command("git", "show", commit+":"+path)
+	showCmd.Dir = clone
+	content, err := showCmd.Output()
+	if err != nil {
+		return nil
+	}
+	return content
+}
+
+// backupTemplateFile copies a project file into this update's backup before it is replaced
+func backupTemplateFile(backup **utils.Backup, path string) error {
+	if *backup == nil {
+		created, err := utils.NewBackup("templates", "template-update")
+		if err != nil {
+			return err
+		}
+		*backup = created
+	}
+	return (*backup).Copy(path)
+}
+
+// writeKeepingMode replaces a file's content, keeping its permissions
+func writeKeepingMode(path string, content []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	return os.WriteFile(path, content, mode)
+}
+
+// printTemplateChanges lists what happened to each file the template changed
+func printTemplateChanges(cmd *mamba.Command, source *templateSource, head string, changes []templateChange) {
+	short := func(commit string) string {
+		if len(commit) > 7 {
+			return commit[:7]
+		}
+		return commit
+	}
+
+	if len(changes) == 0 {
+		cmd.PrintSuccess(fmt.Sprintf("The %s is up to date with its template (%s)", source.name, short(head)))
+		return
+	}
+
+	title := strings.ToUpper(source.name[:1]) + source.name[1:]
+	cmd.PrintHeader(fmt.Sprintf("%s template %s → %s", title, short(source.base), short(head)))
+	conflicts := 0
+	for _, change := range changes {
+		line := fmt.Sprintf("%-8s %s", change.action, change.path)
+		if change.note != "" {
+			line += " (" + change.note + ")"
+		}
+		cmd.PrintBullet(line)
+		if change.action == "conflict" {
+			conflicts++
+		}
+	}
+	if conflicts > 0 {
+		cmd.PrintWarning(fmt.Sprintf("%d file(s) need merging by hand; remove the %s files once done", conflicts, upstreamSuffix))
+	}
+}
+
+// moduleLine matches the module directive of a go.mod file
+var moduleLine = regexp.MustCompile(`(?m)^module\s+\S+`)
+
+// readModulePath returns the module path declared in a go.mod file, or ""
+func readModulePath(path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	match := moduleLine.Find(content)
+	if match == nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimPrefix(string(match), "module"))
+}
+
+// goModuleRewrite returns a rewrite that renames the template's "base" module to modulePath,
+// as bui new does, so template files compare equal to the project's
+func goModuleRewrite(modulePath string) func(string, []byte) []byte {
+	return func(path string, content []byte) []byte {
+		if modulePath == "" || content == nil {
+			return content
+		}
+		switch {
+		case strings.HasSuffix(path, ".go"):
+			return bytes.ReplaceAll(content, []byte(`"base/`), []byte(`"`+modulePath+`/`))
+		case path == "go.mod":
+			return moduleLine.ReplaceAll(content, []byte("module "+modulePath))
+		}
+		return content
+	}
+}
+
+// firstNonEmpty returns the first of values that isn't empty
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -1,131 +1,387 @@
 package commands
 
 import (
-	"encoding/json"
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
-	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/base-al/bui/version"
 	"github.com/base-go/mamba"
 	"github.com/base-go/mamba/pkg/spinner"
 )
 
+var (
+	// upgradeVersion pins the release to install instead of the latest
+	upgradeVersion string
+
+	// upgradeRollback restores the binary kept by the previous upgrade
+	upgradeRollback bool
+)
+
+// checksumsAsset lists the SHA-256 of every archive in a release
+const checksumsAsset = "checksums.txt"
+
 var upgradeCmd = &mamba.Command{
 	Use:   "upgrade",
 	Short: "Upgrade Bui CLI to the latest version",
-	Long:  `Download and install the latest version of Bui CLI.`,
-	Run:   runUpgrade,
+	Long: `Download and install the latest version of Bui CLI.
+
+The release archive for this platform is downloaded from GitHub, checked against the
+release's checksums.txt and swapped in for the running binary. The binary it replaces
+is kept in ~/.bui/bin so --rollback can restore it.
+
+Examples:
+  bui upgrade
+  bui upgrade --version v0.4.0
+  bui upgrade --rollback`,
+	Run: runUpgrade,
 }
 
 func init() {
 	rootCmd.AddCommand(upgradeCmd)
+	upgradeCmd.Flags().StringVar(&upgradeVersion, "version", "", "Install this release (e.g. v1.2.3) instead of the latest")
+	upgradeCmd.Flags().BoolVar(&upgradeRollback, "rollback", false, "Restore the binary replaced by the last upgrade")
 }
 
 func runUpgrade(cmd *mamba.Command, args []string) {
+	exePath, err := currentExecutable()
+	if err != nil {
+		cmd.PrintError("Failed to detect installation path: " + err.Error())
+		os.Exit(1)
+	}
+
+	if upgradeRollback {
+		rollbackUpgrade(cmd, exePath)
+		return
+	}
+
 	// Check current and latest versions
 	currentVersion := version.Version
 	if currentVersion == "" {
 		currentVersion = "unknown"
 	}
 
-	var latestVersion string
-	err := spinner.WithSpinner("Checking for updates...", func() error {
-		version, err := getLatestVersion()
-		if err != nil {
-			return err
+	var release *version.Release
+	err = spinner.WithSpinner("Checking for updates...", func() error {
+		var err error
+		if upgradeVersion != "" {
+			release, err = version.GetRelease("v" + strings.TrimPrefix(upgradeVersion, "v"))
+		} else {
+			release, err = version.CheckLatestVersion()
 		}
-		latestVersion = version
-		return nil
+		if err == nil && release.TagName == "" {
+			err = fmt.Errorf("no release information returned")
+		}
+		return err
 	})
-
 	if err != nil {
-		cmd.PrintWarning(fmt.Sprintf("Failed to check latest version: %v", err))
-		latestVersion = "unknown"
+		cmd.PrintError(fmt.Sprintf("Failed to find the release: %v", err))
+		os.Exit(1)
 	}
 
 	cmd.PrintInfo("")
 	cmd.PrintInfo(fmt.Sprintf("Current version: %s", currentVersion))
-	cmd.PrintInfo(fmt.Sprintf("Latest version:  %s", latestVersion))
+	if upgradeVersion != "" {
+		cmd.PrintInfo(fmt.Sprintf("Target version:  %s", release.TagName))
+	} else {
+		cmd.PrintInfo(fmt.Sprintf("Latest version:  %s", release.TagName))
+	}
 	cmd.PrintInfo("")
 
 	// Check if already up to date
-	if currentVersion != "unknown" && latestVersion != "unknown" {
-		if strings.TrimPrefix(currentVersion, "v") == strings.TrimPrefix(latestVersion, "v") {
-			cmd.PrintSuccess("You are already running the latest version!")
-			return
-		}
-	}
-
-	// Detect if installed via go install or install script
-	exePath, err := os.Executable()
-	if err != nil {
-		cmd.PrintError("Failed to detect installation path")
-		os.Exit(1)
+	if strings.TrimPrefix(currentVersion, "v") == strings.TrimPrefix(release.TagName, "v") {
+		cmd.PrintSuccess("You are already running " + release.TagName + "!")
+		return
 	}
 
 	cmd.PrintHeader("Upgrading Bui CLI")
 	cmd.PrintInfo(fmt.Sprintf("Installation path: %s", exePath))
 	cmd.PrintInfo("")
 
-	// Determine the install script command based on OS
-	var installCmd *exec.Cmd
-	if runtime.GOOS == "windows" {
-		// Windows: download and run with PowerShell
-		installCmd = exec.Command("powershell", "-Command",
-			"Invoke-WebRequest -Uri https://raw.githubusercontent.com/base-al/bui/main/install.sh -OutFile install.sh; bash install.sh; Remove-Item install.sh")
-	} else {
-		// Unix: use curl and bash
-		installCmd = exec.Command("bash", "-c",
-			"curl -sSL https://raw.githubusercontent.com/base-al/bui/main/install.sh | bash")
+	assetName := releaseAssetName(runtime.GOOS, runtime.GOARCH)
+	var binary []byte
+	err = spinner.WithSpinner("Downloading "+assetName+"...", func() error {
+		var err error
+		binary, err = downloadReleaseBinary(release, assetName)
+		return err
+	})
+	if err != nil {
+		cmd.PrintError(fmt.Sprintf("Upgrade failed: %v", err))
+		os.Exit(1)
 	}
+	cmd.PrintSuccess("Checksum verified")
 
-	installCmd.Stdout = os.Stdout
-	installCmd.Stderr = os.Stderr
-	installCmd.Stdin = os.Stdin
+	// Keep the current binary so the upgrade can be rolled back
+	previousPath, err := previousBinaryPath()
+	if err == nil {
+		err = copyExecutable(exePath, previousPath)
+	}
+	if err != nil {
+		cmd.PrintError("Failed to keep the current binary: " + err.Error())
+		os.Exit(1)
+	}
 
-	cmd.PrintInfo("Running installation script...")
-	if err := installCmd.Run(); err != nil {
-		cmd.PrintError("Failed to run install script")
-		cmd.PrintInfo("")
-		cmd.PrintHeader("Manual Installation")
-		cmd.PrintBullet("curl -sSL https://raw.githubusercontent.com/base-al/bui/main/install.sh | bash")
+	if err := replaceExecutable(exePath, binary); err != nil {
+		cmd.PrintError("Failed to replace " + exePath + ": " + err.Error())
+		if os.IsPermission(err) {
+			cmd.PrintInfo("Run the upgrade with permission to write there, e.g. sudo bui upgrade")
+		}
 		os.Exit(1)
 	}
 
 	cmd.PrintInfo("")
-	cmd.PrintSuccess("Successfully upgraded Bui CLI!")
-	cmd.PrintInfo("Run 'bui version' to check the new version")
+	cmd.PrintSuccess("Successfully upgraded Bui CLI to " + release.TagName + "!")
+	cmd.PrintInfo("Run 'bui upgrade --rollback' to go back to " + currentVersion)
 }
 
-// getLatestVersion fetches the latest release version from GitHub
-func getLatestVersion() (string, error) {
-	resp, err := http.Get("https://api.github.com/repos/base-al/bui/releases/latest")
+// rollbackUpgrade swaps the running binary with the one the last upgrade replaced,
+// so rolling back twice returns to the upgraded version
+func rollbackUpgrade(cmd *mamba.Command, exePath string) {
+	previousPath, err := previousBinaryPath()
+	if err != nil {
+		cmd.PrintError("Failed to find the previous binary: " + err.Error())
+		os.Exit(1)
+	}
+	previous, err := os.ReadFile(previousPath)
+	if err != nil {
+		cmd.PrintError("No previous binary to roll back to (" + previousPath + ")")
+		os.Exit(1)
+	}
+
+	current, err := os.ReadFile(exePath)
+	if err != nil {
+		cmd.PrintError("Failed to read " + exePath + ": " + err.Error())
+		os.Exit(1)
+	}
+	if err := replaceExecutable(exePath, previous); err != nil {
+		cmd.PrintError("Failed to replace " + exePath + ": " + err.Error())
+		os.Exit(1)
+	}
+	if err := writeExecutable(previousPath, current); err != nil {
+		cmd.PrintWarning("Rolled back, but failed to keep the replaced binary: " + err.Error())
+	}
+
+	cmd.PrintSuccess("Restored the previous Bui CLI binary")
+	cmd.PrintInfo("Run 'bui version' to check the version")
+}
+
+// currentExecutable returns the path of the running binary with symlinks resolved
+func currentExecutable() (string, error) {
+	exePath, err := os.Executable()
 	if err != nil {
 		return "", err
 	}
+	return filepath.EvalSymlinks(exePath)
+}
+
+// previousBinaryPath is where the binary replaced by an upgrade is kept
+func previousBinaryPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	name := "bui"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return filepath.Join(home, ".bui", "bin", name), nil
+}
+
+// releaseAssetName is the archive GoReleaser publishes for a platform, e.g. bui_linux_x86_64.tar.gz
+func releaseAssetName(goos, goarch string) string {
+	arch := goarch
+	switch goarch {
+	case "amd64":
+		arch = "x86_64"
+	case "386":
+		arch = "i386"
+	}
+	ext := ".tar.gz"
+	if goos == "windows" {
+		ext = ".zip"
+	}
+	return "bui_" + goos + "_" + arch + ext
+}
+
+// downloadReleaseBinary downloads a release archive, verifies it against the release's
+// checksums and returns the bui binary inside it
+func downloadReleaseBinary(release *version.Release, assetName string) ([]byte, error) {
+	urls := map[string]string{}
+	for _, asset := range release.Assets {
+		urls[asset.Name] = asset.BrowserDownloadURL
+	}
+	if urls[assetName] == "" {
+		return nil, fmt.Errorf("release %s has no %s for this platform", release.TagName, assetName)
+	}
+	if urls[checksumsAsset] == "" {
+		return nil, fmt.Errorf("release %s has no %s to verify the download against", release.TagName, checksumsAsset)
+	}
+
+	checksums, err := downloadReleaseFile(urls[checksumsAsset])
+	if err != nil {
+		return nil, err
+	}
+	expected := releaseChecksum(checksums, assetName)
+	if expected == "" {
+		return nil, fmt.Errorf("%s has no entry for %s", checksumsAsset, assetName)
+	}
+
+	archive, err := downloadReleaseFile(urls[assetName])
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(archive)
+	if actual := hex.EncodeToString(sum[:]); actual != expected {
+		return nil, fmt.Errorf("checksum mismatch for %s: expected %s, got %s", assetName, expected, actual)
+	}
+
+	if strings.HasSuffix(assetName, ".zip") {
+		return extractZipBinary(archive)
+	}
+	return extractTarGzBinary(archive)
+}
+
+// downloadReleaseFile fetches a release asset
+func downloadReleaseFile(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+		return nil, fmt.Errorf("downloading %s: status %d", url, resp.StatusCode)
 	}
+	return io.ReadAll(resp.Body)
+}
 
-	body, err := io.ReadAll(resp.Body)
+// releaseChecksum finds the SHA-256 of name in a sha256sum-style checksums file
+func releaseChecksum(checksums []byte, name string) string {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0])
+		}
+	}
+	return ""
+}
+
+// isBuiBinary reports whether an archive entry is the bui executable
+func isBuiBinary(name string) bool {
+	base := filepath.Base(name)
+	return base == "bui" || base == "bui.exe"
+}
+
+// extractTarGzBinary returns the bui binary from a tar.gz release archive
+func extractTarGzBinary(archive []byte) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no bui binary in the archive")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag == tar.TypeReg && isBuiBinary(header.Name) {
+			return io.ReadAll(tr)
+		}
 	}
+}
 
-	var release struct {
-		TagName string `json:"tag_name"`
+// extractZipBinary returns the bui binary from a zip release archive
+func extractZipBinary(archive []byte) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range zr.File {
+		if file.FileInfo().IsDir() || !isBuiBinary(file.Name) {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
 	}
+	return nil, fmt.Errorf("no bui binary in the archive")
+}
 
-	if err := json.Unmarshal(body, &release); err != nil {
-		return "", err
+// copyExecutable copies the binary at src to dst
+func copyExecutable(src, dst string) error {
+	content, err := os.ReadFile(src)
+	if err != nil {
+		return err
 	}
+	return writeExecutable(dst, content)
+}
 
-	return release.TagName, nil
+// writeExecutable writes an executable file, creating its directory; the mode is set
+// explicitly since WriteFile keeps the mode of an existing file
+func writeExecutable(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, content, 0755); err != nil {
+		return err
+	}
+	return os.Chmod(path, 0755)
+}
+
+// replaceExecutable atomically replaces the binary at path: the new one is written next to it
+// and renamed over it. Windows can't overwrite a running binary, so it is moved aside first.
+func replaceExecutable(path string, binary []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".bui-upgrade-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0755); err != nil {
+		return err
+	}
+
+	if runtime.GOOS == "windows" {
+		oldPath := path + ".old"
+		os.Remove(oldPath)
+		if err := os.Rename(path, oldPath); err != nil {
+			return err
+		}
+		if err := os.Rename(tmpPath, path); err != nil {
+			os.Rename(oldPath, path)
+			return err
+		}
+		return nil
+	}
+	return os.Rename(tmpPath, path)
 }
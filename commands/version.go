@@ -17,8 +17,11 @@ var versionCmd = &mamba.Command{
 		cmd.PrintInfo(info.String())
 
 		// Check for updates
-		release, err := version.CheckLatestVersion()
-		if err != nil {
+		if !updateCheckEnabled() {
+			return
+		}
+		release := fetchLatestRelease()
+		if release == nil {
 			return
 		}
 
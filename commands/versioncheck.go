@@ -1,52 +1,118 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/base-al/bui/utils"
 	"github.com/base-al/bui/version"
 	"github.com/base-go/mamba"
 )
 
-// CheckForUpdate checks if a new version is available and prints a message
-func CheckForUpdate(cmd *mamba.Command) {
-	currentVersion := version.Version
-	if currentVersion == "" || currentVersion == "unknown" {
-		return
+// updateCheckTTL is how long the latest release fetched from GitHub is reused
+const updateCheckTTL = 24 * time.Hour
+
+// updateCheckTimeout caps how long a command waits for GitHub
+const updateCheckTimeout = 2 * time.Second
+
+// updateCheckCache is the last update check, stored in ~/.bui/cache
+type updateCheckCache struct {
+	CheckedAt time.Time        `json:"checked_at"`
+	Release   *version.Release `json:"release,omitempty"`
+}
+
+// updateCheckEnabled reports whether commands may check for a newer release: not with
+// --no-update-check, BUI_NO_UPDATE_CHECK or telemetry turned off
+func updateCheckEnabled() bool {
+	if NoUpdateCheck || !utils.User.TelemetryEnabled() {
+		return false
+	}
+	switch strings.ToLower(os.Getenv("BUI_NO_UPDATE_CHECK")) {
+	case "", "0", "false":
+		return true
 	}
+	return false
+}
+
+// updateCheckCachePath returns the path of the update check cache
+func updateCheckCachePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".bui", "cache", "latest-release.json"), nil
+}
 
-	// Check for updates in background (with timeout)
-	done := make(chan string, 1)
+// latestRelease returns the latest release, asking GitHub at most once per updateCheckTTL.
+// It returns nil when the release is unknown; errors are never reported.
+func latestRelease() *version.Release {
+	var cache updateCheckCache
+	path, err := updateCheckCachePath()
+	if err == nil {
+		if data, err := os.ReadFile(path); err == nil {
+			json.Unmarshal(data, &cache)
+		}
+	}
+	if time.Since(cache.CheckedAt) < updateCheckTTL {
+		return cache.Release
+	}
+
+	if release := fetchLatestRelease(); release != nil {
+		cache.Release = release
+	}
+	// Failed checks are recorded too, so being offline doesn't slow down every command
+	cache.CheckedAt = time.Now()
+	if path != "" {
+		if data, err := json.Marshal(cache); err == nil && os.MkdirAll(filepath.Dir(path), 0755) == nil {
+			os.WriteFile(path, data, 0644)
+		}
+	}
+	return cache.Release
+}
+
+// fetchLatestRelease asks GitHub for the latest release, giving up after updateCheckTimeout
+func fetchLatestRelease() *version.Release {
+	done := make(chan *version.Release, 1)
 	go func() {
-		latestVersion, err := getLatestVersion()
-		if err == nil {
-			done <- latestVersion
-		} else {
-			done <- ""
+		release, err := version.CheckLatestVersion()
+		if err != nil || release.TagName == "" {
+			// Rate limits and other API errors come back without a tag
+			release = nil
 		}
+		done <- release
 	}()
 
-	// Wait for result with 2 second timeout
-	var latestVersion string
 	select {
-	case latestVersion = <-done:
-	case <-time.After(2 * time.Second):
-		return // Timeout, don't show update message
+	case release := <-done:
+		return release
+	case <-time.After(updateCheckTimeout):
+		return nil
 	}
+}
 
-	if latestVersion == "" {
+// CheckForUpdate prints a message when a newer release than this binary is available
+func CheckForUpdate(cmd *mamba.Command) {
+	if !updateCheckEnabled() {
+		return
+	}
+	release := latestRelease()
+	if release == nil {
 		return
 	}
 
-	// Compare versions (strip 'v' prefix if present)
-	current := strings.TrimPrefix(currentVersion, "v")
-	latest := strings.TrimPrefix(latestVersion, "v")
-
-	if current != latest {
-		cmd.PrintInfo("")
-		cmd.PrintWarning(fmt.Sprintf("New version available: %s → %s", currentVersion, latestVersion))
-		cmd.PrintInfo("Run 'bui upgrade' to update")
-		cmd.PrintInfo("")
+	info := version.GetBuildInfo()
+	latestVersion := strings.TrimPrefix(release.TagName, "v")
+	// Only show update message if there's actually an update
+	if version.HasUpdate(info.Version, latestVersion) {
+		fmt.Print(version.FormatUpdateMessage(
+			info.Version,
+			latestVersion,
+			release.HTMLURL,
+			release.Body,
+		))
 	}
 }
@@ -0,0 +1,496 @@
+package utils
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Alter makes generators add fields to an existing module instead of regenerating its files
+var Alter bool
+
+// standardModelFields are model columns every generated model gets, which aren't field definitions
+var standardModelFields = map[string]bool{
+	"Id": true, "CreatedAt": true, "UpdatedAt": true, "DeletedAt": true, "CreatedBy": true, "UpdatedBy": true,
+	"LockVersion": true,
+}
+
+// RecoverFieldDefs reads a generated model file and returns field definitions (e.g. "price:float")
+// that regenerate its struct. Relations, enums, state fields, money, decimals, points, encrypted
+// and computed fields, defaults and rules are recovered; select options are not.
+func RecoverFieldDefs(source []byte, model string) ([]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", source, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []*ast.Field
+	enumValues := map[string][]string{}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				if st, ok := spec.Type.(*ast.StructType); ok && spec.Name.Name == model {
+					fields = st.Fields.List
+				}
+			case *ast.ValueSpec:
+				// Enum constants: ProductStatusDraft ProductStatus = "draft"
+				if gen.Tok != token.CONST || spec.Type == nil || len(spec.Values) != 1 {
+					continue
+				}
+				if lit, ok := spec.Values[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					if value, err := strconv.Unquote(lit.Value); err == nil {
+						typeName := exprString(spec.Type)
+						enumValues[typeName] = append(enumValues[typeName], value)
+					}
+				}
+			}
+		}
+	}
+	if fields == nil {
+		return nil, fmt.Errorf("struct %s not found", model)
+	}
+
+	types := map[string]string{}
+	for _, field := range fields {
+		for _, name := range field.Names {
+			types[name.Name] = exprString(field.Type)
+		}
+	}
+
+	var defs []string
+	for _, field := range fields {
+		if len(field.Names) != 1 || standardModelFields[field.Names[0].Name] {
+			continue
+		}
+		name := field.Names[0].Name
+		goType := exprString(field.Type)
+		jsonName, gormTag, tag := structTags(field)
+		if jsonName == "" || jsonName == "-" {
+			continue
+		}
+
+		var def string
+		switch {
+		case goType == "*media.Media":
+			def = jsonName + ":media"
+		case goType == "[]*media.Media":
+			def = jsonName + ":media[]"
+		case goType == "*storage.Attachment":
+			def = jsonName + ":attachment"
+		case goType == "translation.Field":
+			def = jsonName + ":translation"
+		case strings.HasPrefix(goType, "[]*"):
+			related := strings.TrimPrefix(goType, "[]*")
+			if strings.Contains(gormTag, "many2many:") {
+				def = jsonName + ":manyToMany:" + related
+				if pivot := recoverPivot(file, model, related); len(pivot) > 0 {
+					def += ":pivot=" + strings.Join(pivot, ",")
+				}
+			} else {
+				def = jsonName + ":hasMany:" + related
+				// Keys other than <model>_id are part of the definition; a tree's children are keyed on its parent
+				for _, setting := range strings.Split(gormTag, ";") {
+					if key, ok := strings.CutPrefix(setting, "foreignKey:"); ok && key != model+"Id" && related != model {
+						def += ":" + ToSnakeCase(key)
+					}
+				}
+			}
+		case strings.HasSuffix(name, "Id") && strings.HasPrefix(goType, "*"):
+			// Foreign keys pair with a relation object (CategoryId + Category)
+			object := types[TrimIdSuffix(name)]
+			switch {
+			case object == "*media.Media":
+				continue // Generated by the media field
+			case strings.HasPrefix(object, "*"):
+				def = jsonName + ":belongsTo:" + strings.TrimPrefix(object, "*")
+			default:
+				def = jsonName + ":" + fieldTypeAlias(strings.TrimPrefix(goType, "*"))
+			}
+		case goType == "*float64" && strings.HasSuffix(name, "Lat") && types[strings.TrimSuffix(name, "Lat")+"Lng"] == goType:
+			def = strings.TrimSuffix(jsonName, "_lat") + ":point"
+		case goType == "*float64" && strings.HasSuffix(name, "Lng") && types[strings.TrimSuffix(name, "Lng")+"Lat"] == goType:
+			continue // Generated by the point field
+		case strings.HasPrefix(goType, "*"):
+			if _, ok := types[name+"Id"]; ok {
+				continue // Relation object of a belongs_to or media field
+			}
+			def = jsonName + ":hasOne:" + strings.TrimPrefix(goType, "*")
+		case len(enumValues[goType]) > 0 && tag.Get("states") != "":
+			def = jsonName + ":state:" + tag.Get("states")
+		case len(enumValues[goType]) > 0:
+			def = jsonName + ":enum:" + strings.Join(enumValues[goType], ",")
+		case goType == "int64" && tag.Get("currency") != "":
+			def = jsonName + ":money:" + tag.Get("currency")
+		case goType == "string" && tag.Get("encrypted") == "true":
+			def = jsonName + ":encrypted"
+		case goType == "string" && gormTag == "-":
+			def = jsonName + ":computed"
+			if sources := tag.Get("computed"); sources != "" {
+				def += ":" + sources
+			}
+		case goType == "decimal.Decimal":
+			def = jsonName + ":decimal"
+			for _, setting := range strings.Split(gormTag, ";") {
+				if numeric, ok := strings.CutPrefix(setting, "type:numeric"); ok {
+					def += ":" + strings.Trim(numeric, "()")
+				}
+			}
+		default:
+			def = jsonName + ":" + fieldTypeAlias(goType)
+		}
+
+		for _, setting := range strings.Split(gormTag, ";") {
+			if value, ok := strings.CutPrefix(setting, "default:"); ok {
+				def += ":default=" + value
+			}
+		}
+		if rules := tag.Get("rules"); rules != "" {
+			def += ":rules=" + rules
+		}
+		defs = append(defs, def)
+	}
+
+	return defs, nil
+}
+
+// recoverPivot returns the definitions of the pivot columns of a manyToMany join model, the
+// fields of <Model><Related> besides its two keys
+func recoverPivot(file *ast.File, model, related string) []string {
+	var pivot []string
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok || typeSpec.Name.Name != model+related {
+				continue
+			}
+			st, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			for _, field := range st.Fields.List {
+				if len(field.Names) != 1 || field.Names[0].Name == model+"Id" || field.Names[0].Name == related+"Id" {
+					continue
+				}
+				if jsonName, _, _ := structTags(field); jsonName != "" && jsonName != "-" {
+					pivot = append(pivot, jsonName+":"+fieldTypeAlias(strings.TrimPrefix(exprString(field.Type), "*")))
+				}
+			}
+		}
+	}
+	return pivot
+}
+
+// RecoverTableName returns the table name a generated model's TableName method returns, or ""
+func RecoverTableName(source []byte, model string) string {
+	file, err := parser.ParseFile(token.NewFileSet(), "", source, 0)
+	if err != nil {
+		return ""
+	}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != "TableName" || fn.Recv == nil || len(fn.Recv.List) != 1 || fn.Body == nil {
+			continue
+		}
+		if strings.TrimPrefix(exprString(fn.Recv.List[0].Type), "*") != model || len(fn.Body.List) != 1 {
+			continue
+		}
+		if ret, ok := fn.Body.List[0].(*ast.ReturnStmt); ok && len(ret.Results) == 1 {
+			if lit, ok := ret.Results[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				name, _ := strconv.Unquote(lit.Value)
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// RecoverModels returns the structs in source that have a TableName method, which generated
+// models do and their request and response structs don't
+func RecoverModels(source []byte) []string {
+	file, err := parser.ParseFile(token.NewFileSet(), "", source, 0)
+	if err != nil {
+		return nil
+	}
+	structs := map[string]bool{}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+				if _, ok := typeSpec.Type.(*ast.StructType); ok {
+					structs[typeSpec.Name.Name] = true
+				}
+			}
+		}
+	}
+
+	var models []string
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != "TableName" || fn.Recv == nil || len(fn.Recv.List) != 1 {
+			continue
+		}
+		if model := strings.TrimPrefix(exprString(fn.Recv.List[0].Type), "*"); structs[model] {
+			models = append(models, model)
+		}
+	}
+	return models
+}
+
+// fieldTypeAlias returns the field definition type for a Go type
+func fieldTypeAlias(goType string) string {
+	switch goType {
+	case "float64":
+		return "float"
+	case "json.RawMessage":
+		return "json"
+	case "decimal.Decimal":
+		return "decimal"
+	case "types.DateTime":
+		return "datetime"
+	case "time.Time":
+		return "time"
+	}
+	return goType
+}
+
+// structTags returns the JSON name and gorm tag of a struct field, and its whole tag for the
+// markers of money, encrypted and computed fields
+func structTags(field *ast.Field) (string, string, reflect.StructTag) {
+	if field.Tag == nil {
+		return "", "", ""
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return "", "", ""
+	}
+	st := reflect.StructTag(tag)
+	jsonName, _, _ := strings.Cut(st.Get("json"), ",")
+	return jsonName, st.Get("gorm"), st
+}
+
+// exprString prints a type expression such as *models.Category or []*Tag
+func exprString(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name
+	case *ast.StarExpr:
+		return "*" + exprString(expr.X)
+	case *ast.ArrayType:
+		return "[]" + exprString(expr.Elt)
+	case *ast.SelectorExpr:
+		return exprString(expr.X) + "." + expr.Sel.Name
+	}
+	return ""
+}
+
+// AlterFile adds what new fields change in a generated file to the file at path.
+// base is the template rendered with the module's existing fields and updated is the same template
+// with the new fields appended; every block of lines updated adds to base is inserted into the file
+// next to the same surrounding lines, so edits made since the file was generated are kept.
+// It returns the number of blocks that could not be placed.
+func AlterFile(path string, base, updated []byte) (int, error) {
+	return defaultWriter.AlterFile(path, base, updated)
+}
+
+// AlterFile is AlterFile for the generator of w
+func (w *Writer) AlterFile(path string, base, updated []byte) (int, error) {
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	base = normalizeContent(path, base)
+	updated = normalizeContent(path, updated)
+	merged, missed := mergeAdditions(splitLines(string(existing)), splitLines(string(base)), splitLines(string(updated)))
+
+	content := strings.Join(merged, "\n")
+	if content == string(existing) {
+		return missed, nil
+	}
+	return missed, w.UpdateProjectFile(path, []byte(content))
+}
+
+// splitLines splits text into lines, keeping a trailing newline as a final empty line
+func splitLines(text string) []string {
+	return strings.Split(text, "\n")
+}
+
+// addedBlock is a run of lines in the updated render that the base render does not have
+type addedBlock struct {
+	lines   []string
+	removed []string // Base lines the block replaces (e.g. a union type that gained a member)
+	before  []string // Up to three unchanged lines preceding the block
+	after   string   // First unchanged line following the block
+}
+
+// mergeAdditions inserts the blocks updated adds to base into existing and returns the result
+// with the number of blocks whose surrounding lines could not be found
+func mergeAdditions(existing, base, updated []string) ([]string, int) {
+	blocks := addedBlocks(base, updated)
+	result := append([]string(nil), existing...)
+	cursor, missed := 0, 0
+
+	for _, block := range blocks {
+		at := -1
+		if len(block.before) > 0 {
+			if end := findLines(result, cursor, block.before); end != -1 {
+				at = end + 1
+			}
+		}
+		if at == -1 && block.after != "" {
+			at = findLines(result, cursor, []string{block.after})
+		}
+		if at == -1 {
+			missed++
+			continue
+		}
+
+		// Skip blocks already present (e.g. an import the file has, or a re-run)
+		if containsBlock(result, at, block.lines) {
+			cursor = at
+			continue
+		}
+
+		// Changed lines replace the originals, which must still be unedited
+		replace := len(block.removed)
+		if replace > 0 && !hasLinesAt(result, at, block.removed) {
+			missed++
+			continue
+		}
+
+		result = append(result[:at], append(append([]string(nil), block.lines...), result[at+replace:]...)...)
+		cursor = at + len(block.lines)
+	}
+
+	return result, missed
+}
+
+// addedBlocks returns the runs of lines in updated that are not in base, using a longest common subsequence
+func addedBlocks(base, updated []string) []addedBlock {
+	n, m := len(base), len(updated)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if base[i] == updated[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var blocks []addedBlock
+	var current *addedBlock
+	var unchanged, removed []string
+	i, j := 0, 0
+	for j < m {
+		if i < n && base[i] == updated[j] {
+			if current != nil {
+				current.after = updated[j]
+				blocks = append(blocks, *current)
+				current = nil
+			}
+			unchanged = append(unchanged, updated[j])
+			removed = nil
+			i++
+			j++
+		} else if i < n && lcs[i+1][j] >= lcs[i][j+1] {
+			// Removed lines are only applied when a block replaces them
+			if current != nil {
+				current.removed = append(current.removed, base[i])
+			} else {
+				removed = append(removed, base[i])
+			}
+			i++
+		} else {
+			if current == nil {
+				current = &addedBlock{before: lastLines(unchanged, 3), removed: removed}
+			}
+			current.lines = append(current.lines, updated[j])
+			j++
+		}
+	}
+	if current != nil {
+		blocks = append(blocks, *current)
+	}
+
+	// Blank-only blocks add nothing worth placing
+	filtered := blocks[:0]
+	for _, block := range blocks {
+		if strings.TrimSpace(strings.Join(block.lines, "")) != "" {
+			filtered = append(filtered, block)
+		}
+	}
+	return filtered
+}
+
+// lastLines returns up to n lines from the end of lines
+func lastLines(lines []string, n int) []string {
+	if len(lines) < n {
+		n = len(lines)
+	}
+	return append([]string(nil), lines[len(lines)-n:]...)
+}
+
+// findLines returns the index of the last line of the first occurrence of want at or after from, or -1.
+// Leading context lines that don't match are dropped one at a time before giving up.
+func findLines(lines []string, from int, want []string) int {
+	for len(want) > 0 {
+		for i := from; i+len(want) <= len(lines); i++ {
+			if hasLinesAt(lines, i, want) {
+				return i + len(want) - 1
+			}
+		}
+		want = want[1:]
+	}
+	return -1
+}
+
+// hasLinesAt reports whether lines starting at index at match want, ignoring indentation
+func hasLinesAt(lines []string, at int, want []string) bool {
+	if at+len(want) > len(lines) {
+		return false
+	}
+	for k, line := range want {
+		if strings.TrimSpace(lines[at+k]) != strings.TrimSpace(line) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsBlock reports whether block's non-blank lines already appear near index at
+func containsBlock(lines []string, at int, block []string) bool {
+	start := max(at-len(block)-3, 0)
+	end := min(at+len(block)+3, len(lines))
+	window := map[string]bool{}
+	for _, line := range lines[start:end] {
+		window[strings.TrimSpace(line)] = true
+	}
+	for _, line := range block {
+		if trimmed := strings.TrimSpace(line); trimmed != "" && !window[trimmed] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OAuthProviders are the providers bui g auth --oauth supports
+var OAuthProviders = []string{"google", "github"}
+
+// oauthProviderNames are the display names of OAuthProviders
+var oauthProviderNames = map[string]string{
+	"google": "Google",
+	"github": "GitHub",
+}
+
+// AuthFeatures selects what bui g auth adds to the template's email/password login
+type AuthFeatures struct {
+	Providers []string // OAuth providers, from --oauth
+	MagicLink bool
+	TOTP      bool
+}
+
+// ParseOAuthProviders splits --oauth google,github and checks each provider
+func ParseOAuthProviders(list string) ([]string, error) {
+	var providers []string
+	for _, name := range splitColumns(strings.ToLower(list)) {
+		known := false
+		for _, provider := range OAuthProviders {
+			known = known || provider == name
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown OAuth provider %q (supported: %s)", name, strings.Join(OAuthProviders, ", "))
+		}
+		providers = append(providers, name)
+	}
+	return providers, nil
+}
+
+// OAuth reports whether any OAuth provider was selected
+func (f AuthFeatures) OAuth() bool {
+	return len(f.Providers) > 0
+}
+
+// HasProvider reports whether an OAuth provider was selected
+func (f AuthFeatures) HasProvider(name string) bool {
+	for _, provider := range f.Providers {
+		if provider == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Union returns the features selected in either f or other, providers in OAuthProviders order
+func (f AuthFeatures) Union(other AuthFeatures) AuthFeatures {
+	union := AuthFeatures{MagicLink: f.MagicLink || other.MagicLink, TOTP: f.TOTP || other.TOTP}
+	for _, provider := range OAuthProviders {
+		if f.HasProvider(provider) || other.HasProvider(provider) {
+			union.Providers = append(union.Providers, provider)
+		}
+	}
+	return union
+}
+
+// Summary describes the features in a phrase such as "Google sign-in and magic links"
+func (f AuthFeatures) Summary() string {
+	var parts []string
+	if f.OAuth() {
+		names := make([]string, len(f.Providers))
+		for i, provider := range f.Providers {
+			names[i] = oauthProviderNames[provider]
+		}
+		parts = append(parts, strings.Join(names, " and ")+" sign-in")
+	}
+	if f.MagicLink {
+		parts = append(parts, "magic links")
+	}
+	if f.TOTP {
+		parts = append(parts, "TOTP two-factor authentication")
+	}
+	if len(parts) < 2 {
+		return strings.Join(parts, "")
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1]
+}
+
+// Empty reports whether nothing was selected
+func (f AuthFeatures) Empty() bool {
+	return !f.OAuth() && !f.MagicLink && !f.TOTP
+}
+
+// EnvVars returns the .env placeholders the selected features read
+func (f AuthFeatures) EnvVars() []EnvVar {
+	vars := []EnvVar{
+		{Key: "FRONTEND_URL", Value: "http://localhost:3000", Comment: "Where sign-in links and OAuth callbacks send the browser"},
+	}
+	if f.HasProvider("google") {
+		vars = append(vars,
+			EnvVar{Key: "GOOGLE_CLIENT_ID", Value: "your-google-client-id", Comment: "https://console.cloud.google.com/apis/credentials"},
+			EnvVar{Key: "GOOGLE_CLIENT_SECRET", Value: "your-google-client-secret"},
+		)
+	}
+	if f.HasProvider("github") {
+		vars = append(vars,
+			EnvVar{Key: "GITHUB_CLIENT_ID", Value: "your-github-client-id", Comment: "https://github.com/settings/developers"},
+			EnvVar{Key: "GITHUB_CLIENT_SECRET", Value: "your-github-client-secret"},
+		)
+	}
+	if f.OAuth() {
+		vars = append(vars, EnvVar{Key: "OAUTH_CALLBACK_URL", Value: "http://localhost:8000/api/auth/oauth", Comment: "Public URL of the OAuth routes; providers call back to <this>/<provider>/callback"})
+	}
+	if f.MagicLink {
+		vars = append(vars,
+			EnvVar{Key: "SMTP_HOST", Value: "", Comment: "Magic links are logged instead of mailed while SMTP_HOST is empty"},
+			EnvVar{Key: "SMTP_PORT", Value: "587"},
+			EnvVar{Key: "SMTP_USERNAME", Value: ""},
+			EnvVar{Key: "SMTP_PASSWORD", Value: ""},
+			EnvVar{Key: "MAIL_FROM", Value: "no-reply@example.com"},
+		)
+	}
+	if f.TOTP {
+		vars = append(vars, EnvVar{Key: "TOTP_ISSUER", Value: "Base", Comment: "Name authenticator apps show for the account"})
+	}
+	return vars
+}
@@ -0,0 +1,240 @@
+package utils
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BackupRoot is the project-local directory where destroyed and overwritten files are kept
+var BackupRoot = filepath.Join(".bui", "backups")
+
+// BackupManifest records what a backup contains and how to restore it
+type BackupManifest struct {
+	Module     string    `json:"module"`
+	Reason     string    `json:"reason"` // "destroy" or "overwrite"
+	CreatedAt  time.Time `json:"created_at"`
+	Paths      []string  `json:"paths"`
+	Registered bool      `json:"registered"`            // Module was registered in app/init.go
+	BackendDir string    `json:"backend_dir,omitempty"` // Backend directory holding app/init.go
+	Namespace  string    `json:"namespace,omitempty"`   // Namespace of a module in app/<namespace>
+
+	// Sidebar entry removed from the frontend's NavigationFile
+	Navigation  *NavigationItem `json:"navigation,omitempty"`
+	FrontendDir string          `json:"frontend_dir,omitempty"`
+
+	// Dashboard widgets removed from the frontend's DashboardFile
+	Widgets []DashboardWidget `json:"widgets,omitempty"`
+
+	// Sidebar entries of the module's report pages removed from NavigationFile
+	Reports []NavigationItem `json:"reports,omitempty"`
+}
+
+// Backup is a directory of moved-aside files plus its manifest
+type Backup struct {
+	Dir      string
+	Manifest BackupManifest
+}
+
+// BackupName returns the name module's backups are made and restored under: its model in
+// snake_case, without the namespace (shop/product is product), which bui restore takes
+func BackupName(module string) string {
+	return NewNamingConvention(module).ModelSnake
+}
+
+// NewBackup creates an empty backup directory for module
+func NewBackup(module, reason string) (*Backup, error) {
+	return newBackupIn(".", module, reason)
+}
+
+// newBackupIn creates an empty backup directory for module in the BackupRoot of dir
+func newBackupIn(dir, module, reason string) (*Backup, error) {
+	now := time.Now()
+	naming := NewNamingConvention(module)
+	name := fmt.Sprintf("%s-%s-%s", now.Format("20060102-150405"), reason, BackupName(module))
+	dir = filepath.Join(dir, BackupRoot, name)
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	return &Backup{
+		Dir: dir,
+		Manifest: BackupManifest{
+			Module:    naming.Model,
+			Reason:    reason,
+			CreatedAt: now,
+			Namespace: naming.Namespace,
+		},
+	}, nil
+}
+
+// Move moves path (a file or directory) into the backup
+func (b *Backup) Move(path string) error {
+	target := filepath.Join(b.Dir, path)
+	if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return err
+	}
+	if err := os.Rename(path, target); err != nil {
+		return err
+	}
+
+	b.Manifest.Paths = append(b.Manifest.Paths, path)
+	return b.Save()
+}
+
+// Copy copies a single file into the backup, leaving the original in place
+func (b *Backup) Copy(path string) error {
+	return b.copyAs(path, path)
+}
+
+// copyAs copies the file at path into the backup, recording it as rel
+func (b *Backup) copyAs(path, rel string) error {
+	target := filepath.Join(b.Dir, rel)
+	if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return err
+	}
+	if err := copyBackupFile(path, target); err != nil {
+		return err
+	}
+
+	b.Manifest.Paths = append(b.Manifest.Paths, rel)
+	return b.Save()
+}
+
+// Save writes the manifest next to the backed up files
+func (b *Backup) Save() error {
+	content, err := json.MarshalIndent(b.Manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(b.Dir, "manifest.json"), content, 0644)
+}
+
+// Restore moves every backed up path back to its original location. Destroyed paths must not
+// exist again; overwritten files replace what was generated over them.
+func (b *Backup) Restore() error {
+	for _, path := range b.Manifest.Paths {
+		if _, err := os.Stat(path); err == nil && b.Manifest.Reason != "overwrite" {
+			return fmt.Errorf("%s already exists", path)
+		}
+	}
+
+	for _, path := range b.Manifest.Paths {
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			return err
+		}
+		if err := os.Rename(filepath.Join(b.Dir, path), path); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", path, err)
+		}
+	}
+
+	return os.RemoveAll(b.Dir)
+}
+
+// ListBackups returns all backups under BackupRoot, newest first
+func ListBackups() ([]*Backup, error) {
+	entries, err := os.ReadDir(BackupRoot)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var backups []*Backup
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(BackupRoot, entry.Name())
+		content, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+		if err != nil {
+			continue
+		}
+		var manifest BackupManifest
+		if err := json.Unmarshal(content, &manifest); err != nil {
+			continue
+		}
+		backups = append(backups, &Backup{Dir: dir, Manifest: manifest})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Manifest.CreatedAt.After(backups[j].Manifest.CreatedAt)
+	})
+
+	return backups, nil
+}
+
+// LatestBackup returns the most recent backup of module made for reason, or for any reason
+// when reason is "". A namespaced module is found with or without its namespace.
+func LatestBackup(module, reason string) (*Backup, error) {
+	backups, err := ListBackups()
+	if err != nil {
+		return nil, err
+	}
+
+	model := NewNamingConvention(module).Model
+	for _, backup := range backups {
+		if (reason == "" || backup.Manifest.Reason == reason) && strings.EqualFold(backup.Manifest.Module, model) {
+			return backup, nil
+		}
+	}
+
+	if reason == "" {
+		return nil, fmt.Errorf("no backup found for %s", model)
+	}
+	return nil, fmt.Errorf("no %s backup found for %s", reason, model)
+}
+
+// OverwriteBackupDir returns where the files w overwrote were saved, if any
+func (w *Writer) OverwriteBackupDir() string {
+	if !w.backedUp {
+		return ""
+	}
+	if abs, err := filepath.Abs(w.Run.backup.Dir); err == nil {
+		return abs
+	}
+	return w.Run.backup.Dir
+}
+
+// backupOverwritten saves the current contents of path before a generator replaces it, in the
+// run's backup in the project root, where bui restore finds it by the module's name
+func (w *Writer) backupOverwritten(path string) error {
+	w.Run.mu.Lock()
+	defer w.Run.mu.Unlock()
+
+	if w.Run.backup == nil {
+		backup, err := newBackupIn(w.Run.Root, cmp.Or(w.Run.Module, "files"), "overwrite")
+		if err != nil {
+			return err
+		}
+		w.Run.backup = backup
+	}
+	w.backedUp = true
+	return w.Run.backup.copyAs(path, filepath.FromSlash(relativeToRoot(w.Run.Root, path)))
+}
+
+// copyBackupFile copies a single file
+func copyBackupFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
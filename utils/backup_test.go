@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRestoreOverwriteOfNamespacedModule(t *testing.T) {
+	root := t.TempDir()
+	t.Chdir(root)
+	Force = true
+	defer func() { Force = false }()
+
+	path := filepath.Join("api", "app", "shop", "products", "service.go")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("package products // edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewRun(root, "shop/product").Writer("api")
+	if err := w.WriteGeneratedFile(w.Path("app", "shop", "products", "service.go"), []byte("package products\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	backups, err := ListBackups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 {
+		t.Fatalf("expected the overwrite backup in %s, found %d backups", BackupRoot, len(backups))
+	}
+	backup, err := LatestBackup("shop/product", "overwrite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := backup.Restore(); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(restored) != "package products // edited\n" {
+		t.Errorf("expected the edited service.go back, got %q", restored)
+	}
+}
@@ -0,0 +1,28 @@
+package utils
+
+// BulkStatusIndex returns the index of the field bulk updates set: the select field named
+// status, or else the first select field. Checkbox fields hold several values and are skipped,
+// as are fields updates leave out (--fields-write). It returns -1 when the fields have no such select.
+func BulkStatusIndex(fields []Field) int {
+	index := -1
+	for i, field := range fields {
+		if !field.IsSelect || field.SelectType == "checkbox" || len(field.Options) == 0 || field.NoUpdate {
+			continue
+		}
+		if field.JSONName == "status" {
+			return i
+		}
+		if index == -1 {
+			index = i
+		}
+	}
+	return index
+}
+
+// bulkStatusField returns the field bulk updates set, or nil
+func bulkStatusField(fields []Field) *Field {
+	if i := BulkStatusIndex(fields); i != -1 {
+		return &fields[i]
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/base-al/bui/version"
+	"github.com/base-go/mamba"
+	"github.com/spf13/pflag"
+)
+
+// ChangelogFile is the file in the project root the generators record what they wrote in
+const ChangelogFile = "CHANGELOG.md"
+
+// generationSkippedFlags change how a generator runs rather than what it generates, so they're
+// left out of the recorded command
+var generationSkippedFlags = map[string]bool{
+	"dry-run": true, "diff": true, "force": true, "from": true,
+	"verbose": true, "no-update-check": true, "help": true, "yes": true,
+}
+
+// safeShellArg matches the arguments that read the same in a shell without quotes
+var safeShellArg = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// ProjectRoot returns the root of the project: where .bui.yaml is, else dir
+func ProjectRoot(dir string) string {
+	if root := Project.Root(); root != "" {
+		return root
+	}
+	return dir
+}
+
+// GenerationCommand returns the bui command line that generates what cmd generated from args,
+// with the flags that differ from their defaults, e.g.
+// bui generate product name:string price:float --lock-version
+func GenerationCommand(cmd *mamba.Command, args []string) string {
+	parts := commandPath(cmd)
+	for _, arg := range args {
+		parts = append(parts, shellArg(arg))
+	}
+	for _, flag := range generationFlags(cmd) {
+		if name, value, ok := strings.Cut(flag, "="); ok {
+			flag = name + "=" + shellArg(value)
+		}
+		parts = append(parts, flag)
+	}
+	return strings.Join(parts, " ")
+}
+
+// commandPath returns the names of cmd and its parents, e.g. bui generate backend
+func commandPath(cmd *mamba.Command) []string {
+	var names []string
+	for c := cmd; c != nil; c = c.Parent() {
+		names = append([]string{c.Name()}, names...)
+	}
+	return names
+}
+
+// generationFlags returns the flags of cmd that differ from their defaults and change what it
+// generates, as --name=value, or --name for bool flags that are set
+func generationFlags(cmd *mamba.Command) []string {
+	var flags []string
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		// Defaults from .bui.yaml are set without marking the flags changed, so compare values
+		if generationSkippedFlags[flag.Name] || flag.Value.String() == flag.DefValue {
+			return
+		}
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			for _, value := range slice.GetSlice() {
+				flags = append(flags, "--"+flag.Name+"="+value)
+			}
+			return
+		}
+		if flag.Value.Type() == "bool" && flag.Value.String() == "true" {
+			flags = append(flags, "--"+flag.Name)
+			return
+		}
+		flags = append(flags, "--"+flag.Name+"="+flag.Value.String())
+	})
+	return flags
+}
+
+// shellArg quotes s for a POSIX shell when it has characters the shell would read differently
+func shellArg(s string) string {
+	if safeShellArg.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// recordChangelog appends the files w wrote to the CHANGELOG.md in the project root, under the
+// run's entry
+func (w *Writer) recordChangelog(command, part string) error {
+	root := w.Run.Root
+	written, skipped := w.GeneratedFiles()
+	if len(written) == 0 && len(skipped) == 0 && len(w.updated) == 0 {
+		return nil
+	}
+
+	path := filepath.Join(root, ChangelogFile)
+	var b strings.Builder
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		b.WriteString("# Changelog\n\nWhat bui generated in this project, newest last. Re-run a command to regenerate its files.\n")
+		w.Run.logged = false
+	}
+	if !w.Run.logged {
+		fmt.Fprintf(&b, "\n## %s — `%s`\n\n", time.Now().Format("2006-01-02 15:04"), command)
+		fmt.Fprintf(&b, "Generated by bui %s.\n", version.Version)
+		w.Run.logged = true
+	}
+
+	fmt.Fprintf(&b, "\n### %s\n\n", part)
+	for _, entry := range []struct {
+		verb  string
+		files []string
+	}{{"Wrote", written}, {"Updated", w.updated}, {"Kept", skipped}, {"Left conflict markers in", w.conflicted}} {
+		for _, file := range entry.files {
+			fmt.Fprintf(&b, "- %s `%s`\n", entry.verb, relativeToRoot(root, file))
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", ChangelogFile, err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(b.String()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ChangelogFile, err)
+	}
+	return nil
+}
+
+// relativeToRoot returns path, relative to the working directory, relative to root instead
+func relativeToRoot(root, path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	if rel, err := filepath.Rel(root, abs); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(path)
+}
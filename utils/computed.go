@@ -0,0 +1,55 @@
+package utils
+
+import "strings"
+
+// ComputedFields returns the computed fields of a model, with their sources resolved: the text
+// fields a computed field names, or else the ones sharing the last word of its name (first_name
+// and last_name for full_name)
+func ComputedFields(fields []Field) []Field {
+	var computed []Field
+	for _, field := range fields {
+		if !field.IsComputed {
+			continue
+		}
+
+		named := map[string]bool{}
+		for _, source := range field.Sources {
+			named[source] = true
+		}
+		suffix := ""
+		if i := strings.LastIndex(field.JSONName, "_"); i >= 0 {
+			suffix = field.JSONName[i:]
+		}
+
+		var sources []string
+		for _, source := range fields {
+			if !isComputedSource(source) {
+				continue
+			}
+			if named[source.JSONName] || (len(field.Sources) == 0 && suffix != "" && strings.HasSuffix(source.JSONName, suffix)) {
+				sources = append(sources, source.JSONName)
+			}
+		}
+		field.Sources = sources
+		computed = append(computed, field)
+	}
+	return computed
+}
+
+// StoredFields returns the fields of a model that have a column or relation, leaving out the
+// computed ones
+func StoredFields(fields []Field) []Field {
+	var stored []Field
+	for _, field := range fields {
+		if !field.IsComputed {
+			stored = append(stored, field)
+		}
+	}
+	return stored
+}
+
+// isComputedSource reports whether a computed field can derive from field, a plain text field
+func isComputedSource(field Field) bool {
+	return (field.Type == "string" || field.Type == "text" || field.Type == "email") &&
+		!field.IsRelation && !field.IsMediaFK && !field.IsSelect && !field.IsComputed
+}
@@ -0,0 +1,324 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectConfigFile is the project configuration file bui looks for in the working directory and its parents
+const ProjectConfigFile = ".bui.yaml"
+
+// Defaults used when the project config leaves a setting out
+const (
+	DefaultBackendPort    = 8000
+	DefaultFrontendPort   = 3030
+	DefaultPackageManager = "bun"
+)
+
+// ProjectConfig holds the settings of a project's .bui.yaml
+type ProjectConfig struct {
+	Backend         string                `yaml:"backend,omitempty"`  // Backend directory, relative to the config file
+	Frontend        string                `yaml:"frontend,omitempty"` // Frontend directory, relative to the config file
+	Ports           PortsConfig           `yaml:"ports,omitempty"`
+	Templates       TemplatesConfig       `yaml:"templates,omitempty"`
+	TemplateCommits TemplateCommitsConfig `yaml:"templateCommits,omitempty"` // Template commits the project is up to date with
+	PackageManager  string                `yaml:"packageManager,omitempty"`  // bun, npm, pnpm or yarn
+	Database        string                `yaml:"database,omitempty"`        // postgres, mysql or sqlite
+	Flags           map[string]any        `yaml:"flags,omitempty"`           // Flag defaults, e.g. no-tests: true
+	Deploy          DeployConfig          `yaml:"deploy,omitempty"`
+
+	// Shell commands run before and after generate, destroy and new
+	PreGenerate  []Hook `yaml:"pre_generate,omitempty"`
+	PostGenerate []Hook `yaml:"post_generate,omitempty"`
+	PreDestroy   []Hook `yaml:"pre_destroy,omitempty"`
+	PostDestroy  []Hook `yaml:"post_destroy,omitempty"`
+	PreNew       []Hook `yaml:"pre_new,omitempty"`
+	PostNew      []Hook `yaml:"post_new,omitempty"`
+
+	// root is the directory the config file was read from
+	root string
+}
+
+// PortsConfig holds the ports the development servers listen on
+type PortsConfig struct {
+	Backend  int `yaml:"backend,omitempty"`
+	Frontend int `yaml:"frontend,omitempty"`
+}
+
+// TemplatesConfig overrides the repositories bui new clones, as owner/repo paths or clone URLs
+type TemplatesConfig struct {
+	Backend  string `yaml:"backend,omitempty"`
+	Frontend string `yaml:"frontend,omitempty"`
+}
+
+// TemplateCommitsConfig records the template commits a project was created from or last updated to
+type TemplateCommitsConfig struct {
+	Backend  string `yaml:"backend,omitempty"`
+	Frontend string `yaml:"frontend,omitempty"`
+}
+
+// DeployConfig holds the settings of each bui deploy target. Secrets such as the CapRover
+// password come from the environment instead.
+type DeployConfig struct {
+	CapRover CapRoverDeployConfig `yaml:"caprover,omitempty"`
+	SSH      SSHDeployConfig      `yaml:"ssh,omitempty"`
+	Docker   DockerDeployConfig   `yaml:"docker,omitempty"`
+	FlyIO    FlyIODeployConfig    `yaml:"flyio,omitempty"`
+}
+
+// CapRoverDeployConfig points bui deploy caprover at a CapRover app
+type CapRoverDeployConfig struct {
+	URL string `yaml:"url,omitempty"` // Dashboard URL, e.g. https://captain.example.com
+	App string `yaml:"app,omitempty"`
+}
+
+// SSHDeployConfig describes the server bui deploy ssh copies the build to
+type SSHDeployConfig struct {
+	Host    string `yaml:"host,omitempty"`    // user@host
+	Port    int    `yaml:"port,omitempty"`    // Defaults to 22
+	Path    string `yaml:"path,omitempty"`    // Defaults to /opt/<project>
+	Service string `yaml:"service,omitempty"` // systemd unit name, defaults to the project name
+}
+
+// DockerDeployConfig describes the container bui deploy docker runs
+type DockerDeployConfig struct {
+	Host  string `yaml:"host,omitempty"`  // Docker host, e.g. ssh://deploy@example.com; the local daemon when empty
+	Image string `yaml:"image,omitempty"` // Defaults to <project>:latest
+	Name  string `yaml:"name,omitempty"`  // Container name, defaults to the project name
+	Port  int    `yaml:"port,omitempty"`  // Host port for the API, defaults to 8000
+}
+
+// FlyIODeployConfig names the Fly.io app bui deploy flyio deploys to
+type FlyIODeployConfig struct {
+	App    string `yaml:"app,omitempty"`
+	Region string `yaml:"region,omitempty"`
+}
+
+// Hook is a shell command run around a bui command
+type Hook struct {
+	Run string `yaml:"run"`
+	Dir string `yaml:"dir,omitempty"` // backend, frontend, or a path relative to the config file; the project root when empty
+}
+
+// UnmarshalYAML accepts both "go vet ./..." and {run: go vet ./..., dir: backend}
+func (h *Hook) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		h.Run = node.Value
+		return nil
+	}
+
+	type rawHook Hook
+	var raw rawHook
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	*h = Hook(raw)
+	return nil
+}
+
+// Project is the loaded project config, or nil when no .bui.yaml was found
+var Project *ProjectConfig
+
+// LoadProjectConfig reads the nearest .bui.yaml from the working directory or its parents into Project
+func LoadProjectConfig() error {
+	Project = nil
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	for {
+		path := filepath.Join(dir, ProjectConfigFile)
+		if content, err := os.ReadFile(path); err == nil {
+			config := &ProjectConfig{root: dir}
+			if err := yaml.Unmarshal(content, config); err != nil {
+				return fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+			Project = config
+			return nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+}
+
+// WriteProjectConfig writes config as .bui.yaml in dir
+func WriteProjectConfig(dir string, config *ProjectConfig) error {
+	content, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	header := "# bui project settings: directories, ports, template repositories,\n# package manager and default flag values\n"
+	return os.WriteFile(filepath.Join(dir, ProjectConfigFile), append([]byte(header), content...), 0644)
+}
+
+// SetProjectConfigValue sets a top-level key in dir's .bui.yaml, keeping the rest of the file and its comments
+func SetProjectConfigValue(dir, key string, value any) error {
+	path := filepath.Join(dir, ProjectConfigFile)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		// An empty file
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	mapping := doc.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s is not a mapping", path)
+	}
+
+	var valueNode yaml.Node
+	if err := valueNode.Encode(value); err != nil {
+		return err
+	}
+	found := false
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = &valueNode
+			found = true
+		}
+	}
+	if !found {
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, &valueNode)
+	}
+
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, 0644)
+}
+
+// Root returns the directory the config file was read from, or "" when there is no config
+func (c *ProjectConfig) Root() string {
+	if c == nil {
+		return ""
+	}
+	return c.root
+}
+
+// BackendDir returns the configured backend directory relative to the working directory, or "" when unset
+func (c *ProjectConfig) BackendDir() string {
+	if c == nil || c.Backend == "" {
+		return ""
+	}
+	return c.relativeDir(c.Backend)
+}
+
+// FrontendDir returns the configured frontend directory relative to the working directory, or "" when unset
+func (c *ProjectConfig) FrontendDir() string {
+	if c == nil || c.Frontend == "" {
+		return ""
+	}
+	return c.relativeDir(c.Frontend)
+}
+
+// relativeDir resolves a directory from the config file against the working directory
+func (c *ProjectConfig) relativeDir(dir string) string {
+	path := filepath.Join(c.root, dir)
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(wd, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+// PackageManagerName returns the package manager used for frontend installs and scripts
+func (c *ProjectConfig) PackageManagerName() string {
+	if c == nil || c.PackageManager == "" {
+		return DefaultPackageManager
+	}
+	return c.PackageManager
+}
+
+// DatabaseName returns the database generated code and files target
+func (c *ProjectConfig) DatabaseName() string {
+	if c == nil || c.Database == "" {
+		return Postgres
+	}
+	return c.Database
+}
+
+// BackendTemplate returns the backend template repository, or fallback when not overridden
+func (c *ProjectConfig) BackendTemplate(fallback string) string {
+	if c == nil || c.Templates.Backend == "" {
+		return fallback
+	}
+	return c.Templates.Backend
+}
+
+// FrontendTemplate returns the frontend template repository, or fallback when not overridden
+func (c *ProjectConfig) FrontendTemplate(fallback string) string {
+	if c == nil || c.Templates.Frontend == "" {
+		return fallback
+	}
+	return c.Templates.Frontend
+}
+
+// DeploySettings returns the deploy section of the config, empty when there is no config
+func (c *ProjectConfig) DeploySettings() DeployConfig {
+	if c == nil {
+		return DeployConfig{}
+	}
+	return c.Deploy
+}
+
+// Hooks returns the hooks configured for an event such as "post_generate"
+func (c *ProjectConfig) Hooks(event string) []Hook {
+	if c == nil {
+		return nil
+	}
+	switch event {
+	case "pre_generate":
+		return c.PreGenerate
+	case "post_generate":
+		return c.PostGenerate
+	case "pre_destroy":
+		return c.PreDestroy
+	case "post_destroy":
+		return c.PostDestroy
+	case "pre_new":
+		return c.PreNew
+	case "post_new":
+		return c.PostNew
+	}
+	return nil
+}
+
+// FlagDefaults returns the configured flag defaults as strings keyed by flag name.
+// Lists become comma separated values so they can be set like slice flags.
+func (c *ProjectConfig) FlagDefaults() map[string]string {
+	defaults := map[string]string{}
+	if c == nil {
+		return defaults
+	}
+	for name, value := range c.Flags {
+		if list, ok := value.([]any); ok {
+			items := make([]string, len(list))
+			for i, item := range list {
+				items[i] = fmt.Sprint(item)
+			}
+			defaults[name] = strings.Join(items, ",")
+			continue
+		}
+		defaults[name] = fmt.Sprint(value)
+	}
+	return defaults
+}
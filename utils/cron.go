@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronDescriptors are the shorthand schedules robfig/cron's standard parser accepts
+var cronDescriptors = map[string]bool{
+	"@yearly": true, "@annually": true, "@monthly": true, "@weekly": true,
+	"@daily": true, "@midnight": true, "@hourly": true,
+}
+
+// cronFields are the five fields of a standard cron expression with their ranges
+var cronFields = []struct {
+	name     string
+	min, max int
+	names    []string // Three-letter names accepted in place of numbers, starting at min
+}{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{name: "day of week", min: 0, max: 6, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+// ValidateCron checks a schedule the way the generated scheduler will parse it: five fields
+// (minute hour day-of-month month day-of-week), a descriptor such as @daily, or @every <duration>
+func ValidateCron(spec string) error {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return fmt.Errorf("empty cron schedule")
+	}
+
+	if strings.HasPrefix(spec, "@") {
+		if cronDescriptors[spec] {
+			return nil
+		}
+		if every, ok := strings.CutPrefix(spec, "@every "); ok {
+			if d, err := time.ParseDuration(strings.TrimSpace(every)); err != nil || d <= 0 {
+				return fmt.Errorf("invalid duration in %q", spec)
+			}
+			return nil
+		}
+		return fmt.Errorf("unknown cron descriptor %q", spec)
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(cronFields) {
+		return fmt.Errorf("cron schedule %q has %d fields, expected 5 (minute hour day-of-month month day-of-week)", spec, len(parts))
+	}
+	for i, part := range parts {
+		field := cronFields[i]
+		for _, item := range strings.Split(part, ",") {
+			if err := validateCronItem(item, field.min, field.max, field.names); err != nil {
+				return fmt.Errorf("invalid %s %q in %q: %w", field.name, item, spec, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateCronItem checks one comma-separated item: *, a value, a range, each with an optional /step
+func validateCronItem(item string, min, max int, names []string) error {
+	rangePart, step, hasStep := strings.Cut(item, "/")
+	if hasStep {
+		n, err := strconv.Atoi(step)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("step must be a positive number")
+		}
+	}
+
+	if rangePart == "*" || rangePart == "?" {
+		return nil
+	}
+
+	low, high, isRange := strings.Cut(rangePart, "-")
+	from, err := cronValue(low, min, max, names)
+	if err != nil {
+		return err
+	}
+	if !isRange {
+		return nil
+	}
+	to, err := cronValue(high, min, max, names)
+	if err != nil {
+		return err
+	}
+	if from > to {
+		return fmt.Errorf("range runs backwards")
+	}
+	return nil
+}
+
+// cronValue parses a number or name within a field's range
+func cronValue(value string, min, max int, names []string) (int, error) {
+	for i, name := range names {
+		if strings.EqualFold(value, name) {
+			return min + i, nil
+		}
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", value)
+	}
+	if n < min || n > max {
+		return 0, fmt.Errorf("%d is outside %d-%d", n, min, max)
+	}
+	return n, nil
+}
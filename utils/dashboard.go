@@ -0,0 +1,168 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// WidgetTypes are the dashboard widgets bui g widget generates
+var WidgetTypes = []string{"stat", "chart", "recent"}
+
+// DashboardFile lists the dashboard widgets of the generated modules, relative to the frontend
+var DashboardFile = filepath.Join("app", "config", "dashboard.ts")
+
+// dashboardHeader starts a new DashboardFile; the DashboardWidgets component shows dashboardWidgets
+const dashboardHeader = `// Widgets of the generated modules, shown by <DashboardWidgets /> on the admin dashboard in
+// this order. bui g widget adds a widget and bui d removes a module's widgets; entries can be
+// reordered or removed by hand.
+import type { AsyncComponentLoader } from 'vue'
+
+export interface DashboardWidget {
+  id: string
+  component: AsyncComponentLoader
+  wide?: boolean // Spans two columns
+}
+
+export const dashboardWidgets: DashboardWidget[] = [
+]
+`
+
+// DashboardWidget is a widget's entry in DashboardFile
+type DashboardWidget struct {
+	ID        string `json:"id"`
+	Component string `json:"component"` // Import path of the widget's component
+	Wide      bool   `json:"wide,omitempty"`
+}
+
+// dashboardWidgetPattern matches an entry line written by AddDashboardWidget
+var dashboardWidgetPattern = regexp.MustCompile(`^  \{ id: '([^']*)', component: \(\) => import\('([^']*)'\)(, wide: true)? \},\n$`)
+
+// NewDashboardWidget returns the entry of a module's widget of the given type
+func NewDashboardWidget(naming *NamingConvention, widgetType string) DashboardWidget {
+	return DashboardWidget{
+		ID:        naming.Slug + "-" + widgetType,
+		Component: fmt.Sprintf("~/modules/%s/components/%s%sWidget.vue", naming.PluralSnake, naming.Model, ToCapitalCase(widgetType)),
+		Wide:      widgetType == "chart",
+	}
+}
+
+// line returns the entry as AddDashboardWidget writes it
+func (widget DashboardWidget) line() string {
+	line := fmt.Sprintf("  { id: '%s', component: () => import('%s')", widget.ID, widget.Component)
+	if widget.Wide {
+		line += ", wide: true"
+	}
+	return line + " },\n"
+}
+
+// AddDashboardWidget adds widget to the end of <frontendDir>/DashboardFile, creating the file when
+// needed. It reports whether the file changed; a widget that's already listed keeps its place.
+func AddDashboardWidget(frontendDir string, widget DashboardWidget) (bool, error) {
+	return defaultWriter.addDashboardWidget(filepath.Join(frontendDir, DashboardFile), widget)
+}
+
+// AddDashboardWidget is AddDashboardWidget for the generator of w, in the frontend in w.Dir
+func (w *Writer) AddDashboardWidget(widget DashboardWidget) (bool, error) {
+	return w.addDashboardWidget(w.Path(DashboardFile), widget)
+}
+
+// addDashboardWidget adds widget to the dashboard file at path
+func (w *Writer) addDashboardWidget(path string, widget DashboardWidget) (bool, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		content = []byte(dashboardHeader)
+	} else if err != nil {
+		return false, err
+	}
+	contentStr := string(content)
+
+	for _, existing := range dashboardWidgets(contentStr) {
+		if existing.ID == widget.ID {
+			return false, nil
+		}
+	}
+
+	end := strings.LastIndex(contentStr, "\n]")
+	if end == -1 {
+		return false, fmt.Errorf("could not find the end of dashboardWidgets in %s", DashboardFile)
+	}
+	contentStr = contentStr[:end+1] + widget.line() + contentStr[end+1:]
+
+	if err := w.UpdateProjectFile(path, []byte(contentStr)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RemoveDashboardWidgets removes the widgets of a module from <frontendDir>/DashboardFile and
+// returns them
+func RemoveDashboardWidgets(frontendDir string, naming *NamingConvention) ([]DashboardWidget, error) {
+	path := filepath.Join(frontendDir, DashboardFile)
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var removed []DashboardWidget
+	var kept strings.Builder
+	for _, line := range strings.SplitAfter(string(content), "\n") {
+		if widget, ok := parseDashboardWidget(line); ok && moduleWidget(widget, naming) {
+			removed = append(removed, widget)
+			continue
+		}
+		kept.WriteString(line)
+	}
+	if len(removed) == 0 {
+		return nil, nil
+	}
+
+	if err := UpdateProjectFile(path, []byte(kept.String())); err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+// FindDashboardWidgets returns the widgets of a module listed in <frontendDir>/DashboardFile
+func FindDashboardWidgets(frontendDir string, naming *NamingConvention) []DashboardWidget {
+	content, err := os.ReadFile(filepath.Join(frontendDir, DashboardFile))
+	if err != nil {
+		return nil
+	}
+	var found []DashboardWidget
+	for _, widget := range dashboardWidgets(string(content)) {
+		if moduleWidget(widget, naming) {
+			found = append(found, widget)
+		}
+	}
+	return found
+}
+
+// dashboardWidgets returns the entries of content
+func dashboardWidgets(content string) []DashboardWidget {
+	var widgets []DashboardWidget
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if widget, ok := parseDashboardWidget(line); ok {
+			widgets = append(widgets, widget)
+		}
+	}
+	return widgets
+}
+
+// parseDashboardWidget reads an entry line
+func parseDashboardWidget(line string) (DashboardWidget, bool) {
+	match := dashboardWidgetPattern.FindStringSubmatch(line)
+	if match == nil {
+		return DashboardWidget{}, false
+	}
+	return DashboardWidget{ID: match[1], Component: match[2], Wide: match[3] != ""}, true
+}
+
+// moduleWidget reports whether widget's component is in the module's directory
+func moduleWidget(widget DashboardWidget, naming *NamingConvention) bool {
+	return strings.HasPrefix(widget.Component, "~/modules/"+naming.PluralSnake+"/")
+}
@@ -0,0 +1,169 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Database flavors a project can target (--db, database in .bui.yaml)
+const (
+	Postgres = "postgres"
+	MySQL    = "mysql"
+	SQLite   = "sqlite"
+)
+
+// DatabaseFlag overrides the project's database for one command (--db)
+var DatabaseFlag string
+
+// Database returns the database generated code and files target: --db, else database in
+// .bui.yaml, else PostgreSQL
+func Database() string {
+	if DatabaseFlag != "" {
+		return DatabaseFlag
+	}
+	return Project.DatabaseName()
+}
+
+// CheckDatabase reports an unsupported --db value or database setting
+func CheckDatabase() error {
+	switch database := Database(); database {
+	case Postgres, MySQL, SQLite:
+		return nil
+	default:
+		return fmt.Errorf("unknown database %q (use postgres, mysql or sqlite)", database)
+	}
+}
+
+// DatabaseLabel returns the display name of a database flavor
+func DatabaseLabel(database string) string {
+	switch database {
+	case MySQL:
+		return "MySQL"
+	case SQLite:
+		return "SQLite"
+	}
+	return "PostgreSQL"
+}
+
+// DatabaseEnv returns the connection settings a new project's backend .env starts with.
+// SQLite keeps its file in storage/, which builds and deploys already persist.
+func DatabaseEnv(database, project string) []EnvVar {
+	name := strings.ReplaceAll(project, "-", "_")
+	switch database {
+	case MySQL:
+		return []EnvVar{
+			{Key: "DB_DRIVER", Value: MySQL},
+			{Key: "DB_HOST", Value: "127.0.0.1"},
+			{Key: "DB_PORT", Value: "3306"},
+			{Key: "DB_USER", Value: "root"},
+			{Key: "DB_NAME", Value: name},
+		}
+	case SQLite:
+		return []EnvVar{
+			{Key: "DB_DRIVER", Value: SQLite},
+			{Key: "DB_PATH", Value: "storage/" + name + ".db"},
+		}
+	}
+	return []EnvVar{
+		{Key: "DB_DRIVER", Value: Postgres},
+		{Key: "DB_HOST", Value: "localhost"},
+		{Key: "DB_PORT", Value: "5432"},
+		{Key: "DB_USER", Value: "postgres"},
+		{Key: "DB_NAME", Value: name},
+	}
+}
+
+// applyDatabaseTags adjusts the GORM tags of the model's columns to the database: JSON columns
+// get its JSON type, and on MySQL strings with an index or a default get a length, since TEXT
+// columns can have neither there
+func (td *TemplateData) applyDatabaseTags() {
+	database := Database()
+	if database == Postgres {
+		return
+	}
+
+	for i := range td.Fields {
+		field := &td.Fields[i]
+		var typed, sized, indexed bool
+		for _, setting := range gormSettings(*field) {
+			key, _, _ := strings.Cut(setting, ":")
+			switch key {
+			case "type":
+				typed = true
+			case "size":
+				sized = true
+			case "index", "uniqueIndex":
+				indexed = true
+			}
+		}
+		if typed {
+			continue
+		}
+
+		switch {
+		case field.Type == "json.RawMessage":
+			if database == MySQL {
+				addGORMSetting(field, "type:json")
+			} else {
+				addGORMSetting(field, "type:text")
+			}
+		case database == MySQL && (indexed || field.Default != "") && !sized && stringColumn(*field):
+			addGORMSetting(field, "size:255")
+		}
+	}
+}
+
+// stringColumn reports whether field is stored as a string, enums included
+func stringColumn(field Field) bool {
+	return field.Type == "string" || field.IsEnum
+}
+
+// sqlAutoIncrementID returns the definition of an auto-increment id primary key column
+func sqlAutoIncrementID() string {
+	switch Database() {
+	case MySQL:
+		return "id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY"
+	case SQLite:
+		return "id INTEGER PRIMARY KEY AUTOINCREMENT"
+	}
+	return "id BIGSERIAL PRIMARY KEY"
+}
+
+// sqlTimestampType returns the column type of timestamps
+func sqlTimestampType() string {
+	switch Database() {
+	case MySQL:
+		return "DATETIME(3)"
+	case SQLite:
+		return "DATETIME"
+	}
+	return "TIMESTAMPTZ"
+}
+
+// sqlJSONType returns the column type of JSON fields
+func sqlJSONType() string {
+	switch Database() {
+	case MySQL:
+		return "JSON"
+	case SQLite:
+		return "TEXT"
+	}
+	return "JSONB"
+}
+
+// sqlIndexIfNotExists returns the IF NOT EXISTS of CREATE INDEX, which MySQL doesn't have
+func sqlIndexIfNotExists() string {
+	if Database() == MySQL {
+		return ""
+	}
+	return " IF NOT EXISTS"
+}
+
+// sqlColumnIfExists returns clause for ADD COLUMN and DROP COLUMN on PostgreSQL, the only one of
+// the databases that has IF [NOT] EXISTS there
+func sqlColumnIfExists(clause string) string {
+	if Database() != Postgres {
+		return ""
+	}
+	return clause
+}
@@ -0,0 +1,411 @@
+package utils
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DiagramFormats are the formats bui schema diagram writes
+var DiagramFormats = []string{"mermaid", "dbml", "dot"}
+
+// SchemaDiagram is the entity-relationship diagram of a project's models
+type SchemaDiagram struct {
+	Tables    []DiagramTable
+	Relations []DiagramRelation
+}
+
+// DiagramTable is a model's table, or the join table of a many-to-many relation
+type DiagramTable struct {
+	Name    string
+	Model   string // Model stored in the table, or "" for a join table without a model
+	Join    bool   // The table joins the two sides of a many-to-many relation
+	Columns []DiagramColumn
+}
+
+// DiagramColumn is a column of a table
+type DiagramColumn struct {
+	Name       string
+	Type       string // SQL type, as the migrations write it
+	PrimaryKey bool
+	ForeignKey bool
+}
+
+// DiagramRelation is a foreign key: Table.Column refers to RefTable.RefColumn
+type DiagramRelation struct {
+	Table     string
+	Column    string
+	RefTable  string
+	RefColumn string
+	OneToOne  bool   // The referring table has at most one row per referenced row (has_one)
+	Kind      string // belongs_to, has_many, has_one or many_to_many, as the model declares it
+}
+
+// diagramModel is a model struct found while building the diagram
+type diagramModel struct {
+	name   string
+	table  string
+	fields []*ast.Field
+}
+
+// idType returns the SQL type of the model's id, for the columns that refer to it
+func (m *diagramModel) idType() string {
+	for _, field := range m.fields {
+		if len(field.Names) == 1 && (field.Names[0].Name == "Id" || field.Names[0].Name == "ID") {
+			return sqlColumnType(Field{Type: exprString(field.Type)})
+		}
+	}
+	return sqlColumnType(Field{Type: "uint"})
+}
+
+// BuildSchemaDiagram reads the model structs in the sources of app/models and returns their
+// tables, the join tables of their many-to-many relations and the foreign keys between them.
+// Models are the structs with a TableName method.
+func BuildSchemaDiagram(sources [][]byte) (*SchemaDiagram, error) {
+	models := map[string]*diagramModel{}
+	var names []string
+	for _, source := range sources {
+		file, err := parser.ParseFile(token.NewFileSet(), "", source, 0)
+		if err != nil {
+			return nil, err
+		}
+		found := RecoverModels(source)
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec, ok := spec.(*ast.TypeSpec)
+				if !ok || !slices.Contains(found, typeSpec.Name.Name) {
+					continue
+				}
+				model := &diagramModel{
+					name:   typeSpec.Name.Name,
+					table:  RecoverTableName(source, typeSpec.Name.Name),
+					fields: typeSpec.Type.(*ast.StructType).Fields.List,
+				}
+				if model.table == "" {
+					model.table = ToSnakeCase(ToPlural(model.name))
+				}
+				models[model.name] = model
+				names = append(names, model.name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	diagram := &SchemaDiagram{}
+	relations := map[string]bool{}
+	addRelation := func(relation DiagramRelation) {
+		key := relation.Table + "." + relation.Column + ">" + relation.RefTable
+		if !relations[key] {
+			relations[key] = true
+			diagram.Relations = append(diagram.Relations, relation)
+		}
+	}
+	// Join tables usually have a model of their own (PostTag for post_tags), which lists their
+	// columns; the others are added with the two id columns GORM gives them
+	joinTables := map[string]bool{}
+	modelTables := map[string]bool{}
+	for _, model := range models {
+		modelTables[model.table] = true
+	}
+
+	for _, name := range names {
+		model := models[name]
+		table := DiagramTable{Name: model.table, Model: model.name}
+		fieldNames := map[string]string{}
+		for _, field := range model.fields {
+			for _, ident := range field.Names {
+				fieldNames[ident.Name] = diagramColumnName(ident.Name, gormTagOf(field))
+			}
+		}
+
+		for _, field := range model.fields {
+			goType := exprString(field.Type)
+			settings := gormTagOf(field)
+			if len(field.Names) == 0 {
+				if goType == "gorm.Model" {
+					table.Columns = append(table.Columns,
+						DiagramColumn{Name: "id", Type: sqlColumnType(Field{Type: "uint"}), PrimaryKey: true},
+						DiagramColumn{Name: "created_at", Type: sqlTimestampType()},
+						DiagramColumn{Name: "updated_at", Type: sqlTimestampType()},
+						DiagramColumn{Name: "deleted_at", Type: sqlTimestampType()},
+					)
+				}
+				continue
+			}
+			if len(field.Names) != 1 || settings["-"] != "" {
+				continue
+			}
+			if goType == "gorm.DeletedAt" {
+				table.Columns = append(table.Columns, DiagramColumn{Name: fieldNames[field.Names[0].Name], Type: sqlTimestampType()})
+				continue
+			}
+			fieldName := field.Names[0].Name
+			related := strings.TrimPrefix(strings.TrimPrefix(goType, "[]"), "*")
+			other, known := models[related]
+
+			switch {
+			case strings.HasPrefix(goType, "[]") && known:
+				if joinTable := settings["many2many"]; joinTable != "" {
+					left, right := ToSnakeCase(model.name)+"_id", ToSnakeCase(other.name)+"_id"
+					if !joinTables[joinTable] && !modelTables[joinTable] {
+						diagram.Tables = append(diagram.Tables, DiagramTable{Name: joinTable, Columns: []DiagramColumn{
+							{Name: left, Type: model.idType(), PrimaryKey: true, ForeignKey: true},
+							{Name: right, Type: other.idType(), PrimaryKey: true, ForeignKey: true},
+						}})
+					}
+					joinTables[joinTable] = true
+					addRelation(DiagramRelation{Table: joinTable, Column: left, RefTable: model.table, RefColumn: "id", Kind: "many_to_many"})
+					addRelation(DiagramRelation{Table: joinTable, Column: right, RefTable: other.table, RefColumn: "id", Kind: "many_to_many"})
+					continue
+				}
+				foreignKey := settings["foreignKey"]
+				if foreignKey == "" {
+					foreignKey = model.name + "Id"
+				}
+				addRelation(DiagramRelation{Table: other.table, Column: diagramColumnName(foreignKey, nil), RefTable: model.table, RefColumn: "id", Kind: "has_many"})
+				continue
+			case strings.HasPrefix(goType, "[]*"):
+				continue // Relation to a model outside app/models, such as media
+			case known && !strings.HasPrefix(goType, "[]"):
+				foreignKey := settings["foreignKey"]
+				if foreignKey == "" {
+					foreignKey = fieldName + "Id"
+				}
+				if column, ok := fieldNames[foreignKey]; ok {
+					addRelation(DiagramRelation{Table: model.table, Column: column, RefTable: other.table, RefColumn: "id", Kind: "belongs_to"})
+					continue
+				}
+				if settings["foreignKey"] == "" {
+					foreignKey = model.name + "Id"
+				}
+				addRelation(DiagramRelation{Table: other.table, Column: diagramColumnName(foreignKey, nil), RefTable: model.table, RefColumn: "id", OneToOne: true, Kind: "has_one"})
+				continue
+			case strings.HasPrefix(goType, "*") && strings.Contains(goType, "."):
+				if _, ok := fieldNames[fieldName+"Id"]; ok {
+					continue // Relation object of a model outside app/models, such as media
+				}
+			}
+
+			column := DiagramColumn{
+				Name:       fieldNames[fieldName],
+				Type:       sqlColumnType(Field{Type: goType, GORM: `gorm:"` + rawGormTag(field) + `"`}),
+				PrimaryKey: fieldName == "Id" || fieldName == "ID" || hasSetting(settings, "primarykey"),
+			}
+			table.Columns = append(table.Columns, column)
+		}
+		diagram.Tables = append(diagram.Tables, table)
+	}
+
+	// Mark the join tables and the foreign key columns the relations found
+	for i := range diagram.Tables {
+		diagram.Tables[i].Join = joinTables[diagram.Tables[i].Name]
+		for j := range diagram.Tables[i].Columns {
+			for _, relation := range diagram.Relations {
+				if relation.Table == diagram.Tables[i].Name && relation.Column == diagram.Tables[i].Columns[j].Name {
+					diagram.Tables[i].Columns[j].ForeignKey = true
+				}
+			}
+		}
+	}
+	sort.SliceStable(diagram.Tables, func(i, j int) bool { return diagram.Tables[i].Name < diagram.Tables[j].Name })
+	sort.SliceStable(diagram.Relations, func(i, j int) bool {
+		a, b := diagram.Relations[i], diagram.Relations[j]
+		if a.Table != b.Table {
+			return a.Table < b.Table
+		}
+		return a.Column < b.Column
+	})
+	return diagram, nil
+}
+
+// Render writes the diagram in format: mermaid, dbml or dot
+func (d *SchemaDiagram) Render(format string) (string, error) {
+	switch format {
+	case "mermaid":
+		return d.mermaid(), nil
+	case "dbml":
+		return d.dbml(), nil
+	case "dot":
+		return d.dot(), nil
+	}
+	return "", fmt.Errorf("unknown format %q (use %s)", format, strings.Join(DiagramFormats, ", "))
+}
+
+// mermaid renders a Mermaid erDiagram
+func (d *SchemaDiagram) mermaid() string {
+	var b strings.Builder
+	b.WriteString("erDiagram\n")
+	for _, table := range d.Tables {
+		fmt.Fprintf(&b, "    %s {\n", table.Name)
+		for _, column := range table.Columns {
+			// Mermaid types are single words, so NUMERIC(10,2) is written as NUMERIC
+			columnType, _, _ := strings.Cut(column.Type, "(")
+			var keys []string
+			if column.PrimaryKey {
+				keys = append(keys, "PK")
+			}
+			if column.ForeignKey {
+				keys = append(keys, "FK")
+			}
+			fmt.Fprintf(&b, "        %s %s", strings.ReplaceAll(columnType, " ", "_"), column.Name)
+			if len(keys) > 0 {
+				b.WriteString(" " + strings.Join(keys, ","))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("    }\n")
+	}
+	for _, relation := range d.Relations {
+		cardinality := "o{"
+		if relation.OneToOne {
+			cardinality = "o|"
+		}
+		fmt.Fprintf(&b, "    %s ||--%s %s : %s\n", relation.RefTable, cardinality, relation.Table, strconv.Quote(relation.Column))
+	}
+	return b.String()
+}
+
+// dbml renders DBML, as dbdiagram.io reads it
+func (d *SchemaDiagram) dbml() string {
+	var b strings.Builder
+	for i, table := range d.Tables {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "Table %s {\n", table.Name)
+		primaryKeys := 0
+		for _, column := range table.Columns {
+			if column.PrimaryKey {
+				primaryKeys++
+			}
+		}
+		for _, column := range table.Columns {
+			fmt.Fprintf(&b, "  %s %s", column.Name, dbmlType(column.Type))
+			if column.PrimaryKey && primaryKeys == 1 {
+				b.WriteString(" [pk]")
+			}
+			b.WriteString("\n")
+		}
+		if primaryKeys > 1 {
+			var columns []string
+			for _, column := range table.Columns {
+				if column.PrimaryKey {
+					columns = append(columns, column.Name)
+				}
+			}
+			fmt.Fprintf(&b, "\n  indexes {\n    (%s) [pk]\n  }\n", strings.Join(columns, ", "))
+		}
+		if table.Join {
+			b.WriteString("\n  Note: 'Join table'\n")
+		}
+		b.WriteString("}\n")
+	}
+	if len(d.Relations) > 0 {
+		b.WriteString("\n")
+	}
+	for _, relation := range d.Relations {
+		operator := ">"
+		if relation.OneToOne {
+			operator = "-"
+		}
+		fmt.Fprintf(&b, "Ref: %s.%s %s %s.%s\n", relation.Table, relation.Column, operator, relation.RefTable, relation.RefColumn)
+	}
+	return b.String()
+}
+
+// dbmlType quotes column types with spaces or commas, which DBML otherwise can't read
+func dbmlType(columnType string) string {
+	if strings.ContainsAny(columnType, " ,") {
+		return strconv.Quote(columnType)
+	}
+	return columnType
+}
+
+// dot renders a Graphviz digraph with a record node per table
+func (d *SchemaDiagram) dot() string {
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "{", `\{`, "}", `\}`, "|", `\|`, "<", `\<`, ">", `\>`)
+	var b strings.Builder
+	b.WriteString("digraph schema {\n")
+	b.WriteString("    rankdir=LR;\n")
+	b.WriteString("    node [shape=record, fontname=\"Helvetica\", fontsize=10];\n")
+	b.WriteString("    edge [fontname=\"Helvetica\", fontsize=9];\n\n")
+	for _, table := range d.Tables {
+		rows := []string{escape.Replace(table.Name)}
+		for _, column := range table.Columns {
+			row := column.Name + " : " + column.Type
+			if column.PrimaryKey {
+				row += " (PK)"
+			} else if column.ForeignKey {
+				row += " (FK)"
+			}
+			rows = append(rows, escape.Replace(row)+`\l`)
+		}
+		fmt.Fprintf(&b, "    %s [label=\"{%s}\"];\n", strconv.Quote(table.Name), strings.Join(rows, "|"))
+	}
+	if len(d.Relations) > 0 {
+		b.WriteString("\n")
+	}
+	for _, relation := range d.Relations {
+		fmt.Fprintf(&b, "    %s -> %s [label=%s];\n", strconv.Quote(relation.Table), strconv.Quote(relation.RefTable), strconv.Quote(relation.Column))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// gormTagOf returns the settings of a struct field's gorm tag by key; flags such as primaryKey
+// map to themselves and gorm:"-" maps "-" to "-"
+func gormTagOf(field *ast.Field) map[string]string {
+	settings := map[string]string{}
+	for _, setting := range strings.Split(rawGormTag(field), ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(setting), ":")
+		if key == "" {
+			continue
+		}
+		if !ok {
+			value = key
+		}
+		settings[key] = value
+	}
+	return settings
+}
+
+// rawGormTag returns the gorm tag of a struct field
+func rawGormTag(field *ast.Field) string {
+	if field.Tag == nil {
+		return ""
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	return reflect.StructTag(tag).Get("gorm")
+}
+
+// hasSetting reports whether a gorm tag has a flag, ignoring case as GORM does
+func hasSetting(settings map[string]string, flag string) bool {
+	for key := range settings {
+		if strings.EqualFold(key, flag) {
+			return true
+		}
+	}
+	return false
+}
+
+// diagramColumnName returns the column of a struct field: its gorm column setting, or the
+// field name in snake case as GORM names it
+func diagramColumnName(fieldName string, settings map[string]string) string {
+	if column := settings["column"]; column != "" {
+		return column
+	}
+	return ToSnakeCase(fieldName)
+}
@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+)
+
+// EncryptedFields returns the fields a model stores encrypted
+func EncryptedFields(fields []Field) []Field {
+	var encrypted []Field
+	for _, field := range fields {
+		if field.IsEncrypted {
+			encrypted = append(encrypted, field)
+		}
+	}
+	return encrypted
+}
+
+// NewEncryptionKey returns a random AES-256 key, base64 encoded as ENCRYPTION_KEY takes it
+func NewEncryptionKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
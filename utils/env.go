@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strings"
+)
+
+// EnvVar is a setting a generator needs in the project's .env
+type EnvVar struct {
+	Key     string
+	Value   string // Placeholder written when the key is missing
+	Comment string // Optional, written above the key
+}
+
+// AddEnvPlaceholders appends the variables missing from a .env file under a section comment
+// and returns the keys it added. A missing file is left alone.
+func AddEnvPlaceholders(path, section string, vars []EnvVar) ([]string, error) {
+	return defaultWriter.AddEnvPlaceholders(path, section, vars)
+}
+
+// AddEnvPlaceholders is AddEnvPlaceholders for the generator of w
+func (w *Writer) AddEnvPlaceholders(path, section string, vars []EnvVar) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	existing := map[string]bool{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimPrefix(strings.TrimSpace(scanner.Text()), "export ")
+		if key, _, ok := strings.Cut(line, "="); ok && !strings.HasPrefix(line, "#") {
+			existing[strings.TrimSpace(key)] = true
+		}
+	}
+
+	var added []string
+	var b strings.Builder
+	for _, v := range vars {
+		if existing[v.Key] {
+			continue
+		}
+		if len(added) == 0 {
+			b.WriteString("\n# " + section + "\n")
+		}
+		if v.Comment != "" {
+			b.WriteString("# " + v.Comment + "\n")
+		}
+		b.WriteString(v.Key + "=" + v.Value + "\n")
+		added = append(added, v.Key)
+		existing[v.Key] = true
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	if len(content) > 0 && !bytes.HasSuffix(content, []byte("\n")) {
+		content = append(content, '\n')
+	}
+	return added, w.UpdateProjectFile(path, append(content, b.String()...))
+}
+
+// SetEnvValues sets variables in a .env file, replacing the values of the keys it has and
+// appending the others. A missing file is left alone.
+func SetEnvValues(path string, vars []EnvVar) error {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	values := map[string]string{}
+	for _, v := range vars {
+		values[v.Key] = v.Value
+	}
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimPrefix(strings.TrimSpace(line), "export ")
+		key, _, ok := strings.Cut(trimmed, "=")
+		if !ok || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if value, found := values[strings.TrimSpace(key)]; found {
+			lines[i] = strings.TrimSpace(key) + "=" + value
+			delete(values, strings.TrimSpace(key))
+		}
+	}
+	for _, v := range vars {
+		if _, missing := values[v.Key]; missing {
+			lines = append(lines, v.Key+"="+v.Value)
+		}
+	}
+	return UpdateProjectFile(path, []byte(strings.Join(lines, "\n")+"\n"))
+}
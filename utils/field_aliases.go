@@ -48,6 +48,12 @@ var FieldTypeAliases = []FieldTypeAlias{
 	{"toMany", "many_to_many", "", "relationship"},
 	{"to_many", "many_to_many", "", "relationship"},
 
+	// Polymorphic relationship types
+	{"morphTo", "morph_to", "", "relationship"},
+	{"morph_to", "morph_to", "", "relationship"},
+	{"morphMany", "morph_many", "", "relationship"},
+	{"morph_many", "morph_many", "", "relationship"},
+
 	// Date/time aliases
 	{"time", "time.Time", "time.Time", "basic"},
 	{"datetime", "types.DateTime", "types.DateTime", "basic"},
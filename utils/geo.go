@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PostGIS adds a PostGIS geography column per point field, which nearby searches use on PostgreSQL
+var PostGIS bool
+
+// GeoPoint is a point field with the latitude and longitude columns ExpandPoint made of it
+type GeoPoint struct {
+	Name     string // e.g., "Location"
+	JSONName string // e.g., "location"
+	Lat      Field
+	Lng      Field
+}
+
+// Column returns the name of the point's PostGIS geography column
+func (p GeoPoint) Column() string {
+	return p.JSONName + "_geog"
+}
+
+// ExpandPoint replaces a point field with its <name>_lat and <name>_lng columns.
+// Other fields are returned unchanged.
+func ExpandPoint(field Field) []Field {
+	if !field.IsPoint {
+		return []Field{field}
+	}
+
+	name := ToSnakeCase(field.Name)
+	columns := make([]Field, 0, 2)
+	for _, axis := range []string{"lat", "lng"} {
+		columns = append(columns, Field{
+			Name:       field.Name + ToPascalCase(axis),
+			Type:       "*float64",
+			JSONTag:    name + "_" + axis,
+			JSONName:   name + "_" + axis,
+			DBName:     name + "_" + axis,
+			IsRequired: field.IsRequired,
+			PointName:  field.Name,
+			PointAxis:  axis,
+		})
+	}
+	return columns
+}
+
+// GeoPoints returns the point fields among the expanded columns of a model
+func GeoPoints(fields []Field) []GeoPoint {
+	var points []GeoPoint
+	for i, field := range fields {
+		if field.PointAxis != "lat" || i+1 >= len(fields) || fields[i+1].PointName != field.PointName {
+			continue
+		}
+		points = append(points, GeoPoint{
+			Name:     field.PointName,
+			JSONName: ToSnakeCase(field.PointName),
+			Lat:      field,
+			Lng:      fields[i+1],
+		})
+	}
+	return points
+}
+
+// PointMigrationSQL returns PostgreSQL statements that add and drop a table's geography columns,
+// generated from the latitude and longitude of each point, and their GiST indexes
+func PointMigrationSQL(table string, points []GeoPoint) (string, string) {
+	var up, down strings.Builder
+	up.WriteString("CREATE EXTENSION IF NOT EXISTS postgis;\n")
+	for _, point := range points {
+		index := fmt.Sprintf("idx_%s_%s", table, point.Column())
+		fmt.Fprintf(&up, `ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s geography(Point, 4326)
+    GENERATED ALWAYS AS (ST_SetSRID(ST_MakePoint(%s, %s), 4326)::geography) STORED;
+CREATE INDEX IF NOT EXISTS %s ON %s USING GIST (%s);
+`, table, point.Column(), point.Lng.DBName, point.Lat.DBName, index, table, point.Column())
+		fmt.Fprintf(&down, "DROP INDEX IF EXISTS %s;\nALTER TABLE %s DROP COLUMN IF EXISTS %s;\n", index, table, point.Column())
+	}
+	return up.String(), down.String()
+}
@@ -0,0 +1,116 @@
+package utils
+
+import "strings"
+
+// GraphQL adds gqlgen schema and resolver files (backend) or GraphQL store actions (frontend)
+var GraphQL bool
+
+// GraphQLField is a model field as exposed in the module's GraphQL types
+type GraphQLField struct {
+	Name       string // JSON name, which gqlgen matches to the Go field through struct_tag: json
+	Type       string // GraphQL type without the non-null marker
+	Required   bool
+	ForeignKey bool // belongsTo columns, which list responses replace with the related object
+	Filter     bool // Columns the service's GetAll filters on, accepted by the list query
+
+	// Payloads the field is left out of (--fields-read, --fields-write)
+	HideInList   bool
+	HideInDetail bool
+	NoCreate     bool
+	NoUpdate     bool
+}
+
+// GraphQLTypeFor returns the GraphQL type of a field, or "" when it has no GraphQL equivalent
+func GraphQLTypeFor(field Field) string {
+	// Encrypted values are left to the REST API, which masks them in lists, and computed ones to
+	// the model that derives them
+	if field.IsMedia || field.IsMediaFK || field.IsEncrypted || field.IsComputed {
+		return ""
+	}
+	if field.Relationship == "belongs_to" {
+		if field.Type == "uuid.UUID" {
+			return "ID"
+		}
+		return "Int"
+	}
+	if field.IsRelation || field.Relationship != "" {
+		return ""
+	}
+	if field.IsEnum {
+		return "String"
+	}
+
+	switch field.Type {
+	case "string", "text", "email":
+		return "String"
+	case "int", "int64", "uint", "uint64":
+		return "Int"
+	case "float32", "float64":
+		return "Float"
+	case "bool":
+		return "Boolean"
+	case "time.Time":
+		return "Time"
+	case "types.DateTime":
+		return "DateTime"
+	}
+	return ""
+}
+
+// GraphQLFields returns the fields exposed in GraphQL, and the names of those left to the REST API
+func GraphQLFields(fields []Field) ([]GraphQLField, []string) {
+	var exposed []GraphQLField
+	var skipped []string
+	for _, field := range fields {
+		gqlType := GraphQLTypeFor(field)
+		if gqlType == "" {
+			// Media FK columns and belongsTo objects are covered by their media field and foreign key
+			if !field.IsMediaFK && field.Relationship != "belongs_to_object" {
+				skipped = append(skipped, field.JSONName)
+			}
+			continue
+		}
+		exposed = append(exposed, GraphQLField{
+			Name:       field.JSONName,
+			Type:       gqlType,
+			Required:   field.IsRequired && field.Relationship == "",
+			ForeignKey: field.Relationship == "belongs_to",
+			Filter:     field.Relationship == "belongs_to" || field.MorphName != "",
+
+			HideInList:   field.HideInList,
+			HideInDetail: field.HideInDetail,
+			NoCreate:     field.NoCreate,
+			NoUpdate:     field.NoUpdate,
+		})
+	}
+	return exposed, skipped
+}
+
+// goInitialisms are the words gqlgen upper-cases when it names Go identifiers
+var goInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true,
+	"GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"QPS": true, "RAM": true, "RPC": true, "SLA": true, "SMTP": true, "SQL": true, "SSH": true,
+	"TCP": true, "TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true, "UUID": true,
+	"URI": true, "URL": true, "UTF8": true, "VM": true, "XML": true, "XMPP": true, "XSRF": true, "XSS": true,
+}
+
+// GraphQLGoArg returns the Go parameter name gqlgen gives a GraphQL argument (category_id -> categoryID),
+// so generated resolver bodies survive gqlgen rewriting their signatures
+func GraphQLGoArg(name string) string {
+	var b strings.Builder
+	for i, word := range strings.Split(ToSnakeCase(name), "_") {
+		if word == "" {
+			continue
+		}
+		switch {
+		case i == 0:
+			b.WriteString(word)
+		case goInitialisms[strings.ToUpper(word)]:
+			b.WriteString(strings.ToUpper(word))
+		default:
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return b.String()
+}
@@ -82,8 +82,12 @@ func GetGoType(t string) string {
 		return "string"
 	case "datetime", "time", "date", "timestamp":
 		return "time.Time"
-	case "float", "decimal":
+	case "float":
 		return "float64"
+	case "decimal":
+		return "decimal.Decimal"
+	case "money":
+		return "int64"
 	case "sort":
 		return "int"
 	case "image", "file", "attachment":
@@ -184,7 +188,7 @@ func UpdateInitFile(singularName, pluralName string) error {
 
 	packageName := ToSnakeCase(singularName)
 
-	importStr := fmt.Sprintf("\"base/app/%s\"", ToSnakeCase(pluralName))
+	importStr := fmt.Sprintf("\"%s/app/%s\"", GetGoModuleName(), ToSnakeCase(pluralName))
 	content, importAdded := AddImport(content, importStr)
 
 	content, initializerAdded := AddModuleInitializer(content, packageName, singularName)
@@ -196,6 +200,30 @@ func UpdateInitFile(singularName, pluralName string) error {
 	return nil
 }
 
+// RemoveFromInitFile unregisters a module from <backendDir>/app/init.go, removing its import
+// and modules["x"] = x.Init(deps) line. It reports whether the file changed.
+func RemoveFromInitFile(backendDir, dirName string) (bool, error) {
+	initFilePath := filepath.Join(backendDir, "app", "init.go")
+
+	content, err := os.ReadFile(initFilePath)
+	if err != nil {
+		return false, err
+	}
+
+	updated := RemoveImport(content, InitImport(GetGoModuleNameIn(backendDir), dirName))
+	updated = RemoveModuleInitializer(updated, dirName)
+
+	if bytes.Equal(updated, content) {
+		return false, nil
+	}
+
+	if err := UpdateProjectFile(initFilePath, updated); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
 func AddModuleInitializer(content []byte, packageName, singularName string) ([]byte, bool) {
 	contentStr := string(content)
 
@@ -229,10 +257,10 @@ func RemoveImport(content []byte, importStr string) []byte {
 	if importIndex != -1 {
 		// Find the start of the line
 		lineStart := strings.LastIndex(contentStr[:importIndex], "\n") + 1
-		// Find the end of the line
-		lineEnd := strings.Index(contentStr[importIndex:], "\n") + importIndex
-		if lineEnd == -1 {
-			lineEnd = len(contentStr)
+		// Find the end of the line, including the newline
+		lineEnd := len(contentStr)
+		if newline := strings.Index(contentStr[importIndex:], "\n"); newline != -1 {
+			lineEnd = importIndex + newline + 1
 		}
 		// Remove the line
 		contentStr = contentStr[:lineStart] + contentStr[lineEnd:]
@@ -0,0 +1,53 @@
+package utils
+
+import "strings"
+
+// ImportColumn is a spreadsheet column of an --import-export module
+type ImportColumn struct {
+	Header string // JSON name of the field in the create request
+	Kind   string // How a cell is read: string, number, bool or time
+}
+
+// importColumnFor returns the spreadsheet column of a field, or nil for fields a cell can't
+// hold: media, attachments, translations, checkbox arrays and relations other than belongsTo.
+// Encrypted fields are left out too, so their values don't leave the API in bulk.
+func importColumnFor(field Field) *ImportColumn {
+	if field.IsMedia || field.IsMediaFK || field.IsAttachment || field.IsTranslation || field.IsEncrypted || field.IsComputed {
+		return nil
+	}
+	if field.IsSelect && field.SelectType == "checkbox" {
+		return nil
+	}
+
+	if field.Relationship == "belongs_to" {
+		header := field.JSONName
+		if !strings.HasSuffix(field.Name, "Id") {
+			header += "_id"
+		}
+		if field.Type == "uint" {
+			return &ImportColumn{Header: header, Kind: "number"}
+		}
+		return &ImportColumn{Header: header, Kind: "string"}
+	}
+	if field.IsRelation || field.Relationship != "" {
+		return nil
+	}
+
+	column := &ImportColumn{Header: field.JSONName, Kind: "string"}
+	switch field.Type {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+		column.Kind = "number"
+	case "bool":
+		column.Kind = "bool"
+	case "time.Time", "types.DateTime":
+		column.Kind = "time"
+	case "string", "text", "email":
+		// Read as written
+	default:
+		// Enums are strings; other types (JSON, slices, structs) aren't imported
+		if !field.IsSelect {
+			return nil
+		}
+	}
+	return column
+}
@@ -0,0 +1,67 @@
+package utils
+
+// ListFilter is a filter[field] parameter of a generated List endpoint
+type ListFilter struct {
+	Name   string // JSON name, as in ?filter[status]=open
+	Column string // Column the value is compared with
+	Kind   string // How the value is parsed: string, int, uint, float, bool or uuid
+}
+
+// ListFilters returns the filter[field] parameters of a module's List endpoint: foreign keys,
+// polymorphic owner columns, and text, number, bool and select columns. Columns --fields-read
+// keeps out of responses can't be filtered on, so a filter can't reveal their values.
+func ListFilters(fields []Field) []ListFilter {
+	var filters []ListFilter
+	for _, field := range fields {
+		if field.IsMedia || field.IsMediaFK || field.IsAttachment || field.IsTranslation || field.IsEncrypted || field.IsComputed || field.HideInDetail {
+			continue
+		}
+		if field.IsSelect && field.SelectType == "checkbox" {
+			continue
+		}
+		if field.IsRelation && field.Relationship != "belongs_to" {
+			continue
+		}
+		if !field.IsRelation && field.Relationship != "" && field.MorphName == "" {
+			continue
+		}
+
+		filter := ListFilter{Name: field.JSONName, Column: ToSnakeCase(field.Name)}
+		switch {
+		case field.IsEnum:
+			filter.Kind = "string"
+		case field.Type == "uuid.UUID":
+			filter.Kind = "uuid"
+		case field.Type == "uint":
+			filter.Kind = "uint"
+		case field.Type == "int" || field.Type == "int64":
+			filter.Kind = "int"
+		case field.Type == "float32" || field.Type == "float64":
+			filter.Kind = "float"
+		case field.Type == "bool":
+			filter.Kind = "bool"
+		case field.Type == "string":
+			filter.Kind = "string"
+		default:
+			// Times, JSON and other types have no useful equality filter
+			continue
+		}
+		filters = append(filters, filter)
+	}
+	return filters
+}
+
+// SearchColumns returns the text columns the ?search= parameter of a List endpoint matches:
+// the --searchable columns, or else every text column the responses hold
+func SearchColumns(fields []Field) []string {
+	if Searchable != "" {
+		fields, _ = SearchableFields(fields)
+	}
+	var columns []string
+	for _, field := range fields {
+		if isTextField(field) && !field.HideInDetail {
+			columns = append(columns, ToSnakeCase(field.Name))
+		}
+	}
+	return columns
+}
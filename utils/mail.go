@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// MailTemplatesDir holds the mail templates bui g mail writes, relative to the backend. bui build
+// copies the backend's templates directory next to the binary.
+var MailTemplatesDir = filepath.Join("templates", "mail")
+
+// MailEnvVars are the SMTP settings the generated mailer reads, added to .env and .env.sample
+var MailEnvVars = []EnvVar{
+	{Key: "SMTP_HOST", Value: "", Comment: "Mail isn't sent while SMTP_HOST is empty; bui dev --docker runs Mailpit on localhost:1025"},
+	{Key: "SMTP_PORT", Value: "587"},
+	{Key: "SMTP_USERNAME", Value: ""},
+	{Key: "SMTP_PASSWORD", Value: ""},
+	{Key: "MAIL_FROM", Value: "no-reply@example.com"},
+}
+
+// Mail is an email generated by bui g mail: a pair of templates and the typed function that sends them
+type Mail struct {
+	Name   string // Template name in snake_case (e.g., "order_confirmation")
+	Func   string // Name in PascalCase, of the data type and the Send function (e.g., "OrderConfirmation")
+	Title  string // Default subject and heading (e.g., "Order Confirmation")
+	Fields []MailField
+}
+
+// MailField is a field of a mail's data
+type MailField struct {
+	Name    string // Go field (e.g., "Order")
+	Label   string // Heading in the templates (e.g., "Order")
+	Type    string // Go type (e.g., "*models.Order", "[]models.OrderItem", "string")
+	Model   string // Model of a relation, loaded from the database for previews
+	Many    bool   // The field is a list of Model
+	IsMoney bool   // Minor units (e.g., cents), shown with the templates' money function
+	IsTime  bool
+}
+
+// NewMail returns the mail name with the data fields given as for bui g: a belongsTo or hasOne
+// field is one record of its model, a hasMany or manyToMany field a list of them
+func NewMail(name string, fieldDefs []string) (*Mail, error) {
+	name = ToSnakeCase(name)
+	mail := &Mail{Name: name, Func: ToPascalCase(name), Title: ToCapitalCase(name)}
+	for _, def := range fieldDefs {
+		field := ParseField(def)
+		mailField := MailField{}
+		switch field.Relationship {
+		case "belongs_to", "has_one":
+			mailField.Name = TrimIdSuffix(field.Name)
+			mailField.Model = field.RelatedModel
+			mailField.Type = "*models." + field.RelatedModel
+		case "has_many", "many_to_many":
+			mailField.Name = field.Name
+			mailField.Model = field.RelatedModel
+			mailField.Many = true
+			mailField.Type = "[]models." + field.RelatedModel
+		case "":
+			mailField.Name = field.Name
+			switch strings.TrimPrefix(field.Type, "*") {
+			case "string", "int", "uint", "bool", "float64":
+				mailField.Type = strings.TrimPrefix(field.Type, "*")
+			case "int64":
+				mailField.Type = "int64"
+				mailField.IsMoney = field.IsMoney
+			case "time.Time", "types.DateTime":
+				mailField.Type = "time.Time"
+				mailField.IsTime = true
+			default:
+				return nil, fmt.Errorf("unsupported field %s of type %s: use text, numbers, booleans, dates, money or relations", def, field.Type)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported field %s: use belongsTo, hasOne, hasMany or manyToMany relations", def)
+		}
+		mailField.Label = ToCapitalCase(ToSnakeCase(mailField.Name))
+		mail.Fields = append(mail.Fields, mailField)
+	}
+	return mail, nil
+}
+
+// UsesModels reports whether any field is a relation, which needs the models package
+func (m *Mail) UsesModels() bool {
+	for _, field := range m.Fields {
+		if field.Model != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// UsesTime reports whether any field is a date
+func (m *Mail) UsesTime() bool {
+	for _, field := range m.Fields {
+		if field.IsTime {
+			return true
+		}
+	}
+	return false
+}
+
+// Sample returns the Go expression of the field's value in the mail's preview
+func (f MailField) Sample() string {
+	switch {
+	case f.Many:
+		return fmt.Sprintf("samples[models.%s](db)", f.Model)
+	case f.Model != "":
+		return fmt.Sprintf("sample[models.%s](db)", f.Model)
+	case f.IsTime:
+		return "time.Now()"
+	case f.IsMoney:
+		return "1999"
+	}
+	switch f.Type {
+	case "string":
+		return fmt.Sprintf("%q", "Sample "+strings.ToLower(f.Label))
+	case "bool":
+		return "true"
+	case "float64":
+		return "9.99"
+	}
+	return "3"
+}
@@ -0,0 +1,246 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/base-al/bui/version"
+	"github.com/base-go/mamba"
+)
+
+// ManifestFile is where the generations of a project are recorded, relative to its root
+var ManifestFile = filepath.Join(".bui", "manifest.json")
+
+// GeneratedDir keeps a copy of what bui last wrote to each file of the manifest, at its path
+// relative to the project root: the base three-way merges of edited files start from
+var GeneratedDir = filepath.Join(".bui", "generated")
+
+// GenerationManifest records every generate and destroy run of a project, oldest first, so a
+// generation can be re-run and the files bui wrote told apart from the ones edited since
+type GenerationManifest struct {
+	Generations []Generation `json:"generations"`
+}
+
+// Generation is a generate or destroy run recorded in the manifest
+type Generation struct {
+	Action    string    `json:"action"`    // generate or destroy
+	Command   string    `json:"command"`   // Command line, e.g. bui generate product name:string --bulk
+	Generator string    `json:"generator"` // The bui command that ran, e.g. generate or generate backend
+	Module    string    `json:"module"`    // Module name as given, e.g. product or shop/product
+	Fields    []string  `json:"fields,omitempty"`
+	Flags     []string  `json:"flags,omitempty"` // Flags that differ from their defaults, e.g. --bulk or --label=Shop Items
+	Version   string    `json:"version"`         // bui version
+	CreatedAt time.Time `json:"created_at"`
+
+	// Files the run wrote, relative to the project root, with the sha256 of what they held after it
+	Files map[string]string `json:"files,omitempty"`
+
+	// Paths destroy removed, relative to the project root
+	Removed []string `json:"removed,omitempty"`
+}
+
+// IsAlter reports whether the generation added fields to an existing module with --alter
+func (g Generation) IsAlter() bool {
+	return slices.Contains(g.Flags, "--alter")
+}
+
+// LoadManifest reads the manifest of the project at root; a project without one has an empty one
+func LoadManifest(root string) (*GenerationManifest, error) {
+	content, err := os.ReadFile(filepath.Join(root, ManifestFile))
+	if os.IsNotExist(err) {
+		return &GenerationManifest{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	m := &GenerationManifest{}
+	if err := json.Unmarshal(content, m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ManifestFile, err)
+	}
+	return m, nil
+}
+
+// Save writes the manifest to the project at root
+func (m *GenerationManifest) Save(root string) error {
+	path := filepath.Join(root, ManifestFile)
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0644)
+}
+
+// GeneratedHash returns the sha256 of what bui last wrote to path, a path relative to the
+// project root, or false when bui didn't write it or destroy removed it since
+func (m *GenerationManifest) GeneratedHash(path string) (string, bool) {
+	for i := len(m.Generations) - 1; i >= 0; i-- {
+		generation := m.Generations[i]
+		for _, removed := range generation.Removed {
+			if path == removed || strings.HasPrefix(path, removed+"/") {
+				return "", false
+			}
+		}
+		if hash, ok := generation.Files[path]; ok {
+			return hash, true
+		}
+	}
+	return "", false
+}
+
+// Latest returns the generation that made the module as it is: its last generation without
+// --alter, with the fields of the --alter runs after it added. It fails when the module has
+// no recorded generation or was destroyed after it.
+func (m *GenerationManifest) Latest(module string) (Generation, error) {
+	dirName := NewNamingConvention(module).DirName
+	var altered []string
+	for i := len(m.Generations) - 1; i >= 0; i-- {
+		generation := m.Generations[i]
+		if NewNamingConvention(generation.Module).DirName != dirName {
+			continue
+		}
+		switch {
+		case generation.Action == "destroy":
+			return Generation{}, fmt.Errorf("%s was destroyed after it was last generated", module)
+		case generation.IsAlter():
+			altered = append(generation.Fields, altered...)
+		default:
+			generation.Fields = append(slices.Clone(generation.Fields), altered...)
+			return generation, nil
+		}
+	}
+	return Generation{}, fmt.Errorf("no generation of %s is recorded in %s", module, ManifestFile)
+}
+
+// generatedUnchanged reports whether the run's manifest records existing as what bui last wrote
+// to path, a path relative to the working directory
+func (r *Run) generatedUnchanged(path string, existing []byte) bool {
+	if r.manifest == nil {
+		return false
+	}
+	hash, ok := r.manifest.GeneratedHash(relativeToRoot(r.Root, path))
+	return ok && hash == contentHash(existing)
+}
+
+// generatedBefore reports whether bui wrote path, a path relative to the working directory
+func (r *Run) generatedBefore(path string) bool {
+	if r.manifest == nil {
+		return false
+	}
+	_, ok := r.manifest.GeneratedHash(relativeToRoot(r.Root, path))
+	return ok
+}
+
+// generatedSnapshot returns what bui last wrote to path, a path relative to the working
+// directory, when the run's manifest records it and its copy in GeneratedDir is kept
+func (r *Run) generatedSnapshot(path string) ([]byte, bool) {
+	if r.manifest == nil {
+		return nil, false
+	}
+	rel := relativeToRoot(r.Root, path)
+	hash, ok := r.manifest.GeneratedHash(rel)
+	if !ok {
+		return nil, false
+	}
+	content, err := os.ReadFile(filepath.Join(r.Root, GeneratedDir, filepath.FromSlash(rel)))
+	if err != nil || contentHash(content) != hash {
+		return nil, false
+	}
+	return content, true
+}
+
+// contentHash returns the hex sha256 of content
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// recordManifest adds the run to the manifest of the project root, with the hashes of the files
+// w wrote or updated; joined adds them to the generation recorded last instead
+func (w *Writer) recordManifest(cmd *mamba.Command, args []string, joined bool) error {
+	root := w.Run.Root
+	m, err := LoadManifest(root)
+	if err != nil {
+		return err
+	}
+
+	// Merged files are recorded as generated, so their edits are merged again next time
+	files := map[string]string{}
+	for _, path := range append(slices.Clone(w.written), w.updated...) {
+		content, merged := w.merged[path]
+		if !merged {
+			var err error
+			if content, err = os.ReadFile(path); err != nil {
+				continue
+			}
+		}
+		rel := relativeToRoot(root, path)
+		snapshot := filepath.Join(root, GeneratedDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(snapshot), os.ModePerm); err != nil {
+			return err
+		}
+		if err := os.WriteFile(snapshot, content, 0644); err != nil {
+			return err
+		}
+		files[rel] = contentHash(content)
+	}
+
+	if joined && len(m.Generations) > 0 {
+		last := &m.Generations[len(m.Generations)-1]
+		if last.Files == nil {
+			last.Files = map[string]string{}
+		}
+		for path, hash := range files {
+			last.Files[path] = hash
+		}
+	} else {
+		generation := newGeneration("generate", cmd, args)
+		if len(files) > 0 {
+			generation.Files = files
+		}
+		m.Generations = append(m.Generations, generation)
+	}
+	return m.Save(root)
+}
+
+// RecordDestroy adds a destroy run that removed paths, relative to the working directory, to
+// the manifest of the project at root
+func RecordDestroy(root string, cmd *mamba.Command, args []string, paths []string) error {
+	m, err := LoadManifest(root)
+	if err != nil {
+		return err
+	}
+	generation := newGeneration("destroy", cmd, args)
+	for _, path := range paths {
+		rel := relativeToRoot(root, path)
+		generation.Removed = append(generation.Removed, rel)
+		os.RemoveAll(filepath.Join(root, GeneratedDir, filepath.FromSlash(rel)))
+	}
+	m.Generations = append(m.Generations, generation)
+	return m.Save(root)
+}
+
+// newGeneration records the run of cmd with args
+func newGeneration(action string, cmd *mamba.Command, args []string) Generation {
+	generation := Generation{
+		Action:    action,
+		Command:   GenerationCommand(cmd, args),
+		Generator: strings.Join(commandPath(cmd)[1:], " "),
+		Flags:     generationFlags(cmd),
+		Version:   version.Version,
+		CreatedAt: time.Now(),
+	}
+	if len(args) > 0 {
+		generation.Module, generation.Fields = args[0], args[1:]
+	}
+	return generation
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// MergeFile merges the changes from base to theirs into current with git merge-file. It returns
+// the merged content, with the hunks both changed between conflict markers labelled currentLabel
+// and theirsLabel, and the number of conflicts. Files git can't merge, such as binary files,
+// return an error.
+func MergeFile(current, base, theirs []byte, currentLabel, theirsLabel string) ([]byte, int, error) {
+	tmp, err := os.MkdirTemp("", "bui-merge-*")
+	if err != nil {
+		return nil, 0, err
+	}
+	defer os.RemoveAll(tmp)
+
+	paths := make([]string, 3)
+	for i, content := range [][]byte{current, base, theirs} {
+		paths[i] = filepath.Join(tmp, fmt.Sprint(i))
+		if err := os.WriteFile(paths[i], content, 0644); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	// merge-file exits with the number of conflicts, and a negative status on errors
+	merged, err := exec.Command("git", "merge-file", "-p", "--quiet",
+		"-L", currentLabel, "-L", "base", "-L", theirsLabel, paths[0], paths[1], paths[2]).Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 && exitErr.ExitCode() < 128 {
+		return merged, exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("git merge-file failed: %w", err)
+	}
+	return merged, 0, nil
+}
+
+// recordMerge records that the write of what the generator produced for path was merged with
+// the edits made to it, for the manifest and the summary
+func (w *Writer) recordMerge(path string, generated []byte, conflicts int) {
+	w.merged[path] = generated
+	if conflicts > 0 {
+		w.conflicted = append(w.conflicted, path)
+	}
+}
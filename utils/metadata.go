@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ModuleLabel is the plural display name of a generated module (--label "Sales Orders")
+	ModuleLabel string
+
+	// ModuleDescription describes a generated module in module.config.ts and under its list page title
+	ModuleDescription string
+)
+
+// ApplyModuleMetadata sets the --label and --description of the module being generated on its
+// naming. The label replaces the names derived from the model in titles, the sidebar and the
+// swagger tag; its singular is used for a single record.
+func ApplyModuleMetadata(naming *NamingConvention) {
+	if label := strings.TrimSpace(ModuleLabel); label != "" {
+		naming.Label = label
+		naming.LabelSingular = Singularize(label)
+		naming.Tag = label
+	}
+	naming.Description = strings.TrimSpace(ModuleDescription)
+}
+
+// CheckModuleMetadata reports a --label or --description the generated TypeScript strings can't hold
+func CheckModuleMetadata() error {
+	for flag, value := range map[string]string{"--label": ModuleLabel, "--description": ModuleDescription} {
+		if strings.ContainsAny(value, "'`\\\n") {
+			return fmt.Errorf("%s can't contain quotes, backslashes or line breaks: %q", flag, value)
+		}
+	}
+	if NavIcon != "" && !iconPattern.MatchString(NavIcon) {
+		return fmt.Errorf("--icon takes an Iconify name such as i-lucide-shopping-cart, lucide:shopping-cart or shopping-cart: %q", NavIcon)
+	}
+	return nil
+}
+
+// iconPattern matches the --icon forms IconName takes
+var iconPattern = regexp.MustCompile(`^[a-z0-9]+([-:][a-z0-9]+)*$`)
+
+// IconName returns the Nuxt UI class of an --icon: i-lucide-shopping-cart as it is, the Iconify
+// lucide:shopping-cart as i-lucide-shopping-cart, and a bare shopping-cart from the lucide set
+func IconName(icon string) string {
+	switch {
+	case icon == "":
+		return DefaultNavIcon
+	case strings.HasPrefix(icon, "i-"):
+		return icon
+	case strings.Contains(icon, ":"):
+		return "i-" + strings.Replace(icon, ":", "-", 1)
+	}
+	return "i-lucide-" + icon
+}
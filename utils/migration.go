@@ -0,0 +1,377 @@
+package utils
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// MigrationsDir is the backend directory holding SQL migrations
+const MigrationsDir = "migrations"
+
+// NextMigrationVersion returns a timestamp version (e.g. 20250102150405) not used by any migration in dir
+func NextMigrationVersion(dir string) string {
+	t := time.Now().UTC()
+	for {
+		version := t.Format("20060102150405")
+		if matches, _ := filepath.Glob(filepath.Join(dir, version+"_*")); len(matches) == 0 {
+			return version
+		}
+		t = t.Add(time.Second)
+	}
+}
+
+// FindMigration returns the up file of the first migration in dir named name, or "" when none exists
+func FindMigration(dir, name string) string {
+	matches, _ := filepath.Glob(filepath.Join(dir, "*_"+name+".up.sql"))
+	if len(matches) == 0 {
+		return ""
+	}
+	return matches[0]
+}
+
+// WriteMigration writes a <version>_<name>.up.sql and .down.sql pair to dir and returns the up file path
+func WriteMigration(dir, name, up, down string) (string, error) {
+	return defaultWriter.WriteMigration(dir, name, up, down)
+}
+
+// WriteMigration is WriteMigration for the generator of w; dir includes w.Dir
+func (w *Writer) WriteMigration(dir, name, up, down string) (string, error) {
+	base := filepath.Join(dir, NextMigrationVersion(dir)+"_"+ToSnakeCase(name))
+	if err := w.WriteGeneratedFile(base+".up.sql", []byte(up)); err != nil {
+		return "", err
+	}
+	if err := w.WriteGeneratedFile(base+".down.sql", []byte(down)); err != nil {
+		return "", err
+	}
+	return base + ".up.sql", nil
+}
+
+// CreateTableSQL returns statements that create and drop a generated model's table, its indexes
+// and its many-to-many join tables in the project's database. Statements use IF NOT EXISTS where
+// the database has it, so they can run against a database that GORM AutoMigrate has already created.
+func CreateTableSQL(naming *NamingConvention, fields []Field) (string, string) {
+	table := naming.TableName
+	schema := newTableSchema(table)
+	if UUIDKey() {
+		schema.columns = append(schema.columns, "id CHAR(36) PRIMARY KEY")
+	} else {
+		schema.columns = append(schema.columns, sqlAutoIncrementID())
+	}
+	schema.addFields(naming, fields)
+
+	schema.columns = append(schema.columns, "created_at "+sqlTimestampType(), "updated_at "+sqlTimestampType())
+	if !NoSoftDelete {
+		schema.columns = append(schema.columns, "deleted_at "+sqlTimestampType())
+		schema.addIndex(fmt.Sprintf("idx_%s_deleted_at", table), "deleted_at", false)
+	}
+	if Audit {
+		schema.columns = append(schema.columns, "created_by BIGINT", "updated_by BIGINT")
+		schema.addIndex(fmt.Sprintf("idx_%s_created_by", table), "created_by", false)
+		schema.addIndex(fmt.Sprintf("idx_%s_updated_by", table), "updated_by", false)
+	}
+	if LockVersion {
+		schema.columns = append(schema.columns, "lock_version BIGINT NOT NULL DEFAULT 0")
+	}
+	if Tenant {
+		schema.columns = append(schema.columns, "organization_id BIGINT NOT NULL")
+		schema.addIndex(fmt.Sprintf("idx_%s_organization_id", table), "organization_id", false)
+	}
+
+	var up strings.Builder
+	fmt.Fprintf(&up, "CREATE TABLE IF NOT EXISTS %s (\n    %s\n);\n", table, strings.Join(schema.columns, ",\n    "))
+	up.WriteString(schema.indexSQL())
+	for _, statement := range schema.joinSQL {
+		up.WriteString(statement + "\n")
+	}
+
+	var down strings.Builder
+	for i := len(schema.joinTables) - 1; i >= 0; i-- {
+		fmt.Fprintf(&down, "DROP TABLE IF EXISTS %s;\n", schema.joinTables[i])
+	}
+	fmt.Fprintf(&down, "DROP TABLE IF EXISTS %s;\n", table)
+
+	return up.String(), down.String()
+}
+
+// RevisionTableSQL returns statements that create and drop the table --versioned models keep
+// their revisions in
+func RevisionTableSQL(naming *NamingConvention) (string, string) {
+	table := naming.ModelSnake + "_revisions"
+	ownerType := "BIGINT"
+	if UUIDKey() {
+		ownerType = "CHAR(36)"
+	}
+
+	up := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+    %s,
+    %s_id %s NOT NULL,
+    version INTEGER NOT NULL,
+    snapshot TEXT,
+    created_by BIGINT,
+    created_at %s
+);
+CREATE INDEX%s idx_%s_%s_id ON %s (%s_id);
+`, table, sqlAutoIncrementID(), naming.ModelSnake, ownerType, sqlTimestampType(),
+		sqlIndexIfNotExists(), table, naming.ModelSnake, table, naming.ModelSnake)
+	return up, fmt.Sprintf("DROP TABLE IF EXISTS %s;\n", table)
+}
+
+// AuditLogTableSQL returns statements that create and drop the table --audited services record
+// their changes in
+func AuditLogTableSQL() (string, string) {
+	up := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS audit_logs (
+    %s,
+    subject_type VARCHAR(100),
+    subject_id VARCHAR(36),
+    action VARCHAR(20),
+    actor_id BIGINT,
+    changes TEXT,
+    created_at %s
+);
+CREATE INDEX%s idx_audit_logs_subject ON audit_logs (subject_type, subject_id);
+CREATE INDEX%s idx_audit_logs_actor_id ON audit_logs (actor_id);
+`, sqlAutoIncrementID(), sqlTimestampType(), sqlIndexIfNotExists(), sqlIndexIfNotExists())
+	return up, "DROP TABLE IF EXISTS audit_logs;\n"
+}
+
+// NotificationsTableSQL returns statements that create and drop the table bui g notifications
+// stores the users' notifications in
+func NotificationsTableSQL() (string, string) {
+	up := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS notifications (
+    %s,
+    user_id BIGINT NOT NULL,
+    type VARCHAR(50),
+    title VARCHAR(255),
+    body TEXT,
+    link VARCHAR(500),
+    read_at %s NULL,
+    created_at %s
+);
+CREATE INDEX%s idx_notifications_user_read ON notifications (user_id, read_at);
+`, sqlAutoIncrementID(), sqlTimestampType(), sqlTimestampType(), sqlIndexIfNotExists())
+	return up, "DROP TABLE IF EXISTS notifications;\n"
+}
+
+// SettingsTableSQL returns statements that create and drop the table bui g settings stores the
+// settings' values in
+func SettingsTableSQL() (string, string) {
+	up := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS settings (
+    %s,
+    name VARCHAR(100) NOT NULL,
+    value TEXT,
+    updated_at %s
+);
+CREATE UNIQUE INDEX%s idx_settings_name ON settings (name);
+`, sqlAutoIncrementID(), sqlTimestampType(), sqlIndexIfNotExists())
+	return up, "DROP TABLE IF EXISTS settings;\n"
+}
+
+// AlterTableSQL returns statements that add and drop the columns, indexes and join tables of the added fields of an existing model. fields is the full field list, so
+// composite indexes that include an added column cover all of their columns.
+func AlterTableSQL(naming *NamingConvention, fields, added []Field) (string, string) {
+	table := naming.TableName
+	schema := newTableSchema(table)
+	schema.addFields(naming, added)
+	all := newTableSchema(table)
+	all.addFields(naming, fields)
+
+	addedColumns := map[string]bool{}
+	for _, column := range schema.columns {
+		name, _, _ := strings.Cut(column, " ")
+		addedColumns[name] = true
+	}
+	indexes := newTableSchema(table)
+	for _, name := range all.indexOrder {
+		columns, unique := all.uniqueIndexes[name]
+		if !unique {
+			columns = all.indexes[name]
+		}
+		for _, column := range columns {
+			if addedColumns[column] {
+				for _, column := range columns {
+					indexes.addIndex(name, column, unique)
+				}
+				break
+			}
+		}
+	}
+
+	var up strings.Builder
+	for _, column := range schema.columns {
+		fmt.Fprintf(&up, "ALTER TABLE %s ADD COLUMN%s %s;\n", table, sqlColumnIfExists(" IF NOT EXISTS"), column)
+	}
+	up.WriteString(indexes.indexSQL())
+	for _, statement := range schema.joinSQL {
+		up.WriteString(statement + "\n")
+	}
+
+	var down strings.Builder
+	for i := len(schema.joinTables) - 1; i >= 0; i-- {
+		fmt.Fprintf(&down, "DROP TABLE IF EXISTS %s;\n", schema.joinTables[i])
+	}
+	for i := len(schema.columns) - 1; i >= 0; i-- {
+		column, _, _ := strings.Cut(schema.columns[i], " ")
+		fmt.Fprintf(&down, "ALTER TABLE %s DROP COLUMN%s %s;\n", table, sqlColumnIfExists(" IF EXISTS"), column)
+	}
+
+	return up.String(), down.String()
+}
+
+// tableSchema collects the columns, indexes and join tables generated for a model's fields
+type tableSchema struct {
+	table         string
+	columns       []string
+	indexOrder    []string
+	indexes       map[string][]string
+	uniqueIndexes map[string][]string
+	joinTables    []string
+	joinSQL       []string
+}
+
+// newTableSchema returns an empty schema for table
+func newTableSchema(table string) *tableSchema {
+	return &tableSchema{
+		table:         table,
+		indexes:       map[string][]string{},
+		uniqueIndexes: map[string][]string{},
+	}
+}
+
+// addIndex adds column to the named index, keeping indexes in the order they were first seen
+func (s *tableSchema) addIndex(name, column string, unique bool) {
+	target := s.indexes
+	if unique {
+		target = s.uniqueIndexes
+	}
+	if _, ok := target[name]; !ok {
+		s.indexOrder = append(s.indexOrder, name)
+	}
+	target[name] = append(target[name], column)
+}
+
+// addFields adds the columns, indexes and many-to-many join tables of fields
+func (s *tableSchema) addFields(naming *NamingConvention, fields []Field) {
+	ownerType := "BIGINT"
+	if UUIDKey() {
+		ownerType = "CHAR(36)"
+	}
+
+	for _, field := range fields {
+		switch {
+		case field.Relationship == "many_to_many" && field.RelatedModel != "":
+			joinTable := naming.ModelSnake + "_" + ToSnakeCase(ToPlural(field.RelatedModel))
+			s.joinTables = append(s.joinTables, joinTable)
+			var pivot string
+			for _, column := range field.Pivot {
+				pivot += ",\n    " + sqlColumn(column)
+			}
+			s.joinSQL = append(s.joinSQL, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n    %s_id %s NOT NULL,\n    %s_id BIGINT NOT NULL%s,\n    PRIMARY KEY (%s_id, %s_id)\n);",
+				joinTable, naming.ModelSnake, ownerType, ToSnakeCase(field.RelatedModel), pivot, naming.ModelSnake, ToSnakeCase(field.RelatedModel)))
+			continue
+		case field.IsMediaList:
+			joinTable := naming.ModelSnake + "_" + field.DBName
+			s.joinTables = append(s.joinTables, joinTable)
+			s.joinSQL = append(s.joinSQL, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n    %s_id %s NOT NULL,\n    media_id BIGINT NOT NULL,\n    PRIMARY KEY (%s_id, media_id)\n);",
+				joinTable, naming.ModelSnake, ownerType, naming.ModelSnake))
+			continue
+		case field.Relationship == "belongs_to":
+			s.addIndex(fmt.Sprintf("idx_%s_%s", s.table, field.DBName), field.DBName, false)
+		case field.IsRelation || field.Relationship != "" || field.IsMedia || field.IsAttachment || field.IsTranslation || field.IsComputed:
+			continue
+		}
+
+		s.columns = append(s.columns, sqlColumn(field))
+		for _, setting := range gormSettings(field) {
+			key, name, _ := strings.Cut(setting, ":")
+			switch key {
+			case "index", "uniqueIndex":
+				if name == "" {
+					name = fmt.Sprintf("idx_%s_%s", s.table, field.DBName)
+				}
+				s.addIndex(name, field.DBName, key == "uniqueIndex")
+			}
+		}
+	}
+}
+
+// indexSQL returns the CREATE INDEX statements for the collected indexes
+func (s *tableSchema) indexSQL() string {
+	var sql strings.Builder
+	for _, name := range s.indexOrder {
+		if columns, ok := s.uniqueIndexes[name]; ok {
+			fmt.Fprintf(&sql, "CREATE UNIQUE INDEX%s %s ON %s (%s);\n", sqlIndexIfNotExists(), name, s.table, strings.Join(columns, ", "))
+		} else {
+			fmt.Fprintf(&sql, "CREATE INDEX%s %s ON %s (%s);\n", sqlIndexIfNotExists(), name, s.table, strings.Join(s.indexes[name], ", "))
+		}
+	}
+	return sql.String()
+}
+
+// sqlColumn returns the column definition for a model field
+func sqlColumn(field Field) string {
+	column := field.DBName + " " + sqlColumnType(field)
+	if field.Default != "" {
+		column += " DEFAULT " + sqlDefault(field)
+	}
+	return column
+}
+
+// sqlColumnType maps a field's Go type to a column type of the project's database, honouring a
+// GORM type: or size: setting
+func sqlColumnType(field Field) string {
+	for _, setting := range gormSettings(field) {
+		if value, ok := strings.CutPrefix(setting, "type:"); ok {
+			return strings.ToUpper(value)
+		}
+	}
+	for _, setting := range gormSettings(field) {
+		if size, ok := strings.CutPrefix(setting, "size:"); ok && stringColumn(field) {
+			return "VARCHAR(" + size + ")"
+		}
+	}
+
+	switch strings.TrimPrefix(field.Type, "*") {
+	case "int", "int64", "uint":
+		return "BIGINT"
+	case "float64":
+		return "DECIMAL"
+	case "bool":
+		return "BOOLEAN"
+	case "time.Time", "types.DateTime":
+		return sqlTimestampType()
+	case "json.RawMessage":
+		return sqlJSONType()
+	case "uuid.UUID":
+		return "CHAR(36)"
+	}
+	return "TEXT"
+}
+
+// sqlDefault returns a field's default value as a SQL literal
+func sqlDefault(field Field) string {
+	columnType := sqlColumnType(field)
+	switch {
+	case columnType == "BIGINT", columnType == "DECIMAL", columnType == "BOOLEAN", strings.HasPrefix(columnType, "NUMERIC"):
+		return field.Default
+	}
+	if strings.HasPrefix(field.Default, "'") {
+		return field.Default
+	}
+	return "'" + strings.ReplaceAll(field.Default, "'", "''") + "'"
+}
+
+// gormSettings returns the semicolon-separated settings of a field's gorm struct tag
+func gormSettings(field Field) []string {
+	tag := field.GORM
+	start := strings.Index(tag, `gorm:"`)
+	if start == -1 {
+		return nil
+	}
+	tag = tag[start+len(`gorm:"`):]
+	if end := strings.Index(tag, `"`); end != -1 {
+		tag = tag[:end]
+	}
+	return strings.Split(tag, ";")
+}
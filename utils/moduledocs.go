@@ -0,0 +1,284 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ModuleDoc is what bui docs generate knows about a module, read from its generated files
+type ModuleDoc struct {
+	Name   string   // Directory of the module in app/, e.g. products
+	Model  string   // Model struct, e.g. Product
+	Table  string   // Table the model is stored in
+	Defs   []string // Field definitions recovered from the model, e.g. price:float
+	Fields []Field  // Fields of the model, for their Go types
+	Routes []ModuleRoute
+	Pages  []string // Admin routes of the frontend, e.g. /app/products/:id
+}
+
+// ModuleRoute is an API route a module registers
+type ModuleRoute struct {
+	Method     string
+	Path       string
+	Handler    string
+	Permission string // Action the route's policy checks, e.g. list, or "" when it has none
+	Note       string // Comment after the route, e.g. Paginated list
+}
+
+// routePattern matches route registrations such as router.GET("/products/:id", c.Get) // Get by ID
+var routePattern = regexp.MustCompile(`router\.(GET|POST|PUT|PATCH|DELETE)\("([^"]*)",\s*(.*?)\)\s*(?://\s*(.*))?$`)
+
+// permissionPattern matches the policy check around a handler: c.authorize(PermissionList, c.List)
+var permissionPattern = regexp.MustCompile(`^c\.authorize\(Permission(\w+),\s*(.*?)\)?$`)
+
+// RecoverRoutes returns the routes registered in a module's Go source
+func RecoverRoutes(source []byte) []ModuleRoute {
+	var routes []ModuleRoute
+	for _, line := range strings.Split(string(source), "\n") {
+		match := routePattern.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		route := ModuleRoute{Method: match[1], Path: match[2], Handler: strings.TrimSpace(match[3])}
+		if permission := permissionPattern.FindStringSubmatch(route.Handler); permission != nil {
+			route.Permission = strings.ToLower(permission[1])
+			route.Handler = permission[2]
+		}
+		route.Handler = strings.TrimPrefix(route.Handler, "c.")
+		// Drop the ordering reminders, which are for the code, not the reader
+		route.Note, _, _ = strings.Cut(strings.TrimSpace(match[4]), " - MUST")
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+// ReadModuleDoc reads the model and routes of the module in app/<dirName> of backendDir
+func ReadModuleDoc(backendDir, dirName string) ModuleDoc {
+	naming := NewNamingConvention(Singularize(dirName))
+	doc := ModuleDoc{Name: dirName}
+
+	if source, err := os.ReadFile(filepath.Join(backendDir, "app", "models", naming.ModelSnake+".go")); err == nil {
+		if defs, err := RecoverFieldDefs(source, naming.Model); err == nil {
+			doc.Model, doc.Defs = naming.Model, defs
+			doc.Table = RecoverTableName(source, naming.Model)
+			doc.Fields = NewTemplateData(naming.Model, defs).Fields
+		}
+	}
+
+	files, _ := filepath.Glob(filepath.Join(backendDir, "app", dirName, "*.go"))
+	sort.Strings(files)
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		if source, err := os.ReadFile(file); err == nil {
+			doc.Routes = append(doc.Routes, RecoverRoutes(source)...)
+		}
+	}
+	return doc
+}
+
+// DocFileName returns the markdown file of a module's page
+func DocFileName(module string) string {
+	return strings.ReplaceAll(module, "/", "-") + ".md"
+}
+
+// ModuleIndexMarkdown renders the index page that links to every module's page
+func ModuleIndexMarkdown(docs []ModuleDoc) string {
+	var b strings.Builder
+	b.WriteString("# Modules\n\n")
+	b.WriteString("Generated by `bui docs generate` from the project's models, controllers and pages. Regenerate it instead of editing it.\n\n")
+	b.WriteString("| Module | Model | Table | Fields | Endpoints | Admin |\n")
+	b.WriteString("|---|---|---|---|---|---|\n")
+	for _, doc := range docs {
+		admin := "-"
+		if len(doc.Pages) > 0 {
+			admin = "`" + doc.Pages[0] + "`"
+		}
+		fmt.Fprintf(&b, "| [%s](%s) | %s | %s | %d | %d | %s |\n",
+			doc.Name, DocFileName(doc.Name), markdownOrDash(doc.Model), markdownCode(doc.Table), len(doc.Defs), len(doc.Routes), admin)
+	}
+	return b.String()
+}
+
+// Markdown renders the module's page. docs are all modules, for links to related models and
+// for the modules that refer to this one.
+func (doc ModuleDoc) Markdown(docs []ModuleDoc) string {
+	modules := map[string]string{}
+	for _, other := range docs {
+		if other.Model != "" {
+			modules[other.Model] = other.Name
+		}
+	}
+	link := func(model string) string {
+		if module, ok := modules[model]; ok {
+			return fmt.Sprintf("[%s](%s)", model, DocFileName(module))
+		}
+		return model
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", ToTitle(strings.ReplaceAll(doc.Name, "_", " ")))
+	if doc.Model != "" {
+		fmt.Fprintf(&b, "Model `%s`", doc.Model)
+		if doc.Table != "" {
+			fmt.Fprintf(&b, ", stored in the `%s` table", doc.Table)
+		}
+		b.WriteString(".\n\n")
+	}
+
+	if len(doc.Defs) > 0 {
+		goTypes := map[string]string{}
+		for _, field := range doc.Fields {
+			name, _, _ := strings.Cut(field.JSONName, ",")
+			if _, ok := goTypes[name]; !ok {
+				goTypes[name] = field.Type
+			}
+		}
+
+		b.WriteString("## Fields\n\n")
+		b.WriteString("Every record also has `id`, `created_at`, `updated_at` and `deleted_at`.\n\n")
+		b.WriteString("| Field | Type | Go type | Notes |\n")
+		b.WriteString("|---|---|---|---|\n")
+		for _, def := range doc.Defs {
+			name, kind, notes := describeFieldDef(def, link)
+			fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n", name, kind, markdownCode(goTypes[name]), markdownOrDash(strings.Join(notes, "; ")))
+		}
+		b.WriteString("\n")
+	}
+
+	var relations []string
+	for _, def := range doc.Defs {
+		parts := strings.Split(def, ":")
+		if len(parts) < 3 {
+			continue
+		}
+		switch parts[1] {
+		case "belongsTo":
+			relations = append(relations, fmt.Sprintf("Belongs to %s through `%s`", link(parts[2]), parts[0]))
+		case "hasOne":
+			relations = append(relations, fmt.Sprintf("Has one %s as `%s`", link(parts[2]), parts[0]))
+		case "hasMany":
+			relations = append(relations, fmt.Sprintf("Has many %s as `%s`", link(parts[2]), parts[0]))
+		case "manyToMany":
+			relations = append(relations, fmt.Sprintf("Many to many %s as `%s`, through a join table", link(parts[2]), parts[0]))
+		}
+	}
+	for _, other := range docs {
+		if other.Name == doc.Name || doc.Model == "" {
+			continue
+		}
+		for _, def := range other.Defs {
+			if parts := strings.Split(def, ":"); len(parts) >= 3 && parts[2] == doc.Model && isRelationKind(parts[1]) {
+				relations = append(relations, fmt.Sprintf("Referenced by %s.`%s` (%s)", link(other.Model), parts[0], parts[1]))
+			}
+		}
+	}
+	if len(relations) > 0 {
+		b.WriteString("## Relations\n\n")
+		for _, relation := range relations {
+			b.WriteString("- " + relation + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if len(doc.Routes) > 0 {
+		b.WriteString("## Endpoints\n\n")
+		b.WriteString("Paths are relative to the API's base URL.\n\n")
+		b.WriteString("| Method | Path | Handler | Permission | Description |\n")
+		b.WriteString("|---|---|---|---|---|\n")
+		for _, route := range doc.Routes {
+			permission := "-"
+			if route.Permission != "" {
+				permission = "`" + ToSnakeCase(doc.Model) + ":" + route.Permission + "`"
+			}
+			fmt.Fprintf(&b, "| %s | `%s` | `%s` | %s | %s |\n", route.Method, route.Path, route.Handler, permission, markdownOrDash(route.Note))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(doc.Pages) > 0 {
+		b.WriteString("## Admin pages\n\n")
+		for _, page := range doc.Pages {
+			fmt.Fprintf(&b, "- `%s`\n", page)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// describeFieldDef splits a field definition into its name, its type and notes such as its
+// default, rules, enum values, related model or pivot columns
+func describeFieldDef(def string, link func(string) string) (string, string, []string) {
+	def, pivot, hasPivot := strings.Cut(def, ":pivot=")
+	parts := strings.Split(def, ":")
+	if len(parts) < 2 {
+		return def, "-", nil
+	}
+	var notes []string
+	args := parts[1:]
+	for len(args) > 1 {
+		last := args[len(args)-1]
+		if value, ok := strings.CutPrefix(last, "default="); ok {
+			notes = append(notes, "default `"+value+"`")
+		} else if value, ok := strings.CutPrefix(last, "rules="); ok {
+			notes = append(notes, "rules `"+value+"`")
+		} else {
+			break
+		}
+		args = args[:len(args)-1]
+	}
+
+	kind := args[0]
+	var detail string
+	if len(args) > 1 {
+		detail = strings.Join(args[1:], ":")
+	}
+	switch {
+	case isRelationKind(kind):
+		notes = append([]string{link(detail)}, notes...)
+		if hasPivot {
+			notes = append(notes, "pivot "+strings.ReplaceAll(pivot, ",", ", "))
+		}
+	case kind == "enum":
+		notes = append([]string{"one of " + strings.ReplaceAll(detail, ",", ", ")}, notes...)
+	case kind == "state":
+		notes = append([]string{"moves " + strings.ReplaceAll(detail, ">", " → ")}, notes...)
+	case kind == "money":
+		notes = append([]string{"minor units of " + detail}, notes...)
+	case kind == "computed" && detail != "":
+		notes = append([]string{"from " + strings.ReplaceAll(detail, ",", ", ")}, notes...)
+	case detail != "":
+		notes = append([]string{detail}, notes...)
+	}
+	return parts[0], kind, notes
+}
+
+// isRelationKind reports whether a field definition type is a relation to another model
+func isRelationKind(kind string) bool {
+	switch kind {
+	case "belongsTo", "hasOne", "hasMany", "manyToMany":
+		return true
+	}
+	return false
+}
+
+// markdownCode wraps s in backticks, or returns a dash when it's empty
+func markdownCode(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return "`" + s + "`"
+}
+
+// markdownOrDash returns s, or a dash for an empty table cell
+func markdownOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return strings.ReplaceAll(s, "|", `\|`)
+}
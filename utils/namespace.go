@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// InitPackage returns the name app/init.go refers to the module in app/<dirName> by. A namespaced
+// module (shop/products) is shop_products, so modules of the same name in other namespaces don't
+// clash.
+func InitPackage(dirName string) string {
+	return strings.ReplaceAll(dirName, "/", "_")
+}
+
+// InitImport returns the import of the module in app/<dirName>, named InitPackage when it's in a
+// namespace
+func InitImport(goModuleName, dirName string) string {
+	importPath := fmt.Sprintf("\"%s/app/%s\"", goModuleName, dirName)
+	if !strings.Contains(dirName, "/") {
+		return importPath
+	}
+	return InitPackage(dirName) + " " + importPath
+}
+
+// FindModuleDirs returns the modules in backendDir/app that have file, as their directories in
+// app/: top-level modules (products) and the modules of a namespace (shop/products)
+func FindModuleDirs(backendDir, file string) []string {
+	var dirs []string
+	for _, pattern := range []string{
+		filepath.Join(backendDir, "app", "*", file),
+		filepath.Join(backendDir, "app", "*", "*", file),
+	} {
+		matches, _ := filepath.Glob(pattern)
+		for _, match := range matches {
+			if rel, err := filepath.Rel(filepath.Join(backendDir, "app"), filepath.Dir(match)); err == nil {
+				dirs = append(dirs, filepath.ToSlash(rel))
+			}
+		}
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// NamespaceConflict returns the directory among dirs (products, shop/products) of a module named
+// base in another namespace than own, or "" when there is none. Modules of one name share their
+// model, table and admin module whatever their namespace, so only one of them can be generated.
+func NamespaceConflict(dirs []string, base, own string) string {
+	for _, dir := range dirs {
+		if path.Base(dir) == base && dir != own {
+			return dir
+		}
+	}
+	return ""
+}
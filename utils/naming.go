@@ -2,15 +2,20 @@ package utils
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"unicode"
 )
 
 // NamingConvention holds all naming variations derived from a single model name
 type NamingConvention struct {
-	// Original input (e.g., "ProductCategory")
+	// Original input (e.g., "ProductCategory", or "shop/ProductCategory" for a namespaced module)
 	Original string
 
+	// Namespace groups the module under app/<namespace>/ and its routes under /<namespace>/
+	// (e.g., "shop" for "shop/product"); empty for top-level modules
+	Namespace string
+
 	// Model naming
 	Model      string // ProductCategory (PascalCase)
 	ModelLower string // productCategory (camelCase)
@@ -21,15 +26,19 @@ type NamingConvention struct {
 	Plural      string // ProductCategories (PascalCase)
 	PluralLower string // productCategories (camelCase)
 	PluralSnake string // product_categories (snake_case)
-	PluralKebab string // product-categories (kebab-case)
+	PluralKebab string // product-categories (kebab-case), shop/product-categories in a namespace
 
 	// Package and directory naming
 	PackageName string // product_categories (snake_case plural for package)
-	DirName     string // product_categories (snake_case plural for directory)
+	DirName     string // product_categories (snake_case plural for directory), shop/product_categories in a namespace
 
 	// Route naming
-	RoutePath  string // /product-categories (kebab-case plural)
-	RouteGroup string // product-categories (kebab-case plural)
+	RoutePath  string // /product-categories (kebab-case plural), /shop/product-categories in a namespace
+	RouteGroup string // product-categories (kebab-case plural), shop/product-categories in a namespace
+
+	// Slug names the module's files and middleware: product-categories, or shop-product-categories
+	// in a namespace
+	Slug string
 
 	// Controller and Service naming
 	Controller string // ProductCategoriesController
@@ -42,16 +51,36 @@ type NamingConvention struct {
 	VarSingle string // productCategory (camelCase)
 	VarPlural string // productCategories (camelCase)
 	VarId     string // productCategoryId (camelCase + Id)
+
+	// Display naming, which --label and --description override (see ApplyModuleMetadata)
+	Label         string // Product Categories (page titles and the sidebar)
+	LabelSingular string // Product Category
+	Description   string // Shown under the list page title; empty for the default
+	Tag           string // ProductCategory (swagger tag, App/<Tag>)
 }
 
 // NewNamingConvention creates all naming variations from a single model name
 func NewNamingConvention(modelName string) *NamingConvention {
+	// shop/product puts the product module in the shop namespace
+	namespace, name := "", modelName
+	if i := strings.LastIndex(modelName, "/"); i != -1 {
+		namespace, name = strings.Trim(modelName[:i], "/"), modelName[i+1:]
+	}
+	var dirPrefix, routePrefix string
+	if namespace != "" {
+		for _, segment := range strings.Split(namespace, "/") {
+			dirPrefix += ToSnakeCase(segment) + "/"
+			routePrefix += ToKebabCase(segment) + "/"
+		}
+	}
+
 	// Ensure PascalCase for the model name
-	model := ToPascalCase(modelName)
+	model := ToPascalCase(name)
 	plural := PluralizeClient.Plural(model)
 
 	nc := &NamingConvention{
-		Original: modelName,
+		Original:  modelName,
+		Namespace: strings.TrimSuffix(dirPrefix, "/"),
 
 		// Model naming
 		Model:      model,
@@ -63,15 +92,16 @@ func NewNamingConvention(modelName string) *NamingConvention {
 		Plural:      plural,
 		PluralLower: ToCamelCase(plural),
 		PluralSnake: ToSnakeCase(plural),
-		PluralKebab: ToKebabCase(plural),
+		PluralKebab: routePrefix + ToKebabCase(plural),
 
 		// Package and directory naming
 		PackageName: ToSnakeCase(plural),
-		DirName:     ToSnakeCase(plural),
+		DirName:     dirPrefix + ToSnakeCase(plural),
 
 		// Route naming (kebab-case is URL-friendly)
-		RoutePath:  "/" + ToKebabCase(plural),
-		RouteGroup: ToKebabCase(plural),
+		RoutePath:  "/" + routePrefix + ToKebabCase(plural),
+		RouteGroup: routePrefix + ToKebabCase(plural),
+		Slug:       strings.ReplaceAll(routePrefix, "/", "-") + ToKebabCase(plural),
 
 		// Controller and Service naming (singular like model)
 		Controller: model + "Controller",
@@ -84,6 +114,11 @@ func NewNamingConvention(modelName string) *NamingConvention {
 		VarSingle: ToCamelCase(model),
 		VarPlural: ToCamelCase(plural),
 		VarId:     ToCamelCase(model) + "Id",
+
+		// Display naming
+		Label:         ToCapitalCase(ToSnakeCase(plural)),
+		LabelSingular: ToCapitalCase(ToSnakeCase(model)),
+		Tag:           model,
 	}
 
 	return nc
@@ -110,32 +145,128 @@ type Field struct {
 
 	// For relations
 	IsRelation   bool
-	RelationType string // belongs_to, has_many, has_one, many_to_many
+	RelationType string  // belongs_to, has_many, has_one, many_to_many, morph_to, morph_many
+	MorphName    string  // Polymorphic name (e.g., "Commentable" for commentable_id/commentable_type)
+	IsSelfRef    bool    // True for relations back to the model itself (e.g., parent:belongsTo:self)
+	Pivot        []Field // Extra columns of a manyToMany join table (e.g., pivot=assigned_at:datetime)
 
 	// Validation
 	IsRequired bool
 	IsUnique   bool
+	Default    string // Column default from a default=value modifier
+	Rules      []Rule // Validation rules from a rules= modifier (e.g., rules=email,max=255)
 
 	// Special types
 	IsImage         bool
 	IsFile          bool
 	IsAttachment    bool
 	IsMedia         bool
+	IsMediaList     bool   // True for media[] fields, several media joined through a table (e.g., gallery:media[])
 	IsMediaFK       bool   // True for auto-generated FK fields for media (e.g., ImageId field)
 	MediaFKField    string // Foreign key field name for media fields (e.g., "ImageId" for "Image" field)
 	MediaFKJSONName string // JSON name for media FK field (e.g., "image_id" for "Image" field)
 	MediaType       string // Media type filter: "image", "video", "audio", or empty for all types
 	IsTranslation   bool   // True for translation.Field fields
 
+	// Money and decimal fields
+	IsMoney   bool   // True for money fields, stored as an int64 of minor units (e.g., cents)
+	Currency  string // ISO 4217 currency of a money field (e.g., "USD")
+	IsDecimal bool   // True for decimal fields, stored as a shopspring/decimal numeric column
+	Precision int    // Total digits of a decimal field (e.g., 10 for amount:decimal:10,2)
+	Scale     int    // Digits after the decimal point of a decimal field (e.g., 2 for amount:decimal:10,2)
+
+	// Point fields
+	IsPoint   bool   // True for point fields, which ExpandPoint turns into latitude and longitude columns
+	PointName string // Point field a latitude or longitude column belongs to (e.g., "Location")
+	PointAxis string // "lat" or "lng" for the columns of a point field
+
+	// Encrypted fields
+	IsEncrypted bool // True for text stored encrypted with AES-GCM and masked in lists
+
 	// Select/enum fields
 	IsSelect   bool     // True for select fields with predefined options
 	SelectType string   // Type of selection: "select", "radio", "checkbox"
 	Options    []string // Options for select fields (e.g., ["draft", "published", "archived"])
+	IsEnum     bool     // True for enum fields, which also get typed Go constants
+	EnumType   string   // Go type for enum fields (e.g., "PostStatus"), set once the model is known
+
+	// State fields, enums that only move along their transitions
+	IsState     bool              // True for state fields (e.g., status:state:draft>review>published)
+	Transitions []StateTransition // Moves a state field allows, in definition order
+
+	// Computed fields, derived from other fields when a model is loaded
+	IsComputed bool     // True for computed fields (e.g., full_name:computed), which have no column
+	Sources    []string // JSON names of the fields a computed field derives from (e.g., ["first_name", "last_name"])
+
+	// Payloads a field is left out of (--fields-read, --fields-write)
+	HideInList   bool // Not in list responses
+	HideInDetail bool // Not in detail responses
+	NoCreate     bool // Not in create requests
+	NoUpdate     bool // Not in update requests
 }
 
 // ParseField creates a properly structured Field from a field definition string
 func ParseField(fieldDef string) Field {
-	parts := strings.Split(fieldDef, ":")
+	// The pivot columns of a manyToMany relation have colons of their own
+	fieldDef, pivot, hasPivot := strings.Cut(fieldDef, ":pivot=")
+	parts, required, defaultValue, rules := splitFieldModifiers(strings.Split(fieldDef, ":"))
+
+	field := parseFieldParts(parts)
+	field.IsRequired = required
+	field.Rules = parseRules(rules)
+	field.TestValue, field.UpdateTestValue = testValuesFor(field)
+	if len(field.Rules) > 0 {
+		field.TestValue, field.UpdateTestValue = ruleTestValues(field, field.TestValue, field.UpdateTestValue)
+	}
+
+	if hasPivot && field.Relationship == "many_to_many" {
+		for _, def := range strings.Split(pivot, ",") {
+			if def = strings.TrimSpace(def); def != "" {
+				field.Pivot = append(field.Pivot, ParseField(def))
+			}
+		}
+	}
+
+	// Defaults only apply to plain columns; relations and attachments manage their own tags
+	if defaultValue != "" && !field.IsRelation && (field.GORMTag == "" || field.IsDecimal) {
+		field.Default = defaultValue
+		field.GORMTag = fmt.Sprintf(`gorm:"default:%s"`, defaultValue)
+		if field.IsDecimal {
+			field.GORMTag = fmt.Sprintf(`gorm:"type:%s;default:%s"`, decimalColumnType(field), defaultValue)
+		}
+		field.GORM = field.GORMTag
+	}
+
+	return field
+}
+
+// splitFieldModifiers strips trailing "required", "default=value" and "rules=..." segments from a
+// field definition (e.g., title:string:required, views:int:default=0 or age:int:rules=gte=18)
+func splitFieldModifiers(parts []string) ([]string, bool, string, string) {
+	required := false
+	defaultValue := ""
+	rules := ""
+
+	for len(parts) > 2 {
+		last := strings.TrimSpace(parts[len(parts)-1])
+		switch {
+		case last == "required":
+			required = true
+		case strings.HasPrefix(last, "default="):
+			defaultValue = strings.TrimPrefix(last, "default=")
+		case strings.HasPrefix(last, "rules="):
+			rules = strings.TrimPrefix(last, "rules=")
+		default:
+			return parts, required, defaultValue, rules
+		}
+		parts = parts[:len(parts)-1]
+	}
+
+	return parts, required, defaultValue, rules
+}
+
+// parseFieldParts builds a Field from a definition that has had its modifiers removed
+func parseFieldParts(parts []string) Field {
 	fieldName := parts[0]
 	var fieldType string
 
@@ -157,11 +288,36 @@ func ParseField(fieldDef string) Field {
 	field.Relationship = ""
 	field.IsRelation = false
 
-	// Handle select/radio/checkbox fields (e.g., status:select:draft,published,archived)
-	if fieldType == "select" || fieldType == "radio" || fieldType == "checkbox" {
+	// Handle state fields (e.g., status:state:draft>review>published), enums that start in their
+	// first state and only move along their transitions
+	if fieldType == "state" {
+		field.Type = "string"
+		field.IsSelect = true
+		field.SelectType = "select"
+		field.IsEnum = true
+		field.IsState = true
+		if len(parts) > 2 {
+			field.Options, field.Transitions = parseStates(parts[2])
+		}
+		if len(field.Options) > 0 {
+			field.Default = field.Options[0]
+			field.GORMTag = fmt.Sprintf(`gorm:"default:%s"`, field.Default)
+			field.GORM = field.GORMTag
+		}
+		return field
+	}
+
+	// Handle select/radio/checkbox/enum fields (e.g., status:select:draft,published,archived)
+	if fieldType == "select" || fieldType == "radio" || fieldType == "checkbox" || fieldType == "enum" {
 		field.IsSelect = true
 		field.SelectType = fieldType // Store which type: "select", "radio", or "checkbox"
 
+		// Enums render as a select and get typed constants in the model
+		if fieldType == "enum" {
+			field.IsEnum = true
+			field.SelectType = "select"
+		}
+
 		// Checkbox can be array for multiple values
 		if fieldType == "checkbox" {
 			field.Type = "json.RawMessage" // Store as JSON array in DB
@@ -181,8 +337,57 @@ func ParseField(fieldDef string) Field {
 		return field
 	}
 
-	// Handle media fields (e.g., thumbnail:media:image or featured:media)
-	if fieldType == "media" {
+	// Handle money fields (e.g., price:money or price:money:EUR), kept in minor units so sums stay exact
+	if fieldType == "money" {
+		field.Type = "int64"
+		field.IsMoney = true
+		field.Currency = "USD"
+		if len(parts) > 2 && strings.TrimSpace(parts[2]) != "" {
+			field.Currency = strings.ToUpper(strings.TrimSpace(parts[2]))
+		}
+		return field
+	}
+
+	// Handle decimal fields (e.g., rate:decimal or rate:decimal:12,4)
+	if fieldType == "decimal" {
+		return parseDecimalField(parts, field)
+	}
+
+	// Handle encrypted fields (e.g., ssn:encrypted), stored as ciphertext in a text column
+	if fieldType == "encrypted" {
+		field.Type = "string"
+		field.IsEncrypted = true
+		field.GORMTag = `gorm:"type:text"`
+		field.GORM = field.GORMTag
+		return field
+	}
+
+	// Handle computed fields (e.g., full_name:computed or full_name:computed:first_name,last_name),
+	// read-only values the model derives from its other fields
+	if fieldType == "computed" {
+		field.Type = "string"
+		field.IsComputed = true
+		field.GORMTag = `gorm:"-"`
+		field.GORM = field.GORMTag
+		if len(parts) > 2 {
+			for _, source := range strings.Split(parts[2], ",") {
+				if source = strings.TrimSpace(source); source != "" {
+					field.Sources = append(field.Sources, ToSnakeCase(source))
+				}
+			}
+		}
+		return field
+	}
+
+	// Handle point fields (e.g., location:point); ExpandPoint turns them into columns
+	if fieldType == "point" {
+		field.Type = "point"
+		field.IsPoint = true
+		return field
+	}
+
+	// Handle media fields (e.g., thumbnail:media:image, featured:media or gallery:media[]:image)
+	if fieldType == "media" || fieldType == "media[]" {
 		foreignKeyField := field.Name + "Id"
 		field.JSONTag = ToSnakeCase(fieldName)
 		field.JSONName = ToSnakeCase(fieldName)
@@ -200,6 +405,15 @@ func ParseField(fieldDef string) Field {
 			}
 		}
 
+		// A media list has no column; its ids are set through the join table
+		if fieldType == "media[]" {
+			field.Type = "[]*media.Media"
+			field.IsMediaList = true
+			field.MediaFKField = Singularize(field.Name) + "Ids"
+			field.MediaFKJSONName = ToSnakeCase(field.MediaFKField)
+			field.GORMTag = ""
+		}
+
 		field.GORM = field.GORMTag
 		return field
 	}
@@ -216,6 +430,10 @@ func ParseField(fieldDef string) Field {
 			return parseHasOneField(fieldName, parts, field)
 		case "many_to_many":
 			return parseManyToManyField(fieldName, parts, field)
+		case "morph_to":
+			return parseMorphToField(fieldName, field)
+		case "morph_many":
+			return parseMorphManyField(fieldName, parts, field)
 		}
 	} else if fieldType == "attachment" || fieldType == "file" || fieldType == "image" {
 		return parseAttachmentField(fieldName, fieldType, field)
@@ -253,6 +471,34 @@ func ParseField(fieldDef string) Field {
 	return field
 }
 
+// parseDecimalField handles decimal fields, which default to a precision of 10 and a scale of 2.
+// A precision,scale that doesn't parse keeps the defaults.
+func parseDecimalField(parts []string, field Field) Field {
+	field.Type = "decimal.Decimal"
+	field.IsDecimal = true
+	field.Precision, field.Scale = 10, 2
+
+	if len(parts) > 2 {
+		precision, scale, _ := strings.Cut(parts[2], ",")
+		p, err := strconv.Atoi(strings.TrimSpace(precision))
+		if err == nil && p > 0 {
+			field.Precision, field.Scale = p, 0
+			if s, err := strconv.Atoi(strings.TrimSpace(scale)); err == nil && s >= 0 && s <= p {
+				field.Scale = s
+			}
+		}
+	}
+
+	field.GORMTag = fmt.Sprintf(`gorm:"type:%s"`, decimalColumnType(field))
+	field.GORM = field.GORMTag
+	return field
+}
+
+// decimalColumnType returns the SQL type of a decimal field (e.g., "numeric(10,2)")
+func decimalColumnType(field Field) string {
+	return fmt.Sprintf("numeric(%d,%d)", field.Precision, field.Scale)
+}
+
 // parseBelongsToField handles belongsTo relationship fields
 func parseBelongsToField(fieldName string, parts []string, field Field) Field {
 	field.IsRelation = true
@@ -289,7 +535,8 @@ func parseBelongsToField(fieldName string, parts []string, field Field) Field {
 	return field
 }
 
-// parseHasManyField handles hasMany relationship fields
+// parseHasManyField handles hasMany relationship fields (e.g., comments:hasMany:Comment, or
+// posts:hasMany:Post:author_id when the related model's key isn't <model>_id)
 func parseHasManyField(fieldName string, parts []string, field Field) Field {
 	field.IsRelation = true
 	field.RelationType = "has_many"
@@ -302,6 +549,9 @@ func parseHasManyField(fieldName string, parts []string, field Field) Field {
 		// Infer model from field name (plural to singular)
 		relatedModel = ToPascalCase(Singularize(fieldName))
 	}
+	if len(parts) > 3 {
+		field.ForeignKey = ToPascalCase(parts[3])
+	}
 
 	field.Type = "[]*" + relatedModel
 	field.RelatedModel = relatedModel
@@ -351,6 +601,122 @@ func parseManyToManyField(fieldName string, parts []string, field Field) Field {
 	return field
 }
 
+// parseMorphToField handles morphTo fields (e.g., commentable:morphTo).
+// The field itself only names the relation; ExpandMorphTo turns it into columns.
+func parseMorphToField(fieldName string, field Field) Field {
+	field.IsRelation = true
+	field.RelationType = "morph_to"
+	field.Relationship = "morph_to"
+	field.MorphName = ToPascalCase(fieldName)
+
+	return field
+}
+
+// parseMorphManyField handles morphMany fields (e.g., comments:morphMany:Comment or comments:morphMany:Comment:commentable)
+func parseMorphManyField(fieldName string, parts []string, field Field) Field {
+	field.IsRelation = true
+	field.RelationType = "morph_many"
+	field.Relationship = "morph_many"
+
+	var relatedModel string
+	if len(parts) > 2 {
+		relatedModel = ToPascalCase(parts[2])
+	} else {
+		relatedModel = ToPascalCase(Singularize(fieldName))
+	}
+
+	// The morph name defaults to <related>able, matching <related>able:morphTo on the child
+	if len(parts) > 3 {
+		field.MorphName = ToPascalCase(parts[3])
+	} else {
+		field.MorphName = relatedModel + "able"
+	}
+
+	field.Type = "[]*" + relatedModel
+	field.RelatedModel = relatedModel
+
+	return field
+}
+
+// ExpandMorphTo replaces a morphTo field with its <name>_id and <name>_type columns.
+// Other fields are returned unchanged.
+func ExpandMorphTo(field Field) []Field {
+	if field.Relationship != "morph_to" {
+		return []Field{field}
+	}
+
+	name := ToSnakeCase(field.MorphName)
+	idField := Field{
+		Name:       field.MorphName + "Id",
+		Type:       "uint",
+		JSONTag:    name + "_id",
+		JSONName:   name + "_id",
+		DBName:     name + "_id",
+		MorphName:  field.MorphName,
+		IsRequired: field.IsRequired,
+	}
+	typeField := Field{
+		Name:       field.MorphName + "Type",
+		Type:       "string",
+		JSONTag:    name + "_type",
+		JSONName:   name + "_type",
+		DBName:     name + "_type",
+		MorphName:  field.MorphName,
+		IsRequired: field.IsRequired,
+	}
+	idField.TestValue, idField.UpdateTestValue = testValuesFor(idField)
+	typeField.TestValue, typeField.UpdateTestValue = testValuesFor(typeField)
+
+	return []Field{idField, typeField}
+}
+
+// ResolveSelfRelations points relations to "self" (or to the model itself) at the model being
+// generated. A tree needs both ends, so a parent_id belongsTo gets a matching children hasMany
+// and vice versa, with the hasMany keyed on the parent's foreign key.
+func ResolveSelfRelations(model string, fields []Field) []Field {
+	parent, children := -1, -1
+	resolved := make([]Field, 0, len(fields)+1)
+	for _, field := range fields {
+		if field.IsRelation && (strings.EqualFold(field.RelatedModel, "self") || field.RelatedModel == model) {
+			switch field.Relationship {
+			case "belongs_to":
+				if parent == -1 {
+					parent = len(resolved)
+				}
+				field.RelatedModel = model
+				field.IsSelfRef = true
+			case "has_many":
+				if children == -1 {
+					children = len(resolved)
+				}
+				field.RelatedModel = model
+				field.Type = "[]*" + model
+				field.IsSelfRef = true
+			}
+		}
+		resolved = append(resolved, field)
+	}
+
+	if parent == -1 && children == -1 {
+		return resolved
+	}
+	if parent == -1 {
+		field := ParseField("parent:belongsTo:" + model)
+		field.IsSelfRef = true
+		parent = len(resolved)
+		resolved = append(resolved, field)
+	}
+	if children == -1 {
+		field := ParseField("children:hasMany:" + model)
+		field.IsSelfRef = true
+		children = len(resolved)
+		resolved = append(resolved, field)
+	}
+	resolved[children].ForeignKey = resolved[parent].Name
+
+	return resolved
+}
+
 // parseAttachmentField handles attachment/file/image fields
 func parseAttachmentField(_ string, fieldType string, field Field) Field {
 	field.Type = "*storage.Attachment"
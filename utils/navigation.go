@@ -0,0 +1,223 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// NavIcon is the icon of a generated module's sidebar entry and module.config.ts (--icon
+	// i-lucide-shopping-cart); see IconName
+	NavIcon string
+
+	// NavGroup is the sidebar group a generated module is listed in (--nav-group Catalog)
+	NavGroup string
+)
+
+// DefaultNavIcon is the sidebar icon of modules generated without --icon
+const DefaultNavIcon = "i-lucide-box"
+
+// DefaultNavGroup is the sidebar group of top-level modules generated without --nav-group
+const DefaultNavGroup = "Modules"
+
+// NavigationFile is the sidebar configuration of the generated modules, relative to the frontend
+var NavigationFile = filepath.Join("app", "config", "navigation.ts")
+
+// navigationHeader starts a new NavigationFile; the admin layout lists moduleNavigation in its sidebar
+const navigationHeader = `// Sidebar entries of the generated modules, listed by the admin layout under their group.
+// bui g adds an entry for each module and bui d removes it; entries can be edited, reordered
+// or moved to another group by hand.
+
+export interface NavigationItem {
+  label: string
+  icon: string
+  to: string
+  permission?: string
+}
+
+export interface NavigationGroup {
+  label: string
+  items: NavigationItem[]
+}
+
+export const moduleNavigation: NavigationGroup[] = [
+]
+`
+
+// NavigationItem is a module's sidebar entry in NavigationFile
+type NavigationItem struct {
+	Group      string `json:"group"`
+	Label      string `json:"label"`
+	Icon       string `json:"icon"`
+	To         string `json:"to"`
+	Permission string `json:"permission,omitempty"`
+}
+
+// navigationItemPattern matches an entry line written by AddNavigationItem
+var navigationItemPattern = regexp.MustCompile(`\{ label: '((?:[^'\\]|\\.)*)', icon: '([^']*)', to: '([^']*)'(?:, permission: '([^']*)')? \}`)
+
+// navigationGroupPattern matches the label line of a group
+var navigationGroupPattern = regexp.MustCompile(`(?m)^  \{\n    label: '((?:[^'\\]|\\.)*)',\n    items: \[\n`)
+
+// NewNavigationItem returns the sidebar entry of a module with its label, in the --nav-group and
+// with the --icon when they are given. Namespaced modules default to a group named after the namespace.
+func NewNavigationItem(naming *NamingConvention) NavigationItem {
+	group := NavGroup
+	if group == "" && naming.Namespace != "" {
+		group = ToCapitalCase(strings.ReplaceAll(naming.Namespace, "/", " "))
+	}
+	if group == "" {
+		group = DefaultNavGroup
+	}
+	return NavigationItem{
+		Group:      group,
+		Label:      naming.Label,
+		Icon:       IconName(NavIcon),
+		To:         "/app/" + naming.PluralKebab,
+		Permission: naming.ModelSnake + ":list",
+	}
+}
+
+// line returns the entry as AddNavigationItem writes it
+func (item NavigationItem) line() string {
+	line := fmt.Sprintf("{ label: '%s', icon: '%s', to: '%s'", jsString(item.Label), item.Icon, item.To)
+	if item.Permission != "" {
+		line += fmt.Sprintf(", permission: '%s'", item.Permission)
+	}
+	return "      " + line + " },\n"
+}
+
+// AddNavigationItem adds item to its group in <frontendDir>/NavigationFile, creating the file or
+// the group when needed. It reports whether the file changed; a module that already has an entry
+// keeps it.
+func AddNavigationItem(frontendDir string, item NavigationItem) (bool, error) {
+	return defaultWriter.addNavigationItem(filepath.Join(frontendDir, NavigationFile), item)
+}
+
+// AddNavigationItem is AddNavigationItem for the generator of w, in the frontend in w.Dir
+func (w *Writer) AddNavigationItem(item NavigationItem) (bool, error) {
+	return w.addNavigationItem(w.Path(NavigationFile), item)
+}
+
+// addNavigationItem adds item to the navigation file at path
+func (w *Writer) addNavigationItem(path string, item NavigationItem) (bool, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		content = []byte(navigationHeader)
+	} else if err != nil {
+		return false, err
+	}
+	contentStr := string(content)
+
+	if existing, _, _ := findNavigationItem(contentStr, item.To); existing != nil {
+		return false, nil
+	}
+
+	// Entries go at the end of their group's items
+	groupStart := -1
+	for _, match := range navigationGroupPattern.FindAllStringSubmatchIndex(contentStr, -1) {
+		if contentStr[match[2]:match[3]] == jsString(item.Group) {
+			groupStart = match[1]
+			break
+		}
+	}
+	if groupStart != -1 {
+		end := strings.Index(contentStr[groupStart:], "    ],\n")
+		if end == -1 {
+			return false, fmt.Errorf("could not find the end of the %s group in %s", item.Group, NavigationFile)
+		}
+		insertPoint := groupStart + end
+		contentStr = contentStr[:insertPoint] + item.line() + contentStr[insertPoint:]
+	} else {
+		// A new group goes at the end of moduleNavigation
+		end := strings.LastIndex(contentStr, "\n]")
+		if end == -1 {
+			return false, fmt.Errorf("could not find the end of moduleNavigation in %s", NavigationFile)
+		}
+		group := fmt.Sprintf("  {\n    label: '%s',\n    items: [\n%s    ],\n  },", jsString(item.Group), item.line())
+		contentStr = contentStr[:end+1] + group + contentStr[end:]
+	}
+
+	if err := w.UpdateProjectFile(path, []byte(contentStr)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RemoveNavigationItem removes the entry that links to route from <frontendDir>/NavigationFile,
+// and its group when it was the last entry. It returns the removed entry, or nil when there was none.
+func RemoveNavigationItem(frontendDir, route string) (*NavigationItem, error) {
+	path := filepath.Join(frontendDir, NavigationFile)
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	contentStr := string(content)
+
+	item, start, end := findNavigationItem(contentStr, route)
+	if item == nil {
+		return nil, nil
+	}
+	contentStr = contentStr[:start] + contentStr[end:]
+
+	// Drop the group it leaves empty
+	emptyGroup := fmt.Sprintf("  {\n    label: '%s',\n    items: [\n    ],\n  },\n", jsString(item.Group))
+	contentStr = strings.Replace(contentStr, emptyGroup, "", 1)
+
+	if err := UpdateProjectFile(path, []byte(contentStr)); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// FindNavigationItem returns the entry of <frontendDir>/NavigationFile that links to route, or nil
+func FindNavigationItem(frontendDir, route string) *NavigationItem {
+	content, err := os.ReadFile(filepath.Join(frontendDir, NavigationFile))
+	if err != nil {
+		return nil
+	}
+	item, _, _ := findNavigationItem(string(content), route)
+	return item
+}
+
+// findNavigationItem returns the entry of content that links to route, with the group it's in,
+// and where its line starts and ends
+func findNavigationItem(content, route string) (*NavigationItem, int, int) {
+	group := ""
+	offset := 0
+	for _, line := range strings.SplitAfter(content, "\n") {
+		start := offset
+		offset += len(line)
+		if label, ok := strings.CutPrefix(strings.TrimSpace(line), "label: '"); ok {
+			group = unescapeJSString(strings.TrimSuffix(label, "',"))
+			continue
+		}
+		match := navigationItemPattern.FindStringSubmatch(line)
+		if match == nil || match[3] != route {
+			continue
+		}
+		return &NavigationItem{
+			Group:      group,
+			Label:      unescapeJSString(match[1]),
+			Icon:       match[2],
+			To:         match[3],
+			Permission: match[4],
+		}, start, offset
+	}
+	return nil, 0, 0
+}
+
+// jsString escapes s for a single-quoted TypeScript string
+func jsString(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), "'", `\'`)
+}
+
+// unescapeJSString reverses jsString
+func unescapeJSString(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, `\'`, "'"), `\\`, `\`)
+}
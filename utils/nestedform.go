@@ -0,0 +1,185 @@
+package utils
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// NestedForms lists the hasMany fields whose rows are edited inside the parent's form (--nested-form items)
+var NestedForms string
+
+// NestedForm is a hasMany relation edited inline: the parent's create and update requests carry
+// its rows, and they are saved in the same transaction as the parent
+type NestedForm struct {
+	Name        string  // hasMany field, e.g. Items
+	JSONName    string  // e.g. items
+	Child       string  // Related model, e.g. OrderItem
+	ChildTable  string  // e.g. order_items
+	ChildIDType string  // Go type of the child's id: uint or uuid.UUID
+	UUIDKey     bool    // Whether the child has uuid ids
+	ForeignKey  string  // Child field holding the parent's id, e.g. OrderId
+	FKColumn    string  // e.g. order_id
+	FKPointer   bool    // Whether the foreign key is a pointer, as generated belongsTo keys are
+	Tenant      bool    // Whether the child has an organization_id, copied from the parent
+	Fields      []Field // Child columns edited in the form
+}
+
+// ResolveNestedForms reads the child models of the --nested-form fields from modelsDir. Besides the
+// forms it returns problems to report: fields that aren't hasMany relations, child models that
+// don't exist yet, and child columns the form leaves out.
+func ResolveNestedForms(model string, fields []Field, modelsDir string) ([]NestedForm, []string) {
+	var forms []NestedForm
+	var problems []string
+	for _, name := range splitColumns(NestedForms) {
+		match := -1
+		for i, field := range fields {
+			if field.Relationship == "has_many" && field.JSONName == name {
+				match = i
+				break
+			}
+		}
+		if match == -1 {
+			problems = append(problems, fmt.Sprintf("--nested-form %s is not a hasMany field of this model", name))
+			continue
+		}
+		field := fields[match]
+		if field.IsSelfRef {
+			problems = append(problems, fmt.Sprintf("--nested-form %s: the children of a tree are edited on their own pages", name))
+			continue
+		}
+
+		form, skipped, err := resolveNestedForm(model, field, modelsDir)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("--nested-form %s: %v", name, err))
+			continue
+		}
+		for _, column := range skipped {
+			problems = append(problems, fmt.Sprintf("--nested-form %s: %s.%s can't be edited inline; edit it on the %s page", name, form.Child, column, ToPlural(form.Child)))
+		}
+		forms = append(forms, form)
+	}
+	return forms, problems
+}
+
+// resolveNestedForm reads the child model of one hasMany field, returning the form and the child
+// columns it leaves out
+func resolveNestedForm(model string, field Field, modelsDir string) (NestedForm, []string, error) {
+	child := field.RelatedModel
+	form := NestedForm{
+		Name:       field.Name,
+		JSONName:   field.JSONName,
+		Child:      child,
+		ForeignKey: field.ForeignKey,
+	}
+	if form.ForeignKey == "" {
+		form.ForeignKey = model + "Id"
+	}
+	form.FKColumn = ToSnakeCase(form.ForeignKey)
+
+	path := filepath.Join(modelsDir, ToSnakeCase(child)+".go")
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return form, nil, fmt.Errorf("%s not found; generate the %s module first", path, child)
+	}
+
+	types, err := structFieldTypes(source, child)
+	if err != nil {
+		return form, nil, err
+	}
+	fkType, ok := types[form.ForeignKey]
+	if !ok {
+		return form, nil, fmt.Errorf("%s has no %s; add %s:belongsTo:%s to it", child, form.ForeignKey, ToSnakeCase(model), model)
+	}
+	form.FKPointer = strings.HasPrefix(fkType, "*")
+	form.ChildIDType = types["Id"]
+	form.UUIDKey = form.ChildIDType == "uuid.UUID"
+	_, form.Tenant = types["OrganizationId"]
+	form.ChildTable = RecoverTableName(source, child)
+	if form.ChildTable == "" {
+		form.ChildTable = ToSnakeCase(ToPlural(child))
+	}
+
+	defs, err := RecoverFieldDefs(source, child)
+	if err != nil {
+		return form, nil, err
+	}
+	var skipped []string
+	for _, def := range defs {
+		column := ParseField(def)
+		if column.Name == form.ForeignKey || column.Name+"Id" == form.ForeignKey || column.Name == "OrganizationId" {
+			continue
+		}
+		if !isInlineField(column) {
+			skipped = append(skipped, column.Name)
+			continue
+		}
+		if column.IsEnum {
+			column.EnumType = child + column.Name
+			column.Type = column.EnumType
+		}
+		form.Fields = append(form.Fields, column)
+	}
+	return form, skipped, nil
+}
+
+// isInlineField reports whether a child column can be edited in a row of the parent's form
+func isInlineField(field Field) bool {
+	if field.IsRelation || field.Relationship != "" || field.IsMedia || field.IsMediaFK || field.IsAttachment || field.IsTranslation {
+		return false
+	}
+	if field.IsSelect {
+		return field.SelectType != "checkbox"
+	}
+	switch field.Type {
+	case "string", "int", "uint", "float64", "bool":
+		return true
+	}
+	return false
+}
+
+// structFieldTypes returns the type of each field of a model struct, by field name
+func structFieldTypes(source []byte, model string) (map[string]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", source, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			spec, ok := spec.(*ast.TypeSpec)
+			if !ok || spec.Name.Name != model {
+				continue
+			}
+			st, ok := spec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			types := map[string]string{}
+			for _, field := range st.Fields.List {
+				for _, name := range field.Names {
+					types[name.Name] = exprString(field.Type)
+				}
+			}
+			return types, nil
+		}
+	}
+	return nil, fmt.Errorf("struct %s not found", model)
+}
+
+// nestedFormsIn returns the --nested-form relations of a module of the backend in dir, read from
+// its app/models
+func nestedFormsIn(dir, model string, fields []Field) []NestedForm {
+	if NestedForms == "" {
+		return nil
+	}
+	forms, _ := ResolveNestedForms(model, fields, filepath.Join(dir, "app", "models"))
+	return forms
+}
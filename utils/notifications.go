@@ -0,0 +1,10 @@
+package utils
+
+// NotificationTransports are how the admin bell of bui g notifications gets new notifications
+var NotificationTransports = []string{"sse", "polling"}
+
+// Notifications are the options of bui g notifications
+type Notifications struct {
+	Stream      bool // The API streams notifications over server-sent events, else the bell polls
+	PollSeconds int  // Seconds between the bell's polls, and its fallback when the stream fails
+}
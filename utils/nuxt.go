@@ -13,6 +13,7 @@ type NuxtField struct {
 	ShowInTable          bool
 	ShowInForm           bool
 	ShowInDetail         bool
+	FormCondition        string // v-if of a field only the create or the update form edits
 	IsFilterable         bool
 	IsSortable           bool
 	IsNullable           bool
@@ -51,6 +52,44 @@ func ConvertToNuxtField(field Field) NuxtField {
 		LabelLower:     strings.ToLower(ToCapitalCase(cleanJSONName)),
 	}
 
+	// Enums are typed as a union of their options (e.g., 'draft' | 'published')
+	if field.IsEnum && len(field.Options) > 0 {
+		nf.TypeScriptType = GetEnumTypeScriptType(field.Options)
+	}
+
+	// State fields start in their first state and move with the transition buttons
+	if field.IsState {
+		nf.ShowInForm = false
+	}
+
+	// Computed fields are derived by the backend, so they're shown but never edited
+	if field.IsComputed {
+		nf.ShowInForm = false
+	}
+
+	// A point is labelled with its name and shown once, in its latitude column
+	if field.PointName != "" {
+		nf.Label = ToCapitalCase(ToSnakeCase(field.PointName))
+		nf.LabelLower = strings.ToLower(nf.Label)
+		nf.ShowInDetail = field.PointAxis == "lat"
+	}
+
+	// Fields left out of a payload (--fields-read, --fields-write) are left out of its page
+	if field.HideInList {
+		nf.ShowInTable = false
+	}
+	if field.HideInDetail {
+		nf.ShowInDetail = false
+	}
+	switch {
+	case field.Unwritable():
+		nf.ShowInForm = false
+	case field.NoCreate:
+		nf.FormCondition = "isEdit"
+	case field.NoUpdate:
+		nf.FormCondition = "!isEdit"
+	}
+
 	// Handle relation-specific fields
 	if field.IsRelation && field.RelatedModel != "" {
 		// Extract model name from package.Model format (e.g., "users.User" -> "User")
@@ -74,8 +113,8 @@ func ConvertToNuxtField(field Field) NuxtField {
 			nf.ShowInDetail = false // Don't show FK in detail, will show relation object instead
 			nf.IsFilterable = true
 
-		case "has_many":
-			// hasMany: show count in table with link
+		case "has_many", "morph_many":
+			// hasMany/morphMany: show count in table with link
 			nf.RelationModelPlural = ToPlural(relatedModelName)
 			nf.RelationModelKebab = ToKebabCase(ToPlural(relatedModelName))
 			nf.RelationModelSingular = strings.ToLower(relatedModelName)
@@ -85,6 +124,9 @@ func ConvertToNuxtField(field Field) NuxtField {
 			nf.ShowInTable = true   // Show count in table
 			nf.ShowInDetail = true  // Show list in detail view
 			nf.IsFilterable = false
+			if field.IsSelfRef {
+				nf.ShowInTable = false // The list is already indented as a tree
+			}
 
 		case "many_to_many":
 			// manyToMany: show chips in table
@@ -128,6 +170,8 @@ func GetTypeScriptType(goType string) string {
 		return "string"
 	case goType == "datatypes.JSON", goType == "json.RawMessage":
 		return "Record<string, any>"
+	case goType == "decimal.Decimal":
+		return "string" // Sent as a string, so no digits are lost
 	case strings.Contains(goType, "storage.Attachment"):
 		return "string" // URL to the file
 	default:
@@ -136,6 +180,15 @@ func GetTypeScriptType(goType string) string {
 	}
 }
 
+// GetEnumTypeScriptType returns a TypeScript union of string literals for enum options
+func GetEnumTypeScriptType(options []string) string {
+	literals := make([]string, len(options))
+	for i, option := range options {
+		literals[i] = "'" + option + "'"
+	}
+	return strings.Join(literals, " | ")
+}
+
 // GetFormType determines the form input type
 func GetFormType(field Field) string {
 	fieldName := strings.ToLower(field.JSONName)
@@ -151,6 +204,29 @@ func GetFormType(field Field) string {
 		return "file"
 	}
 
+	// Money gets the currency input; decimals are typed as text to keep their digits
+	if field.IsMoney {
+		return "money"
+	}
+	if field.IsDecimal {
+		return "decimal"
+	}
+
+	// Both columns of a point are picked on one map
+	if field.PointName != "" {
+		return "point"
+	}
+
+	// Encrypted values are typed like passwords
+	if field.IsEncrypted {
+		return "encrypted"
+	}
+
+	// JSON documents get the JSON editor
+	if field.Type == "datatypes.JSON" || field.Type == "json.RawMessage" {
+		return "json"
+	}
+
 	switch field.Type {
 	case "bool":
 		return "checkbox"
@@ -181,10 +257,6 @@ func GetFormType(field Field) string {
 		if strings.Contains(fieldName, "password") {
 			return "password"
 		}
-		// Check for enum-like fields
-		if strings.Contains(fieldName, "status") || strings.Contains(fieldName, "category") || strings.Contains(fieldName, "type") {
-			return "select"
-		}
 		return "text"
 	default:
 		return "text"
@@ -217,8 +289,18 @@ func ShouldShowInTable(field Field) bool {
 		return false
 	}
 
-	// Never show large text fields or JSON in table
-	if field.Type == "text" || field.Type == "datatypes.JSON" || field.Type == "json.RawMessage" {
+	// morphTo ids are shown together with their type column
+	if field.MorphName != "" && !field.IsRelation && field.Type == "uint" {
+		return false
+	}
+
+	// Points are shown in their latitude column
+	if field.PointAxis == "lng" {
+		return false
+	}
+
+	// Never show large text fields in table; JSON gets a one-line preview
+	if field.Type == "text" || (field.IsSelect && field.Type == "json.RawMessage") {
 		return false
 	}
 
@@ -230,6 +312,11 @@ func ShouldShowInTable(field Field) bool {
 		return false
 	}
 
+	// Media lists are shown on the detail page
+	if field.IsMediaList {
+		return false
+	}
+
 	return true
 }
 
@@ -252,9 +339,15 @@ func ShouldShowInForm(field Field) bool {
 
 // IsFilterable determines if field can be used as a filter
 func IsFilterable(field Field) bool {
+	// Encrypted values can only be compared after decrypting, computed ones have no column, and
+	// filtering on a column the API doesn't return would reveal it
+	if field.IsEncrypted || field.IsComputed || field.HideInDetail {
+		return false
+	}
+
 	// Can filter by: strings, enums, booleans, numbers, foreign keys
 	switch field.Type {
-	case "string", "bool", "int", "uint", "float32", "float64":
+	case "string", "bool", "int", "int64", "uint", "float32", "float64":
 		return true
 	case "time.Time", "types.DateTime":
 		return true
@@ -265,9 +358,15 @@ func IsFilterable(field Field) bool {
 
 // IsSortable determines if field can be used for sorting
 func IsSortable(field Field) bool {
+	// The order of ciphertext says nothing about the values, computed ones have no column, and
+	// sorting on a column the API doesn't return would reveal it
+	if field.IsEncrypted || field.IsComputed || field.HideInDetail {
+		return false
+	}
+
 	// Can sort by: strings, numbers, dates
 	switch field.Type {
-	case "string", "int", "uint", "float32", "float64", "time.Time", "types.DateTime":
+	case "string", "int", "int64", "uint", "float32", "float64", "decimal.Decimal", "time.Time", "types.DateTime":
 		return true
 	default:
 		return false
@@ -276,6 +375,32 @@ func IsSortable(field Field) bool {
 
 // GetDefaultValue returns the TypeScript default value for a field
 func GetDefaultValue(field Field) string {
+	// Enum unions have no empty member, so start on the default or first option
+	if field.IsEnum && len(field.Options) > 0 {
+		value := field.Default
+		if value == "" {
+			value = field.Options[0]
+		}
+		return "'" + strings.ReplaceAll(value, "'", "\\'") + "'"
+	}
+	// Points start unset rather than at 0, 0
+	if field.PointName != "" {
+		return "null"
+	}
+	// Decimals are strings, starting at zero
+	if field.IsDecimal {
+		value := field.Default
+		if value == "" {
+			value = "0"
+		}
+		return "'" + value + "'"
+	}
+	if field.Default != "" {
+		if field.Type == "string" {
+			return "'" + strings.ReplaceAll(field.Default, "'", "\\'") + "'"
+		}
+		return field.Default
+	}
 	if field.Type == "bool" {
 		return "false"
 	}
@@ -324,11 +449,21 @@ func IsRequiredField(field Field) bool {
 		return false
 	}
 
+	// Explicitly required fields (e.g., title:string:required)
+	if field.IsRequired {
+		return true
+	}
+
 	// Nullable fields are not required
 	if IsNullableField(field) {
 		return false
 	}
 
+	// A media list may be empty
+	if field.IsMediaList {
+		return false
+	}
+
 	// Fields with "optional" in name are not required
 	if strings.Contains(fieldName, "optional") {
 		return false
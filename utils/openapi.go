@@ -0,0 +1,360 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OpenAPIMethods are the operation keys of a path item, in the order bui generates them
+var OpenAPIMethods = []string{"get", "post", "put", "patch", "delete"}
+
+// OpenAPISpec is the part of a Swagger 2.0 or OpenAPI 3 document bui reads
+type OpenAPISpec struct {
+	Swagger     string                                  `json:"swagger"`
+	OpenAPI     string                                  `json:"openapi"`
+	BasePath    string                                  `json:"basePath"`
+	Paths       map[string]map[string]*OpenAPIOperation `json:"-"`
+	Definitions map[string]*OpenAPISchema               `json:"definitions"` // Swagger 2.0
+	Components  struct {
+		Schemas map[string]*OpenAPISchema `json:"schemas"` // OpenAPI 3
+	} `json:"components"`
+
+	// RawPaths is paths as read; path items mix operations with shared keys, so LoadOpenAPISpec splits them into Paths
+	RawPaths map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+// OpenAPIOperation is a single method of a path
+type OpenAPIOperation struct {
+	OperationID string                      `json:"operationId"`
+	Summary     string                      `json:"summary"`
+	Description string                      `json:"description"`
+	Tags        []string                    `json:"tags"`
+	Parameters  []*OpenAPIParameter         `json:"parameters"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody"` // OpenAPI 3
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+}
+
+// OpenAPIParameter is a path, query, header or (Swagger 2.0) body parameter
+type OpenAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description"`
+	Required    bool           `json:"required"`
+	Type        OpenAPIType    `json:"type"`   // Swagger 2.0 non-body parameters
+	Format      string         `json:"format"` // Swagger 2.0 non-body parameters
+	Items       *OpenAPISchema `json:"items"`
+	Enum        []any          `json:"enum"`
+	Schema      *OpenAPISchema `json:"schema"`
+	Ref         string         `json:"$ref"`
+}
+
+// OpenAPIRequestBody is an OpenAPI 3 request body
+type OpenAPIRequestBody struct {
+	Required bool                         `json:"required"`
+	Content  map[string]*OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIResponse is a response of an operation
+type OpenAPIResponse struct {
+	Description string                       `json:"description"`
+	Schema      *OpenAPISchema               `json:"schema"`  // Swagger 2.0
+	Content     map[string]*OpenAPIMediaType `json:"content"` // OpenAPI 3
+}
+
+// OpenAPIMediaType holds the schema of one content type
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPISchema is a JSON schema as used by both spec versions
+type OpenAPISchema struct {
+	Ref                  string                    `json:"$ref"`
+	Type                 OpenAPIType               `json:"type"`
+	Format               string                    `json:"format"`
+	Description          string                    `json:"description"`
+	Enum                 []any                     `json:"enum"`
+	Items                *OpenAPISchema            `json:"items"`
+	Properties           map[string]*OpenAPISchema `json:"properties"`
+	Required             []string                  `json:"required"`
+	AdditionalProperties *OpenAPISchema            `json:"-"`
+	AllOf                []*OpenAPISchema          `json:"allOf"`
+	OneOf                []*OpenAPISchema          `json:"oneOf"`
+	AnyOf                []*OpenAPISchema          `json:"anyOf"`
+	Nullable             bool                      `json:"nullable"`
+	XNullable            bool                      `json:"x-nullable"`
+	MaxLength            *int                      `json:"maxLength"`
+	Default              any                       `json:"default"`
+	Discriminator        json.RawMessage           `json:"discriminator"`
+
+	// RawAdditionalProperties is additionalProperties as read, decoded into AdditionalProperties on load
+	RawAdditionalProperties json.RawMessage `json:"additionalProperties"`
+}
+
+// OpenAPIType is a schema type; OpenAPI 3.1 allows a list such as ["string", "null"]
+type OpenAPIType struct {
+	Name     string
+	Nullable bool
+}
+
+// UnmarshalJSON accepts both "string" and ["string", "null"]
+func (t *OpenAPIType) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		t.Name = name
+		return nil
+	}
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if name == "null" {
+			t.Nullable = true
+		} else if t.Name == "" {
+			t.Name = name
+		}
+	}
+	return nil
+}
+
+// IsNullable reports whether null is allowed by any of the spec versions' conventions
+func (s *OpenAPISchema) IsNullable() bool {
+	return s.Nullable || s.XNullable || s.Type.Nullable
+}
+
+// LoadOpenAPISpec reads a Swagger 2.0 or OpenAPI 3 document in JSON or YAML
+func LoadOpenAPISpec(path string) (*OpenAPISpec, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// YAML is converted to JSON so one set of struct tags covers both
+	if trimmed := strings.TrimSpace(string(content)); !strings.HasPrefix(trimmed, "{") {
+		var doc any
+		if err := yaml.Unmarshal(content, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if content, err = json.Marshal(stringKeys(doc)); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+
+	spec := &OpenAPISpec{}
+	if err := json.Unmarshal(content, spec); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if spec.Swagger == "" && spec.OpenAPI == "" {
+		return nil, fmt.Errorf("%s is not a Swagger or OpenAPI document", path)
+	}
+
+	// Path items also hold shared parameters and $ref, so only the method keys are operations
+	spec.Paths = map[string]map[string]*OpenAPIOperation{}
+	for route, item := range spec.RawPaths {
+		var shared []*OpenAPIParameter
+		if raw, ok := item["parameters"]; ok {
+			json.Unmarshal(raw, &shared)
+		}
+		for _, method := range OpenAPIMethods {
+			raw, ok := item[method]
+			if !ok {
+				continue
+			}
+			op := &OpenAPIOperation{}
+			if err := json.Unmarshal(raw, op); err != nil {
+				return nil, fmt.Errorf("failed to parse %s %s in %s: %w", strings.ToUpper(method), route, path, err)
+			}
+			op.Parameters = append(append([]*OpenAPIParameter{}, shared...), op.Parameters...)
+			op.resolveAdditionalProperties()
+			if spec.Paths[route] == nil {
+				spec.Paths[route] = map[string]*OpenAPIOperation{}
+			}
+			spec.Paths[route][method] = op
+		}
+	}
+
+	for _, schema := range spec.Schemas() {
+		schema.resolveAdditionalProperties()
+	}
+	return spec, nil
+}
+
+// stringKeys turns the map[interface{}]interface{} YAML produces for keys like 200 into JSON-compatible maps
+func stringKeys(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			v[key] = stringKeys(item)
+		}
+		return v
+	case map[any]any:
+		converted := make(map[string]any, len(v))
+		for key, item := range v {
+			converted[fmt.Sprint(key)] = stringKeys(item)
+		}
+		return converted
+	case []any:
+		for i, item := range v {
+			v[i] = stringKeys(item)
+		}
+		return v
+	}
+	return value
+}
+
+// resolveAdditionalProperties decodes additionalProperties, which is either a boolean or a schema
+func (s *OpenAPISchema) resolveAdditionalProperties() {
+	if s == nil {
+		return
+	}
+	if len(s.RawAdditionalProperties) > 0 && s.AdditionalProperties == nil {
+		raw := strings.TrimSpace(string(s.RawAdditionalProperties))
+		switch raw {
+		case "false":
+		case "true":
+			s.AdditionalProperties = &OpenAPISchema{}
+		default:
+			additional := &OpenAPISchema{}
+			if json.Unmarshal(s.RawAdditionalProperties, additional) == nil {
+				s.AdditionalProperties = additional
+			}
+		}
+	}
+
+	s.Items.resolveAdditionalProperties()
+	s.AdditionalProperties.resolveAdditionalProperties()
+	for _, property := range s.Properties {
+		property.resolveAdditionalProperties()
+	}
+	for _, list := range [][]*OpenAPISchema{s.AllOf, s.OneOf, s.AnyOf} {
+		for _, item := range list {
+			item.resolveAdditionalProperties()
+		}
+	}
+}
+
+// resolveAdditionalProperties decodes additionalProperties in an operation's inline schemas
+func (op *OpenAPIOperation) resolveAdditionalProperties() {
+	for _, param := range op.Parameters {
+		param.Schema.resolveAdditionalProperties()
+	}
+	if op.RequestBody != nil {
+		for _, media := range op.RequestBody.Content {
+			if media != nil {
+				media.Schema.resolveAdditionalProperties()
+			}
+		}
+	}
+	for _, response := range op.Responses {
+		if response == nil {
+			continue
+		}
+		response.Schema.resolveAdditionalProperties()
+		for _, media := range response.Content {
+			if media != nil {
+				media.Schema.resolveAdditionalProperties()
+			}
+		}
+	}
+}
+
+// Schemas returns the named schemas: definitions in Swagger 2.0, components.schemas in OpenAPI 3
+func (s *OpenAPISpec) Schemas() map[string]*OpenAPISchema {
+	if s.Definitions != nil {
+		return s.Definitions
+	}
+	if s.Components.Schemas == nil {
+		s.Components.Schemas = map[string]*OpenAPISchema{}
+	}
+	return s.Components.Schemas
+}
+
+// Routes returns the spec's paths in sorted order
+func (s *OpenAPISpec) Routes() []string {
+	routes := make([]string, 0, len(s.Paths))
+	for route := range s.Paths {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	return routes
+}
+
+// Resolve follows a schema's $ref to the named schema it points to
+func (s *OpenAPISpec) Resolve(schema *OpenAPISchema) *OpenAPISchema {
+	for i := 0; schema != nil && schema.Ref != "" && i < 10; i++ {
+		schema = s.Schemas()[OpenAPIRefName(schema.Ref)]
+	}
+	return schema
+}
+
+// OpenAPIRefName returns the schema name a $ref points to, e.g. models.Product for #/definitions/models.Product
+func OpenAPIRefName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// BodySchema returns the JSON request body of an operation, and whether it is required
+func (op *OpenAPIOperation) BodySchema() (*OpenAPISchema, bool) {
+	for _, param := range op.Parameters {
+		if param.In == "body" {
+			return param.Schema, param.Required
+		}
+	}
+	if op.RequestBody != nil {
+		if schema := jsonContentSchema(op.RequestBody.Content); schema != nil {
+			return schema, op.RequestBody.Required
+		}
+	}
+	return nil, false
+}
+
+// ResponseSchema returns the schema of the first 2xx response, or nil when it has no body
+func (op *OpenAPIOperation) ResponseSchema() *OpenAPISchema {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		response := op.Responses[code]
+		if response == nil {
+			continue
+		}
+		if response.Schema != nil {
+			return response.Schema
+		}
+		if schema := jsonContentSchema(response.Content); schema != nil {
+			return schema
+		}
+	}
+	return nil
+}
+
+// ParameterSchema returns a non-body parameter's type as a schema for either spec version
+func (p *OpenAPIParameter) ParameterSchema() *OpenAPISchema {
+	if p.Schema != nil {
+		return p.Schema
+	}
+	return &OpenAPISchema{Type: p.Type, Format: p.Format, Items: p.Items, Enum: p.Enum}
+}
+
+// jsonContentSchema picks the JSON schema out of an OpenAPI 3 content map
+func jsonContentSchema(content map[string]*OpenAPIMediaType) *OpenAPISchema {
+	for mediaType, media := range content {
+		if media != nil && strings.Contains(mediaType, "json") {
+			return media.Schema
+		}
+	}
+	for _, media := range content {
+		if media != nil {
+			return media.Schema
+		}
+	}
+	return nil
+}
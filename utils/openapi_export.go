@@ -0,0 +1,597 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OpenAPIDocument is an OpenAPI 3 document as bui openapi writes it, with the top-level keys in
+// their usual order
+type OpenAPIDocument struct {
+	OpenAPI      string         `json:"openapi" yaml:"openapi"`
+	Info         any            `json:"info,omitempty" yaml:"info,omitempty"`
+	Servers      []any          `json:"servers,omitempty" yaml:"servers,omitempty"`
+	Tags         any            `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Security     any            `json:"security,omitempty" yaml:"security,omitempty"`
+	Paths        map[string]any `json:"paths" yaml:"paths"`
+	Components   map[string]any `json:"components,omitempty" yaml:"components,omitempty"`
+	ExternalDocs any            `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
+}
+
+// swaggerSchemaKeys are the keys of a Swagger 2.0 non-body parameter or header that move into its
+// schema in OpenAPI 3
+var swaggerSchemaKeys = []string{
+	"type", "format", "items", "enum", "default", "minimum", "maximum", "exclusiveMinimum",
+	"exclusiveMaximum", "minLength", "maxLength", "pattern", "minItems", "maxItems", "uniqueItems",
+	"multipleOf",
+}
+
+// ConvertToOpenAPI3 reads a Swagger 2.0 or OpenAPI 3 JSON document, converting Swagger 2.0 to
+// OpenAPI 3.0. Schema names lose the Go package prefix swag gives them (models.Product becomes
+// Product) unless that makes two names clash, and empty info fields are dropped.
+func ConvertToOpenAPI3(content []byte) (*OpenAPIDocument, error) {
+	var source map[string]any
+	if err := json.Unmarshal(content, &source); err != nil {
+		return nil, err
+	}
+
+	var doc *OpenAPIDocument
+	switch {
+	case source["openapi"] != nil:
+		doc = &OpenAPIDocument{
+			OpenAPI:      fmt.Sprint(source["openapi"]),
+			Info:         source["info"],
+			Servers:      anyList(source["servers"]),
+			Tags:         source["tags"],
+			Security:     source["security"],
+			Paths:        anyMap(source["paths"]),
+			Components:   anyMap(source["components"]),
+			ExternalDocs: source["externalDocs"],
+		}
+	case source["swagger"] != nil:
+		doc = convertSwagger2(source)
+	default:
+		return nil, fmt.Errorf("not a Swagger or OpenAPI document")
+	}
+
+	doc.Info = cleanOpenAPIInfo(doc.Info)
+	doc.shortenSchemaNames()
+	if len(doc.Components) == 0 {
+		doc.Components = nil
+	}
+	return doc, nil
+}
+
+// Encode returns the document as indented JSON, or as YAML when asYAML is set
+func (d *OpenAPIDocument) Encode(asYAML bool) ([]byte, error) {
+	if asYAML {
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(d); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), encoder.Close()
+	}
+
+	// Descriptions often hold <, > and &, which are easier to read unescaped
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(d); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SchemaCount returns the number of named schemas in the document
+func (d *OpenAPIDocument) SchemaCount() int {
+	return len(anyMap(d.Components["schemas"]))
+}
+
+// convertSwagger2 converts a Swagger 2.0 document to OpenAPI 3.0
+func convertSwagger2(source map[string]any) *OpenAPIDocument {
+	doc := &OpenAPIDocument{
+		OpenAPI:      "3.0.3",
+		Info:         source["info"],
+		Servers:      swaggerServers(source),
+		Tags:         source["tags"],
+		Security:     source["security"],
+		Paths:        map[string]any{},
+		Components:   map[string]any{},
+		ExternalDocs: source["externalDocs"],
+	}
+	consumes := stringList(source["consumes"], "application/json")
+	produces := stringList(source["produces"], "application/json")
+
+	if definitions := anyMap(source["definitions"]); len(definitions) > 0 {
+		schemas := make(map[string]any, len(definitions))
+		for name, schema := range definitions {
+			schemas[name] = convertSwaggerSchema(schema)
+		}
+		doc.Components["schemas"] = schemas
+	}
+	if parameters := anyMap(source["parameters"]); len(parameters) > 0 {
+		converted, bodies := map[string]any{}, map[string]any{}
+		for name, parameter := range parameters {
+			param := anyMap(parameter)
+			if param["in"] == "body" {
+				bodies[name] = swaggerRequestBody(param, consumes)
+			} else {
+				converted[name] = convertSwaggerParameter(param)
+			}
+		}
+		if len(converted) > 0 {
+			doc.Components["parameters"] = converted
+		}
+		if len(bodies) > 0 {
+			doc.Components["requestBodies"] = bodies
+		}
+	}
+	if responses := anyMap(source["responses"]); len(responses) > 0 {
+		converted := make(map[string]any, len(responses))
+		for name, response := range responses {
+			converted[name] = convertSwaggerResponse(anyMap(response), produces)
+		}
+		doc.Components["responses"] = converted
+	}
+	if definitions := anyMap(source["securityDefinitions"]); len(definitions) > 0 {
+		schemes := make(map[string]any, len(definitions))
+		for name, definition := range definitions {
+			schemes[name] = convertSwaggerSecurityScheme(anyMap(definition))
+		}
+		doc.Components["securitySchemes"] = schemes
+	}
+
+	for route, value := range anyMap(source["paths"]) {
+		item := map[string]any{}
+		for key, entry := range anyMap(value) {
+			switch key {
+			case "get", "put", "post", "delete", "options", "head", "patch":
+				item[key] = convertSwaggerOperation(anyMap(entry), consumes, produces)
+			case "parameters":
+				var parameters []any
+				for _, parameter := range anyList(entry) {
+					param := anyMap(parameter)
+					if param["in"] != "body" && param["in"] != "formData" {
+						parameters = append(parameters, convertSwaggerParameter(param))
+					}
+				}
+				if len(parameters) > 0 {
+					item[key] = parameters
+				}
+			default:
+				item[key] = entry
+			}
+		}
+		doc.Paths[route] = item
+	}
+
+	rewriteOpenAPIRefs(doc.Paths, swaggerRef)
+	rewriteOpenAPIRefs(doc.Components, swaggerRef)
+	return doc
+}
+
+// swaggerRef points a Swagger 2.0 $ref at where the converted document keeps the target
+func swaggerRef(ref string) string {
+	for from, to := range map[string]string{
+		"#/definitions/": "#/components/schemas/",
+		"#/parameters/":  "#/components/parameters/",
+		"#/responses/":   "#/components/responses/",
+	} {
+		if strings.HasPrefix(ref, from) {
+			return to + strings.TrimPrefix(ref, from)
+		}
+	}
+	return ref
+}
+
+// swaggerServers builds the servers list from host, basePath and schemes. Without a host the
+// base path is used on its own, so the API is taken to be served from the document's origin.
+func swaggerServers(source map[string]any) []any {
+	host, _ := source["host"].(string)
+	basePath, _ := source["basePath"].(string)
+	if host == "" {
+		if basePath == "" || basePath == "/" {
+			return nil
+		}
+		return []any{map[string]any{"url": basePath}}
+	}
+
+	defaultScheme := "https"
+	if strings.HasPrefix(host, "localhost") || strings.HasPrefix(host, "127.0.0.1") {
+		defaultScheme = "http"
+	}
+	var servers []any
+	for _, scheme := range stringList(source["schemes"], defaultScheme) {
+		servers = append(servers, map[string]any{"url": scheme + "://" + host + strings.TrimSuffix(basePath, "/")})
+	}
+	return servers
+}
+
+// convertSwaggerOperation moves body and formData parameters into a request body and response
+// schemas into content, using the operation's consumes and produces
+func convertSwaggerOperation(op map[string]any, consumes, produces []string) map[string]any {
+	consumes = stringList(op["consumes"], consumes...)
+	produces = stringList(op["produces"], produces...)
+
+	result := map[string]any{}
+	for key, value := range op {
+		switch key {
+		case "consumes", "produces", "schemes", "parameters", "responses":
+		default:
+			result[key] = value
+		}
+	}
+
+	var parameters []any
+	form := map[string]any{"type": "object", "properties": map[string]any{}}
+	var formRequired []any
+	multipart := false
+	for _, parameter := range anyList(op["parameters"]) {
+		param := anyMap(parameter)
+		switch param["in"] {
+		case "body":
+			result["requestBody"] = swaggerRequestBody(param, consumes)
+		case "formData":
+			schema := swaggerParameterSchema(param)
+			if description, ok := param["description"]; ok {
+				schema["description"] = description
+			}
+			if schema["format"] == "binary" {
+				multipart = true
+			}
+			form["properties"].(map[string]any)[fmt.Sprint(param["name"])] = schema
+			if param["required"] == true {
+				formRequired = append(formRequired, param["name"])
+			}
+		default:
+			parameters = append(parameters, convertSwaggerParameter(param))
+		}
+	}
+	if len(parameters) > 0 {
+		result["parameters"] = parameters
+	}
+	if properties := form["properties"].(map[string]any); len(properties) > 0 {
+		if len(formRequired) > 0 {
+			form["required"] = formRequired
+		}
+		mediaType := "application/x-www-form-urlencoded"
+		for _, consumed := range consumes {
+			if consumed == "multipart/form-data" {
+				multipart = true
+			}
+		}
+		if multipart {
+			mediaType = "multipart/form-data"
+		}
+		result["requestBody"] = map[string]any{"content": map[string]any{mediaType: map[string]any{"schema": form}}}
+	}
+
+	responses := map[string]any{}
+	for code, response := range anyMap(op["responses"]) {
+		responses[code] = convertSwaggerResponse(anyMap(response), produces)
+	}
+	result["responses"] = responses
+	return result
+}
+
+// swaggerRequestBody turns a body parameter into a request body with its schema under each
+// content type the operation consumes
+func swaggerRequestBody(param map[string]any, consumes []string) map[string]any {
+	if ref, ok := param["$ref"]; ok {
+		return map[string]any{"$ref": ref}
+	}
+	content := map[string]any{}
+	for _, mediaType := range consumes {
+		if strings.HasPrefix(mediaType, "multipart/") || mediaType == "application/x-www-form-urlencoded" {
+			continue
+		}
+		content[mediaType] = map[string]any{"schema": convertSwaggerSchema(param["schema"])}
+	}
+	if len(content) == 0 {
+		content["application/json"] = map[string]any{"schema": convertSwaggerSchema(param["schema"])}
+	}
+
+	body := map[string]any{"content": content}
+	if description, ok := param["description"]; ok && description != "" {
+		body["description"] = description
+	}
+	if param["required"] == true {
+		body["required"] = true
+	}
+	return body
+}
+
+// convertSwaggerParameter moves the type of a path, query or header parameter into its schema and
+// collectionFormat into style and explode
+func convertSwaggerParameter(param map[string]any) map[string]any {
+	if ref, ok := param["$ref"]; ok {
+		return map[string]any{"$ref": ref}
+	}
+	result := map[string]any{"schema": swaggerParameterSchema(param)}
+	for key, value := range param {
+		switch key {
+		case "name", "in", "description", "required", "allowEmptyValue", "example":
+			result[key] = value
+		default:
+			if strings.HasPrefix(key, "x-") {
+				result[key] = value
+			}
+		}
+	}
+
+	switch param["collectionFormat"] {
+	case "csv":
+		if param["in"] == "query" {
+			result["style"], result["explode"] = "form", false
+		}
+	case "multi":
+		result["style"], result["explode"] = "form", true
+	case "ssv":
+		result["style"] = "spaceDelimited"
+	case "pipes":
+		result["style"] = "pipeDelimited"
+	}
+	return result
+}
+
+// swaggerParameterSchema collects the schema keys of a non-body parameter or header; a file
+// becomes a binary string
+func swaggerParameterSchema(param map[string]any) map[string]any {
+	schema := map[string]any{}
+	for _, key := range swaggerSchemaKeys {
+		if value, ok := param[key]; ok {
+			schema[key] = value
+		}
+	}
+	if items, ok := schema["items"]; ok {
+		schema["items"] = convertSwaggerSchema(items)
+	}
+	if schema["type"] == "file" {
+		schema["type"], schema["format"] = "string", "binary"
+	}
+	return schema
+}
+
+// convertSwaggerResponse moves a response's schema and examples under each content type the
+// operation produces and the types of its headers into schemas
+func convertSwaggerResponse(response map[string]any, produces []string) map[string]any {
+	if ref, ok := response["$ref"]; ok {
+		return map[string]any{"$ref": ref}
+	}
+	result := map[string]any{"description": ""}
+	for key, value := range response {
+		switch key {
+		case "schema", "examples", "headers":
+		default:
+			result[key] = value
+		}
+	}
+
+	if schema, ok := response["schema"]; ok {
+		examples := anyMap(response["examples"])
+		content := map[string]any{}
+		for _, mediaType := range produces {
+			media := map[string]any{"schema": convertSwaggerSchema(schema)}
+			if example, ok := examples[mediaType]; ok {
+				media["example"] = example
+			}
+			content[mediaType] = media
+		}
+		result["content"] = content
+	}
+	if headers := anyMap(response["headers"]); len(headers) > 0 {
+		converted := make(map[string]any, len(headers))
+		for name, value := range headers {
+			header := anyMap(value)
+			convertedHeader := map[string]any{"schema": swaggerParameterSchema(header)}
+			if description, ok := header["description"]; ok {
+				convertedHeader["description"] = description
+			}
+			converted[name] = convertedHeader
+		}
+		result["headers"] = converted
+	}
+	return result
+}
+
+// convertSwaggerSchema rewrites the Swagger 2.0 parts of a schema and everything nested in it:
+// x-nullable becomes nullable, file a binary string and a discriminator name an object
+func convertSwaggerSchema(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			v[key] = convertSwaggerSchema(item)
+		}
+		if nullable, ok := v["x-nullable"].(bool); ok {
+			delete(v, "x-nullable")
+			if nullable {
+				v["nullable"] = true
+			}
+		}
+		if v["type"] == "file" {
+			v["type"], v["format"] = "string", "binary"
+		}
+		if discriminator, ok := v["discriminator"].(string); ok {
+			v["discriminator"] = map[string]any{"propertyName": discriminator}
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = convertSwaggerSchema(item)
+		}
+		return v
+	}
+	return value
+}
+
+// convertSwaggerSecurityScheme converts a security definition; basic becomes http basic and the
+// oauth2 flow moves under flows
+func convertSwaggerSecurityScheme(definition map[string]any) map[string]any {
+	switch definition["type"] {
+	case "basic":
+		scheme := map[string]any{"type": "http", "scheme": "basic"}
+		if description, ok := definition["description"]; ok {
+			scheme["description"] = description
+		}
+		return scheme
+	case "oauth2":
+		flow := map[string]any{"scopes": map[string]any{}}
+		if scopes, ok := definition["scopes"]; ok {
+			flow["scopes"] = scopes
+		}
+		for _, key := range []string{"authorizationUrl", "tokenUrl"} {
+			if url, ok := definition[key]; ok {
+				flow[key] = url
+			}
+		}
+		flowName := map[string]string{
+			"implicit":    "implicit",
+			"password":    "password",
+			"application": "clientCredentials",
+			"accessCode":  "authorizationCode",
+		}[fmt.Sprint(definition["flow"])]
+		scheme := map[string]any{"type": "oauth2", "flows": map[string]any{flowName: flow}}
+		if description, ok := definition["description"]; ok {
+			scheme["description"] = description
+		}
+		return scheme
+	}
+	return definition
+}
+
+// shortenSchemaNames drops the Go package prefix swag puts on schema names, keeping the full name
+// where the short one would clash
+func (d *OpenAPIDocument) shortenSchemaNames() {
+	schemas := anyMap(d.Components["schemas"])
+	if len(schemas) == 0 {
+		return
+	}
+
+	counts := map[string]int{}
+	for name := range schemas {
+		counts[shortSchemaName(name)]++
+	}
+	renamed := map[string]string{}
+	for name := range schemas {
+		short := shortSchemaName(name)
+		if short == name || counts[short] > 1 {
+			continue
+		}
+		if _, taken := schemas[short]; taken {
+			continue
+		}
+		renamed[name] = short
+	}
+	if len(renamed) == 0 {
+		return
+	}
+
+	for name, short := range renamed {
+		schemas[short] = schemas[name]
+		delete(schemas, name)
+	}
+	rename := func(ref string) string {
+		if name, ok := strings.CutPrefix(ref, "#/components/schemas/"); ok {
+			if short, ok := renamed[name]; ok {
+				return "#/components/schemas/" + short
+			}
+		}
+		return ref
+	}
+	rewriteOpenAPIRefs(d.Paths, rename)
+	rewriteOpenAPIRefs(d.Components, rename)
+}
+
+// shortSchemaName returns a schema name without its leading package, e.g. Product for
+// models.Product
+func shortSchemaName(name string) string {
+	prefix, rest, found := strings.Cut(name, ".")
+	if !found || rest == "" || prefix == "" || strings.ToLower(prefix) != prefix || strings.ContainsAny(prefix, "-_") {
+		return name
+	}
+	return rest
+}
+
+// rewriteOpenAPIRefs replaces every $ref in value with what rename returns for it
+func rewriteOpenAPIRefs(value any, rename func(string) string) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if ref, ok := item.(string); ok && key == "$ref" {
+				v[key] = rename(ref)
+				continue
+			}
+			rewriteOpenAPIRefs(item, rename)
+		}
+	case []any:
+		for _, item := range v {
+			rewriteOpenAPIRefs(item, rename)
+		}
+	}
+}
+
+// cleanOpenAPIInfo drops the empty strings and objects swag leaves in info and fills in the
+// title and version OpenAPI requires
+func cleanOpenAPIInfo(value any) any {
+	info := anyMap(value)
+	if info == nil {
+		info = map[string]any{}
+	}
+	for key, item := range info {
+		switch item := item.(type) {
+		case string:
+			if item == "" {
+				delete(info, key)
+			}
+		case map[string]any:
+			for field, fieldValue := range item {
+				if fieldValue == "" {
+					delete(item, field)
+				}
+			}
+			if len(item) == 0 {
+				delete(info, key)
+			}
+		}
+	}
+	if info["title"] == nil {
+		info["title"] = "API"
+	}
+	if info["version"] == nil {
+		info["version"] = "1.0"
+	}
+	return info
+}
+
+// anyMap returns value as a JSON object, or nil when it isn't one
+func anyMap(value any) map[string]any {
+	m, _ := value.(map[string]any)
+	return m
+}
+
+// anyList returns value as a JSON array, or nil when it isn't one
+func anyList(value any) []any {
+	list, _ := value.([]any)
+	return list
+}
+
+// stringList returns a JSON array of strings, or fallback when value is missing or empty
+func stringList(value any, fallback ...string) []string {
+	var list []string
+	for _, item := range anyList(value) {
+		if s, ok := item.(string); ok {
+			list = append(list, s)
+		}
+	}
+	if len(list) == 0 {
+		return fallback
+	}
+	return list
+}
@@ -0,0 +1,488 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// OpenAPIImport is the result of mapping an OpenAPI spec onto bui modules
+type OpenAPIImport struct {
+	Schema      Schema   // One model per resource, in the form bui g --from takes
+	Routes      []string // CRUD routes the generated controllers provide, e.g. GET /products/{id}
+	Unsupported []string // Constructs that were skipped or approximated, with where they appear
+}
+
+// openAPIVersionSegment matches version prefixes such as v1 that aren't resources
+var openAPIVersionSegment = regexp.MustCompile(`^v[0-9]+$`)
+
+// openAPIEnumValue matches enum values that make usable Go constant names
+var openAPIEnumValue = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9 _-]*$`)
+
+// openAPIGeneratedColumns are added by the generator, so the spec's copies are skipped
+var openAPIGeneratedColumns = map[string]bool{"id": true, "created_at": true, "updated_at": true, "deleted_at": true}
+
+// openAPIResource collects the paths of one top-level collection such as /products
+type openAPIResource struct {
+	segment    string // products
+	prefix     string // Leading segments that aren't resources, e.g. /api/v1
+	model      string // Product
+	schemaName string
+	createBody *OpenAPISchema
+	operations map[string]bool // Route keys such as "GET /products/{id}"
+}
+
+// ImportOpenAPI maps an OpenAPI spec onto bui modules. Every top-level collection path (/products,
+// /products/{id}) becomes a module whose fields come from the schema its routes return; anything
+// the generator can't express is listed in Unsupported instead of being dropped silently.
+func ImportOpenAPI(spec *OpenAPISpec) *OpenAPIImport {
+	result := &OpenAPIImport{}
+
+	// Collections are known up front so /categories/{id}/products can be told apart from an action
+	collections := map[string]bool{}
+	for _, route := range spec.Routes() {
+		segments, start := openAPIRouteSegments(route)
+		if start < len(segments) {
+			collections[segments[start]] = true
+		}
+	}
+
+	resources := map[string]*openAPIResource{}
+	var order []string
+	for _, route := range spec.Routes() {
+		segments, start := openAPIRouteSegments(route)
+		if start >= len(segments) || strings.HasPrefix(segments[start], "{") {
+			for _, method := range OpenAPIMethods {
+				if spec.Paths[route][method] != nil {
+					result.report("%s %s: no collection segment to map to a module", strings.ToUpper(method), route)
+				}
+			}
+			continue
+		}
+
+		segment := segments[start]
+		resource := resources[segment]
+		if resource == nil {
+			resource = &openAPIResource{
+				segment:    segment,
+				prefix:     "/" + strings.Join(segments[:start], "/"),
+				model:      ToPascalCase(PluralizeClient.Singular(segment)),
+				operations: map[string]bool{},
+			}
+			resources[segment] = resource
+			order = append(order, segment)
+		}
+
+		rest := segments[start+1:]
+		for _, method := range OpenAPIMethods {
+			op := spec.Paths[route][method]
+			if op == nil {
+				continue
+			}
+			key := strings.ToUpper(method) + " " + route
+			crud := (len(rest) == 0 && (method == "get" || method == "post")) ||
+				(len(rest) == 1 && strings.HasPrefix(rest[0], "{") && (method == "get" || method == "put" || method == "delete"))
+			switch {
+			case crud:
+				resource.operations[key] = true
+				resource.useSchema(spec, method, len(rest) == 0, op)
+			case len(rest) == 1 && strings.HasPrefix(rest[0], "{") && method == "patch":
+				result.report("%s: partial updates are generated as PUT %s", key, route)
+				resource.useSchema(spec, method, false, op)
+			case len(rest) > 1 && strings.HasPrefix(rest[0], "{") && collections[rest[1]]:
+				result.report("%s: nested route; generate the child module with --nested or add it by hand", key)
+			default:
+				result.report("%s: custom action, add it to the %s controller by hand", key, segment)
+			}
+		}
+	}
+
+	models := map[string]string{} // Schema name to model name, for relations
+	for _, segment := range order {
+		if resource := resources[segment]; resource.schemaName != "" {
+			models[resource.schemaName] = resource.model
+		}
+	}
+
+	for _, segment := range order {
+		resource := resources[segment]
+		if len(resource.operations) == 0 {
+			continue
+		}
+		if resource.schemaName == "" {
+			result.report("/%s: no request or response schema to take fields from; module skipped", segment)
+			continue
+		}
+
+		if resource.prefix != "/" && resource.prefix != "/api" {
+			result.report("%s/%s: the %s prefix is dropped; generated routes are served under /api", resource.prefix, segment, resource.prefix)
+		}
+		if route := NewNamingConvention(resource.model).PluralKebab; route != segment {
+			result.report("/%s: generated routes are served at /%s", segment, route)
+		}
+
+		model := SchemaModel{Name: resource.model}
+		model.Fields = result.importFields(spec, resource, models)
+		result.Schema.Models = append(result.Schema.Models, model)
+
+		routes := make([]string, 0, len(resource.operations))
+		for route := range resource.operations {
+			routes = append(routes, route)
+		}
+		sort.Strings(routes)
+		result.Routes = append(result.Routes, routes...)
+	}
+
+	// Schemas no resource uses aren't generated
+	referenced := referencedSchemas(spec)
+	for _, name := range sortedSchemaNames(spec) {
+		if _, used := models[name]; used {
+			continue
+		}
+		if schema := spec.Schemas()[name]; schema != nil && len(schema.Properties) > 0 && !openAPIWrapperSchema(name) {
+			if !referenced[name] {
+				result.report("components.schemas.%s: not used by any collection path; no module generated", name)
+			}
+		}
+	}
+
+	return result
+}
+
+// openAPIRouteSegments splits a route and returns the index of its first resource segment,
+// skipping prefixes such as /api/v1
+func openAPIRouteSegments(route string) ([]string, int) {
+	segments := strings.Split(strings.Trim(route, "/"), "/")
+	start := 0
+	for start < len(segments) && (segments[start] == "api" || openAPIVersionSegment.MatchString(segments[start])) {
+		start++
+	}
+	return segments, start
+}
+
+// useSchema picks the schema a resource's fields come from: the single item response first, then
+// the list item, then the create body. The create body is kept for its required fields.
+func (r *openAPIResource) useSchema(spec *OpenAPISpec, method string, collection bool, op *OpenAPIOperation) {
+	if body, _ := op.BodySchema(); body != nil && method == "post" {
+		r.createBody = body
+		if r.schemaName == "" {
+			r.schemaName = namedSchema(spec, body, false)
+		}
+	}
+	if method != "get" {
+		return
+	}
+	if name := namedSchema(spec, op.ResponseSchema(), collection); name != "" {
+		if !collection || r.schemaName == "" {
+			r.schemaName = name
+		}
+	}
+}
+
+// namedSchema returns the named schema a body refers to, looking through {data: ...} envelopes
+// and, for lists, through arrays
+func namedSchema(spec *OpenAPISpec, schema *OpenAPISchema, list bool) string {
+	for depth := 0; schema != nil && depth < 4; depth++ {
+		if len(schema.AllOf) == 1 {
+			schema = schema.AllOf[0]
+		}
+		if list && schema.Type.Name == "array" {
+			schema = schema.Items
+			list = false
+			continue
+		}
+		resolved := spec.Resolve(schema)
+		if resolved == nil {
+			return ""
+		}
+		if data, ok := resolved.Properties["data"]; ok {
+			schema = data
+			continue
+		}
+		if list {
+			if items, ok := resolved.Properties["items"]; ok {
+				schema = items
+				continue
+			}
+			return ""
+		}
+		if schema.Ref != "" && len(resolved.Properties) > 0 {
+			return OpenAPIRefName(schema.Ref)
+		}
+		return ""
+	}
+	return ""
+}
+
+// importFields converts a resource schema's properties into field definitions
+func (result *OpenAPIImport) importFields(spec *OpenAPISpec, resource *openAPIResource, models map[string]string) []SchemaField {
+	schema := spec.Schemas()[resource.schemaName]
+	properties, required := flattenProperties(spec, schema)
+
+	// Fields only the create body declares, and its required list, are merged in
+	if body := spec.Resolve(resource.createBody); body != nil && body != schema {
+		bodyProperties, bodyRequired := flattenProperties(spec, body)
+		for name, property := range bodyProperties {
+			if _, ok := properties[name]; !ok {
+				properties[name] = property
+			}
+		}
+		for name := range bodyRequired {
+			required[name] = true
+		}
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// A reference and its foreign key (category and category_id) become one belongsTo field
+	belongsTo := map[string]bool{}
+	for _, name := range names {
+		if target := models[refTarget(properties[name])]; target != "" {
+			belongsTo[ToSnakeCase(name)+"_id"] = true
+		}
+	}
+
+	var fields []SchemaField
+	for _, name := range names {
+		property := properties[name]
+		column := ToSnakeCase(name)
+		where := fmt.Sprintf("components.schemas.%s.properties.%s", resource.schemaName, name)
+		if openAPIGeneratedColumns[column] || (belongsTo[column] && !isOpenAPIRef(property)) {
+			continue
+		}
+		if column != name {
+			// camelCase names are stored in snake_case, as the generated JSON tags are
+			result.report("%s: stored as %s", where, column)
+		}
+
+		field := result.importField(spec, where, column, property, models, resource.model)
+		if field == nil {
+			continue
+		}
+		field.Required = required[name] && field.Type != "hasMany" && field.Type != "manyToMany"
+		if property.Default != nil && field.Model == "" {
+			field.Default = fmt.Sprint(property.Default)
+		}
+		fields = append(fields, *field)
+	}
+	return fields
+}
+
+// importField maps one property onto a bui field type
+func (result *OpenAPIImport) importField(spec *OpenAPISpec, where, column string, property *OpenAPISchema, models map[string]string, model string) *SchemaField {
+	field := &SchemaField{Name: column}
+
+	if len(property.AllOf) == 1 && len(property.Properties) == 0 {
+		property = property.AllOf[0]
+	}
+	if target := models[refTarget(property)]; target != "" {
+		field.Name = strings.TrimSuffix(column, "_id") + "_id"
+		field.Type = "belongsTo"
+		field.Model = target
+		return field
+	}
+	if strings.HasSuffix(column, "_id") && (property.Type.Name == "integer" || property.Type.Name == "string") {
+		if target := modelNamed(models, strings.TrimSuffix(column, "_id")); target != "" {
+			field.Type = "belongsTo"
+			field.Model = target
+			return field
+		}
+	}
+
+	switch {
+	case len(property.OneOf) > 0 || len(property.AnyOf) > 0 || len(property.Discriminator) > 0 || len(property.AllOf) > 1:
+		result.report("%s: oneOf/anyOf/allOf compositions are stored as json", where)
+		field.Type = "json"
+	case property.Ref != "":
+		result.report("%s: embedded %s object is stored as json", where, OpenAPIRefName(property.Ref))
+		field.Type = "json"
+	case property.Type.Name == "array":
+		if target := models[refTarget(property.Items)]; target != "" {
+			field.Type = "manyToMany"
+			if targetHasForeignKey(spec, property.Items, model) {
+				field.Type = "hasMany"
+			}
+			field.Model = target
+			return field
+		}
+		result.report("%s: arrays of non-module values are stored as json", where)
+		field.Type = "json"
+	case len(property.Enum) > 0:
+		options := make([]string, 0, len(property.Enum))
+		for _, value := range property.Enum {
+			options = append(options, fmt.Sprint(value))
+		}
+		field.Type = "enum"
+		for _, option := range options {
+			if !openAPIEnumValue.MatchString(option) {
+				result.report("%s: enum values that aren't identifiers are generated as a plain select", where)
+				field.Type = "select"
+				break
+			}
+		}
+		if property.Type.Name == "integer" || property.Type.Name == "number" {
+			result.report("%s: numeric enums are generated as string choices", where)
+		}
+		field.Options = options
+	case property.Type.Name == "string":
+		field.Type = map[string]string{
+			"date-time": "datetime", "date": "date", "email": "email", "uri": "url", "url": "url",
+			"password": "password", "binary": "file", "byte": "file",
+		}[property.Format]
+		if field.Type == "" {
+			field.Type = "string"
+			if property.MaxLength != nil && *property.MaxLength > 255 {
+				field.Type = "text"
+			}
+		}
+	case property.Type.Name == "integer":
+		field.Type = "int"
+	case property.Type.Name == "number":
+		field.Type = "float"
+	case property.Type.Name == "boolean":
+		field.Type = "bool"
+	case property.Type.Name == "object" || len(property.Properties) > 0 || property.AdditionalProperties != nil:
+		result.report("%s: inline objects are stored as json", where)
+		field.Type = "json"
+	default:
+		result.report("%s: no type given; stored as json", where)
+		field.Type = "json"
+	}
+	return field
+}
+
+// flattenProperties merges a schema's properties with those of its allOf parts
+func flattenProperties(spec *OpenAPISpec, schema *OpenAPISchema) (map[string]*OpenAPISchema, map[string]bool) {
+	properties := map[string]*OpenAPISchema{}
+	required := map[string]bool{}
+	if schema == nil {
+		return properties, required
+	}
+	for _, part := range append([]*OpenAPISchema{schema}, schema.AllOf...) {
+		part = spec.Resolve(part)
+		if part == nil {
+			continue
+		}
+		if part != schema && len(part.AllOf) > 0 {
+			nested, nestedRequired := flattenProperties(spec, part)
+			for name, property := range nested {
+				properties[name] = property
+			}
+			for name := range nestedRequired {
+				required[name] = true
+			}
+		}
+		for name, property := range part.Properties {
+			properties[name] = property
+		}
+		for _, name := range part.Required {
+			required[name] = true
+		}
+	}
+	return properties, required
+}
+
+// targetHasForeignKey reports whether the related schema points back at the model with a <model>_id property
+func targetHasForeignKey(spec *OpenAPISpec, items *OpenAPISchema, model string) bool {
+	properties, _ := flattenProperties(spec, spec.Resolve(items))
+	for name := range properties {
+		if ToSnakeCase(name) == ToSnakeCase(model)+"_id" {
+			return true
+		}
+	}
+	return false
+}
+
+// modelNamed finds the model a foreign key such as category points at
+func modelNamed(models map[string]string, name string) string {
+	for _, model := range models {
+		if ToSnakeCase(model) == name {
+			return model
+		}
+	}
+	return ""
+}
+
+// refTarget returns the schema name a property refers to, looking through a single allOf
+func refTarget(property *OpenAPISchema) string {
+	if property == nil {
+		return ""
+	}
+	if len(property.AllOf) == 1 {
+		property = property.AllOf[0]
+	}
+	if property.Ref == "" {
+		return ""
+	}
+	return OpenAPIRefName(property.Ref)
+}
+
+// isOpenAPIRef reports whether a property refers to a named schema
+func isOpenAPIRef(property *OpenAPISchema) bool {
+	return refTarget(property) != ""
+}
+
+// referencedSchemas returns the schemas other schemas or operations refer to
+func referencedSchemas(spec *OpenAPISpec) map[string]bool {
+	referenced := map[string]bool{}
+	var walk func(schema *OpenAPISchema, depth int)
+	walk = func(schema *OpenAPISchema, depth int) {
+		if schema == nil || depth > 8 {
+			return
+		}
+		if schema.Ref != "" {
+			referenced[OpenAPIRefName(schema.Ref)] = true
+		}
+		walk(schema.Items, depth+1)
+		walk(schema.AdditionalProperties, depth+1)
+		for _, property := range schema.Properties {
+			walk(property, depth+1)
+		}
+		for _, list := range [][]*OpenAPISchema{schema.AllOf, schema.OneOf, schema.AnyOf} {
+			for _, item := range list {
+				walk(item, depth+1)
+			}
+		}
+	}
+	for _, schema := range spec.Schemas() {
+		walk(schema, 0)
+	}
+	for _, route := range spec.Routes() {
+		for _, op := range spec.Paths[route] {
+			body, _ := op.BodySchema()
+			walk(body, 0)
+			walk(op.ResponseSchema(), 0)
+		}
+	}
+	return referenced
+}
+
+// openAPIWrapperSchema reports whether a schema name looks like a request, response or error envelope
+func openAPIWrapperSchema(name string) bool {
+	for _, suffix := range []string{"Request", "Response", "Input", "Error", "Page", "List"} {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedSchemaNames returns the spec's schema names in sorted order
+func sortedSchemaNames(spec *OpenAPISpec) []string {
+	names := make([]string, 0, len(spec.Schemas()))
+	for name := range spec.Schemas() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// report records an unsupported construct
+func (result *OpenAPIImport) report(format string, args ...any) {
+	result.Unsupported = append(result.Unsupported, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// FieldsRead lists the fields responses hold, e.g. name,price or list=name,price (--fields-read)
+var FieldsRead []string
+
+// FieldsWrite lists the fields requests take, e.g. name,price or update=price (--fields-write)
+var FieldsWrite []string
+
+// payloadScopes are the payloads --fields-read and --fields-write may name before an =
+var payloadScopes = map[string][]string{
+	"--fields-read":  {"list", "detail"},
+	"--fields-write": {"create", "update"},
+}
+
+// parsePayloadFields reads the values of a --fields-read or --fields-write flag into the columns
+// of each payload they restrict. A value without a payload restricts all of them; payloads no
+// value names are left out of the map.
+func parsePayloadFields(flag string, values []string) (map[string][]string, error) {
+	scopes := payloadScopes[flag]
+	columns := map[string][]string{}
+	for _, value := range values {
+		targets := scopes
+		if scope, list, ok := strings.Cut(value, "="); ok {
+			scope = strings.ToLower(strings.TrimSpace(scope))
+			if !slices.Contains(scopes, scope) {
+				return nil, fmt.Errorf("%s takes columns, optionally after %s=: %q", flag, strings.Join(scopes, "= or "), value)
+			}
+			targets, value = []string{scope}, list
+		}
+		names := splitColumns(value)
+		if len(names) == 0 {
+			return nil, fmt.Errorf("%s names no columns: %q", flag, value)
+		}
+		for _, scope := range targets {
+			columns[scope] = append(columns[scope], names...)
+		}
+	}
+	return columns, nil
+}
+
+// CheckPayloadFields reports a --fields-read or --fields-write value that can't be parsed
+func CheckPayloadFields() error {
+	if _, err := parsePayloadFields("--fields-read", FieldsRead); err != nil {
+		return err
+	}
+	_, err := parsePayloadFields("--fields-write", FieldsWrite)
+	return err
+}
+
+// isPayloadField reports whether a --fields-read or --fields-write column names field: by its
+// JSON name, its column, a belongsTo or media field without the _id of its key, or the point
+// a latitude or longitude belongs to
+func isPayloadField(field Field, column string) bool {
+	name := strings.TrimSuffix(field.JSONName, ",omitempty")
+	return column == name || column == field.DBName || column+"_id" == name ||
+		(field.IsMedia && column == field.MediaFKJSONName) ||
+		(field.PointName != "" && column == ToSnakeCase(field.PointName))
+}
+
+// ApplyPayloadFields marks the fields --fields-read and --fields-write leave out of each payload.
+// A field left out of detail responses is left out of lists too, state fields stay in requests for
+// their transitions, and --nested-form fields stay in every payload.
+func ApplyPayloadFields(fields []Field) {
+	read, _ := parsePayloadFields("--fields-read", FieldsRead)
+	write, _ := parsePayloadFields("--fields-write", FieldsWrite)
+	nested := splitColumns(NestedForms)
+
+	leftOut := func(columns map[string][]string, scope string, field Field) bool {
+		names, restricted := columns[scope]
+		return restricted && !slices.ContainsFunc(names, func(column string) bool {
+			return isPayloadField(field, column)
+		})
+	}
+	for i := range fields {
+		field := fields[i]
+		if slices.Contains(nested, strings.TrimSuffix(field.JSONName, ",omitempty")) {
+			continue
+		}
+		fields[i].HideInDetail = leftOut(read, "detail", field)
+		fields[i].HideInList = fields[i].HideInDetail || leftOut(read, "list", field)
+		if field.IsState {
+			continue
+		}
+		fields[i].NoCreate = leftOut(write, "create", field)
+		fields[i].NoUpdate = leftOut(write, "update", field)
+	}
+}
+
+// UnknownPayloadFields returns the --fields-read and --fields-write columns that match no field
+func UnknownPayloadFields(fields []Field) []string {
+	var unknown []string
+	for _, flag := range []string{"--fields-read", "--fields-write"} {
+		values := FieldsRead
+		if flag == "--fields-write" {
+			values = FieldsWrite
+		}
+		columns, _ := parsePayloadFields(flag, values)
+		for _, scope := range payloadScopes[flag] {
+			for _, column := range columns[scope] {
+				known := slices.ContainsFunc(fields, func(field Field) bool { return isPayloadField(field, column) })
+				if !known && !slices.Contains(unknown, column) {
+					unknown = append(unknown, column)
+				}
+			}
+		}
+	}
+	return unknown
+}
+
+// Unwritable reports whether --fields-write leaves the field out of both requests
+func (f Field) Unwritable() bool {
+	return f.NoCreate && f.NoUpdate
+}
+
+// fieldsWithout returns the fields leftOut doesn't report
+func fieldsWithout(fields []Field, leftOut func(Field) bool) []Field {
+	kept := make([]Field, 0, len(fields))
+	for _, field := range fields {
+		if !leftOut(field) {
+			kept = append(kept, field)
+		}
+	}
+	return kept
+}
+
+// payloadFuncs are the template functions filtering fields to those of a payload
+var payloadFuncs = map[string]any{
+	"listFields": func(fields []Field) []Field {
+		return fieldsWithout(fields, func(f Field) bool { return f.HideInList })
+	},
+	"detailFields": func(fields []Field) []Field {
+		return fieldsWithout(fields, func(f Field) bool { return f.HideInDetail })
+	},
+	"createFields": func(fields []Field) []Field {
+		return fieldsWithout(fields, func(f Field) bool { return f.NoCreate })
+	},
+	"updateFields": func(fields []Field) []Field {
+		return fieldsWithout(fields, func(f Field) bool { return f.NoUpdate })
+	},
+}
@@ -0,0 +1,50 @@
+package utils
+
+import "strings"
+
+// PDFRow is a line of the PDF of a --pdf module's record
+type PDFRow struct {
+	Label    string // Printed before the value (e.g., "Category")
+	Key      string // JSON name of the value in the record's response
+	Kind     string // How the value is printed: text, money, time, bool, relation or list
+	Currency string // ISO 4217 currency of a money field
+}
+
+// pdfRowFor returns the PDF line of a field, or nil for fields a page doesn't print: media,
+// attachments, translations, JSON, points and encrypted fields
+func pdfRowFor(field Field) *PDFRow {
+	if field.IsMedia || field.IsMediaFK || field.IsMediaList || field.IsAttachment || field.IsTranslation || field.IsEncrypted || field.IsPoint || field.PointName != "" {
+		return nil
+	}
+
+	key := strings.TrimSuffix(field.JSONName, ",omitempty")
+	row := &PDFRow{Key: key, Kind: "text"}
+	switch {
+	case field.Relationship == "belongs_to":
+		// The response holds the related record under the relation's name
+		row.Key = ToSnakeCase(field.RelatedModel)
+		if strings.HasSuffix(field.Name, "Id") {
+			row.Key = ToSnakeCase(TrimIdSuffix(field.Name))
+		}
+		row.Kind = "relation"
+	case field.Relationship == "has_one":
+		row.Kind = "relation"
+	case field.Relationship == "has_many", field.Relationship == "many_to_many", field.Relationship == "morph_many":
+		row.Kind = "list"
+	case field.IsRelation || field.Relationship != "":
+		return nil
+	case field.IsMoney:
+		row.Kind = "money"
+		row.Currency = field.Currency
+	case field.IsSelect && field.SelectType == "checkbox":
+		row.Kind = "list"
+	case field.Type == "bool":
+		row.Kind = "bool"
+	case field.Type == "time.Time" || field.Type == "types.DateTime":
+		row.Kind = "time"
+	case field.Type == "json.RawMessage" || field.Type == "datatypes.JSON":
+		return nil
+	}
+	row.Label = ToCapitalCase(row.Key)
+	return row
+}
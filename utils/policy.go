@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PolicyActions are the permission actions every generated module seeds, in CRUD order
+var PolicyActions = []string{"list", "read", "create", "update", "delete"}
+
+// defaultRoleActions are the actions a --roles entry without =actions gets, by role name;
+// other names are read-only
+var defaultRoleActions = map[string][]string{
+	"owner":   PolicyActions,
+	"admin":   PolicyActions,
+	"manager": PolicyActions,
+	"editor":  {"list", "read", "create", "update"},
+	"author":  {"list", "read", "create", "update"},
+}
+
+// PolicyRole is a role and the actions it's granted on a module
+type PolicyRole struct {
+	Name    string
+	Actions []string
+}
+
+// Permissions returns the role's actions as the policy.go constants, e.g. PermissionList
+func (r PolicyRole) Permissions() []string {
+	permissions := make([]string, len(r.Actions))
+	for i, action := range r.Actions {
+		permissions[i] = "Permission" + ToPascalCase(action)
+	}
+	return permissions
+}
+
+// ParsePolicyRoles parses --roles admin,editor=list+read+update,viewer
+func ParsePolicyRoles(list string) ([]PolicyRole, error) {
+	var roles []PolicyRole
+	seen := map[string]bool{}
+	for _, entry := range strings.Split(list, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		name, actionList, explicit := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("invalid role %q", entry)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("role %q is listed twice", name)
+		}
+		seen[name] = true
+
+		actions := defaultRoleActions[strings.ToLower(name)]
+		if actions == nil {
+			actions = []string{"list", "read"}
+		}
+		if explicit {
+			var err error
+			if actions, err = parsePolicyActions(actionList); err != nil {
+				return nil, fmt.Errorf("role %s: %w", name, err)
+			}
+		}
+		roles = append(roles, PolicyRole{Name: name, Actions: actions})
+	}
+	if len(roles) == 0 {
+		return nil, fmt.Errorf("no roles given")
+	}
+	return roles, nil
+}
+
+// parsePolicyActions parses list+read+update, keeping PolicyActions order
+func parsePolicyActions(list string) ([]string, error) {
+	requested := map[string]bool{}
+	for _, action := range strings.Split(list, "+") {
+		action = strings.ToLower(strings.TrimSpace(action))
+		known := false
+		for _, a := range PolicyActions {
+			known = known || a == action
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown action %q (use %s)", action, strings.Join(PolicyActions, ", "))
+		}
+		requested[action] = true
+	}
+
+	var actions []string
+	for _, action := range PolicyActions {
+		if requested[action] {
+			actions = append(actions, action)
+		}
+	}
+	return actions, nil
+}
+
+// HasPolicy reports whether bui g policy generated app/<module>/policy.go, so regenerated
+// controllers and modules keep their permission checks
+func HasPolicy(naming *NamingConvention) bool {
+	return hasPolicyIn(".", naming)
+}
+
+// hasPolicyIn is HasPolicy for the backend in dir
+func hasPolicyIn(dir string, naming *NamingConvention) bool {
+	_, err := os.Stat(filepath.Join(dir, "app", naming.DirName, "policy.go"))
+	return err == nil
+}
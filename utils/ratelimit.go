@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rate is a --rate-limit: Requests per Unit, with Per the Go duration of the unit (e.g., time.Minute)
+type Rate struct {
+	Requests int
+	Unit     string
+	Per      string
+}
+
+// rateUnits maps the units a --rate-limit accepts to their names
+var rateUnits = map[string]string{
+	"s": "second", "sec": "second", "second": "second",
+	"m": "minute", "min": "minute", "minute": "minute",
+	"h": "hour", "hour": "hour",
+	"d": "day", "day": "day",
+}
+
+// rateDurations are the Go durations of the rate units
+var rateDurations = map[string]string{
+	"second": "time.Second",
+	"minute": "time.Minute",
+	"hour":   "time.Hour",
+	"day":    "24 * time.Hour",
+}
+
+// ParseRate parses a --rate-limit such as 100/min, 10/s or 5000/day, nil when it's empty
+func ParseRate(value string) (*Rate, error) {
+	if value == "" {
+		return nil, nil
+	}
+	count, unit, ok := strings.Cut(value, "/")
+	requests, err := strconv.Atoi(strings.TrimSpace(count))
+	unit = strings.ToLower(strings.TrimSpace(unit))
+	name, known := rateUnits[unit]
+	if !known {
+		// Plurals such as 100/minutes
+		name = rateUnits[strings.TrimSuffix(unit, "s")]
+	}
+	if !ok || err != nil || requests < 1 || name == "" {
+		return nil, fmt.Errorf("--rate-limit takes requests per unit such as 100/min, 10/s, 1000/hour or 5000/day: %q", value)
+	}
+	return &Rate{Requests: requests, Unit: name, Per: rateDurations[name]}, nil
+}
+
+// CacheTTL returns the Go duration expression of a --cache such as 60s or 5m, "" when it's empty
+func CacheTTL(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		return "", fmt.Errorf("--cache takes a positive duration such as 30s, 5m or 1h: %q", value)
+	}
+	switch {
+	case ttl%time.Hour == 0:
+		return fmt.Sprintf("%d * time.Hour", ttl/time.Hour), nil
+	case ttl%time.Minute == 0:
+		return fmt.Sprintf("%d * time.Minute", ttl/time.Minute), nil
+	case ttl%time.Second == 0:
+		return fmt.Sprintf("%d * time.Second", ttl/time.Second), nil
+	}
+	return fmt.Sprintf("%d * time.Millisecond", ttl/time.Millisecond), nil
+}
+
+// CheckRateLimitAndCache reports a --rate-limit or --cache that can't be parsed
+func CheckRateLimitAndCache() error {
+	if _, err := ParseRate(RateLimit); err != nil {
+		return err
+	}
+	_, err := CacheTTL(Cache)
+	return err
+}
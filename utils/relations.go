@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// MissingRelation is a relation to a model that doesn't exist and isn't being generated
+type MissingRelation struct {
+	Model   string // Model the relation is declared on, e.g. Post
+	Field   string // Name of the relation field, e.g. tags
+	Related string // Model it refers to, e.g. Tag
+}
+
+// String returns the relation as Post.tags → Tag
+func (r MissingRelation) String() string {
+	return fmt.Sprintf("%s.%s → %s", r.Model, r.Field, r.Related)
+}
+
+// MissingRelations returns the relations among a model's field definitions whose related model
+// is neither the model itself nor one of known. Self references and morphTo, which has no fixed
+// related model, are never missing.
+func MissingRelations(model string, defs []string, known map[string]bool) []MissingRelation {
+	var missing []MissingRelation
+	for _, def := range defs {
+		field := ParseField(def)
+		if !field.IsRelation || field.RelatedModel == "" || strings.EqualFold(field.RelatedModel, "self") {
+			continue
+		}
+		related := ToPascalCase(field.RelatedModel)
+		if related == model || known[related] {
+			continue
+		}
+		name, _, _ := strings.Cut(def, ":")
+		missing = append(missing, MissingRelation{Model: model, Field: name, Related: related})
+	}
+	return missing
+}
+
+// FindModels returns the models declared in the backend's app/models, sorted by name
+func FindModels(backendDir string) []string {
+	files, err := filepath.Glob(filepath.Join(backendDir, "app", "models", "*.go"))
+	if err != nil {
+		return nil
+	}
+
+	var models []string
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		source, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		models = append(models, RecoverModels(source)...)
+	}
+	sort.Strings(models)
+	return models
+}
+
+// childLists returns the hasMany and morphMany fields that are preloaded with a record and
+// returned in its response. The rows of nested forms are preloaded in the order they were added.
+func childLists(fields []Field) []Field {
+	nested := splitColumns(NestedForms)
+	var lists []Field
+	for _, field := range fields {
+		if (field.Relationship == "has_many" || field.Relationship == "morph_many") && !slices.Contains(nested, field.JSONName) {
+			lists = append(lists, field)
+		}
+	}
+	return lists
+}
+
+// WithInverse adds the hasMany side of belongsTo fields to the models they point to (--with-inverse)
+var WithInverse bool
+
+// InverseRelation is the hasMany field a belongsTo field adds to the model it points to
+type InverseRelation struct {
+	Model string // Model that gets the field, e.g. Post
+	Def   string // Field definition, e.g. comments:hasMany:Comment
+}
+
+// InverseRelations returns the hasMany fields that the belongsTo fields among a model's field
+// definitions need on the models in modelsDir they point to. Models that already have the
+// hasMany are left out; problems are inverses that can't be added.
+func InverseRelations(model string, defs []string, modelsDir string) ([]InverseRelation, []string) {
+	naming := NewNamingConvention(model)
+	var inverses []InverseRelation
+	var problems []string
+	for _, def := range defs {
+		field := ParseField(def)
+		if field.Relationship != "belongs_to" || field.RelatedModel == "" || strings.EqualFold(field.RelatedModel, "self") {
+			continue
+		}
+		related := NewNamingConvention(ToPascalCase(field.RelatedModel))
+		if related.Model == model {
+			continue // A tree gets its children with the model
+		}
+
+		inverse := naming.PluralSnake + ":hasMany:" + model
+		if field.ForeignKey != related.Model+"Id" {
+			inverse += ":" + ToSnakeCase(field.ForeignKey)
+		}
+		source, err := os.ReadFile(filepath.Join(modelsDir, related.ModelSnake+".go"))
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s is not in %s; add %s to it when it is generated", related.Model, modelsDir, inverse))
+			continue
+		}
+		existing, err := RecoverFieldDefs(source, related.Model)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("Could not read the fields of %s: %v", related.Model, err))
+			continue
+		}
+
+		synced, taken := false, false
+		for _, other := range existing {
+			parsed := ParseField(other)
+			key := parsed.ForeignKey
+			if key == "" {
+				key = related.Model + "Id"
+			}
+			switch {
+			case parsed.Relationship == "has_many" && ToPascalCase(parsed.RelatedModel) == model && key == field.ForeignKey:
+				synced = true
+			case parsed.JSONName == naming.PluralSnake:
+				taken = true
+			}
+		}
+		switch {
+		case synced:
+			continue
+		case taken:
+			problems = append(problems, fmt.Sprintf("%s already has a %s field; add %s under another name by hand", related.Model, naming.PluralSnake, inverse))
+		default:
+			inverses = append(inverses, InverseRelation{Model: related.Model, Def: inverse})
+		}
+	}
+	return inverses, problems
+}
+
+// PivotRelations returns the manyToMany fields whose join tables have pivot columns
+func PivotRelations(fields []Field) []Field {
+	var pivots []Field
+	for _, field := range fields {
+		if field.Relationship == "many_to_many" && len(field.Pivot) > 0 {
+			pivots = append(pivots, field)
+		}
+	}
+	return pivots
+}
@@ -0,0 +1,214 @@
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ReportPeriods are the date buckets a report can group by
+var ReportPeriods = []string{"day", "week", "month", "year"}
+
+// ReportMeasure is a column a report sums
+type ReportMeasure struct {
+	Field
+	SumType string // Go type of the sum: int64 for whole numbers and money, float64 otherwise
+}
+
+// Label returns the heading of the measure's column
+func (m ReportMeasure) Label() string {
+	return ToCapitalCase(m.DBName)
+}
+
+// Report is a GORM group query over a module's table, generated by bui g report
+type Report struct {
+	*NamingConvention // Module the report reads
+
+	Name       string // Report name in snake_case (e.g., "sales")
+	Title      string // Title of the report page (e.g., "Sales")
+	Handler    string // Service method and controller handler (e.g., "SalesReport")
+	Slug       string // Last segment of the report's routes (e.g., "sales")
+	Period     string // Date bucket, or "" when grouped by GroupField
+	GroupField *Field // Field grouped by when Period is ""
+	DateColumn string // Column the date range, and the period, apply to
+	Measures   []ReportMeasure
+}
+
+// NewReport returns the report name over a module with the given fields. groupBy is a period or
+// a field, sums the numeric fields to total and dateField the date column, created_at when empty.
+func NewReport(naming *NamingConvention, fields []Field, name, groupBy string, sums []string, dateField string) (*Report, error) {
+	report := &Report{
+		NamingConvention: naming,
+		Name:             ToSnakeCase(name),
+		Title:            ToCapitalCase(ToSnakeCase(name)),
+		Handler:          ToPascalCase(name) + "Report",
+		Slug:             ToKebabCase(name),
+		DateColumn:       "created_at",
+	}
+
+	byName := map[string]Field{}
+	for _, field := range fields {
+		byName[field.DBName] = field
+	}
+	find := func(name string) (Field, bool) {
+		field, ok := byName[ToSnakeCase(name)]
+		return field, ok
+	}
+
+	if slices.Contains(ReportPeriods, groupBy) {
+		report.Period = groupBy
+	} else {
+		field, ok := find(groupBy)
+		if !ok {
+			return nil, fmt.Errorf("unknown --group-by %q: use %s or a field of %s", groupBy, strings.Join(ReportPeriods, ", "), naming.Model)
+		}
+		if timeField(field) {
+			return nil, fmt.Errorf("cannot group by %s: group by a period of it, e.g. --group-by month --date-field %s", field.DBName, field.DBName)
+		}
+		if !groupable(field) {
+			return nil, fmt.Errorf("cannot group by %s: use a text, number, boolean, select or foreign key field", field.DBName)
+		}
+		report.GroupField = &field
+	}
+
+	if dateField != "" && ToSnakeCase(dateField) != "created_at" && ToSnakeCase(dateField) != "updated_at" {
+		field, ok := find(dateField)
+		if !ok {
+			return nil, fmt.Errorf("unknown --date-field %q: not a field of %s", dateField, naming.Model)
+		}
+		if !timeField(field) {
+			return nil, fmt.Errorf("--date-field %s is not a date or datetime field", field.DBName)
+		}
+		report.DateColumn = field.DBName
+	} else if dateField != "" {
+		report.DateColumn = ToSnakeCase(dateField)
+	}
+
+	for _, sum := range sums {
+		field, ok := find(sum)
+		if !ok {
+			return nil, fmt.Errorf("unknown --sum %q: not a field of %s", sum, naming.Model)
+		}
+		if field.DBName == "count" || field.JSONName == report.KeyJSON() {
+			return nil, fmt.Errorf("cannot sum %s: the report has a %s column of its own", field.DBName, field.DBName)
+		}
+		measure := ReportMeasure{Field: field}
+		switch strings.TrimPrefix(field.Type, "*") {
+		case "int", "int64", "uint", "uint64":
+			measure.SumType = "int64"
+		case "float64", "decimal.Decimal":
+			measure.SumType = "float64"
+		default:
+			return nil, fmt.Errorf("cannot sum %s: use a number, money or decimal field", field.DBName)
+		}
+		if field.IsRelation || strings.HasSuffix(field.DBName, "_id") {
+			return nil, fmt.Errorf("cannot sum %s: it's a foreign key", field.DBName)
+		}
+		report.Measures = append(report.Measures, measure)
+	}
+	return report, nil
+}
+
+// groupable reports whether a report can group by field
+func groupable(field Field) bool {
+	if field.IsEncrypted || field.IsPoint || field.IsTranslation {
+		return false
+	}
+	switch strings.TrimPrefix(field.Type, "*") {
+	case "string", "bool", "int", "int64", "uint":
+		return true
+	}
+	return field.IsEnum
+}
+
+// timeField reports whether field holds a date or a date and time
+func timeField(field Field) bool {
+	t := strings.TrimPrefix(field.Type, "*")
+	return t == "time.Time" || t == "types.DateTime"
+}
+
+// GroupColumn returns the SQL the report groups by: the project database's expression for the
+// period of DateColumn, or the grouped field's column
+func (r *Report) GroupColumn() string {
+	if r.GroupField != nil {
+		return r.GroupField.DBName
+	}
+	column := r.DateColumn
+	formats := map[string][3]string{ // PostgreSQL, MySQL, SQLite
+		"day":   {"YYYY-MM-DD", "%Y-%m-%d", "%Y-%m-%d"},
+		"month": {"YYYY-MM", "%Y-%m", "%Y-%m"},
+		"year":  {"YYYY", "%Y", "%Y"},
+	}
+	// Weeks start on Monday and are named after it
+	if r.Period == "week" {
+		switch Database() {
+		case MySQL:
+			return fmt.Sprintf("DATE_FORMAT(DATE_SUB(%s, INTERVAL WEEKDAY(%s) DAY), '%%Y-%%m-%%d')", column, column)
+		case SQLite:
+			return fmt.Sprintf("date(%s, 'weekday 0', '-6 days')", column)
+		}
+		return fmt.Sprintf("to_char(date_trunc('week', %s), 'YYYY-MM-DD')", column)
+	}
+	format := formats[r.Period]
+	switch Database() {
+	case MySQL:
+		return fmt.Sprintf("DATE_FORMAT(%s, '%s')", column, format[1])
+	case SQLite:
+		return fmt.Sprintf("strftime('%s', %s)", format[2], column)
+	}
+	return fmt.Sprintf("to_char(%s, '%s')", column, format[0])
+}
+
+// KeyName returns the Go field of a report row's group
+func (r *Report) KeyName() string {
+	if r.GroupField != nil {
+		return r.GroupField.Name
+	}
+	return "Period"
+}
+
+// KeyJSON returns the JSON name of a report row's group
+func (r *Report) KeyJSON() string {
+	if r.GroupField != nil {
+		return r.GroupField.JSONName
+	}
+	return "period"
+}
+
+// KeyType returns the Go type of a report row's group
+func (r *Report) KeyType() string {
+	if r.GroupField != nil && !r.GroupField.IsEnum {
+		return strings.TrimPrefix(r.GroupField.Type, "*")
+	}
+	return "string"
+}
+
+// KeyLabel returns the heading of the report's group column
+func (r *Report) KeyLabel() string {
+	if r.GroupField != nil {
+		return ToCapitalCase(r.GroupField.DBName)
+	}
+	return ToCapitalCase(r.Period)
+}
+
+// SumList returns the summed columns as a phrase, e.g. "total, tax and discount"
+func (r *Report) SumList() string {
+	columns := make([]string, len(r.Measures))
+	for i, measure := range r.Measures {
+		columns[i] = measure.DBName
+	}
+	if len(columns) < 2 {
+		return strings.Join(columns, "")
+	}
+	return strings.Join(columns[:len(columns)-1], ", ") + " and " + columns[len(columns)-1]
+}
+
+// Route returns the API route of the report
+func (r *Report) Route() string {
+	return r.RoutePath + "/reports/" + r.Slug
+}
+
+// PagePath returns the admin route of the report's page
+func (r *Report) PagePath() string {
+	return "/app/" + r.PluralKebab + "/reports/" + r.Slug
+}
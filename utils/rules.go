@@ -0,0 +1,275 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Rule is a validation rule of a rules= modifier, e.g. max=255 in email:string:rules=email,max=255
+type Rule struct {
+	Name  string
+	Param string // Text after the =, empty for rules without one
+}
+
+// String returns the rule as it is written in the field definition
+func (r Rule) String() string {
+	if r.Param == "" {
+		return r.Name
+	}
+	return r.Name + "=" + r.Param
+}
+
+// builtinRules are the rules app/rules and useRules.ts check, by the kind of field they apply to,
+// and whether they take a parameter
+var builtinRules = map[string]map[string]bool{
+	"string": {
+		"email": false, "url": false, "uuid": false,
+		"alpha": false, "alphanum": false, "numeric": false,
+		"min": true, "max": true, "len": true,
+	},
+	"number": {
+		"min": true, "max": true, "gt": true, "gte": true, "lt": true, "lte": true,
+	},
+}
+
+// customRuleName matches the names of the rules that aren't built in
+var customRuleName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// ruleParam matches the parameters of rules, which end up in Go and TypeScript string literals
+var ruleParam = regexp.MustCompile(`^[^'"\\\s]+$`)
+
+// RuleList returns the rules of the field as its rules= modifier has them (e.g., "email,max=255"),
+// the form the model's rules tag keeps for --alter
+func (f Field) RuleList() string {
+	rules := make([]string, len(f.Rules))
+	for i, rule := range f.Rules {
+		rules[i] = rule.String()
+	}
+	return strings.Join(rules, ",")
+}
+
+// parseRules reads the comma-separated rules of a rules= modifier
+func parseRules(value string) []Rule {
+	var rules []Rule
+	for _, rule := range strings.Split(value, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		if name != "" {
+			rules = append(rules, Rule{Name: name, Param: param})
+		}
+	}
+	return rules
+}
+
+// RuleKind returns "string" or "number" for the fields rules= applies to, "" for the others
+func (f Field) RuleKind() string {
+	if f.IsRelation || f.IsSelect || f.IsComputed || f.IsMoney || f.PointName != "" {
+		return ""
+	}
+	switch f.Type {
+	case "string", "text", "email":
+		return "string"
+	case "int", "uint", "float64":
+		return "number"
+	}
+	return ""
+}
+
+// IsCustomRule reports whether a rule has no built-in check, so it's looked up among the custom rules
+func IsCustomRule(name string) bool {
+	return !slices.ContainsFunc([]string{"string", "number"}, func(kind string) bool {
+		_, ok := builtinRules[kind][name]
+		return ok
+	})
+}
+
+// CheckFieldRules reports the rules= modifiers of the field definitions that can't be generated:
+// rules on fields that aren't text or numbers, built-in rules of the other kind of field, missing
+// or malformed parameters, and custom rule names that aren't lower_snake_case
+func CheckFieldRules(fieldDefs []string) error {
+	for _, def := range fieldDefs {
+		field := ParseField(def)
+		if len(field.Rules) == 0 {
+			continue
+		}
+		kind := field.RuleKind()
+		if kind == "" {
+			return fmt.Errorf("rules= applies to text and number fields: %q", def)
+		}
+		for _, rule := range field.Rules {
+			if err := checkRule(kind, rule); err != nil {
+				return fmt.Errorf("%w: %q", err, def)
+			}
+		}
+	}
+	return nil
+}
+
+// checkRule reports a rule a field of kind can't have
+func checkRule(kind string, rule Rule) error {
+	if rule.Param != "" && !ruleParam.MatchString(rule.Param) {
+		return fmt.Errorf("the parameter of %s can't have quotes, backslashes or spaces", rule.Name)
+	}
+	if IsCustomRule(rule.Name) {
+		if !customRuleName.MatchString(rule.Name) {
+			return fmt.Errorf("custom rule %s must be lower_snake_case", rule.Name)
+		}
+		return nil
+	}
+
+	takesParam, ok := builtinRules[kind][rule.Name]
+	switch {
+	case !ok:
+		return fmt.Errorf("%s applies to %s fields", rule.Name, otherRuleKind(kind))
+	case !takesParam && rule.Param != "":
+		return fmt.Errorf("%s takes no parameter", rule.Name)
+	case takesParam && kind == "string":
+		if n, err := strconv.Atoi(rule.Param); err != nil || n < 0 {
+			return fmt.Errorf("%s takes a number of characters, e.g. %s=255", rule.Name, rule.Name)
+		}
+	case takesParam:
+		if _, err := strconv.ParseFloat(rule.Param, 64); err != nil {
+			return fmt.Errorf("%s takes a number, e.g. %s=18", rule.Name, rule.Name)
+		}
+	}
+	return nil
+}
+
+// otherRuleKind returns the kind of field rules= applies to that isn't kind
+func otherRuleKind(kind string) string {
+	if kind == "string" {
+		return "number"
+	}
+	return "text"
+}
+
+// CustomRules returns the names of the custom rules the fields use, in order
+func CustomRules(fields []Field) []string {
+	var names []string
+	for _, field := range fields {
+		for _, rule := range field.Rules {
+			if IsCustomRule(rule.Name) && !slices.Contains(names, rule.Name) {
+				names = append(names, rule.Name)
+			}
+		}
+	}
+	return names
+}
+
+// HasRules reports whether any of the fields has a rules= modifier
+func HasRules(fields []Field) bool {
+	return slices.ContainsFunc(fields, func(f Field) bool { return len(f.Rules) > 0 })
+}
+
+// ruleTestValues changes the test values of a field with built-in rules to values that pass them.
+// Custom rules start out passing everything, so they don't change the values.
+func ruleTestValues(field Field, create, update string) (string, string) {
+	switch field.RuleKind() {
+	case "string":
+		return ruleTestString(field.Rules, create), ruleTestString(field.Rules, update)
+	case "number":
+		return ruleTestNumber(field, create, 0), ruleTestNumber(field, update, 1)
+	}
+	return create, update
+}
+
+// ruleTestString returns a string literal passing the built-in rules of a text field
+func ruleTestString(rules []Rule, literal string) string {
+	value, err := strconv.Unquote(literal)
+	if err != nil || strings.TrimSpace(value) == "" {
+		return literal
+	}
+	word := strings.ToLower(strings.Fields(value)[0])
+
+	pad := "x"
+	padFront := false
+	for _, rule := range rules {
+		switch rule.Name {
+		case "email":
+			value, padFront = word+"@example.com", true
+		case "url":
+			value = "https://example.com/" + word
+		case "uuid":
+			value = "00000000-0000-4000-8000-000000000001"
+			if word != "test" {
+				value = "00000000-0000-4000-8000-000000000002"
+			}
+		case "alpha":
+			value = strings.ReplaceAll(value, " ", "")
+		case "alphanum":
+			value = strings.ReplaceAll(value, " ", "") + "1"
+		case "numeric":
+			value, pad, padFront = "1", "0", true
+			if word != "test" {
+				value = "2"
+			}
+		}
+	}
+
+	for _, rule := range rules {
+		n, err := strconv.Atoi(rule.Param)
+		if err != nil {
+			continue
+		}
+		length := len([]rune(value))
+		switch {
+		case (rule.Name == "min" || rule.Name == "len") && length < n:
+			if padFront {
+				value = strings.Repeat(pad, n-length) + value
+			} else {
+				value += strings.Repeat(pad, n-length)
+			}
+		case (rule.Name == "max" || rule.Name == "len") && length > n:
+			value = string([]rune(value)[:n])
+		}
+	}
+	return strconv.Quote(value)
+}
+
+// ruleTestNumber returns a number literal passing the bounds of a number field, step above the
+// lower bound when the literal is out of range
+func ruleTestNumber(field Field, literal string, step float64) string {
+	value, err := strconv.ParseFloat(literal, 64)
+	if err != nil {
+		return literal
+	}
+	unit := 1.0
+	if field.Type == "float64" {
+		unit = 0.5
+	}
+
+	low, high := math.Inf(-1), math.Inf(1)
+	for _, rule := range field.Rules {
+		bound, err := strconv.ParseFloat(rule.Param, 64)
+		if err != nil {
+			continue
+		}
+		switch rule.Name {
+		case "min", "gte":
+			low = math.Max(low, bound)
+		case "gt":
+			low = math.Max(low, bound+unit)
+		case "max", "lte":
+			high = math.Min(high, bound)
+		case "lt":
+			high = math.Min(high, bound-unit)
+		}
+	}
+	if field.Type != "float64" {
+		low, high = math.Ceil(low), math.Floor(high)
+	}
+
+	switch {
+	case value < low:
+		value = math.Min(low+step*unit, high)
+	case value > high:
+		value = math.Max(high-step*unit, low)
+	}
+	if field.Type == "uint" && value < 0 {
+		value = 0
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/base-go/mamba"
+)
+
+// Run is one run of the module generators. bui g generates the backend and frontend modules side
+// by side, each writing through its own Writer of the run, which holds what the two share.
+type Run struct {
+	Root   string // Project root, where the changelog, manifest and backups are
+	Module string // Module the run generates, which names its overwrite backup
+
+	// Inverses are the inverse relations the backend generator added (--with-inverse), which the
+	// frontend generator adds to the related frontend modules
+	Inverses []InverseRelation
+
+	// manifest is the project's manifest, which writes consult for the files bui wrote and nobody
+	// edited since; nil when it can't be read
+	manifest *GenerationManifest
+
+	// mu keeps the writers' overwrite prompts, dry-run reports and recording from interleaving
+	mu sync.Mutex
+
+	// backup collects the files the writers replaced with --force or confirmed overwrites
+	backup *Backup
+
+	// recorded and logged are set once the run has a manifest and changelog entry, which the
+	// writers recording after the first one join
+	recorded, logged bool
+}
+
+// NewRun starts a run generating module in the project at root. A manifest that can't be read is
+// ignored, so every existing file is asked about.
+func NewRun(root, module string) *Run {
+	run := &Run{Root: root, Module: module}
+	if m, err := LoadManifest(root); err == nil {
+		run.manifest = m
+	}
+	return run
+}
+
+// Writer returns a writer of the run for a generator working in dir, "" being the current directory
+func (r *Run) Writer(dir string) *Writer {
+	if dir == "" {
+		dir = "."
+	}
+	return &Writer{Dir: dir, Run: r, merged: map[string][]byte{}}
+}
+
+// Path joins elem to the directory the writer's generator works in
+func (w *Writer) Path(elem ...string) string {
+	return filepath.Join(append([]string{w.Dir}, elem...)...)
+}
+
+// Record records what the generator cmd wrote through w from args in the project root: in its
+// CHANGELOG.md, under a heading with the command and the bui version, and in its manifest with the
+// hashes of the files. part names what was generated (e.g. "Backend module Product"); the writers
+// of the run recording after the first join its entries.
+func (w *Writer) Record(cmd *mamba.Command, args []string, part string) error {
+	w.Run.mu.Lock()
+	defer w.Run.mu.Unlock()
+
+	joined := w.Run.recorded
+	w.Run.recorded = true
+	if err := w.recordManifest(cmd, args, joined); err != nil {
+		return fmt.Errorf("failed to update %s: %w", ManifestFile, err)
+	}
+	return w.recordChangelog(GenerationCommand(cmd, args), part)
+}
@@ -0,0 +1,196 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Schema describes multiple models in a single YAML or JSON document
+type Schema struct {
+	Models []SchemaModel `yaml:"models" json:"models"`
+}
+
+// SchemaModel describes one model and its fields
+type SchemaModel struct {
+	Name   string        `yaml:"name" json:"name"`
+	Fields []SchemaField `yaml:"fields" json:"fields"`
+	Table  string        `yaml:"table" json:"table"`   // Custom table name, like --table
+	Unique []string      `yaml:"unique" json:"unique"` // Composite unique indexes, like --unique
+
+	// Module metadata, like --label, --description and --icon
+	Label       string `yaml:"label" json:"label"`
+	Description string `yaml:"description" json:"description"`
+	Icon        string `yaml:"icon" json:"icon"`
+}
+
+// SchemaField is either a plain "name:type[:extra]" string or a structured field
+type SchemaField struct {
+	Name     string   `yaml:"name" json:"name"`
+	Type     string   `yaml:"type" json:"type"`
+	Model    string   `yaml:"model" json:"model"`       // Related model for relations
+	Options  []string `yaml:"options" json:"options"`   // Options for enum/select/radio/checkbox fields
+	Required bool     `yaml:"required" json:"required"` // Emits the :required modifier
+	Default  string   `yaml:"default" json:"default"`   // Emits the :default=value modifier
+	Rules    []string `yaml:"rules" json:"rules"`       // Emits the :rules=a,b modifier
+
+	// definition holds the raw string when the field was given in CLI form
+	definition string
+}
+
+// UnmarshalYAML accepts both "title:string" and {name: title, type: string}
+func (f *SchemaField) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		f.definition = strings.TrimSpace(node.Value)
+		f.Name = strings.Split(f.definition, ":")[0]
+		return nil
+	}
+
+	type rawField SchemaField
+	var raw rawField
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	*f = SchemaField(raw)
+	return nil
+}
+
+// Definition returns the field in the same "name:type[:extra]" form used on the command line
+func (f SchemaField) Definition() string {
+	if f.definition != "" {
+		return f.definition
+	}
+
+	parts := []string{f.Name}
+	if f.Type != "" {
+		parts = append(parts, f.Type)
+	}
+	if f.Model != "" {
+		parts = append(parts, f.Model)
+	} else if len(f.Options) > 0 {
+		parts = append(parts, strings.Join(f.Options, ","))
+	}
+	if f.Type != "" && f.Required {
+		parts = append(parts, "required")
+	}
+	if f.Type != "" && f.Default != "" {
+		parts = append(parts, "default="+f.Default)
+	}
+	if f.Type != "" && len(f.Rules) > 0 {
+		parts = append(parts, "rules="+strings.Join(f.Rules, ","))
+	}
+	return strings.Join(parts, ":")
+}
+
+// Args returns the model name followed by its field definitions, matching `bui g` arguments
+func (m SchemaModel) Args() []string {
+	args := []string{m.Name}
+	for _, field := range m.Fields {
+		args = append(args, field.Definition())
+	}
+	return args
+}
+
+// LoadSchema reads a schema file. JSON is parsed by the YAML decoder since it is a subset.
+func LoadSchema(path string) (*Schema, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema file: %w", err)
+	}
+
+	var schema Schema
+	if err := yaml.Unmarshal(content, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse schema file: %w", err)
+	}
+
+	if len(schema.Models) == 0 {
+		return nil, fmt.Errorf("schema file defines no models")
+	}
+
+	seen := make(map[string]bool)
+	for i, model := range schema.Models {
+		if strings.TrimSpace(model.Name) == "" {
+			return nil, fmt.Errorf("model #%d is missing a name", i+1)
+		}
+		key := ToPascalCase(model.Name)
+		if seen[key] {
+			return nil, fmt.Errorf("model %s is defined more than once", key)
+		}
+		seen[key] = true
+
+		for _, field := range model.Fields {
+			if strings.TrimSpace(field.Definition()) == "" || field.Name == "" {
+				return nil, fmt.Errorf("model %s has a field without a name", key)
+			}
+		}
+	}
+
+	return &schema, nil
+}
+
+// OrderedModels returns the models sorted so that belongs_to and many_to_many targets are generated first.
+// Models outside the schema are ignored; cycles keep their declaration order.
+func (s *Schema) OrderedModels() []SchemaModel {
+	index := make(map[string]int, len(s.Models))
+	for i, model := range s.Models {
+		index[ToPascalCase(model.Name)] = i
+	}
+
+	ordered := make([]SchemaModel, 0, len(s.Models))
+	state := make(map[int]int) // 0 = unvisited, 1 = visiting, 2 = done
+
+	var visit func(i int)
+	visit = func(i int) {
+		if state[i] != 0 {
+			return
+		}
+		state[i] = 1
+		for _, dep := range s.Models[i].Dependencies() {
+			if j, ok := index[dep]; ok && j != i {
+				visit(j)
+			}
+		}
+		state[i] = 2
+		ordered = append(ordered, s.Models[i])
+	}
+
+	for i := range s.Models {
+		visit(i)
+	}
+
+	return ordered
+}
+
+// Dependencies returns the related models this model points to via belongs_to, or shares a
+// join table with via many_to_many
+func (m SchemaModel) Dependencies() []string {
+	var deps []string
+	for _, def := range m.Fields {
+		field := ParseField(def.Definition())
+		if (field.Relationship == "belongs_to" || field.Relationship == "many_to_many") && field.RelatedModel != "" {
+			deps = append(deps, ToPascalCase(field.RelatedModel))
+		}
+	}
+	return deps
+}
+
+// MissingRelations returns the relations of the schema's models to models that are neither in
+// the schema nor among existing, the models already in app/models
+func (s *Schema) MissingRelations(existing []string) []MissingRelation {
+	known := make(map[string]bool, len(s.Models)+len(existing))
+	for _, model := range s.Models {
+		known[ToPascalCase(model.Name)] = true
+	}
+	for _, model := range existing {
+		known[model] = true
+	}
+
+	var missing []MissingRelation
+	for _, model := range s.Models {
+		args := model.Args()
+		missing = append(missing, MissingRelations(ToPascalCase(model.Name), args[1:], known)...)
+	}
+	return missing
+}
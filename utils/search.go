@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Searchable lists the text columns generated modules search in full text (--searchable title,description)
+var Searchable string
+
+// SearchableFields returns the fields of the --searchable columns, and the columns that aren't
+// text fields of the model
+func SearchableFields(fields []Field) ([]Field, []string) {
+	var found []Field
+	var unknown []string
+	for _, column := range splitColumns(Searchable) {
+		match := -1
+		for i, field := range fields {
+			if isColumn(field, column) && isTextField(field) {
+				match = i
+				break
+			}
+		}
+		if match == -1 {
+			unknown = append(unknown, column)
+			continue
+		}
+		found = append(found, fields[match])
+	}
+	return found, unknown
+}
+
+// isTextField reports whether field is a plain text column
+func isTextField(field Field) bool {
+	return field.Type == "string" && !field.IsRelation && field.Relationship == "" && field.MorphName == "" && !field.IsSelect && !field.IsEncrypted && !field.IsComputed
+}
+
+// SearchMigrationSQL returns PostgreSQL statements that add and drop a table's search_vector,
+// a generated tsvector of the searchable columns, and its GIN index
+func SearchMigrationSQL(table string, fields []Field) (string, string) {
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = fmt.Sprintf("coalesce(%s, '')", field.DBName)
+	}
+	index := fmt.Sprintf("idx_%s_search_vector", table)
+
+	up := fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', %s)) STORED;
+CREATE INDEX IF NOT EXISTS %s ON %s USING GIN (search_vector);
+`, table, strings.Join(parts, " || ' ' || "), index, table)
+	down := fmt.Sprintf("DROP INDEX IF EXISTS %s;\nALTER TABLE %s DROP COLUMN IF EXISTS search_vector;\n", index, table)
+	return up, down
+}
+
+// fullTextFields returns the --searchable fields, or nil without --searchable
+func fullTextFields(fields []Field) []Field {
+	found, _ := SearchableFields(fields)
+	return found
+}
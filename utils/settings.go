@@ -0,0 +1,180 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// SettingTypes are the types of the settings bui g settings generates
+var SettingTypes = []string{"string", "text", "int", "float", "bool", "email", "url", "color", "image", "file"}
+
+// DefaultSettingGroup is the group of the settings given before any name:group
+const DefaultSettingGroup = "general"
+
+// Setting is a key of the settings module, generated by bui g settings
+type Setting struct {
+	Group string // Tab of the settings page, in snake_case (e.g., "branding")
+	Key   string // Unique key in snake_case (e.g., "site_name")
+	Type  string // One of SettingTypes
+}
+
+// SettingGroup is a tab of the settings page
+type SettingGroup struct {
+	Name     string
+	Settings []Setting
+}
+
+// SettingsModule is the settings the settings module and page are generated with
+type SettingsModule struct {
+	Settings []Setting
+}
+
+// settingNamePattern matches the keys of settings and the names of groups
+var settingNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// reservedSettingAccessors are names of the settings package that a setting's accessor can't take
+var reservedSettingAccessors = []string{"Definition", "Definitions", "DefaultService", "ErrInvalidSetting", "Find", "Init", "Module", "NewService", "Service", "Setting"}
+
+// settingDefinitionPattern matches a setting in the Definitions the settings module is generated with
+var settingDefinitionPattern = regexp.MustCompile(`\{Group: "([a-z0-9_]+)", Key: "([a-z0-9_]+)", Type: "([a-z]+)"`)
+
+// ParseSettings reads the arguments of bui g settings: name:group starts a group and key:type
+// adds a setting to the current one, DefaultSettingGroup before any group
+func ParseSettings(args []string) ([]Setting, error) {
+	var settings []Setting
+	group := DefaultSettingGroup
+	for _, arg := range args {
+		name, kind, ok := strings.Cut(arg, ":")
+		name = ToSnakeCase(name)
+		if !ok || kind == "" {
+			return nil, fmt.Errorf("invalid setting %q: use key:type or name:group", arg)
+		}
+		if !settingNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid name %q: use letters, digits and underscores, starting with a letter", name)
+		}
+		kind = strings.ToLower(kind)
+		if kind == "group" {
+			group = name
+			continue
+		}
+		if !slices.Contains(SettingTypes, kind) {
+			return nil, fmt.Errorf("unknown type %q of %s: use %s", kind, name, strings.Join(SettingTypes, ", "))
+		}
+		if slices.Contains(reservedSettingAccessors, ToPascalCase(name)) {
+			return nil, fmt.Errorf("setting %s would clash with %s of the settings package: use another key", name, ToPascalCase(name))
+		}
+		if slices.ContainsFunc(settings, func(s Setting) bool { return s.Key == name }) {
+			return nil, fmt.Errorf("setting %s is given twice", name)
+		}
+		settings = append(settings, Setting{Group: group, Key: name, Type: kind})
+	}
+	if len(settings) == 0 {
+		return nil, fmt.Errorf("no settings given: use key:type, e.g. site_name:string")
+	}
+	return settings, nil
+}
+
+// ParseSettingDefinitions returns the settings listed in the Definitions of a generated
+// app/settings/definitions.go
+func ParseSettingDefinitions(content string) []Setting {
+	var settings []Setting
+	for _, match := range settingDefinitionPattern.FindAllStringSubmatch(content, -1) {
+		settings = append(settings, Setting{Group: match[1], Key: match[2], Type: match[3]})
+	}
+	return settings
+}
+
+// MergeSettings returns existing with added: an added setting replaces the existing one of its
+// key in place, and the others follow in order
+func MergeSettings(existing, added []Setting) []Setting {
+	merged := slices.Clone(existing)
+	for _, setting := range added {
+		if i := slices.IndexFunc(merged, func(s Setting) bool { return s.Key == setting.Key }); i != -1 {
+			merged[i] = setting
+		} else {
+			merged = append(merged, setting)
+		}
+	}
+	return merged
+}
+
+// GroupSettings returns the groups of settings in the order they first appear
+func GroupSettings(settings []Setting) []SettingGroup {
+	var groups []SettingGroup
+	for _, setting := range settings {
+		i := slices.IndexFunc(groups, func(g SettingGroup) bool { return g.Name == setting.Group })
+		if i == -1 {
+			groups = append(groups, SettingGroup{Name: setting.Group})
+			i = len(groups) - 1
+		}
+		groups[i].Settings = append(groups[i].Settings, setting)
+	}
+	return groups
+}
+
+// Label returns the heading of the group's tab
+func (g SettingGroup) Label() string {
+	return ToCapitalCase(g.Name)
+}
+
+// Label returns the label of the setting's input
+func (s Setting) Label() string {
+	return ToCapitalCase(s.Key)
+}
+
+// Accessor returns the name of the setting's typed accessor (e.g., "SiteName")
+func (s Setting) Accessor() string {
+	return ToPascalCase(s.Key)
+}
+
+// GoType returns the Go type of the setting's accessor
+func (s Setting) GoType() string {
+	switch s.Type {
+	case "int":
+		return "int"
+	case "float":
+		return "float64"
+	case "bool":
+		return "bool"
+	}
+	return "string"
+}
+
+// TSType returns the TypeScript type of the setting's value
+func (s Setting) TSType() string {
+	switch s.Type {
+	case "int", "float":
+		return "number"
+	case "bool":
+		return "boolean"
+	}
+	return "string"
+}
+
+// Upload reports whether the setting's value is the URL of an uploaded file
+func (s Setting) Upload() bool {
+	return s.Type == "image" || s.Type == "file"
+}
+
+// Groups returns the tabs of the settings page
+func (m SettingsModule) Groups() []SettingGroup {
+	return GroupSettings(m.Settings)
+}
+
+// UploadSettings returns the image and file settings
+func (m SettingsModule) UploadSettings() []Setting {
+	var uploads []Setting
+	for _, setting := range m.Settings {
+		if setting.Upload() {
+			uploads = append(uploads, setting)
+		}
+	}
+	return uploads
+}
+
+// Uploads reports whether any setting is an image or a file
+func (m SettingsModule) Uploads() bool {
+	return len(m.UploadSettings()) > 0
+}
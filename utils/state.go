@@ -0,0 +1,94 @@
+package utils
+
+import "strings"
+
+// StateTransition is a move a state field allows, from one of its states to another
+type StateTransition struct {
+	From string
+	To   string
+}
+
+// parseStates reads the states and transitions of a state field: chains of states separated by
+// ">", several chains separated by commas (e.g., draft>review>published,review>draft). The states
+// are listed in the order they first appear, so the first one is where new items start.
+func parseStates(definition string) ([]string, []StateTransition) {
+	var states []string
+	var transitions []StateTransition
+	seen := map[string]bool{}
+	moves := map[StateTransition]bool{}
+
+	for _, chain := range strings.Split(definition, ",") {
+		previous := ""
+		for _, state := range strings.Split(chain, ">") {
+			state = strings.TrimSpace(state)
+			if state == "" {
+				continue
+			}
+			if !seen[state] {
+				seen[state] = true
+				states = append(states, state)
+			}
+			move := StateTransition{From: previous, To: state}
+			if previous != "" && previous != state && !moves[move] {
+				moves[move] = true
+				transitions = append(transitions, move)
+			}
+			previous = state
+		}
+	}
+	return states, transitions
+}
+
+// StateDefinition returns the definition parseStates reads the field's states back from, one
+// chain per transition (e.g., draft>review,review>published)
+func (f Field) StateDefinition() string {
+	var chains []string
+	linked := map[string]bool{}
+	for _, transition := range f.Transitions {
+		chains = append(chains, transition.From+">"+transition.To)
+		linked[transition.From], linked[transition.To] = true, true
+	}
+	for _, state := range f.Options {
+		if !linked[state] {
+			chains = append(chains, state)
+		}
+	}
+	return strings.Join(chains, ",")
+}
+
+// TransitionsFrom returns the states a state field can move to from state
+func (f Field) TransitionsFrom(state string) []string {
+	var next []string
+	for _, transition := range f.Transitions {
+		if transition.From == state {
+			next = append(next, transition.To)
+		}
+	}
+	return next
+}
+
+// BlockedFrom returns a state a state field can't move to from state, or "" when it can move to
+// every other state
+func (f Field) BlockedFrom(state string) string {
+	allowed := map[string]bool{state: true}
+	for _, next := range f.TransitionsFrom(state) {
+		allowed[next] = true
+	}
+	for _, option := range f.Options {
+		if !allowed[option] {
+			return option
+		}
+	}
+	return ""
+}
+
+// StateFields returns the state fields of a model
+func StateFields(fields []Field) []Field {
+	var states []Field
+	for _, field := range fields {
+		if field.IsState {
+			states = append(states, field)
+		}
+	}
+	return states
+}
@@ -1,10 +1,12 @@
 package utils
 
 import (
+	"bytes"
 	_ "embed"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 )
@@ -24,6 +26,93 @@ var moduleTemplate string
 //go:embed templates/validator.tmpl
 var validatorTemplate string
 
+//go:embed templates/test.tmpl
+var testTemplate string
+
+//go:embed templates/seed.tmpl
+var seedTemplate string
+
+//go:embed templates/realtime_hub.tmpl
+var realtimeHubTemplate string
+
+//go:embed templates/realtime_module.tmpl
+var realtimeModuleTemplate string
+
+//go:embed templates/auditlog_entry.tmpl
+var auditlogEntryTemplate string
+
+//go:embed templates/auditlog_module.tmpl
+var auditlogModuleTemplate string
+
+//go:embed templates/policy.tmpl
+var policyTemplate string
+
+//go:embed templates/import_export.tmpl
+var importExportTemplate string
+
+//go:embed templates/pdf.tmpl
+var pdfTemplate string
+
+//go:embed templates/throttle.tmpl
+var throttleTemplate string
+
+//go:embed templates/ratelimit.tmpl
+var ratelimitTemplate string
+
+//go:embed templates/httpcache.tmpl
+var httpcacheTemplate string
+
+//go:embed templates/rules.tmpl
+var rulesTemplate string
+
+//go:embed templates/rules_custom.tmpl
+var rulesCustomTemplate string
+
+//go:embed templates/bulk.tmpl
+var bulkTemplate string
+
+//go:embed templates/search.tmpl
+var searchTemplate string
+
+//go:embed templates/geo.tmpl
+var geoTemplate string
+
+//go:embed templates/stats.tmpl
+var statsTemplate string
+
+//go:embed templates/report.tmpl
+var reportTemplate string
+
+//go:embed templates/notifications/notification.go.tmpl
+var notificationsNotificationTemplate string
+
+//go:embed templates/notifications/controller.go.tmpl
+var notificationsControllerTemplate string
+
+//go:embed templates/notifications/module.go.tmpl
+var notificationsModuleTemplate string
+
+//go:embed templates/state.tmpl
+var stateTemplate string
+
+//go:embed templates/revision.tmpl
+var revisionTemplate string
+
+//go:embed templates/lock.tmpl
+var lockTemplate string
+
+//go:embed templates/pivot.tmpl
+var pivotTemplate string
+
+//go:embed templates/encryption.tmpl
+var encryptionTemplate string
+
+//go:embed templates/nested.tmpl
+var nestedTemplate string
+
+//go:embed templates/media.tmpl
+var mediaTemplate string
+
 // Nuxt templates
 //go:embed templates/nuxt/module.config.ts.tmpl
 var nuxtModuleConfigTemplate string
@@ -49,6 +138,376 @@ var nuxtIndexTemplate string
 //go:embed templates/nuxt/detail.vue.tmpl
 var nuxtDetailTemplate string
 
+//go:embed templates/nuxt/api-client.ts.tmpl
+var nuxtAPIClientTemplate string
+
+//go:embed templates/nuxt/realtime.ts.tmpl
+var nuxtRealtimeTemplate string
+
+//go:embed templates/nuxt/abilities.ts.tmpl
+var nuxtAbilitiesTemplate string
+
+//go:embed templates/nuxt/policy-middleware.ts.tmpl
+var nuxtPolicyMiddlewareTemplate string
+
+//go:embed templates/nuxt/organization.ts.tmpl
+var nuxtOrganizationTemplate string
+
+//go:embed templates/nuxt/activity.vue.tmpl
+var nuxtActivityTemplate string
+
+//go:embed templates/nuxt/revisions.vue.tmpl
+var nuxtRevisionsTemplate string
+
+//go:embed templates/nuxt/pivot.vue.tmpl
+var nuxtPivotTemplate string
+
+//go:embed templates/nuxt/view-switcher.vue.tmpl
+var nuxtViewSwitcherTemplate string
+
+//go:embed templates/nuxt/stat-widget.vue.tmpl
+var nuxtStatWidgetTemplate string
+
+//go:embed templates/nuxt/chart-widget.vue.tmpl
+var nuxtChartWidgetTemplate string
+
+//go:embed templates/nuxt/recent-widget.vue.tmpl
+var nuxtRecentWidgetTemplate string
+
+//go:embed templates/nuxt/dashboard-widgets.vue.tmpl
+var nuxtDashboardWidgetsTemplate string
+
+//go:embed templates/nuxt/report.vue.tmpl
+var nuxtReportTemplate string
+
+//go:embed templates/nuxt/notifications/store.ts.tmpl
+var nuxtNotificationsStoreTemplate string
+
+//go:embed templates/nuxt/notifications/bell.vue.tmpl
+var nuxtNotificationsBellTemplate string
+
+//go:embed templates/nuxt/notifications/page.vue.tmpl
+var nuxtNotificationsPageTemplate string
+
+//go:embed templates/settings/setting.go.tmpl
+var settingsSettingTemplate string
+
+//go:embed templates/settings/definitions.go.tmpl
+var settingsDefinitionsTemplate string
+
+//go:embed templates/settings/service.go.tmpl
+var settingsServiceTemplate string
+
+//go:embed templates/settings/controller.go.tmpl
+var settingsControllerTemplate string
+
+//go:embed templates/settings/module.go.tmpl
+var settingsModuleTemplate string
+
+//go:embed templates/nuxt/settings/store.ts.tmpl
+var nuxtSettingsStoreTemplate string
+
+//go:embed templates/nuxt/settings/page.vue.tmpl
+var nuxtSettingsPageTemplate string
+
+//go:embed templates/mail/mailer.go.tmpl
+var mailMailerTemplate string
+
+//go:embed templates/mail/module.go.tmpl
+var mailModuleTemplate string
+
+//go:embed templates/mail/mail.go.tmpl
+var mailMailTemplate string
+
+//go:embed templates/mail/mail.html.tmpl
+var mailHTMLTemplate string
+
+//go:embed templates/mail/mail.txt.tmpl
+var mailTextTemplate string
+
+//go:embed templates/nuxt/kanban.vue.tmpl
+var nuxtKanbanTemplate string
+
+//go:embed templates/nuxt/calendar.vue.tmpl
+var nuxtCalendarTemplate string
+
+//go:embed templates/nuxt/gallery.vue.tmpl
+var nuxtGalleryTemplate string
+
+//go:embed templates/nuxt/mocks.ts.tmpl
+var nuxtMocksTemplate string
+
+//go:embed templates/nuxt/table.stories.ts.tmpl
+var nuxtTableStoriesTemplate string
+
+//go:embed templates/nuxt/form-modal.stories.ts.tmpl
+var nuxtFormModalStoriesTemplate string
+
+//go:embed templates/nuxt/store.test.ts.tmpl
+var nuxtStoreTestTemplate string
+
+//go:embed templates/nuxt/form-modal.test.ts.tmpl
+var nuxtFormModalTestTemplate string
+
+//go:embed templates/nuxt/list.test.ts.tmpl
+var nuxtListTestTemplate string
+
+//go:embed templates/nuxt/vitest.config.ts.tmpl
+var nuxtVitestConfigTemplate string
+
+//go:embed templates/nuxt/e2e.spec.ts.tmpl
+var nuxtE2ESpecTemplate string
+
+//go:embed templates/nuxt/e2e-auth.setup.ts.tmpl
+var nuxtE2EAuthSetupTemplate string
+
+//go:embed templates/nuxt/playwright.config.ts.tmpl
+var nuxtPlaywrightConfigTemplate string
+
+//go:embed templates/nuxt/import-modal.vue.tmpl
+var nuxtImportModalTemplate string
+
+//go:embed templates/nuxt/relation-select.vue.tmpl
+var nuxtRelationSelectTemplate string
+
+//go:embed templates/nuxt/upload.ts.tmpl
+var nuxtUploadTemplate string
+
+//go:embed templates/nuxt/file-upload.vue.tmpl
+var nuxtFileUploadTemplate string
+
+//go:embed templates/nuxt/json-editor.vue.tmpl
+var nuxtJSONEditorTemplate string
+
+//go:embed templates/nuxt/json-view.vue.tmpl
+var nuxtJSONViewTemplate string
+
+//go:embed templates/nuxt/money-input.vue.tmpl
+var nuxtMoneyInputTemplate string
+
+//go:embed templates/nuxt/rules.ts.tmpl
+var nuxtRulesTemplate string
+
+//go:embed templates/nuxt/custom-rules.ts.tmpl
+var nuxtCustomRulesTemplate string
+
+//go:embed templates/nuxt/leaflet.ts.tmpl
+var nuxtLeafletTemplate string
+
+//go:embed templates/nuxt/map-picker.vue.tmpl
+var nuxtMapPickerTemplate string
+
+//go:embed templates/nuxt/map-view.vue.tmpl
+var nuxtMapViewTemplate string
+
+//go:embed templates/nuxt/secret-value.vue.tmpl
+var nuxtSecretValueTemplate string
+
+//go:embed templates/auth/module.go.tmpl
+var authModuleTemplate string
+
+//go:embed templates/auth/session.go.tmpl
+var authSessionTemplate string
+
+//go:embed templates/auth/oauth.go.tmpl
+var authOAuthTemplate string
+
+//go:embed templates/auth/magic_link.go.tmpl
+var authMagicLinkTemplate string
+
+//go:embed templates/auth/totp.go.tmpl
+var authTOTPTemplate string
+
+//go:embed templates/nuxt/auth/store.ts.tmpl
+var nuxtAuthStoreTemplate string
+
+//go:embed templates/nuxt/auth/callback.vue.tmpl
+var nuxtAuthCallbackTemplate string
+
+//go:embed templates/nuxt/auth/magic-link.vue.tmpl
+var nuxtAuthMagicLinkTemplate string
+
+//go:embed templates/nuxt/auth/two-factor.vue.tmpl
+var nuxtAuthTwoFactorTemplate string
+
+//go:embed templates/nuxt/auth/security.vue.tmpl
+var nuxtAuthSecurityTemplate string
+
+//go:embed templates/nuxt/auth/buttons.vue.tmpl
+var nuxtAuthButtonsTemplate string
+
+// embeddedTemplates maps template names to their embedded content
+var embeddedTemplates = map[string]string{
+	"model.tmpl":                      modelTemplate,
+	"controller.tmpl":                 controllerTemplate,
+	"service.tmpl":                    serviceTemplate,
+	"module.tmpl":                     moduleTemplate,
+	"validator.tmpl":                  validatorTemplate,
+	"test.tmpl":                       testTemplate,
+	"seed.tmpl":                       seedTemplate,
+	"realtime_hub.tmpl":               realtimeHubTemplate,
+	"realtime_module.tmpl":            realtimeModuleTemplate,
+	"auditlog_entry.tmpl":             auditlogEntryTemplate,
+	"auditlog_module.tmpl":            auditlogModuleTemplate,
+	"policy.tmpl":                     policyTemplate,
+	"import_export.tmpl":              importExportTemplate,
+	"pdf.tmpl":                        pdfTemplate,
+	"throttle.tmpl":                   throttleTemplate,
+	"ratelimit.tmpl":                  ratelimitTemplate,
+	"httpcache.tmpl":                  httpcacheTemplate,
+	"rules.tmpl":                      rulesTemplate,
+	"rules_custom.tmpl":               rulesCustomTemplate,
+	"bulk.tmpl":                       bulkTemplate,
+	"search.tmpl":                     searchTemplate,
+	"geo.tmpl":                        geoTemplate,
+	"stats.tmpl":                      statsTemplate,
+	"report.tmpl":                     reportTemplate,
+	"state.tmpl":                      stateTemplate,
+	"revision.tmpl":                   revisionTemplate,
+	"lock.tmpl":                       lockTemplate,
+	"pivot.tmpl":                      pivotTemplate,
+	"encryption.tmpl":                 encryptionTemplate,
+	"nested.tmpl":                     nestedTemplate,
+	"media.tmpl":                      mediaTemplate,
+	"nuxt/module.config.ts.tmpl":      nuxtModuleConfigTemplate,
+	"nuxt/types.ts.tmpl":              nuxtTypesTemplate,
+	"nuxt/store.ts.tmpl":              nuxtStoreTemplate,
+	"nuxt/table.vue.tmpl":             nuxtTableTemplate,
+	"nuxt/form-modal.vue.tmpl":        nuxtFormModalTemplate,
+	"nuxt/formatters.ts.tmpl":         nuxtFormattersTemplate,
+	"nuxt/index.vue.tmpl":             nuxtIndexTemplate,
+	"nuxt/detail.vue.tmpl":            nuxtDetailTemplate,
+	"nuxt/api-client.ts.tmpl":         nuxtAPIClientTemplate,
+	"nuxt/realtime.ts.tmpl":           nuxtRealtimeTemplate,
+	"nuxt/abilities.ts.tmpl":          nuxtAbilitiesTemplate,
+	"nuxt/policy-middleware.ts.tmpl":  nuxtPolicyMiddlewareTemplate,
+	"nuxt/activity.vue.tmpl":          nuxtActivityTemplate,
+	"nuxt/revisions.vue.tmpl":         nuxtRevisionsTemplate,
+	"nuxt/pivot.vue.tmpl":             nuxtPivotTemplate,
+	"nuxt/view-switcher.vue.tmpl":     nuxtViewSwitcherTemplate,
+	"nuxt/stat-widget.vue.tmpl":       nuxtStatWidgetTemplate,
+	"nuxt/chart-widget.vue.tmpl":      nuxtChartWidgetTemplate,
+	"nuxt/recent-widget.vue.tmpl":     nuxtRecentWidgetTemplate,
+	"nuxt/dashboard-widgets.vue.tmpl": nuxtDashboardWidgetsTemplate,
+	"nuxt/report.vue.tmpl":            nuxtReportTemplate,
+	"nuxt/kanban.vue.tmpl":            nuxtKanbanTemplate,
+	"nuxt/calendar.vue.tmpl":          nuxtCalendarTemplate,
+	"nuxt/gallery.vue.tmpl":           nuxtGalleryTemplate,
+	"nuxt/mocks.ts.tmpl":              nuxtMocksTemplate,
+	"nuxt/table.stories.ts.tmpl":      nuxtTableStoriesTemplate,
+	"nuxt/form-modal.stories.ts.tmpl": nuxtFormModalStoriesTemplate,
+	"nuxt/store.test.ts.tmpl":         nuxtStoreTestTemplate,
+	"nuxt/form-modal.test.ts.tmpl":    nuxtFormModalTestTemplate,
+	"nuxt/list.test.ts.tmpl":          nuxtListTestTemplate,
+	"nuxt/vitest.config.ts.tmpl":      nuxtVitestConfigTemplate,
+	"nuxt/e2e.spec.ts.tmpl":           nuxtE2ESpecTemplate,
+	"nuxt/e2e-auth.setup.ts.tmpl":     nuxtE2EAuthSetupTemplate,
+	"nuxt/playwright.config.ts.tmpl":  nuxtPlaywrightConfigTemplate,
+	"nuxt/organization.ts.tmpl":       nuxtOrganizationTemplate,
+	"nuxt/import-modal.vue.tmpl":      nuxtImportModalTemplate,
+	"nuxt/relation-select.vue.tmpl":   nuxtRelationSelectTemplate,
+	"nuxt/upload.ts.tmpl":             nuxtUploadTemplate,
+	"nuxt/file-upload.vue.tmpl":       nuxtFileUploadTemplate,
+	"nuxt/json-editor.vue.tmpl":       nuxtJSONEditorTemplate,
+	"nuxt/json-view.vue.tmpl":         nuxtJSONViewTemplate,
+	"nuxt/money-input.vue.tmpl":       nuxtMoneyInputTemplate,
+	"nuxt/rules.ts.tmpl":              nuxtRulesTemplate,
+	"nuxt/custom-rules.ts.tmpl":       nuxtCustomRulesTemplate,
+	"nuxt/leaflet.ts.tmpl":            nuxtLeafletTemplate,
+	"nuxt/map-picker.vue.tmpl":        nuxtMapPickerTemplate,
+	"nuxt/map-view.vue.tmpl":          nuxtMapViewTemplate,
+	"nuxt/secret-value.vue.tmpl":      nuxtSecretValueTemplate,
+	"auth/module.go.tmpl":             authModuleTemplate,
+	"auth/session.go.tmpl":            authSessionTemplate,
+	"auth/oauth.go.tmpl":              authOAuthTemplate,
+	"auth/magic_link.go.tmpl":         authMagicLinkTemplate,
+	"auth/totp.go.tmpl":               authTOTPTemplate,
+	"nuxt/auth/store.ts.tmpl":         nuxtAuthStoreTemplate,
+	"nuxt/auth/callback.vue.tmpl":     nuxtAuthCallbackTemplate,
+	"nuxt/auth/magic-link.vue.tmpl":   nuxtAuthMagicLinkTemplate,
+	"nuxt/auth/two-factor.vue.tmpl":   nuxtAuthTwoFactorTemplate,
+	"nuxt/auth/security.vue.tmpl":     nuxtAuthSecurityTemplate,
+	"nuxt/auth/buttons.vue.tmpl":      nuxtAuthButtonsTemplate,
+
+	"notifications/notification.go.tmpl": notificationsNotificationTemplate,
+	"notifications/controller.go.tmpl":   notificationsControllerTemplate,
+	"notifications/module.go.tmpl":       notificationsModuleTemplate,
+	"nuxt/notifications/store.ts.tmpl":   nuxtNotificationsStoreTemplate,
+	"nuxt/notifications/bell.vue.tmpl":   nuxtNotificationsBellTemplate,
+	"nuxt/notifications/page.vue.tmpl":   nuxtNotificationsPageTemplate,
+	"settings/setting.go.tmpl":           settingsSettingTemplate,
+	"settings/definitions.go.tmpl":       settingsDefinitionsTemplate,
+	"settings/service.go.tmpl":           settingsServiceTemplate,
+	"settings/controller.go.tmpl":        settingsControllerTemplate,
+	"settings/module.go.tmpl":            settingsModuleTemplate,
+	"nuxt/settings/store.ts.tmpl":        nuxtSettingsStoreTemplate,
+	"nuxt/settings/page.vue.tmpl":        nuxtSettingsPageTemplate,
+	"mail/mailer.go.tmpl":                mailMailerTemplate,
+	"mail/module.go.tmpl":                mailModuleTemplate,
+	"mail/mail.go.tmpl":                  mailMailTemplate,
+	"mail/mail.html.tmpl":                mailHTMLTemplate,
+	"mail/mail.txt.tmpl":                 mailTextTemplate,
+}
+
+// TemplateOverrideDir holds project copies of the templates, written by bui template eject.
+// A template found there is used instead of the embedded one.
+var TemplateOverrideDir = filepath.Join(".bui", "templates")
+
+// EmbeddedTemplates returns the names of all embedded templates
+func EmbeddedTemplates() []string {
+	names := make([]string, 0, len(embeddedTemplates))
+	for name := range embeddedTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// EmbeddedTemplate returns the embedded content of a template
+func EmbeddedTemplate(name string) (string, bool) {
+	content, ok := embeddedTemplates[name]
+	return content, ok
+}
+
+// FindTemplateOverrideDir returns the nearest .bui/templates in the working directory or its parents, or ""
+func FindTemplateOverrideDir() string {
+	return findTemplateOverrideDir(".")
+}
+
+// findTemplateOverrideDir is FindTemplateOverrideDir for a generator working in dir
+func findTemplateOverrideDir(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		candidate := filepath.Join(dir, TemplateOverrideDir)
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			return candidate
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// loadTemplate returns a template's project override, found from the directory the generator works
+// in, when there is one, otherwise its embedded content
+func loadTemplate(workDir, name string) (string, error) {
+	content, ok := embeddedTemplates[name]
+	if !ok {
+		return "", fmt.Errorf("unknown template: %s", name)
+	}
+	if dir := findTemplateOverrideDir(workDir); dir != "" {
+		if override, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name))); err == nil {
+			return string(override), nil
+		}
+	}
+	return content, nil
+}
+
 // TemplateData contains all data needed for template generation
 type TemplateData struct {
 	// Naming conventions for the model
@@ -86,19 +545,37 @@ type TemplateData struct {
 
 // NewTemplateData creates template data from model name and field definitions
 func NewTemplateData(modelName string, fieldDefs []string) *TemplateData {
+	return NewTableTemplateData(modelName, TableOverride, fieldDefs)
+}
+
+// NewTableTemplateData is NewTemplateData for a model stored in table, "" being its default table
+func NewTableTemplateData(modelName, table string, fieldDefs []string) *TemplateData {
 	nc := NewNamingConvention(modelName)
+	if table != "" {
+		nc.TableName = table
+	}
 	td := &TemplateData{
 		NamingConvention: nc,
 		Fields:           []Field{},
 		Imports:          []string{},
+		HasSoftDelete:    !NoSoftDelete,
 	}
 
 	// Generate field structs using centralized parsing
+	parsed := make([]Field, 0, len(fieldDefs))
 	for _, fieldDef := range fieldDefs {
-		field := ParseField(fieldDef)
+		parsed = append(parsed, ParseField(fieldDef))
+	}
 
+	for _, field := range ResolveSelfRelations(nc.Model, parsed) {
 		// Handle belongsTo relationships - need both foreign key and relationship object
 		if field.Relationship == "belongs_to" {
+			// A parent of the same model shares its key type
+			if field.IsSelfRef && UUIDKey() {
+				field.Type = IDType()
+				addGORMSetting(&field, "type:char(36)")
+			}
+
 			// Add the foreign key field
 			td.Fields = append(td.Fields, field)
 
@@ -118,7 +595,7 @@ func NewTemplateData(modelName string, fieldDefs []string) *TemplateData {
 				RelationType: "belongs_to_object",
 			}
 			td.Fields = append(td.Fields, relationField)
-		} else if field.IsMedia {
+		} else if field.IsMedia && !field.IsMediaList {
 			// Handle media fields - need both foreign key and media object
 			// Add the foreign key field (e.g., ImageId)
 			fkField := Field{
@@ -138,7 +615,21 @@ func NewTemplateData(modelName string, fieldDefs []string) *TemplateData {
 
 			// Add the media relation field (e.g., Image)
 			td.Fields = append(td.Fields, field)
+		} else if field.Relationship == "morph_to" {
+			// Polymorphic owner columns share one composite index (e.g., idx_comments_commentable)
+			for _, column := range ExpandMorphTo(field) {
+				column.GORMTag = fmt.Sprintf(`gorm:"index:idx_%s_%s"`, nc.TableName, ToSnakeCase(field.MorphName))
+				column.GORM = column.GORMTag
+				td.Fields = append(td.Fields, column)
+			}
+		} else if field.IsPoint {
+			td.Fields = append(td.Fields, ExpandPoint(field)...)
 		} else {
+			// Enums get a model-scoped string type (e.g., PostStatus) for their constants
+			if field.IsEnum {
+				field.EnumType = nc.Model + field.Name
+				field.Type = field.EnumType
+			}
 			td.Fields = append(td.Fields, field)
 		}
 
@@ -146,6 +637,10 @@ func NewTemplateData(modelName string, fieldDefs []string) *TemplateData {
 		td.updateComputedProperties(field)
 	}
 
+	td.applyUniqueIndexes()
+	ApplyPayloadFields(td.Fields)
+	td.applyDatabaseTags()
+
 	// Add standard imports
 	td.addStandardImports()
 
@@ -156,10 +651,10 @@ func NewTemplateData(modelName string, fieldDefs []string) *TemplateData {
 }
 
 // setDisplayField determines the display field for this model
-// Uses the first string-type field that's not a relation
+// Uses the first string-type field that's not a relation, encrypted or computed
 func (td *TemplateData) setDisplayField() {
 	for _, field := range td.Fields {
-		if !field.IsRelation && !field.IsMediaFK && (field.Type == "string" || field.Type == "translation.Field") {
+		if !field.IsRelation && !field.IsMediaFK && !field.IsEncrypted && !field.IsComputed && (field.Type == "string" || field.Type == "translation.Field") {
 			td.DisplayField = field.JSONName
 			return
 		}
@@ -215,6 +710,7 @@ func (td *TemplateData) updateComputedProperties(field Field) {
 // addStandardImports adds standard imports based on fields
 func (td *TemplateData) addStandardImports() {
 	imports := make(map[string]bool)
+	moduleName := GetGoModuleName()
 
 	// Always needed
 	imports["time"] = true
@@ -228,15 +724,15 @@ func (td *TemplateData) addStandardImports() {
 		case "datatypes.JSON":
 			imports["gorm.io/datatypes"] = true
 		case "*storage.Attachment":
-			imports["base/core/storage"] = true
+			imports[moduleName+"/core/storage"] = true
 		case "translation.Field":
-			imports["base/core/translation"] = true
+			imports[moduleName+"/core/translation"] = true
 		case "media.Media":
-			imports["base/core/media"] = true
+			imports[moduleName+"/core/media"] = true
 		}
 		// Check for media fields
 		if field.IsMedia {
-			imports["base/core/app/media"] = true
+			imports[moduleName+"/core/app/media"] = true
 		}
 	}
 
@@ -272,6 +768,30 @@ func HasMediaField(fields []Field) bool {
 	return false
 }
 
+// HasMediaList checks if any field is a media[] list
+func HasMediaList(fields []Field) bool {
+	for _, field := range fields {
+		if field.IsMediaList {
+			return true
+		}
+	}
+	return false
+}
+
+// RelatedModels returns the distinct models referenced by relation fields, excluding the model itself
+func RelatedModels(fields []Field, model string) []string {
+	var related []string
+	seen := map[string]bool{model: true}
+	for _, field := range fields {
+		if !field.IsRelation || field.RelatedModel == "" || seen[field.RelatedModel] {
+			continue
+		}
+		seen[field.RelatedModel] = true
+		related = append(related, field.RelatedModel)
+	}
+	return related
+}
+
 // Singularize converts plural to singular (basic implementation)
 func Singularize(word string) string {
 	if strings.HasSuffix(word, "ies") {
@@ -396,9 +916,325 @@ func inferFieldTypeCompat(fieldName string) string {
 	return "string"
 }
 
+// PrimaryKey selects the primary key of generated models: "uint" (auto-increment) or "uuid" (--pk)
+var PrimaryKey = "uint"
+
+// UUIDKey reports whether generated models use UUID primary keys
+func UUIDKey() bool {
+	return PrimaryKey == "uuid"
+}
+
+// CheckPrimaryKey reports an unsupported --pk value
+func CheckPrimaryKey() error {
+	if PrimaryKey != "uint" && PrimaryKey != "uuid" {
+		return fmt.Errorf("unknown primary key type %q (use uint or uuid)", PrimaryKey)
+	}
+	return nil
+}
+
+// IDType returns the Go type of generated primary keys
+func IDType() string {
+	if UUIDKey() {
+		return "uuid.UUID"
+	}
+	return "uint"
+}
+
+// NoSoftDelete omits gorm.DeletedAt from generated models (--no-soft-delete)
+var NoSoftDelete bool
+
+// Audit adds created_by/updated_by columns filled from the authenticated user (--audit)
+var Audit bool
+
+// Realtime publishes create/update/delete events to websocket clients (--realtime)
+var Realtime bool
+
+// Tenant scopes generated modules to the organization of the request (--tenant)
+var Tenant bool
+
+// Audited records the changes made through generated services in the audit_logs table (--audited)
+var Audited bool
+
+// Versioned keeps a revision of generated models on every update, with a restore endpoint (--versioned)
+var Versioned bool
+
+// LockVersion adds a lock_version column that updates compare and swap, answering 409 on stale updates (--lock-version)
+var LockVersion bool
+
+// ImportExport adds CSV/XLSX export and import endpoints to generated modules (--import-export)
+var ImportExport bool
+
+// PDF adds a PDF download of each record to generated modules (--pdf)
+var PDF bool
+
+// RateLimit limits the requests each client makes to generated routes, e.g. 100/min (--rate-limit)
+var RateLimit string
+
+// Cache keeps the responses of generated read routes for a duration, e.g. 60s (--cache)
+var Cache string
+
+// Bulk adds multi-row selection, bulk delete and bulk status updates to generated modules (--bulk)
+var Bulk bool
+
+// TableOverride replaces the model's default table name (--table)
+var TableOverride string
+
+// UniqueIndexes lists composite unique indexes, each a comma-separated list of columns (--unique)
+var UniqueIndexes []string
+
+// applyUniqueIndexes tags every column of each --unique index with a shared uniqueIndex name
+// (e.g., name,tenant_id becomes uniqueIndex:idx_products_name_tenant_id on both columns)
+func (td *TemplateData) applyUniqueIndexes() {
+	for _, index := range UniqueIndexes {
+		// Unknown columns are reported by UnknownIndexColumns and left out of the index
+		var columns []string
+		var targets []int
+		for _, column := range splitColumns(index) {
+			for i := range td.Fields {
+				if isColumn(td.Fields[i], column) {
+					columns = append(columns, column)
+					targets = append(targets, i)
+					break
+				}
+			}
+		}
+		if len(columns) == 0 {
+			continue
+		}
+
+		setting := fmt.Sprintf("uniqueIndex:idx_%s_%s", td.TableName, strings.Join(columns, "_"))
+		for _, i := range targets {
+			addGORMSetting(&td.Fields[i], setting)
+		}
+	}
+}
+
+// UnknownIndexColumns returns the --unique columns that don't match any generated column
+func UnknownIndexColumns(fields []Field) []string {
+	var unknown []string
+	for _, index := range UniqueIndexes {
+		for _, column := range splitColumns(index) {
+			found := false
+			for _, field := range fields {
+				if isColumn(field, column) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				unknown = append(unknown, column)
+			}
+		}
+	}
+	return unknown
+}
+
+// splitColumns splits a comma-separated column list into snake_case column names
+func splitColumns(list string) []string {
+	var columns []string
+	for _, column := range strings.Split(list, ",") {
+		if column = strings.TrimSpace(column); column != "" {
+			columns = append(columns, ToSnakeCase(column))
+		}
+	}
+	return columns
+}
+
+// isColumn reports whether field is stored in the given database column
+func isColumn(field Field, column string) bool {
+	if field.IsRelation && field.Relationship != "belongs_to" {
+		return false
+	}
+	return field.DBName == column
+}
+
+// addGORMSetting appends a setting to the field's gorm tag, creating the tag if needed
+func addGORMSetting(field *Field, setting string) {
+	if field.GORMTag == "" {
+		field.GORMTag = fmt.Sprintf(`gorm:"%s"`, setting)
+	} else {
+		field.GORMTag = strings.TrimSuffix(field.GORMTag, `"`) + ";" + setting + `"`
+	}
+	field.GORM = field.GORMTag
+}
+
+// Nested scopes a module's list/create routes and admin list page under its first belongs_to parent
+var Nested bool
+
+// NestedParent describes the parent resource a nested module is generated under
+type NestedParent struct {
+	*NamingConvention
+	Param  string // Route parameter and foreign key column (e.g., "post_id")
+	FKName string // Foreign key field on the child model (e.g., "PostId")
+}
+
+// NestedParentFor returns the parent of a nested module, or nil when Nested is off or no belongs_to field exists
+func NestedParentFor(fields []Field) *NestedParent {
+	if !Nested {
+		return nil
+	}
+	for _, field := range fields {
+		if field.Relationship == "belongs_to" && field.RelatedModel != "" && !field.IsSelfRef {
+			return &NestedParent{
+				NamingConvention: NewNamingConvention(field.RelatedModel),
+				Param:            field.JSONName,
+				FKName:           field.Name,
+			}
+		}
+	}
+	return nil
+}
+
+// testValuesFor returns Go literals used by the generated tests for create and update requests.
+// Fields whose request type can't be expressed as a simple literal get empty values and are skipped.
+func testValuesFor(field Field) (string, string) {
+	if field.IsRelation || field.IsMedia || field.IsAttachment || field.IsTranslation || field.IsComputed {
+		return "", ""
+	}
+
+	// State fields start in their first state and update along its first transition
+	if field.IsState && len(field.Options) > 0 {
+		next := field.TransitionsFrom(field.Options[0])
+		if len(next) == 0 {
+			return fmt.Sprintf("%q", field.Options[0]), ""
+		}
+		return fmt.Sprintf("%q", field.Options[0]), fmt.Sprintf("%q", next[0])
+	}
+
+	if field.IsSelect && field.Type == "string" && len(field.Options) > 0 {
+		return fmt.Sprintf("%q", field.Options[0]), fmt.Sprintf("%q", field.Options[len(field.Options)-1])
+	}
+
+	switch field.Type {
+	case "string", "text", "email":
+		return fmt.Sprintf("%q", "Test "+field.Name), fmt.Sprintf("%q", "Updated "+field.Name)
+	case "int", "uint":
+		return "1", "2"
+	case "int64":
+		// Money fields, in minor units
+		return "1999", "2499"
+	case "float64":
+		return "1.5", "2.5"
+	case "bool":
+		// Update requests use *bool, so only the create value is a plain literal
+		return "true", ""
+	}
+
+	return "", ""
+}
+
+// seedValueFor returns a Go expression producing fake data for a field in the generated seed.go.
+// The expression may use the row index i and the random source rng; unsupported fields return "".
+func seedValueFor(field Field) string {
+	if field.IsRelation || field.IsMedia || field.IsMediaFK || field.IsAttachment || field.IsTranslation {
+		return ""
+	}
+
+	// Checkbox fields store a JSON array and are left empty
+	if field.IsSelect && field.SelectType != "checkbox" && len(field.Options) > 0 {
+		options := make([]string, len(field.Options))
+		for i, option := range field.Options {
+			options[i] = fmt.Sprintf("%q", option)
+		}
+		value := fmt.Sprintf("seedPick(rng, %s)", strings.Join(options, ", "))
+		if field.IsEnum {
+			return fmt.Sprintf("models.%s(%s)", field.EnumType, value)
+		}
+		return value
+	}
+
+	name := strings.ToLower(field.JSONName)
+	has := func(words ...string) bool {
+		for _, word := range words {
+			if strings.Contains(name, word) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var value string
+	switch field.Type {
+	case "string":
+		switch {
+		case has("email"):
+			return `fmt.Sprintf("user%d@example.com", i+1)`
+		case has("url", "link", "website"):
+			return `fmt.Sprintf("https://example.com/%d", i+1)`
+		case has("slug"):
+			return `fmt.Sprintf("item-%d", i+1)`
+		case has("password"):
+			return `"password"`
+		case has("phone", "mobile"):
+			value = `fmt.Sprintf("+1 555 %04d", rng.Intn(10000))`
+		case has("first_name"):
+			value = "seedPick(rng, seedFirstNames...)"
+		case has("last_name"):
+			value = "seedPick(rng, seedLastNames...)"
+		case has("full_name", "author", "username"):
+			value = `seedPick(rng, seedFirstNames...) + " " + seedPick(rng, seedLastNames...)`
+		case has("city"):
+			value = "seedPick(rng, seedCities...)"
+		case has("address", "street"):
+			value = `fmt.Sprintf("%d %s Street", rng.Intn(900)+100, seedPick(rng, seedLastNames...))`
+		case has("description", "content", "body", "bio", "summary", "note", "text", "comment"):
+			value = "seedSentence(rng, 12)"
+		default:
+			value = "seedTitle(rng, 3)"
+		}
+		if field.IsUnique {
+			return fmt.Sprintf(`fmt.Sprintf("%%s %%d", %s, i+1)`, value)
+		}
+		return value
+	case "int", "uint":
+		switch {
+		case has("price", "amount", "cost", "total"):
+			value = "rng.Intn(10000) + 100"
+		case has("year"):
+			value = "2000 + rng.Intn(25)"
+		case has("age"):
+			value = "18 + rng.Intn(60)"
+		default:
+			value = "rng.Intn(100)"
+		}
+		if field.Type == "uint" {
+			return fmt.Sprintf("uint(%s)", value)
+		}
+		return value
+	case "int64":
+		// Money fields, in minor units
+		return "int64(rng.Intn(100000) + 100)"
+	case "decimal.Decimal":
+		return fmt.Sprintf("decimal.New(int64(rng.Intn(100000)+100), -%d)", min(field.Scale, 2))
+	case "float64":
+		switch {
+		case has("price", "amount", "cost", "total"):
+			return "float64(rng.Intn(100000)+100) / 100"
+		case has("rating", "score"):
+			return "float64(rng.Intn(41)+10) / 10"
+		case has("lat"):
+			return "rng.Float64()*180 - 90"
+		case has("lng", "lon"):
+			return "rng.Float64()*360 - 180"
+		}
+		return "rng.Float64() * 100"
+	case "bool":
+		return "rng.Intn(2) == 0"
+	case "time.Time":
+		return "time.Now().AddDate(0, 0, -rng.Intn(365))"
+	}
+
+	return ""
+}
+
 // GetGoModuleName reads the Go module name from go.mod file
 func GetGoModuleName() string {
-	content, err := os.ReadFile("go.mod")
+	return GetGoModuleNameIn(".")
+}
+
+// GetGoModuleNameIn reads the Go module name from the go.mod file in dir
+func GetGoModuleNameIn(dir string) string {
+	content, err := os.ReadFile(filepath.Join(dir, "go.mod"))
 	if err != nil {
 		return "base" // fallback to default
 	}
@@ -416,24 +1252,42 @@ func GetGoModuleName() string {
 
 // GenerateFileFromTemplate generates a file from embedded template (for backward compatibility)
 func GenerateFileFromTemplate(dir, filename, templateName string, naming *NamingConvention, fields []Field) {
-	// Convert Field slice to embedded template data
-	var tmplContent string
-	switch templateName {
-	case "model.tmpl":
-		tmplContent = modelTemplate
-	case "controller.tmpl":
-		tmplContent = controllerTemplate
-	case "service.tmpl":
-		tmplContent = serviceTemplate
-	case "module.tmpl":
-		tmplContent = moduleTemplate
-	case "validator.tmpl":
-		tmplContent = validatorTemplate
-	default:
-		fmt.Printf("Unknown template: %s\n", templateName)
+	defaultWriter.GenerateFileFromTemplate(dir, filename, templateName, naming, fields)
+}
+
+// GenerateFileFromTemplate is GenerateFileFromTemplate for the generator of w; dir includes w.Dir
+func (w *Writer) GenerateFileFromTemplate(dir, filename, templateName string, naming *NamingConvention, fields []Field) {
+	content, err := RenderTemplateIn(w.Dir, templateName, naming, fields)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if err := w.WriteGeneratedFile(filepath.Join(dir, filename), content); err != nil {
+		fmt.Printf("Error writing file: %v\n", err)
 		return
 	}
 
+	// Logging is handled by the caller (generate commands)
+}
+
+// RenderTemplate executes an embedded backend template for a model and its fields
+func RenderTemplate(templateName string, naming *NamingConvention, fields []Field) ([]byte, error) {
+	return RenderTemplateIn(".", templateName, naming, fields)
+}
+
+// RenderTemplateIn is RenderTemplate for the backend in dir: the module name, policy and nested
+// form children are read from there
+func RenderTemplateIn(dir, templateName string, naming *NamingConvention, fields []Field) ([]byte, error) {
+	// Get the template content, preferring a project override
+	if strings.HasPrefix(templateName, "nuxt/") {
+		return nil, fmt.Errorf("unknown template: %s", templateName)
+	}
+	tmplContent, err := loadTemplate(dir, templateName)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create template with functions
 	funcMap := template.FuncMap{
 		"toLower":      strings.ToLower,
@@ -460,29 +1314,26 @@ func GenerateFileFromTemplate(dir, filename, templateName string, naming *Naming
 		"hasField": func(fields []Field, fieldType string) bool {
 			return HasFieldType(fields, fieldType)
 		},
+		"seedValue":    seedValueFor,
+		"importColumn": importColumnFor,
+		"pdfRow":       pdfRowFor,
+	}
+	for name, fn := range payloadFuncs {
+		funcMap[name] = fn
 	}
 
 	tmpl, err := template.New(templateName).Funcs(funcMap).Parse(tmplContent)
 	if err != nil {
-		fmt.Printf("Error parsing template %s: %v\n", templateName, err)
-		return
+		return nil, fmt.Errorf("error parsing template %s: %w", templateName, err)
 	}
 
-	// Create output directory
-	err = os.MkdirAll(dir, 0755)
-	if err != nil {
-		fmt.Printf("Error creating directory: %v\n", err)
-		return
-	}
+	// generateBackendModule has checked the flags, so errors here leave the features off
+	rateLimit, _ := ParseRate(RateLimit)
+	cacheTTL, _ := CacheTTL(Cache)
 
-	// Create output file
-	outputFile := filepath.Join(dir, filename)
-	f, err := os.Create(outputFile)
-	if err != nil {
-		fmt.Printf("Error creating file %s: %v\n", outputFile, err)
-		return
-	}
-	defer f.Close()
+	// Computed fields have no column, so templates only see them through Computed
+	computed := ComputedFields(fields)
+	fields = StoredFields(fields)
 
 	// Execute template with data structure
 	data := struct {
@@ -491,6 +1342,7 @@ func GenerateFileFromTemplate(dir, filename, templateName string, naming *Naming
 		Fields                []Field
 		HasImageField         bool
 		HasMediaField         bool
+		HasMediaList          bool
 		HasTranslatableFields bool
 		HasSoftDelete         bool
 		HasTimestamps         bool
@@ -500,14 +1352,43 @@ func GenerateFileFromTemplate(dir, filename, templateName string, naming *Naming
 		HasHasMany            bool
 		HasHasOne             bool
 		HasManyToMany         bool
+		RelatedModels         []string
+		Parent                *NestedParent
+		IDType                string
+		UUIDKey               bool
+		HasAudit              bool
+		Realtime              bool
+		Policy                bool
+		Tenant                bool
+		Audited               bool
+		Versioned             bool
+		LockVersion           bool
+		ImportExport          bool
+		PDF                   bool
+		RateLimit             *Rate  // nil without --rate-limit
+		CacheTTL              string // Go duration of --cache, "" without it
+		Bulk                  bool
+		BulkStatus            *Field // Select field bulk updates set, nil for bulk delete only
+		ListFilters           []ListFilter
+		SearchColumns         []string
+		FullText              []Field
+		NestedForms           []NestedForm
+		ChildLists            []Field
+		Pivots                []Field
+		GeoPoints             []GeoPoint
+		Encrypted             []Field
+		States                []Field
+		Computed              []Field
+		HasRules              bool // Writable fields with rules= modifiers, checked with app/rules
 	}{
 		NamingConvention:      naming,
-		ModuleName:            GetGoModuleName(),
+		ModuleName:            GetGoModuleNameIn(dir),
 		Fields:                fields,
 		HasImageField:         HasImageField(fields),
 		HasMediaField:         HasMediaField(fields),
+		HasMediaList:          HasMediaList(fields),
 		HasTranslatableFields: HasFieldType(fields, "translation.Field"),
-		HasSoftDelete:         HasFieldType(fields, "gorm.DeletedAt"),
+		HasSoftDelete:         !NoSoftDelete,
 		HasTimestamps:         HasFieldType(fields, "time.Time"),
 		HasAttachments:        HasFieldType(fields, "*storage.Attachment"),
 		HasRelations:          HasFieldType(fields, "*models."),
@@ -515,39 +1396,94 @@ func GenerateFileFromTemplate(dir, filename, templateName string, naming *Naming
 		HasHasMany:            HasFieldType(fields, "hasMany"),
 		HasHasOne:             HasFieldType(fields, "hasOne"),
 		HasManyToMany:         HasFieldType(fields, "manyToMany"),
+		RelatedModels:         RelatedModels(fields, naming.Model),
+		Parent:                NestedParentFor(fields),
+		IDType:                IDType(),
+		UUIDKey:               UUIDKey(),
+		HasAudit:              Audit,
+		Realtime:              Realtime,
+		Policy:                hasPolicyIn(dir, naming),
+		Tenant:                Tenant,
+		Audited:               Audited,
+		Versioned:             Versioned,
+		LockVersion:           LockVersion,
+		ImportExport:          ImportExport,
+		PDF:                   PDF,
+		RateLimit:             rateLimit,
+		CacheTTL:              cacheTTL,
+		Bulk:                  Bulk,
+		BulkStatus:            bulkStatusField(fields),
+		ListFilters:           ListFilters(fields),
+		SearchColumns:         SearchColumns(fields),
+		FullText:              fullTextFields(fields),
+		NestedForms:           nestedFormsIn(dir, naming.Model, fields),
+		ChildLists:            childLists(fields),
+		Pivots:                PivotRelations(fields),
+		GeoPoints:             GeoPoints(fields),
+		Encrypted:             EncryptedFields(fields),
+		States:                StateFields(fields),
+		Computed:              computed,
+		HasRules:              HasRules(fieldsWithout(fields, Field.Unwritable)),
 	}
 
-	if err := tmpl.Execute(f, data); err != nil {
-		fmt.Printf("Error executing template: %v\n", err)
-		return
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("error executing template: %w", err)
 	}
 
-	// Logging is handled by the caller (generate commands)
+	return buf.Bytes(), nil
 }
 
 // GenerateNuxtFile generates a Nuxt/TypeScript file from a template
 func GenerateNuxtFile(dir, filename, templateName string, data interface{}) error {
-	// Get the embedded template content based on template name
-	var templateContent string
-	switch templateName {
-	case "nuxt/module.config.ts.tmpl":
-		templateContent = nuxtModuleConfigTemplate
-	case "nuxt/types.ts.tmpl":
-		templateContent = nuxtTypesTemplate
-	case "nuxt/store.ts.tmpl":
-		templateContent = nuxtStoreTemplate
-	case "nuxt/table.vue.tmpl":
-		templateContent = nuxtTableTemplate
-	case "nuxt/form-modal.vue.tmpl":
-		templateContent = nuxtFormModalTemplate
-	case "nuxt/formatters.ts.tmpl":
-		templateContent = nuxtFormattersTemplate
-	case "nuxt/index.vue.tmpl":
-		templateContent = nuxtIndexTemplate
-	case "nuxt/detail.vue.tmpl":
-		templateContent = nuxtDetailTemplate
-	default:
-		return fmt.Errorf("unknown template: %s", templateName)
+	return defaultWriter.GenerateNuxtFile(dir, filename, templateName, data)
+}
+
+// GenerateNuxtFile is GenerateNuxtFile for the generator of w; dir includes w.Dir
+func (w *Writer) GenerateNuxtFile(dir, filename, templateName string, data interface{}) error {
+	content, err := RenderNuxtTemplateIn(w.Dir, templateName, data)
+	if err != nil {
+		return err
+	}
+
+	return w.WriteGeneratedFile(filepath.Join(dir, filename), content)
+}
+
+// GenerateFileFromData generates a file from a template that takes generator-specific data
+// rather than a module's naming and fields, such as the auth extras
+func GenerateFileFromData(dir, filename, templateName string, data interface{}) error {
+	return defaultWriter.GenerateFileFromData(dir, filename, templateName, data)
+}
+
+// GenerateFileFromData is GenerateFileFromData for the generator of w; dir includes w.Dir
+func (w *Writer) GenerateFileFromData(dir, filename, templateName string, data interface{}) error {
+	content, err := renderDataTemplate(w.Dir, templateName, data)
+	if err != nil {
+		return err
+	}
+
+	return w.WriteGeneratedFile(filepath.Join(dir, filename), content)
+}
+
+// RenderNuxtTemplate executes an embedded Nuxt template
+func RenderNuxtTemplate(templateName string, data interface{}) ([]byte, error) {
+	return RenderNuxtTemplateIn(".", templateName, data)
+}
+
+// RenderNuxtTemplateIn is RenderNuxtTemplate for the frontend in dir, whose template overrides it uses
+func RenderNuxtTemplateIn(dir, templateName string, data interface{}) ([]byte, error) {
+	if !strings.HasPrefix(templateName, "nuxt/") {
+		return nil, fmt.Errorf("unknown template: %s", templateName)
+	}
+	return renderDataTemplate(dir, templateName, data)
+}
+
+// renderDataTemplate executes a template with arbitrary data, preferring an override found from dir
+func renderDataTemplate(dir, templateName string, data interface{}) ([]byte, error) {
+	// Get the template content, preferring a project override
+	templateContent, err := loadTemplate(dir, templateName)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create template with helper functions
@@ -561,30 +1497,20 @@ func GenerateNuxtFile(dir, filename, templateName string, data interface{}) erro
 		"ToKebabCase":  ToKebabCase,
 		"ToPlural":     ToPlural,
 		"TrimIdSuffix": TrimIdSuffix,
+		// regexPath escapes a route path like shop/products for a JavaScript regex literal
+		"regexPath": func(path string) string { return strings.ReplaceAll(path, "/", `\/`) },
 	}
 
-	tmpl, err := template.New(filename).Funcs(funcMap).Parse(templateContent)
-	if err != nil {
-		return fmt.Errorf("error parsing template: %w", err)
-	}
-
-	// Ensure directory exists
-	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
-		return fmt.Errorf("error creating directory %s: %w", dir, err)
-	}
-
-	// Create output file
-	outputFile := filepath.Join(dir, filename)
-	f, err := os.Create(outputFile)
+	tmpl, err := template.New(templateName).Funcs(funcMap).Parse(templateContent)
 	if err != nil {
-		return fmt.Errorf("error creating file %s: %w", outputFile, err)
+		return nil, fmt.Errorf("error parsing template: %w", err)
 	}
-	defer f.Close()
 
 	// Execute template
-	if err := tmpl.Execute(f, data); err != nil {
-		return fmt.Errorf("error executing template: %w", err)
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("error executing template: %w", err)
 	}
 
-	return nil
+	return buf.Bytes(), nil
 }
@@ -0,0 +1,163 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UserConfig holds user-wide settings from ~/.bui/config.yaml
+type UserConfig struct {
+	Clone     CloneConfig     `yaml:"clone,omitempty"`
+	Author    AuthorConfig    `yaml:"author,omitempty"`
+	Templates TemplatesConfig `yaml:"templates,omitempty"` // Used when the project's .bui.yaml doesn't override them
+	Telemetry string          `yaml:"telemetry,omitempty"` // "off" disables background network requests
+}
+
+// CloneConfig holds how bui new clones template repositories
+type CloneConfig struct {
+	Protocol string `yaml:"protocol,omitempty"` // auto, ssh or https
+}
+
+// AuthorConfig is the author written into generated READMEs
+type AuthorConfig struct {
+	Name  string `yaml:"name,omitempty"`
+	Email string `yaml:"email,omitempty"`
+}
+
+// User is the loaded user config; it is empty when ~/.bui/config.yaml does not exist
+var User = &UserConfig{}
+
+// UserSetting describes one key accepted by bui config
+type UserSetting struct {
+	Key         string
+	Description string
+	Values      []string // Allowed values, empty when any value is accepted
+	Default     string
+	field       func(*UserConfig) *string
+}
+
+// UserSettings lists the settings bui config can read and write
+var UserSettings = []UserSetting{
+	{"clone.protocol", "Protocol bui new clones templates with", []string{"auto", "ssh", "https"}, "auto",
+		func(c *UserConfig) *string { return &c.Clone.Protocol }},
+	{"author.name", "Author name written into generated READMEs", nil, "",
+		func(c *UserConfig) *string { return &c.Author.Name }},
+	{"author.email", "Author email written into generated READMEs", nil, "",
+		func(c *UserConfig) *string { return &c.Author.Email }},
+	{"templates.backend", "Backend template repository for bui new (owner/repo or clone URL)", nil, "",
+		func(c *UserConfig) *string { return &c.Templates.Backend }},
+	{"templates.frontend", "Frontend template repository for bui new (owner/repo or clone URL)", nil, "",
+		func(c *UserConfig) *string { return &c.Templates.Frontend }},
+	{"telemetry", "Background network requests such as the update check", []string{"on", "off"}, "on",
+		func(c *UserConfig) *string { return &c.Telemetry }},
+}
+
+// UserConfigPath returns the path of the user config file
+func UserConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".bui", "config.yaml"), nil
+}
+
+// LoadUserConfig reads ~/.bui/config.yaml into User; a missing file leaves every setting at its default
+func LoadUserConfig() error {
+	User = &UserConfig{}
+	path, err := UserConfigPath()
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(content, User); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// SaveUserConfig writes User to ~/.bui/config.yaml
+func SaveUserConfig() error {
+	path, err := UserConfigPath()
+	if err != nil {
+		return err
+	}
+	content, err := yaml.Marshal(User)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0644)
+}
+
+// FindUserSetting returns the setting with the given key
+func FindUserSetting(key string) (UserSetting, bool) {
+	for _, setting := range UserSettings {
+		if setting.Key == key {
+			return setting, true
+		}
+	}
+	return UserSetting{}, false
+}
+
+// Get returns the setting's value in c, or its default when unset
+func (s UserSetting) Get(c *UserConfig) string {
+	if value := *s.field(c); value != "" {
+		return value
+	}
+	return s.Default
+}
+
+// Set stores value in c after checking it against the allowed values; an empty value unsets it
+func (s UserSetting) Set(c *UserConfig, value string) error {
+	if value != "" && len(s.Values) > 0 && !slices.Contains(s.Values, value) {
+		return fmt.Errorf("%s must be one of: %s", s.Key, strings.Join(s.Values, ", "))
+	}
+	*s.field(c) = value
+	return nil
+}
+
+// BackendTemplate returns the backend template repository, or fallback when not set
+func (c *UserConfig) BackendTemplate(fallback string) string {
+	if c.Templates.Backend == "" {
+		return fallback
+	}
+	return c.Templates.Backend
+}
+
+// FrontendTemplate returns the frontend template repository, or fallback when not set
+func (c *UserConfig) FrontendTemplate(fallback string) string {
+	if c.Templates.Frontend == "" {
+		return fallback
+	}
+	return c.Templates.Frontend
+}
+
+// AuthorLine returns the author as "Name <email>", or "" when neither is set
+func (c *UserConfig) AuthorLine() string {
+	switch {
+	case c.Author.Name != "" && c.Author.Email != "":
+		return fmt.Sprintf("%s <%s>", c.Author.Name, c.Author.Email)
+	case c.Author.Name != "":
+		return c.Author.Name
+	}
+	return c.Author.Email
+}
+
+// TelemetryEnabled reports whether background network requests are allowed
+func (c *UserConfig) TelemetryEnabled() bool {
+	return c.Telemetry != "off"
+}
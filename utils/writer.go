@@ -0,0 +1,292 @@
+package utils
+
+import (
+	"fmt"
+	"go/format"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/base-go/mamba/pkg/interactive"
+)
+
+// DryRun makes generators report the files they would write instead of writing them
+var DryRun bool
+
+// ShowDiff prints a unified diff for every file reported during a dry run
+var ShowDiff bool
+
+// Force lets generators overwrite existing files without asking
+var Force bool
+
+// Writer writes the files of a generator working in Dir, and keeps what it wrote for the
+// summary, the changelog and the manifest
+type Writer struct {
+	Dir string // Directory the generator works in, which its paths are joined to
+	Run *Run   // Run the writer belongs to
+
+	// written and skipped track the outcome of generated writes for the summary, and updated the
+	// project files edited for the changelog
+	written []string
+	skipped []string
+	updated []string
+
+	// merged holds what the generator produced for the written files that were merged with the
+	// edits made to them, which the manifest records instead of the merged content; conflicted
+	// are the merged files left with conflict markers
+	merged     map[string][]byte
+	conflicted []string
+
+	// backedUp is set once the run's backup has a file w replaced
+	backedUp bool
+}
+
+// defaultWriter writes for the package-level functions, used by the generators that work in the
+// current directory
+var defaultWriter = (&Run{}).Writer(".")
+
+// DefaultWriter returns the writer of the package-level functions, for helpers shared with the
+// module generators
+func DefaultWriter() *Writer {
+	return defaultWriter
+}
+
+// ResetGeneratedFiles clears the written/skipped summary and overwrite backup before a generator runs
+func ResetGeneratedFiles() {
+	defaultWriter = (&Run{}).Writer(".")
+}
+
+// GeneratedFiles returns the files written and skipped since the last reset
+func GeneratedFiles() (written, skipped []string) {
+	return defaultWriter.GeneratedFiles()
+}
+
+// GeneratedFiles returns the files w wrote and skipped
+func (w *Writer) GeneratedFiles() (written, skipped []string) {
+	return w.written, w.skipped
+}
+
+// MergedFiles returns the files w wrote merged with their edits, and those of them left with
+// conflict markers
+func (w *Writer) MergedFiles() (merged, conflicted []string) {
+	for _, path := range w.written {
+		if _, ok := w.merged[path]; ok {
+			merged = append(merged, path)
+		}
+	}
+	return merged, w.conflicted
+}
+
+// WriteGeneratedFile writes generated content to path, creating parent directories.
+// Existing files that differ are only replaced with Force or after confirmation.
+// In dry-run mode it only reports the change (and prints a diff when ShowDiff is set).
+func WriteGeneratedFile(path string, content []byte) error {
+	return defaultWriter.WriteGeneratedFile(path, content)
+}
+
+// WriteGeneratedFile is WriteGeneratedFile for the generator of w
+func (w *Writer) WriteGeneratedFile(path string, content []byte) error {
+	return w.writeFile(path, content, true)
+}
+
+// UpdateProjectFile writes an edit to an existing project file such as app/init.go.
+// It honours DryRun but not overwrite protection, since the edit is intentional.
+func UpdateProjectFile(path string, content []byte) error {
+	return defaultWriter.UpdateProjectFile(path, content)
+}
+
+// UpdateProjectFile is UpdateProjectFile for the generator of w
+func (w *Writer) UpdateProjectFile(path string, content []byte) error {
+	return w.writeFile(path, content, false)
+}
+
+// writeFile performs the write shared by WriteGeneratedFile and UpdateProjectFile
+func (w *Writer) writeFile(path string, content []byte, protect bool) error {
+	content = normalizeContent(path, content)
+
+	if DryRun {
+		w.Run.reportDryRun(path, content, protect)
+		return nil
+	}
+
+	existing, err := os.ReadFile(path)
+	unchanged := err == nil && string(existing) == string(content)
+	if protect && unchanged {
+		return nil
+	}
+
+	// Files the manifest records as bui wrote them, unedited since, are replaced without asking
+	if protect && err == nil && !w.Run.generatedUnchanged(path, existing) {
+		// Files edited since bui wrote them are merged instead, unless the template is unchanged
+		base, edited := w.Run.generatedSnapshot(path)
+		edited = edited && !Force
+		if edited && string(base) == string(content) {
+			return nil
+		}
+		// Files git can't merge, such as binary files, are asked about like the unrecorded ones
+		var merged []byte
+		var conflicts int
+		if edited {
+			var err error
+			merged, conflicts, err = MergeFile(existing, base, content, "edited", "generated")
+			edited = err == nil
+		}
+		if !Force && !edited && !w.Run.confirmOverwrite(path) {
+			w.skipped = append(w.skipped, path)
+			return nil
+		}
+		if err := w.backupOverwritten(path); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+		if edited {
+			w.recordMerge(path, content, conflicts)
+			content = merged
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("error creating directory %s: %w", filepath.Dir(path), err)
+	}
+
+	if err := writeFileAtomic(path, content); err != nil {
+		return fmt.Errorf("error writing file %s: %w", path, err)
+	}
+
+	if protect {
+		w.written = append(w.written, path)
+	} else if !unchanged && !slices.Contains(w.updated, path) {
+		w.updated = append(w.updated, path)
+	}
+
+	return nil
+}
+
+// writeFileAtomic replaces path with content through a temporary file, so the other generator of
+// a run reading it, such as the frontend reading the backend model, never sees it half written
+func writeFileAtomic(path string, content []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// normalizeContent gofmts Go sources so they compare equal to previously generated, formatted files
+func normalizeContent(path string, content []byte) []byte {
+	if !strings.HasSuffix(path, ".go") {
+		return content
+	}
+	if formatted, err := format.Source(content); err == nil {
+		return formatted
+	}
+	return content
+}
+
+// confirmOverwrite asks before replacing an existing file; without a terminal the file is kept.
+// The writers of a run ask one at a time.
+func (r *Run) confirmOverwrite(path string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+
+	question := fmt.Sprintf("%s already exists. Overwrite?", path)
+	if r.generatedBefore(path) {
+		question = fmt.Sprintf("%s was edited since bui generated it. Overwrite?", path)
+	}
+	confirmed, err := interactive.AskConfirm(question, false)
+	if err != nil {
+		return false
+	}
+	return confirmed
+}
+
+// reportDryRun prints what writeFile would do to path, with its diff in one piece
+func (r *Run) reportDryRun(path string, content []byte, protect bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := os.ReadFile(path)
+	base, edited := r.generatedSnapshot(path)
+	switch {
+	case err != nil:
+		fmt.Printf("  create    %s\n", path)
+	case string(existing) == string(content):
+		fmt.Printf("  identical %s\n", path)
+		return
+	case !protect || Force || r.generatedUnchanged(path, existing):
+		fmt.Printf("  modify    %s\n", path)
+	case edited && string(base) == string(content):
+		fmt.Printf("  edited    %s (kept, the template is unchanged)\n", path)
+		return
+	case edited:
+		merged, conflicts, err := MergeFile(existing, base, content, "edited", "generated")
+		if err != nil {
+			fmt.Printf("  conflict  %s (kept unless --force or confirmed)\n", path)
+			break
+		}
+		content = merged
+		if conflicts > 0 {
+			fmt.Printf("  conflict  %s (%d conflicts merging the edits)\n", path, conflicts)
+		} else {
+			fmt.Printf("  merge     %s\n", path)
+		}
+	default:
+		fmt.Printf("  conflict  %s (kept unless --force or confirmed)\n", path)
+	}
+
+	if ShowDiff {
+		printDiff(path, content)
+	}
+}
+
+// printDiff prints a unified diff between the file on disk and the new content
+func printDiff(path string, content []byte) {
+	tmp, err := os.CreateTemp("", "bui-diff-*")
+	if err != nil {
+		fmt.Println(string(content))
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		fmt.Println(string(content))
+		return
+	}
+	tmp.Close()
+
+	original := path
+	if _, err := os.Stat(path); err != nil {
+		original = os.DevNull
+	}
+
+	// diff exits with status 1 when files differ, so only a missing binary is an error
+	out, err := exec.Command("diff", "-u", "--label", "a/"+path, "--label", "b/"+path, original, tmp.Name()).CombinedOutput()
+	if err != nil && len(out) == 0 {
+		fmt.Println(string(content))
+		return
+	}
+	fmt.Print(string(out))
+}
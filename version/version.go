@@ -85,6 +85,30 @@ func CheckLatestVersion() (*Release, error) {
 	return &release, nil
 }
 
+// GetRelease fetches the GitHub release for a tag such as v1.2.3
+func GetRelease(tag string) (*Release, error) {
+	url := "https://api.github.com/repos/base-al/bui/releases/tags/" + tag
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("release %s not found", tag)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, err
+	}
+
+	return &release, nil
+}
+
 // HasUpdate checks if the current version is behind the latest release
 func HasUpdate(current, latest string) bool {
 	if current == "dev" {